@@ -34,7 +34,7 @@ generate_jwt() {
   exp=$((now + 86400 * 365))
 
   header=$(printf '{"alg":"HS256","typ":"JWT"}' | b64url)
-  payload=$(printf '{"iss":"aevum-seed","sub":"seed-runner","iat":%s,"exp":%s}' "$now" "$exp" | b64url)
+  payload=$(printf '{"iss":"aevum-seed","sub":"seed-runner","scope":"events:read events:write","streams":["*"],"iat":%s,"exp":%s}' "$now" "$exp" | b64url)
   unsigned="$header.$payload"
   signature=$(printf '%s' "$unsigned" | openssl dgst -sha256 -hmac "$secret" -binary | b64url)
   printf '%s.%s\n' "$unsigned" "$signature"
@@ -9,6 +9,9 @@ namespace Aevum.DecisionEngine.Api.Endpoints;
 
 public static class DecisionEndpoints
 {
+    private const int DefaultPageSize = 50;
+    private const int MaxPageSize = 200;
+
     public static RouteGroupBuilder MapDecisionEndpoints(this RouteGroupBuilder group)
     {
         group.MapPost("/evaluate", EvaluateDecisionAsync)
@@ -19,6 +22,12 @@ public static class DecisionEndpoints
             .Produces(StatusCodes.Status404NotFound)
             .Produces(StatusCodes.Status422UnprocessableEntity);
 
+        group.MapGet("/", ListDecisionsAsync)
+            .WithName("ListDecisions")
+            .WithOpenApi()
+            .Produces<PaginatedResponse<DecisionResponse>>()
+            .ProducesValidationProblem();
+
         group.MapGet("/{id}", GetDecisionByIdAsync)
             .WithName("GetDecisionById")
             .WithOpenApi()
@@ -67,6 +76,37 @@ public static class DecisionEndpoints
         return Results.Ok(decision.ToResponse());
     }
 
+    // Decisions are listed oldest first, so a caller paging with a fixed from
+    // only sees newly evaluated decisions appended after the pages it has read.
+    private static async Task<IResult> ListDecisionsAsync(
+        [FromQuery] string? streamId,
+        [FromQuery] DateTimeOffset? from,
+        [FromQuery] DateTimeOffset? to,
+        [FromQuery] int? page,
+        [FromQuery] int? pageSize,
+        [FromServices] IDecisionRepository repository,
+        CancellationToken cancellationToken)
+    {
+        var currentPage = page ?? 1;
+        var size = pageSize ?? DefaultPageSize;
+        if (currentPage < 1 || size < 1 || size > MaxPageSize)
+        {
+            return Results.ValidationProblem(new Dictionary<string, string[]>
+            {
+                ["page"] = [$"page must be at least 1 and pageSize between 1 and {MaxPageSize}"]
+            });
+        }
+
+        var (decisions, total) = await repository.ListAsync(streamId, from, to, currentPage, size, cancellationToken);
+        return Results.Ok(new PaginatedResponse<DecisionResponse>
+        {
+            Items = decisions.Select(d => d.ToResponse()).ToList(),
+            Page = currentPage,
+            PageSize = size,
+            Total = total
+        });
+    }
+
     private static async Task<IResult> GetDecisionByIdAsync(
         [FromRoute] string id,
         [FromServices] IDecisionRepository repository,
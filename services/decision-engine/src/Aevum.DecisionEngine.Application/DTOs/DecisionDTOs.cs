@@ -8,6 +8,7 @@ public sealed record EvaluateDecisionRequest
     public int? RuleVersion { get; init; }
     public required Dictionary<string, object> Context { get; init; }
     public required string RequestId { get; init; }
+    public string? StreamId { get; init; }
     public Dictionary<string, string>? Metadata { get; init; }
 }
 
@@ -17,6 +18,7 @@ public sealed record DecisionResponse
     public required string RuleId { get; init; }
     public required int RuleVersion { get; init; }
     public required string RequestId { get; init; }
+    public string? StreamId { get; init; }
     public required DecisionStatus Status { get; init; }
     public required Dictionary<string, object> InputContext { get; init; }
     public required IReadOnlyList<string> MatchedConditions { get; init; }
@@ -27,3 +29,11 @@ public sealed record DecisionResponse
     public Dictionary<string, object>? OutputData { get; init; }
     public required long EvaluationDurationMs { get; init; }
 }
+
+public sealed record PaginatedResponse<T>
+{
+    public required IReadOnlyList<T> Items { get; init; }
+    public required int Page { get; init; }
+    public required int PageSize { get; init; }
+    public required long Total { get; init; }
+}
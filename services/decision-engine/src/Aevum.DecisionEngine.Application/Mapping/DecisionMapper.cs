@@ -12,6 +12,7 @@ public static class DecisionMapper
             Data = request.Context,
             RequestId = request.RequestId,
             Timestamp = DateTimeOffset.UtcNow, // Will be replaced by TimeProvider in service layer
+            StreamId = request.StreamId,
             Metadata = request.Metadata ?? new Dictionary<string, string>()
         };
     }
@@ -24,6 +25,7 @@ public static class DecisionMapper
             RuleId = decision.RuleId,
             RuleVersion = decision.RuleVersion,
             RequestId = decision.RequestId,
+            StreamId = decision.StreamId,
             Status = decision.Status,
             InputContext = decision.InputContext.ToDictionary(kvp => kvp.Key, kvp => kvp.Value),
             MatchedConditions = decision.MatchedConditions,
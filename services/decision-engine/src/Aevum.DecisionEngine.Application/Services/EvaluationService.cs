@@ -39,6 +39,7 @@ public sealed class EvaluationService(
             RuleId = rule.Id,
             RuleVersion = rule.Version,
             RequestId = context.RequestId,
+            StreamId = context.StreamId,
             Status = result.Status,
             InputContext = context.Data,
             MatchedConditions = result.MatchedConditions,
@@ -21,5 +21,8 @@ public sealed class EvaluateDecisionRequestValidator : AbstractValidator<Evaluat
         RuleFor(x => x.RequestId)
             .NotEmpty().WithMessage("RequestId is required")
             .MaximumLength(100).WithMessage("RequestId must not exceed 100 characters");
+
+        RuleFor(x => x.StreamId)
+            .MaximumLength(256).WithMessage("StreamId must not exceed 256 characters");
     }
 }
@@ -7,5 +7,6 @@ public interface IDecisionRepository
     Task<Decision?> GetByIdAsync(string id, CancellationToken cancellationToken = default);
     Task<Decision?> GetByRequestIdAsync(string requestId, CancellationToken cancellationToken = default);
     Task<IReadOnlyList<Decision>> GetByRuleIdAsync(string ruleId, int? version = null, CancellationToken cancellationToken = default);
+    Task<(IReadOnlyList<Decision> Items, long Total)> ListAsync(string? streamId, DateTimeOffset? from, DateTimeOffset? to, int page, int pageSize, CancellationToken cancellationToken = default);
     Task<Decision> CreateAsync(Decision decision, CancellationToken cancellationToken = default);
 }
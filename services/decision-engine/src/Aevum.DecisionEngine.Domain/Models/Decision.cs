@@ -8,6 +8,7 @@ public sealed record Decision
     public required string RuleId { get; init; }
     public required int RuleVersion { get; init; }
     public required string RequestId { get; init; }
+    public string? StreamId { get; init; }
     public required DecisionStatus Status { get; init; }
     public required IReadOnlyDictionary<string, object> InputContext { get; init; }
     public required IReadOnlyList<string> MatchedConditions { get; init; }
@@ -5,5 +5,6 @@ public sealed record EvaluationContext
     public required IReadOnlyDictionary<string, object> Data { get; init; }
     public required string RequestId { get; init; }
     public required DateTimeOffset Timestamp { get; init; }
+    public string? StreamId { get; init; }
     public IReadOnlyDictionary<string, string> Metadata { get; init; } = new Dictionary<string, string>();
 }
@@ -15,6 +15,7 @@ public static class DecisionDocumentMapper
             RuleId = decision.RuleId,
             RuleVersion = decision.RuleVersion,
             RequestId = decision.RequestId,
+            StreamId = decision.StreamId,
             Status = decision.Status,
             InputContext = decision.InputContext.ToDictionary(kvp => kvp.Key, kvp => BsonValue.Create(NormalizeValue(kvp.Value))),
             MatchedConditions = decision.MatchedConditions.ToList(),
@@ -68,6 +69,7 @@ public static class DecisionDocumentMapper
             RuleId = doc.RuleId,
             RuleVersion = doc.RuleVersion,
             RequestId = doc.RequestId,
+            StreamId = doc.StreamId,
             Status = doc.Status,
             InputContext = doc.InputContext.ToDictionary(kvp => kvp.Key, kvp => BsonValueToObject(kvp.Value)),
             MatchedConditions = doc.MatchedConditions,
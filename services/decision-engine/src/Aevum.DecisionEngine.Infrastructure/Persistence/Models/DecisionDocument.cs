@@ -19,6 +19,9 @@ public sealed class DecisionDocument
     [BsonElement("requestId")]
     public string RequestId { get; set; } = string.Empty;
 
+    [BsonElement("streamId")]
+    public string? StreamId { get; set; }
+
     [BsonElement("status")]
     [BsonRepresentation(BsonType.String)]
     public DecisionStatus Status { get; set; }
@@ -8,6 +8,10 @@ namespace Aevum.DecisionEngine.Infrastructure.Persistence.Repositories;
 
 public sealed class MongoDbDecisionRepository : IDecisionRepository
 {
+    // EvaluatedAt is stored in the driver's default [ticks, offset] array form and
+    // is always UTC, so the first element orders decisions by evaluation time.
+    private const string EvaluatedAtTicks = "evaluatedAt.0";
+
     private readonly IMongoCollection<DecisionDocument> _collection;
 
     public MongoDbDecisionRepository(MongoDbContext context)
@@ -33,6 +37,19 @@ public sealed class MongoDbDecisionRepository : IDecisionRepository
             .Ascending(d => d.RuleVersion);
         var ruleIndexModel = new CreateIndexModel<DecisionDocument>(ruleIndexKeys);
         _collection.Indexes.CreateOne(ruleIndexModel);
+
+        var evaluatedAtIndexKeys = Builders<DecisionDocument>.IndexKeys
+            .Ascending(EvaluatedAtTicks)
+            .Ascending(d => d.Id);
+        var evaluatedAtIndexModel = new CreateIndexModel<DecisionDocument>(evaluatedAtIndexKeys);
+        _collection.Indexes.CreateOne(evaluatedAtIndexModel);
+
+        var streamIndexKeys = Builders<DecisionDocument>.IndexKeys
+            .Ascending(d => d.StreamId)
+            .Ascending(EvaluatedAtTicks)
+            .Ascending(d => d.Id);
+        var streamIndexModel = new CreateIndexModel<DecisionDocument>(streamIndexKeys);
+        _collection.Indexes.CreateOne(streamIndexModel);
     }
 
     public async Task<Decision?> GetByIdAsync(string id, CancellationToken cancellationToken = default)
@@ -63,6 +80,35 @@ public sealed class MongoDbDecisionRepository : IDecisionRepository
         return docs.Select(d => d.ToDomain()).ToList();
     }
 
+    public async Task<(IReadOnlyList<Decision> Items, long Total)> ListAsync(string? streamId, DateTimeOffset? from, DateTimeOffset? to, int page, int pageSize, CancellationToken cancellationToken = default)
+    {
+        var filter = Builders<DecisionDocument>.Filter.Empty;
+
+        if (!string.IsNullOrEmpty(streamId))
+        {
+            filter &= Builders<DecisionDocument>.Filter.Eq(d => d.StreamId, streamId);
+        }
+
+        if (from.HasValue)
+        {
+            filter &= Builders<DecisionDocument>.Filter.Gte(EvaluatedAtTicks, from.Value.UtcTicks);
+        }
+
+        if (to.HasValue)
+        {
+            filter &= Builders<DecisionDocument>.Filter.Lte(EvaluatedAtTicks, to.Value.UtcTicks);
+        }
+
+        var sort = Builders<DecisionDocument>.Sort.Ascending(EvaluatedAtTicks).Ascending(d => d.Id);
+        var total = await _collection.CountDocumentsAsync(filter, cancellationToken: cancellationToken);
+        var docs = await _collection.Find(filter)
+            .Sort(sort)
+            .Skip((page - 1) * pageSize)
+            .Limit(pageSize)
+            .ToListAsync(cancellationToken);
+        return (docs.Select(d => d.ToDomain()).ToList(), total);
+    }
+
     public async Task<Decision> CreateAsync(Decision decision, CancellationToken cancellationToken = default)
     {
         var doc = decision.ToDocument();
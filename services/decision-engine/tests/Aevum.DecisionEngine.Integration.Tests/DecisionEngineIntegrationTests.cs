@@ -283,4 +283,113 @@ public sealed class DecisionEngineIntegrationTests : IAsyncLifetime
         first!.Id.Should().Be(second!.Id);
         first.DeterministicHash.Should().Be(second.DeterministicHash);
     }
+
+    [Fact]
+    public async Task ListDecisions_ShouldPageOldestFirst()
+    {
+        // Arrange
+        var createRuleRequest = new CreateRuleRequest
+        {
+            Name = "Listing Rule",
+            Conditions =
+            [
+                new RuleConditionDto
+                {
+                    Field = "score",
+                    Operator = ComparisonOperator.GreaterThanOrEqual,
+                    Value = 80
+                }
+            ],
+            Actions =
+            [
+                new RuleActionDto
+                {
+                    Type = ActionType.StoreDecision,
+                    Parameters = new Dictionary<string, object> { ["result"] = "pass" },
+                    Order = 1
+                }
+            ],
+            Priority = 10
+        };
+
+        var createResponse = await _client.PostAsJsonAsync("/api/v1/rules", createRuleRequest);
+        var rule = await createResponse.Content.ReadFromJsonAsync<RuleResponse>();
+        await _client.PostAsync($"/api/v1/rules/{rule!.Id}/activate", null);
+
+        for (var i = 0; i < 3; i++)
+        {
+            await _client.PostAsJsonAsync("/api/v1/decisions/evaluate", new EvaluateDecisionRequest
+            {
+                RuleId = rule.Id,
+                Context = new Dictionary<string, object> { ["score"] = 85 + i },
+                RequestId = Guid.NewGuid().ToString()
+            });
+        }
+
+        // Act
+        var firstPage = await _client.GetFromJsonAsync<PaginatedResponse<DecisionResponse>>("/api/v1/decisions?pageSize=2");
+        var secondPage = await _client.GetFromJsonAsync<PaginatedResponse<DecisionResponse>>("/api/v1/decisions?page=2&pageSize=2");
+        var invalidResponse = await _client.GetAsync("/api/v1/decisions?pageSize=500");
+
+        // Assert
+        firstPage!.Total.Should().Be(3);
+        firstPage.Items.Should().HaveCount(2);
+        firstPage.Items.Should().BeInAscendingOrder(d => d.EvaluatedAt);
+        secondPage!.Items.Should().ContainSingle();
+        secondPage.Items[0].EvaluatedAt.Should().BeOnOrAfter(firstPage.Items[1].EvaluatedAt);
+        invalidResponse.StatusCode.Should().Be(HttpStatusCode.BadRequest);
+    }
+
+    [Fact]
+    public async Task ListDecisions_ShouldFilterByStream()
+    {
+        // Arrange
+        var createRuleRequest = new CreateRuleRequest
+        {
+            Name = "Stream Listing Rule",
+            Conditions =
+            [
+                new RuleConditionDto
+                {
+                    Field = "score",
+                    Operator = ComparisonOperator.GreaterThanOrEqual,
+                    Value = 80
+                }
+            ],
+            Actions =
+            [
+                new RuleActionDto
+                {
+                    Type = ActionType.StoreDecision,
+                    Parameters = new Dictionary<string, object> { ["result"] = "pass" },
+                    Order = 1
+                }
+            ],
+            Priority = 10
+        };
+
+        var createResponse = await _client.PostAsJsonAsync("/api/v1/rules", createRuleRequest);
+        var rule = await createResponse.Content.ReadFromJsonAsync<RuleResponse>();
+        await _client.PostAsync($"/api/v1/rules/{rule!.Id}/activate", null);
+
+        var streamId = $"orders-{Guid.NewGuid()}";
+        foreach (var stream in new[] { streamId, streamId, "invoices-1" })
+        {
+            await _client.PostAsJsonAsync("/api/v1/decisions/evaluate", new EvaluateDecisionRequest
+            {
+                RuleId = rule.Id,
+                Context = new Dictionary<string, object> { ["score"] = 90 },
+                RequestId = Guid.NewGuid().ToString(),
+                StreamId = stream
+            });
+        }
+
+        // Act
+        var page = await _client.GetFromJsonAsync<PaginatedResponse<DecisionResponse>>($"/api/v1/decisions?streamId={streamId}");
+
+        // Assert
+        page!.Total.Should().Be(2);
+        page.Items.Should().HaveCount(2);
+        page.Items.Should().OnlyContain(d => d.StreamId == streamId);
+    }
 }
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,14 +17,26 @@ import (
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/grpcapi"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/handlers"
 	adminhandlers "github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/handlers/admin"
+	mw "github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/middleware"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/apikey"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/config"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/encryption"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/export"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/integrity"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/mtls"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ratelimit"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/redaction"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/replay"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
@@ -43,7 +57,15 @@ func run() error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
+	var redactionPolicy *redaction.Policy
+	if cfg.RedactionPolicyFile != "" {
+		if redactionPolicy, err = redaction.LoadPolicy(cfg.RedactionPolicyFile); err != nil {
+			return fmt.Errorf("load redaction policy: %w", err)
+		}
+	}
+
 	logger := observability.NewLogger(cfg.LogLevel)
+	logger = slog.New(redaction.NewLogHandler(logger.Handler(), redactionPolicy))
 	slog.SetDefault(logger)
 
 	tp, err := observability.InitTracerProvider(ctx, cfg.OTELEndpoint)
@@ -79,57 +101,226 @@ func run() error {
 	metrics := observability.NewMetrics()
 	eventStore := storage.NewDynamoDBEventStore(dynamoClient, cfg.DynamoTable)
 	streamStore := storage.NewDynamoDBStreamStore(dynamoClient, cfg.DynamoTable)
+	checkpointStore := storage.NewDynamoDBCheckpointStore(dynamoClient, cfg.DynamoTable)
+	masterKey, err := encryption.ParseMasterKey(cfg.EncryptionMasterKey)
+	if err != nil {
+		return fmt.Errorf("load encryption master key: %w", err)
+	}
+	var encryptionPolicy encryption.Policy
+	if cfg.EncryptionPolicyFile != "" {
+		if encryptionPolicy, err = encryption.LoadPolicy(cfg.EncryptionPolicyFile); err != nil {
+			return fmt.Errorf("load encryption policy: %w", err)
+		}
+	}
+	var encryptionService *encryption.Service
+	if masterKey != nil {
+		encryptionService = encryption.NewService(encryption.Dependencies{
+			Keys:   storage.NewDynamoDBDataKeyStore(dynamoClient, cfg.DynamoTable),
+			KMS:    masterKey,
+			Policy: encryptionPolicy,
+		})
+	}
+	readStore := redaction.NewRedactingEventStore(encryption.NewDecryptingEventStore(eventStore, encryptionService), redactionPolicy)
 	ingestService := ingest.NewService(eventStore, identifier.NewULIDGenerator(), clock.RealClock{}, metrics)
-	replayEngine := replay.NewEngine(eventStore, metrics)
+	if encryptionService.Enabled() {
+		ingestService.WithPayloadEncryptor(encryptionService)
+	}
+	replayEngine := replay.NewEngine(readStore, metrics)
+	signingKey, err := integrity.ParseSigningKey(cfg.CheckpointSigningKey)
+	if err != nil {
+		return fmt.Errorf("load checkpoint signing key: %w", err)
+	}
+	checkpointer := integrity.NewCheckpointer(integrity.CheckpointerDependencies{
+		EventStore:      eventStore,
+		StreamStore:     streamStore,
+		CheckpointStore: checkpointStore,
+		SigningKey:      signingKey,
+		Clock:           clock.RealClock{},
+		Logger:          logger,
+		Interval:        cfg.CheckpointInterval,
+	})
+
+	var keySet *mw.KeySet
+	if cfg.JWKSURL != "" || cfg.JWKSFile != "" {
+		keySet, err = mw.NewKeySet(ctx, mw.JWKSOptions{
+			URL:             cfg.JWKSURL,
+			File:            cfg.JWKSFile,
+			RefreshInterval: cfg.JWKSRefreshInterval,
+		})
+		if err != nil {
+			return fmt.Errorf("load jwks: %w", err)
+		}
+	}
+	var tlsConfig *tls.Config
+	if cfg.TLSCertFile != "" {
+		reloader, err := mtls.NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+		if err != nil {
+			return fmt.Errorf("load tls certificates: %w", err)
+		}
+		tlsConfig = reloader.ServerConfig(mtls.ClientAuth(cfg.TLSClientAuth))
+	}
+	var certIdentities *mtls.Identities
+	if cfg.MTLSIdentitiesFile != "" {
+		if certIdentities, err = mtls.LoadIdentities(cfg.MTLSIdentitiesFile); err != nil {
+			return fmt.Errorf("load mtls identities: %w", err)
+		}
+	}
+	apiKeys := apikey.NewService(apikey.Dependencies{
+		Store:  storage.NewDynamoDBAPIKeyStore(dynamoClient, cfg.DynamoTable),
+		Logger: logger,
+	})
+	tokenValidator := mw.NewTokenValidator(mw.TokenValidatorOptions{
+		HMACSecret:     cfg.JWTSecret,
+		KeySet:         keySet,
+		Audience:       cfg.JWTAudience,
+		ClockSkew:      cfg.JWTClockSkew,
+		TenantClaim:    cfg.TenantClaim,
+		CertIdentities: certIdentities,
+		APIKeys:        apiKeys,
+	})
+
+	var rateLimitPolicies []ratelimit.Policy
+	if cfg.RateLimitPolicyFile != "" {
+		rateLimitPolicies, err = ratelimit.LoadPolicies(cfg.RateLimitPolicyFile)
+		if err != nil {
+			return fmt.Errorf("load rate limit policies: %w", err)
+		}
+	}
+	var rateLimitBackend ratelimit.Backend
+	if cfg.RateLimitRedisURL != "" {
+		redisOptions, err := redis.ParseURL(cfg.RateLimitRedisURL)
+		if err != nil {
+			return fmt.Errorf("parse rate limit redis url: %w", err)
+		}
+		redisClient := redis.NewClient(redisOptions)
+		defer func() { _ = redisClient.Close() }()
+		if err := redisClient.Ping(ctx).Err(); err != nil {
+			logger.Warn("rate limit redis unreachable, local limits apply until it recovers", slog.String("error", err.Error()))
+		}
+		rateLimitBackend = ratelimit.NewRedisBackend(redisClient, ratelimit.DefaultRedisKeyPrefix)
+	}
+	rateLimiter := mw.NewRateLimiter(mw.RateLimiterOptions{
+		Policies: rateLimitPolicies,
+		Fallback: ratelimit.Limit{Rate: cfg.RateLimitPerSec, Burst: cfg.RateLimitBurst},
+		Backend:  rateLimitBackend,
+		Metrics:  metrics,
+		Logger:   logger,
+	})
 
 	ingestHandler := handlers.NewIngestHandler(ingestService)
 	batchIngestHandler := handlers.NewBatchIngestHandler(ingestService)
-	streamHandler := handlers.NewStreamHandler(eventStore)
-	eventHandler := handlers.NewEventHandler(eventStore)
+	streamHandler := handlers.NewStreamHandler(readStore)
+	eventHandler := handlers.NewEventHandler(readStore)
+	proofHandler := handlers.NewProofHandler(checkpointer)
 
 	healthHandler := adminhandlers.NewHealthHandler(eventStore)
 	readyHandler := adminhandlers.NewReadyHandler()
 	replayHandler := adminhandlers.NewReplayHandler(replayEngine)
 	streamsHandler := adminhandlers.NewStreamsHandler(streamStore)
 	metricsHandler := adminhandlers.NewMetricsHandler(metrics)
+	importHandler := adminhandlers.NewImportHandler(ingest.NewImporter(ingestService, logger, ingest.ImportOptions{Workers: cfg.ImportWorkers}))
+	exportHandler := adminhandlers.NewExportHandler(export.NewExporter(eventStore, clock.RealClock{}), logger)
+	verifyHandler := adminhandlers.NewVerifyHandler(integrity.NewVerifier(eventStore))
+	checkpointHandler := adminhandlers.NewCheckpointHandler(checkpointer)
+	dataKeyHandler := adminhandlers.NewDataKeyHandler(encryptionService)
+	apiKeyHandler := adminhandlers.NewAPIKeyHandler(apiKeys)
+	accessLogHandler := adminhandlers.NewAccessLogHandler(accesslog.NewReader(eventStore))
+
+	var accessRecorder *accesslog.Recorder
+	if cfg.AccessLogEnabled {
+		accessRecorder = accesslog.NewRecorder(accesslog.Options{
+			Sink:          accesslog.NewStreamSink(ingestService),
+			BufferSize:    cfg.AccessLogBufferSize,
+			FlushInterval: cfg.AccessLogFlushInterval,
+			Metrics:       metrics,
+			Logger:        logger,
+		})
+	}
 
 	ginRouter := api.NewGinRouter(api.GinDependencies{
-		Logger:      logger,
-		Metrics:     metrics,
-		JWTSecret:   cfg.JWTSecret,
-		RatePerSec:  cfg.RateLimitPerSec,
-		RateBurst:   cfg.RateLimitBurst,
-		Ingest:      ingestHandler,
-		BatchIngest: batchIngestHandler,
-		Stream:      streamHandler,
-		Event:       eventHandler,
+		Logger:         logger,
+		Metrics:        metrics,
+		TokenValidator: tokenValidator,
+		RateLimiter:    rateLimiter,
+		Ingest:         ingestHandler,
+		BatchIngest:    batchIngestHandler,
+		Stream:         streamHandler,
+		StreamList:     handlers.NewStreamListHandler(streamStore),
+		Event:          eventHandler,
+		Proof:          proofHandler,
+		AccessLog:      accessRecorder,
 	})
 	echoRouter := api.NewEchoRouter(api.EchoDependencies{
-		Health:  healthHandler,
-		Ready:   readyHandler,
-		Replay:  replayHandler,
-		Streams: streamsHandler,
-		Metrics: metricsHandler,
+		Logger:         logger,
+		TokenValidator: tokenValidator,
+		Health:         healthHandler,
+		Ready:          readyHandler,
+		Replay:         replayHandler,
+		Streams:        streamsHandler,
+		Metrics:        metricsHandler,
+		Import:         importHandler,
+		Export:         exportHandler,
+		Verify:         verifyHandler,
+		Checkpoint:     checkpointHandler,
+		DataKey:        dataKeyHandler,
+		APIKeys:        apiKeyHandler,
+		AccessLog:      accessLogHandler,
+		AccessRecorder: accessRecorder,
 	})
+	grpcServer := grpcapi.NewServer(grpcapi.Dependencies{
+		Logger:         logger,
+		TokenValidator: tokenValidator,
+		Ingest:         ingestService,
+		EventStore:     readStore,
+		Replay:         replayEngine,
+		TLSConfig:      tlsConfig,
+		AccessLog:      accessRecorder,
+	})
+
+	ginServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.GinPort), Handler: ginRouter, TLSConfig: tlsConfig}
+	echoServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.EchoPort), Handler: echoRouter, TLSConfig: tlsConfig}
 
-	ginServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.GinPort), Handler: ginRouter}
-	echoServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.EchoPort), Handler: echoRouter}
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+	if err != nil {
+		return fmt.Errorf("listen grpc: %w", err)
+	}
 
 	g, gctx := errgroup.WithContext(context.Background())
+	backgroundCtx, stopBackground := context.WithCancel(gctx)
+	defer stopBackground()
+	// The access log outlives the servers so calls made while draining are
+	// still flushed.
+	accessLogCtx, stopAccessLog := context.WithCancel(gctx)
+	defer stopAccessLog()
 	g.Go(func() error {
-		logger.Info("starting gin server", slog.Int("port", cfg.GinPort))
-		if err := ginServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Info("starting gin server", slog.Int("port", cfg.GinPort), slog.Bool("tls", tlsConfig != nil))
+		if err := listenAndServe(ginServer); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			return fmt.Errorf("gin server: %w", err)
 		}
 		return nil
 	})
 	g.Go(func() error {
-		logger.Info("starting echo server", slog.Int("port", cfg.EchoPort))
-		if err := echoServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Info("starting echo server", slog.Int("port", cfg.EchoPort), slog.Bool("tls", tlsConfig != nil))
+		if err := listenAndServe(echoServer); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			return fmt.Errorf("echo server: %w", err)
 		}
 		return nil
 	})
+	g.Go(func() error {
+		logger.Info("starting grpc server", slog.Int("port", cfg.GRPCPort), slog.Bool("tls", tlsConfig != nil))
+		if err := grpcServer.Serve(grpcListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			return fmt.Errorf("grpc server: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		return checkpointer.Run(backgroundCtx)
+	})
+	if accessRecorder != nil {
+		g.Go(func() error {
+			return accessRecorder.Run(accessLogCtx)
+		})
+	}
 	g.Go(func() error {
 		sigCh := make(chan os.Signal, 1)
 		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
@@ -139,6 +330,7 @@ func run() error {
 			return nil
 		case sig := <-sigCh:
 			logger.Info("shutdown signal received", slog.String("signal", sig.String()))
+			stopBackground()
 			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 			if err := ginServer.Shutdown(shutdownCtx); err != nil {
@@ -150,6 +342,8 @@ func run() error {
 			if err := echoRouter.Shutdown(shutdownCtx); err != nil {
 				return fmt.Errorf("shutdown echo router: %w", err)
 			}
+			stopGRPC(shutdownCtx, grpcServer)
+			stopAccessLog()
 			return nil
 		}
 	})
@@ -159,3 +353,23 @@ func run() error {
 	}
 	return nil
 }
+
+func listenAndServe(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
+
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
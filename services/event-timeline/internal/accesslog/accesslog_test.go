@@ -0,0 +1,172 @@
+package accesslog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+)
+
+type memorySink struct {
+	mu      sync.Mutex
+	batches [][]Entry
+	err     error
+}
+
+func (s *memorySink) WriteAccessLog(_ context.Context, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, append([]Entry{}, entries...))
+	return nil
+}
+
+func (s *memorySink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, batch := range s.batches {
+		total += len(batch)
+	}
+	return total
+}
+
+func quietLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestRecorderDropsWhenBufferIsFull(t *testing.T) {
+	metrics := observability.NewMetrics()
+	recorder := NewRecorder(Options{Sink: &memorySink{}, BufferSize: 2, Metrics: metrics, Logger: quietLogger()})
+
+	for i := 0; i < 5; i++ {
+		recorder.Record(Entry{Route: "/api/v1/events"})
+	}
+
+	require.Equal(t, float64(3), testutil.ToFloat64(metrics.AccessLogDropped))
+}
+
+func TestRecorderBatchesAndDrainsOnShutdown(t *testing.T) {
+	sink := &memorySink{}
+	recorder := NewRecorder(Options{Sink: sink, BatchSize: 2, FlushInterval: time.Hour, Logger: quietLogger()})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- recorder.Run(ctx) }()
+
+	for i := 0; i < 3; i++ {
+		recorder.Record(Entry{Route: "/api/v1/events"})
+	}
+	require.Eventually(t, func() bool { return sink.count() == 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	require.Equal(t, 3, sink.count())
+	require.Len(t, sink.batches, 2)
+}
+
+func TestRecorderCountsFailedWritesAsDropped(t *testing.T) {
+	metrics := observability.NewMetrics()
+	recorder := NewRecorder(Options{Sink: &memorySink{err: errors.New("unavailable")}, Metrics: metrics, Logger: quietLogger()})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	recorder.Record(Entry{Route: "/api/v1/events"})
+	recorder.Record(Entry{Route: "/api/v1/events"})
+	require.NoError(t, recorder.Run(ctx))
+
+	require.Equal(t, float64(2), testutil.ToFloat64(metrics.AccessLogDropped))
+}
+
+func TestNilRecorderIgnoresEntries(t *testing.T) {
+	var recorder *Recorder
+	require.NotPanics(t, func() { recorder.Record(Entry{}) })
+}
+
+// logStore implements both Ingester and storage.EventStore over one
+// in-memory stream so entries written by the sink can be read back.
+type logStore struct {
+	events []domain.Event
+	tenant string
+}
+
+func (s *logStore) Ingest(ctx context.Context, in ingest.EventInput) (domain.Event, bool, error) {
+	s.tenant = tenant.FromContext(ctx)
+	event := domain.Event{
+		StreamID:       in.StreamID,
+		EventType:      in.EventType,
+		SequenceNumber: int64(len(s.events) + 1),
+		Payload:        in.Payload,
+	}
+	s.events = append(s.events, event)
+	return event, false, nil
+}
+
+func (s *logStore) PutEvent(context.Context, domain.Event) error { return nil }
+func (s *logStore) GetByEventID(context.Context, string) (domain.Event, error) {
+	return domain.Event{}, domain.ErrNotFound
+}
+func (s *logStore) FindByIdempotencyKey(context.Context, string, string) (domain.Event, error) {
+	return domain.Event{}, domain.ErrNotFound
+}
+func (s *logStore) GetLatestSequence(context.Context, string) (int64, error) {
+	return int64(len(s.events)), nil
+}
+func (s *logStore) QueryByStream(_ context.Context, _ string, from int64, direction string, limit int32) ([]domain.Event, int64, bool, error) {
+	if direction != domain.DirectionBackward {
+		return nil, 0, false, errors.New("unexpected direction")
+	}
+	events := make([]domain.Event, 0, limit)
+	for seq := from; seq >= 1 && len(events) < int(limit); seq-- {
+		events = append(events, s.events[seq-1])
+	}
+	return events, from - int64(len(events)), from-int64(len(events)) > 0, nil
+}
+
+func TestStreamSinkRoundTripsThroughReader(t *testing.T) {
+	store := &logStore{}
+	sink := NewStreamSink(store)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for batch := 0; batch < 3; batch++ {
+		entries := []Entry{
+			{Time: base.Add(time.Duration(batch*2) * time.Minute), TenantID: "acme", Subject: "alice", StreamIDs: []string{"orders"}},
+			{Time: base.Add(time.Duration(batch*2+1) * time.Minute), TenantID: "globex", Subject: "bob", EventID: "evt-1"},
+		}
+		require.NoError(t, sink.WriteAccessLog(context.Background(), entries))
+	}
+	require.Equal(t, tenant.System, store.tenant)
+	require.Equal(t, BatchEventType, store.events[0].EventType)
+
+	reader := NewReader(store)
+	page, err := reader.Query(context.Background(), Filter{})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 6)
+	require.Equal(t, base.Add(5*time.Minute), page.Entries[0].Time)
+	require.Zero(t, page.NextBefore)
+
+	page, err = reader.Query(context.Background(), Filter{TenantID: "acme", StreamID: "orders", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	require.Equal(t, int64(2), page.NextBefore)
+
+	page, err = reader.Query(context.Background(), Filter{TenantID: "acme", Before: page.NextBefore})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	require.Equal(t, base, page.Entries[0].Time)
+
+	page, err = reader.Query(context.Background(), Filter{Subject: "bob", Since: base.Add(3 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+}
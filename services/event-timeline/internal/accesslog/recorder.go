@@ -0,0 +1,146 @@
+package accesslog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+)
+
+const (
+	TransportPublic = "public"
+	TransportAdmin  = "admin"
+	TransportGRPC   = "grpc"
+
+	DefaultBufferSize    = 1024
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = time.Second
+	drainTimeout         = 5 * time.Second
+)
+
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Transport string    `json:"transport"`
+	Subject   string    `json:"subject,omitempty"`
+	TenantID  string    `json:"tenant_id"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	StreamIDs []string  `json:"stream_ids,omitempty"`
+	EventID   string    `json:"event_id,omitempty"`
+	// HTTP status for public and admin entries, gRPC code for grpc entries.
+	Status    int    `json:"status"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+type Sink interface {
+	WriteAccessLog(ctx context.Context, entries []Entry) error
+}
+
+type Options struct {
+	Sink          Sink
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+	Metrics       *observability.Metrics
+	Logger        *slog.Logger
+}
+
+type Recorder struct {
+	sink          Sink
+	entries       chan Entry
+	batchSize     int
+	flushInterval time.Duration
+	metrics       *observability.Metrics
+	logger        *slog.Logger
+}
+
+func NewRecorder(opts Options) *Recorder {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultBufferSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return &Recorder{
+		sink:          opts.Sink,
+		entries:       make(chan Entry, opts.BufferSize),
+		batchSize:     opts.BatchSize,
+		flushInterval: opts.FlushInterval,
+		metrics:       opts.Metrics,
+		logger:        opts.Logger,
+	}
+}
+
+// Record never blocks the request path: when the buffer is full the entry
+// is dropped and counted.
+func (r *Recorder) Record(entry Entry) {
+	if r == nil {
+		return
+	}
+	select {
+	case r.entries <- entry:
+	default:
+		r.dropped(1)
+	}
+}
+
+func (r *Recorder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+	batch := make([]Entry, 0, r.batchSize)
+	for {
+		select {
+		case entry := <-r.entries:
+			batch = append(batch, entry)
+			if len(batch) >= r.batchSize {
+				batch = r.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(ctx, batch)
+		case <-ctx.Done():
+			r.drain(batch)
+			return nil
+		}
+	}
+}
+
+func (r *Recorder) drain(batch []Entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	for {
+		select {
+		case entry := <-r.entries:
+			batch = append(batch, entry)
+			if len(batch) >= r.batchSize {
+				batch = r.flush(ctx, batch)
+			}
+		default:
+			r.flush(ctx, batch)
+			return
+		}
+	}
+}
+
+func (r *Recorder) flush(ctx context.Context, batch []Entry) []Entry {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := r.sink.WriteAccessLog(ctx, batch); err != nil {
+		r.logger.Warn("access log write failed", slog.Int("entries", len(batch)), slog.String("error", err.Error()))
+		r.dropped(len(batch))
+	}
+	return batch[:0]
+}
+
+func (r *Recorder) dropped(n int) {
+	if r.metrics != nil {
+		r.metrics.AccessLogDropped.Add(float64(n))
+	}
+}
@@ -0,0 +1,158 @@
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+)
+
+const (
+	StreamID       = "access-log"
+	BatchEventType = "access_log.batch"
+
+	readPageSize    = int32(50)
+	maxScannedPages = 20
+	DefaultLimit    = 100
+	MaxLimit        = 1000
+)
+
+type batchPayload struct {
+	Entries []Entry `json:"entries"`
+}
+
+type Ingester interface {
+	Ingest(ctx context.Context, in ingest.EventInput) (domain.Event, bool, error)
+}
+
+// Each flushed batch becomes one event of the system tenant's access-log
+// stream, so entries inherit the hash chain and cannot be edited in place.
+type StreamSink struct {
+	ingest Ingester
+}
+
+func NewStreamSink(ingester Ingester) *StreamSink {
+	return &StreamSink{ingest: ingester}
+}
+
+func (s *StreamSink) WriteAccessLog(ctx context.Context, entries []Entry) error {
+	payload, err := json.Marshal(batchPayload{Entries: entries})
+	if err != nil {
+		return fmt.Errorf("marshal access log batch: %w", err)
+	}
+	_, _, err = s.ingest.Ingest(tenant.WithTenant(ctx, tenant.System), ingest.EventInput{
+		StreamID:      StreamID,
+		EventType:     BatchEventType,
+		Payload:       payload,
+		OccurredAt:    entries[0].Time,
+		SchemaVersion: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("append access log batch: %w", err)
+	}
+	return nil
+}
+
+type Filter struct {
+	TenantID string
+	Subject  string
+	StreamID string
+	Since    time.Time
+	Before   int64
+	Limit    int
+}
+
+func (f Filter) matches(entry Entry) bool {
+	if f.TenantID != "" && entry.TenantID != f.TenantID {
+		return false
+	}
+	if f.Subject != "" && entry.Subject != f.Subject {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if f.StreamID == "" {
+		return true
+	}
+	for _, streamID := range entry.StreamIDs {
+		if streamID == f.StreamID {
+			return true
+		}
+	}
+	return false
+}
+
+type Page struct {
+	Entries []Entry `json:"entries"`
+	// Sequence to pass as before= for the next, older page; 0 when done.
+	NextBefore int64 `json:"next_before"`
+}
+
+type Reader struct {
+	events storage.EventStore
+}
+
+func NewReader(events storage.EventStore) *Reader {
+	return &Reader{events: events}
+}
+
+// Query walks the stream newest first. It stops after maxScannedPages
+// batches even when fewer than Limit entries matched, so a narrow filter
+// over a long log stays cheap; NextBefore continues from there.
+func (r *Reader) Query(ctx context.Context, f Filter) (Page, error) {
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+	ctx = tenant.WithTenant(ctx, tenant.System)
+	from := f.Before - 1
+	if f.Before <= 0 {
+		latest, err := r.events.GetLatestSequence(ctx, StreamID)
+		if err != nil {
+			return Page{}, fmt.Errorf("get latest access log sequence: %w", err)
+		}
+		from = latest
+	}
+	page := Page{Entries: []Entry{}}
+	for pages := 0; from > 0 && pages < maxScannedPages; pages++ {
+		events, _, _, err := r.events.QueryByStream(ctx, StreamID, from, domain.DirectionBackward, readPageSize)
+		if err != nil {
+			return Page{}, fmt.Errorf("read access log: %w", err)
+		}
+		if len(events) == 0 {
+			from = 0
+			break
+		}
+		for _, event := range events {
+			var batch batchPayload
+			if err := json.Unmarshal(event.Payload, &batch); err != nil {
+				return Page{}, fmt.Errorf("decode access log batch %d: %w", event.SequenceNumber, err)
+			}
+			for i := len(batch.Entries) - 1; i >= 0; i-- {
+				if f.matches(batch.Entries[i]) {
+					page.Entries = append(page.Entries, batch.Entries[i])
+				}
+			}
+			from = event.SequenceNumber - 1
+			if len(page.Entries) >= f.Limit {
+				page.NextBefore = event.SequenceNumber
+				return page, nil
+			}
+			if !f.Since.IsZero() && len(batch.Entries) > 0 && batch.Entries[0].Time.Before(f.Since) {
+				return page, nil
+			}
+		}
+	}
+	if from > 0 {
+		page.NextBefore = from + 1
+	}
+	return page, nil
+}
@@ -1,32 +1,69 @@
 package api
 
 import (
+	"log/slog"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/handlers/admin"
+	mw "github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/middleware"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
 )
 
 type EchoDependencies struct {
-	Health  *admin.HealthHandler
-	Ready   *admin.ReadyHandler
-	Replay  *admin.ReplayHandler
-	Streams *admin.StreamsHandler
-	Metrics *admin.MetricsHandler
+	Logger         *slog.Logger
+	TokenValidator *mw.TokenValidator
+	Health         *admin.HealthHandler
+	Ready          *admin.ReadyHandler
+	Replay         *admin.ReplayHandler
+	Streams        *admin.StreamsHandler
+	Metrics        *admin.MetricsHandler
+	Import         *admin.ImportHandler
+	Export         *admin.ExportHandler
+	Verify         *admin.VerifyHandler
+	Checkpoint     *admin.CheckpointHandler
+	DataKey        *admin.DataKeyHandler
+	AccessLog      *admin.AccessLogHandler
+	APIKeys        *admin.APIKeyHandler
+	AccessRecorder *accesslog.Recorder
 }
 
 func NewEchoRouter(deps EchoDependencies) *echo.Echo {
 	e := echo.New()
 	e.Use(middleware.Recover())
 	e.Use(observability.EchoOTelMiddleware("event-timeline-admin"))
+	e.Use(mw.EchoAccessLog(deps.AccessRecorder))
+
+	auth := mw.EchoJWTAuth(deps.TokenValidator)
+	scoped := func(scope string) []echo.MiddlewareFunc {
+		return []echo.MiddlewareFunc{auth, mw.EchoRequireScope(deps.Logger, scope)}
+	}
 
 	adminGroup := e.Group("/admin")
 	adminGroup.GET("/health", deps.Health.GetHealth)
 	adminGroup.GET("/ready", deps.Ready.GetReady)
-	adminGroup.POST("/replay", deps.Replay.TriggerReplay)
-	adminGroup.GET("/streams", deps.Streams.ListStreams)
+	adminGroup.POST("/replay", deps.Replay.TriggerReplay, scoped(authz.ScopeReplayRun)...)
+	adminGroup.GET("/streams", deps.Streams.ListStreams, scoped(authz.ScopeAdmin)...)
+	adminGroup.POST("/streams/backfill-heads", deps.Streams.BackfillHeads, scoped(authz.ScopeAdmin)...)
+	adminGroup.GET("/tenants", deps.Streams.ListTenants, scoped(authz.ScopeAdmin)...)
 	adminGroup.GET("/metrics", deps.Metrics.GetMetrics)
+	adminGroup.POST("/import", deps.Import.ImportEvents, scoped(authz.ScopeAdmin)...)
+	// Exports carry the stored events unchanged, so only callers that may
+	// read every field in plaintext can take them.
+	exportScopes := append(scoped(authz.ScopeAdmin), mw.EchoRequireScope(deps.Logger, authz.ScopeDecrypt), mw.EchoRequireScope(deps.Logger, authz.ScopePIIRead))
+	adminGroup.GET("/streams/:id/export", deps.Export.ExportStream, exportScopes...)
+	adminGroup.POST("/export", deps.Export.ExportStreams, exportScopes...)
+	adminGroup.GET("/streams/:id/verify", deps.Verify.VerifyStream, scoped(authz.ScopeAdmin)...)
+	adminGroup.POST("/streams/:id/checkpoints", deps.Checkpoint.CreateCheckpoint, scoped(authz.ScopeAdmin)...)
+	adminGroup.DELETE("/streams/:id/data-key", deps.DataKey.ShredDataKey, scoped(authz.ScopeAdmin)...)
+	adminGroup.POST("/api-keys", deps.APIKeys.CreateAPIKey, scoped(authz.ScopeAdmin)...)
+	adminGroup.GET("/api-keys", deps.APIKeys.ListAPIKeys, scoped(authz.ScopeAdmin)...)
+	adminGroup.DELETE("/api-keys/:id", deps.APIKeys.RevokeAPIKey, scoped(authz.ScopeAdmin)...)
+	adminGroup.POST("/api-keys/:id/rotate", deps.APIKeys.RotateAPIKey, scoped(authz.ScopeAdmin)...)
+	adminGroup.GET("/access-log", deps.AccessLog.QueryAccessLog, scoped(authz.ScopeAdmin)...)
 
 	return e
 }
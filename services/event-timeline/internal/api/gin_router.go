@@ -5,37 +5,50 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/handlers"
 	mw "github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/middleware"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ratelimit"
 )
 
 type GinDependencies struct {
-	Logger      *slog.Logger
-	Metrics     *observability.Metrics
-	JWTSecret   string
-	RatePerSec  float64
-	RateBurst   int
-	Ingest      *handlers.IngestHandler
-	BatchIngest *handlers.BatchIngestHandler
-	Stream      *handlers.StreamHandler
-	Event       *handlers.EventHandler
+	Logger         *slog.Logger
+	Metrics        *observability.Metrics
+	TokenValidator *mw.TokenValidator
+	RateLimiter    *mw.RateLimiter
+	Ingest         *handlers.IngestHandler
+	BatchIngest    *handlers.BatchIngestHandler
+	Stream         *handlers.StreamHandler
+	StreamList     *handlers.StreamListHandler
+	Event          *handlers.EventHandler
+	Proof          *handlers.ProofHandler
+	AccessLog      *accesslog.Recorder
 }
 
 func NewGinRouter(deps GinDependencies) *gin.Engine {
 	r := gin.New()
 	r.Use(mw.RequestID())
+	r.Use(mw.AccessLog(deps.AccessLog))
 	r.Use(mw.Recovery(deps.Logger))
 	r.Use(mw.Logging(deps.Logger, deps.Metrics))
 	r.Use(observability.GinOTelMiddleware("event-timeline-public"))
-	r.Use(mw.RateLimit(deps.RatePerSec, deps.RateBurst))
-	r.Use(mw.JWTAuth(deps.JWTSecret))
+	r.Use(mw.JWTAuth(deps.TokenValidator))
+
+	write := []gin.HandlerFunc{mw.RequireScope(deps.Logger, authz.ScopeEventsWrite), deps.RateLimiter.Routes(ratelimit.RoutesIngest)}
+	read := []gin.HandlerFunc{mw.RequireScope(deps.Logger, authz.ScopeEventsRead), deps.RateLimiter.Routes(ratelimit.RoutesRead)}
+	route := func(guards []gin.HandlerFunc, h ...gin.HandlerFunc) []gin.HandlerFunc {
+		return append(append([]gin.HandlerFunc{}, guards...), h...)
+	}
 
 	v1 := r.Group("/api/v1")
-	v1.POST("/events", deps.Ingest.Ingest)
-	v1.POST("/events/batch", deps.BatchIngest.IngestBatch)
-	v1.GET("/events/:eventId", deps.Event.GetByID)
-	v1.GET("/streams/:streamId/events", deps.Stream.GetByStream)
+	v1.POST("/events", route(write, deps.Ingest.Ingest)...)
+	v1.POST("/events/batch", route(write, deps.BatchIngest.IngestBatch)...)
+	v1.GET("/events/:eventId", route(read, deps.Event.GetByID)...)
+	v1.GET("/events/:eventId/proof", route(read, deps.Proof.GetProof)...)
+	v1.GET("/streams", route(read, deps.StreamList.List)...)
+	v1.GET("/streams/:streamId/events", route(read, mw.RequireStreamParam(deps.Logger, "streamId"), deps.Stream.GetByStream)...)
 
 	return r
 }
@@ -0,0 +1,142 @@
+package grpcapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+)
+
+// The access log interceptor runs before authentication so rejected calls
+// are logged too. accessIdentity, chained after authentication, hands the
+// authenticated context back through accessCall.
+type accessCall struct {
+	mu        sync.Mutex
+	ctx       context.Context
+	streamIDs []string
+	eventID   string
+}
+
+type accessCallKey struct{}
+
+func (a *accessCall) setContext(ctx context.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ctx = ctx
+}
+
+func (a *accessCall) observe(req any) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.streamIDs != nil || a.eventID != "" {
+		return
+	}
+	switch m := req.(type) {
+	case *AppendRequest:
+		a.streamIDs = []string{m.GetStreamId()}
+	case *AppendBatchRequest:
+		seen := map[string]bool{}
+		a.streamIDs = []string{}
+		for _, e := range m.Events {
+			if e != nil && !seen[e.GetStreamId()] {
+				seen[e.GetStreamId()] = true
+				a.streamIDs = append(a.streamIDs, e.GetStreamId())
+			}
+		}
+	case *GetEventRequest:
+		a.eventID = m.GetEventId()
+	case *ReadStreamRequest:
+		a.streamIDs = []string{m.GetStreamId()}
+	case *SubscribeRequest:
+		a.streamIDs = []string{m.GetStreamId()}
+	case *ReplayRequest:
+		a.streamIDs = []string{m.GetStreamId()}
+	}
+}
+
+func (a *accessCall) entry(method string, err error, start time.Time) accesslog.Entry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	principal, _ := authz.FromContext(a.ctx)
+	entry := accesslog.Entry{
+		Time:      start.UTC(),
+		Transport: accesslog.TransportGRPC,
+		Subject:   principal.Subject,
+		TenantID:  tenant.FromContext(a.ctx),
+		Method:    "POST",
+		Route:     method,
+		StreamIDs: a.streamIDs,
+		EventID:   a.eventID,
+		Status:    int(status.Code(err)),
+	}
+	if md, ok := metadata.FromIncomingContext(a.ctx); ok {
+		if values := md.Get("x-request-id"); len(values) > 0 {
+			entry.RequestID = values[0]
+		}
+	}
+	return entry
+}
+
+func UnaryAccessLog(recorder *accesslog.Recorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if recorder == nil {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		call := &accessCall{ctx: ctx}
+		call.observe(req)
+		resp, err := handler(context.WithValue(ctx, accessCallKey{}, call), req)
+		recorder.Record(call.entry(info.FullMethod, err, start))
+		return resp, err
+	}
+}
+
+func StreamAccessLog(recorder *accesslog.Recorder) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if recorder == nil {
+			return handler(srv, ss)
+		}
+		start := time.Now()
+		call := &accessCall{ctx: ss.Context()}
+		err := handler(srv, &accessStream{
+			contextStream: contextStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), accessCallKey{}, call)},
+			call:          call,
+		})
+		recorder.Record(call.entry(info.FullMethod, err, start))
+		return err
+	}
+}
+
+type accessStream struct {
+	contextStream
+	call *accessCall
+}
+
+func (s *accessStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.call.observe(m)
+	}
+	return err
+}
+
+func unaryAccessIdentity(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if call, ok := ctx.Value(accessCallKey{}).(*accessCall); ok {
+		call.setContext(ctx)
+	}
+	return handler(ctx, req)
+}
+
+func streamAccessIdentity(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if call, ok := ss.Context().Value(accessCallKey{}).(*accessCall); ok {
+		call.setContext(ss.Context())
+	}
+	return handler(srv, ss)
+}
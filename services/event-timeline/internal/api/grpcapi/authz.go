@@ -0,0 +1,34 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+)
+
+func (s *Server) authorize(ctx context.Context, method, scope string, streamIDs ...string) error {
+	principal, ok := authz.FromContext(ctx)
+	if !ok || !principal.HasScope(scope) {
+		s.logger.Warn("authorization denied",
+			slog.String("subject", principal.Subject),
+			slog.String("scope", scope),
+			slog.String("method", method),
+		)
+		return status.Errorf(codes.PermissionDenied, "insufficient_scope: token lacks required scope %s", scope)
+	}
+	for _, streamID := range streamIDs {
+		if !principal.CanAccessStream(streamID) {
+			s.logger.Warn("authorization denied",
+				slog.String("subject", principal.Subject),
+				slog.String("stream_id", streamID),
+				slog.String("method", method),
+			)
+			return status.Errorf(codes.PermissionDenied, "stream_forbidden: token is not granted access to stream %s", streamID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+)
+
+// ServiceName is the fully qualified name of the EventTimeline service.
+const ServiceName = "aevum.eventtimeline.v1.EventTimeline"
+
+func EventFromDomain(e domain.Event) *Event {
+	return &Event{
+		EventId:        e.EventID,
+		StreamId:       e.StreamID,
+		SequenceNumber: e.SequenceNumber,
+		EventType:      e.EventType,
+		Payload:        e.Payload,
+		Metadata:       e.Metadata,
+		IdempotencyKey: e.IdempotencyKey,
+		OccurredAt:     timestamp(e.OccurredAt),
+		IngestedAt:     timestamp(e.IngestedAt),
+		SchemaVersion:  int32(e.SchemaVersion),
+		PrevHash:       e.PrevHash,
+		Hash:           e.Hash,
+	}
+}
+
+func appendInput(m *AppendRequest) ingest.EventInput {
+	return ingest.EventInput{
+		StreamID:       m.GetStreamId(),
+		EventType:      m.GetEventType(),
+		Payload:        json.RawMessage(m.GetPayload()),
+		Metadata:       m.GetMetadata(),
+		IdempotencyKey: m.GetIdempotencyKey(),
+		OccurredAt:     asTime(m.GetOccurredAt()),
+		SchemaVersion:  int(m.GetSchemaVersion()),
+	}
+}
+
+func replayRequest(m *ReplayRequest) domain.ReplayRequest {
+	return domain.ReplayRequest{
+		StreamID:   m.GetStreamId(),
+		From:       asTime(m.GetFrom()),
+		To:         asTime(m.GetTo()),
+		EventTypes: m.GetEventTypes(),
+		PageSize:   int(m.GetPageSize()),
+	}
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// asTime keeps unset timestamps as the zero time.Time, which the ingest and
+// replay layers treat as "not provided".
+func asTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
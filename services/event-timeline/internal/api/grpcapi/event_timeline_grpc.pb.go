@@ -0,0 +1,327 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: eventtimeline/v1/event_timeline.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EventTimeline_Append_FullMethodName      = "/aevum.eventtimeline.v1.EventTimeline/Append"
+	EventTimeline_AppendBatch_FullMethodName = "/aevum.eventtimeline.v1.EventTimeline/AppendBatch"
+	EventTimeline_GetEvent_FullMethodName    = "/aevum.eventtimeline.v1.EventTimeline/GetEvent"
+	EventTimeline_ReadStream_FullMethodName  = "/aevum.eventtimeline.v1.EventTimeline/ReadStream"
+	EventTimeline_Subscribe_FullMethodName   = "/aevum.eventtimeline.v1.EventTimeline/Subscribe"
+	EventTimeline_Replay_FullMethodName      = "/aevum.eventtimeline.v1.EventTimeline/Replay"
+)
+
+// EventTimelineClient is the client API for EventTimeline service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EventTimeline mirrors the public Gin API. Calls must carry an
+// "authorization: Bearer <jwt>" metadata entry validated like the HTTP API.
+type EventTimelineClient interface {
+	Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error)
+	AppendBatch(ctx context.Context, in *AppendBatchRequest, opts ...grpc.CallOption) (*AppendBatchResponse, error)
+	GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*GetEventResponse, error)
+	ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	Replay(ctx context.Context, in *ReplayRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type eventTimelineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventTimelineClient(cc grpc.ClientConnInterface) EventTimelineClient {
+	return &eventTimelineClient{cc}
+}
+
+func (c *eventTimelineClient) Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AppendResponse)
+	err := c.cc.Invoke(ctx, EventTimeline_Append_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventTimelineClient) AppendBatch(ctx context.Context, in *AppendBatchRequest, opts ...grpc.CallOption) (*AppendBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AppendBatchResponse)
+	err := c.cc.Invoke(ctx, EventTimeline_AppendBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventTimelineClient) GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*GetEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEventResponse)
+	err := c.cc.Invoke(ctx, EventTimeline_GetEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventTimelineClient) ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventTimeline_ServiceDesc.Streams[0], EventTimeline_ReadStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReadStreamRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventTimeline_ReadStreamClient = grpc.ServerStreamingClient[Event]
+
+func (c *eventTimelineClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventTimeline_ServiceDesc.Streams[1], EventTimeline_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventTimeline_SubscribeClient = grpc.ServerStreamingClient[Event]
+
+func (c *eventTimelineClient) Replay(ctx context.Context, in *ReplayRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventTimeline_ServiceDesc.Streams[2], EventTimeline_Replay_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReplayRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventTimeline_ReplayClient = grpc.ServerStreamingClient[Event]
+
+// EventTimelineServer is the server API for EventTimeline service.
+// All implementations must embed UnimplementedEventTimelineServer
+// for forward compatibility.
+//
+// EventTimeline mirrors the public Gin API. Calls must carry an
+// "authorization: Bearer <jwt>" metadata entry validated like the HTTP API.
+type EventTimelineServer interface {
+	Append(context.Context, *AppendRequest) (*AppendResponse, error)
+	AppendBatch(context.Context, *AppendBatchRequest) (*AppendBatchResponse, error)
+	GetEvent(context.Context, *GetEventRequest) (*GetEventResponse, error)
+	ReadStream(*ReadStreamRequest, grpc.ServerStreamingServer[Event]) error
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Event]) error
+	Replay(*ReplayRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedEventTimelineServer()
+}
+
+// UnimplementedEventTimelineServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEventTimelineServer struct{}
+
+func (UnimplementedEventTimelineServer) Append(context.Context, *AppendRequest) (*AppendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Append not implemented")
+}
+func (UnimplementedEventTimelineServer) AppendBatch(context.Context, *AppendBatchRequest) (*AppendBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppendBatch not implemented")
+}
+func (UnimplementedEventTimelineServer) GetEvent(context.Context, *GetEventRequest) (*GetEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvent not implemented")
+}
+func (UnimplementedEventTimelineServer) ReadStream(*ReadStreamRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method ReadStream not implemented")
+}
+func (UnimplementedEventTimelineServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedEventTimelineServer) Replay(*ReplayRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method Replay not implemented")
+}
+func (UnimplementedEventTimelineServer) mustEmbedUnimplementedEventTimelineServer() {}
+func (UnimplementedEventTimelineServer) testEmbeddedByValue()                       {}
+
+// UnsafeEventTimelineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventTimelineServer will
+// result in compilation errors.
+type UnsafeEventTimelineServer interface {
+	mustEmbedUnimplementedEventTimelineServer()
+}
+
+func RegisterEventTimelineServer(s grpc.ServiceRegistrar, srv EventTimelineServer) {
+	// If the following call pancis, it indicates UnimplementedEventTimelineServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EventTimeline_ServiceDesc, srv)
+}
+
+func _EventTimeline_Append_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventTimelineServer).Append(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventTimeline_Append_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventTimelineServer).Append(ctx, req.(*AppendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventTimeline_AppendBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventTimelineServer).AppendBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventTimeline_AppendBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventTimelineServer).AppendBatch(ctx, req.(*AppendBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventTimeline_GetEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventTimelineServer).GetEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventTimeline_GetEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventTimelineServer).GetEvent(ctx, req.(*GetEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventTimeline_ReadStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventTimelineServer).ReadStream(m, &grpc.GenericServerStream[ReadStreamRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventTimeline_ReadStreamServer = grpc.ServerStreamingServer[Event]
+
+func _EventTimeline_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventTimelineServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventTimeline_SubscribeServer = grpc.ServerStreamingServer[Event]
+
+func _EventTimeline_Replay_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReplayRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventTimelineServer).Replay(m, &grpc.GenericServerStream[ReplayRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventTimeline_ReplayServer = grpc.ServerStreamingServer[Event]
+
+// EventTimeline_ServiceDesc is the grpc.ServiceDesc for EventTimeline service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventTimeline_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aevum.eventtimeline.v1.EventTimeline",
+	HandlerType: (*EventTimelineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Append",
+			Handler:    _EventTimeline_Append_Handler,
+		},
+		{
+			MethodName: "AppendBatch",
+			Handler:    _EventTimeline_AppendBatch_Handler,
+		},
+		{
+			MethodName: "GetEvent",
+			Handler:    _EventTimeline_GetEvent_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadStream",
+			Handler:       _EventTimeline_ReadStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _EventTimeline_Subscribe_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Replay",
+			Handler:       _EventTimeline_Replay_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "eventtimeline/v1/event_timeline.proto",
+}
@@ -0,0 +1,139 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	mw "github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/middleware"
+)
+
+type claimsContextKey struct{}
+
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(jwt.MapClaims)
+	return claims, ok
+}
+
+func authenticate(ctx context.Context, validator *mw.TokenValidator) (context.Context, error) {
+	header := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+	if header == "" {
+		if p, ok := peer.FromContext(ctx); ok {
+			if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+				if ctx, ok := validator.AuthenticateCertificate(ctx, &info.State); ok {
+					return ctx, nil
+				}
+			}
+		}
+	}
+	tokenStr, err := mw.ParseBearerToken(header)
+	if err != nil {
+		return nil, unauthenticated(err)
+	}
+	ctx, claims, err := validator.Authenticate(ctx, tokenStr)
+	if err != nil {
+		return nil, unauthenticated(err)
+	}
+	return context.WithValue(ctx, claimsContextKey{}, claims), nil
+}
+
+func unauthenticated(err error) error {
+	var authErr *mw.AuthError
+	if errors.As(err, &authErr) {
+		return status.Errorf(codes.Unauthenticated, "%s: %s", authErr.Code, authErr.Message)
+	}
+	return status.Error(codes.Unauthenticated, "invalid token")
+}
+
+func UnaryJWTAuth(validator *mw.TokenValidator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, validator)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamJWTAuth(validator *mw.TokenValidator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), validator)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func UnaryLogging(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(logger, info.FullMethod, err, start)
+		return resp, err
+	}
+}
+
+func StreamLogging(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(logger, info.FullMethod, err, start)
+		return err
+	}
+}
+
+func logCall(logger *slog.Logger, method string, err error, start time.Time) {
+	logger.Info("grpc request",
+		slog.String("method", method),
+		slog.String("code", status.Code(err).String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+func UnaryRecovery(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logger.Error("panic recovered", slog.Any("error", recovered), slog.String("method", info.FullMethod))
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func StreamRecovery(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logger.Error("panic recovered", slog.Any("error", recovered), slog.String("method", info.FullMethod))
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,258 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
+	mw "github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/middleware"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/replay"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+)
+
+const (
+	readPageSize        = int32(200)
+	defaultPollInterval = 500 * time.Millisecond
+)
+
+type Dependencies struct {
+	Logger         *slog.Logger
+	TokenValidator *mw.TokenValidator
+	Ingest         *ingest.Service
+	EventStore     storage.EventStore
+	Replay         *replay.Engine
+	PollInterval   time.Duration
+	TLSConfig      *tls.Config
+	AccessLog      *accesslog.Recorder
+}
+
+type Server struct {
+	UnimplementedEventTimelineServer
+
+	logger       *slog.Logger
+	ingest       *ingest.Service
+	eventStore   storage.EventStore
+	replay       *replay.Engine
+	pollInterval time.Duration
+}
+
+func NewServer(deps Dependencies) *grpc.Server {
+	pollInterval := deps.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(observability.GRPCOTelHandler()),
+		grpc.ChainUnaryInterceptor(UnaryRecovery(deps.Logger), UnaryLogging(deps.Logger), UnaryAccessLog(deps.AccessLog), UnaryJWTAuth(deps.TokenValidator), unaryAccessIdentity),
+		grpc.ChainStreamInterceptor(StreamRecovery(deps.Logger), StreamLogging(deps.Logger), StreamAccessLog(deps.AccessLog), StreamJWTAuth(deps.TokenValidator), streamAccessIdentity),
+	}
+	if deps.TLSConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(deps.TLSConfig)))
+	}
+	srv := grpc.NewServer(opts...)
+	RegisterEventTimelineServer(srv, &Server{
+		logger:       deps.Logger,
+		ingest:       deps.Ingest,
+		eventStore:   deps.EventStore,
+		replay:       deps.Replay,
+		pollInterval: pollInterval,
+	})
+	return srv
+}
+
+func (s *Server) Append(ctx context.Context, req *AppendRequest) (*AppendResponse, error) {
+	if err := s.authorize(ctx, "Append", authz.ScopeEventsWrite, req.StreamId); err != nil {
+		return nil, err
+	}
+	event, created, err := s.ingest.Ingest(ctx, appendInput(req))
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, domain.ErrKeyShredded) {
+			return nil, status.Error(codes.FailedPrecondition, "stream data key was shredded")
+		}
+		s.logger.Error("grpc ingest failed", slog.String("error", err.Error()))
+		return nil, status.Error(codes.Internal, "failed to ingest event")
+	}
+	return &AppendResponse{Event: EventFromDomain(event), Created: created}, nil
+}
+
+func (s *Server) AppendBatch(ctx context.Context, req *AppendBatchRequest) (*AppendBatchResponse, error) {
+	if len(req.Events) == 0 || len(req.Events) > ingest.MaxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch size must be between 1 and %d", ingest.MaxBatchSize)
+	}
+	inputs := make([]ingest.EventInput, 0, len(req.Events))
+	streamIDs := make([]string, 0, len(req.Events))
+	for _, e := range req.Events {
+		inputs = append(inputs, appendInput(e))
+		streamIDs = append(streamIDs, e.GetStreamId())
+	}
+	if err := s.authorize(ctx, "AppendBatch", authz.ScopeEventsWrite, streamIDs...); err != nil {
+		return nil, err
+	}
+	results := s.ingest.BatchIngest(ctx, inputs)
+	resp := &AppendBatchResponse{Results: make([]*BatchResult, 0, len(results))}
+	for _, r := range results {
+		result := &BatchResult{Status: r.Status, Error: r.Error, Created: r.Created}
+		if r.Event.EventID != "" {
+			result.Event = EventFromDomain(r.Event)
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+func (s *Server) GetEvent(ctx context.Context, req *GetEventRequest) (*GetEventResponse, error) {
+	if req.EventId == "" {
+		return nil, status.Error(codes.InvalidArgument, "event_id is required")
+	}
+	if err := s.authorize(ctx, "GetEvent", authz.ScopeEventsRead); err != nil {
+		return nil, err
+	}
+	event, err := s.eventStore.GetByEventID(ctx, req.EventId)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "event not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to fetch event")
+	}
+	if err := s.authorize(ctx, "GetEvent", authz.ScopeEventsRead, event.StreamID); err != nil {
+		return nil, err
+	}
+	return &GetEventResponse{Event: EventFromDomain(event)}, nil
+}
+
+func (s *Server) ReadStream(req *ReadStreamRequest, stream grpc.ServerStreamingServer[Event]) error {
+	ctx := stream.Context()
+	if req.StreamId == "" {
+		return status.Error(codes.InvalidArgument, "stream_id is required")
+	}
+	if err := s.authorize(ctx, "ReadStream", authz.ScopeEventsRead, req.StreamId); err != nil {
+		return err
+	}
+	direction := req.Direction
+	if direction == "" {
+		direction = domain.DirectionForward
+	}
+	if direction != domain.DirectionForward && direction != domain.DirectionBackward {
+		return status.Error(codes.InvalidArgument, "direction must be forward or backward")
+	}
+	if req.Limit < 0 {
+		return status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	sequence := req.FromSequence
+	if sequence <= 0 {
+		sequence = 1
+		if direction == domain.DirectionBackward {
+			latest, err := s.eventStore.GetLatestSequence(ctx, req.StreamId)
+			if err != nil {
+				return status.Error(codes.Internal, "failed to query stream events")
+			}
+			if latest > 0 {
+				sequence = latest
+			}
+		}
+	}
+
+	sent := int32(0)
+	for {
+		pageSize := readPageSize
+		if req.Limit > 0 && req.Limit-sent < pageSize {
+			pageSize = req.Limit - sent
+		}
+		events, nextSeq, hasMore, err := s.eventStore.QueryByStream(ctx, req.StreamId, sequence, direction, pageSize)
+		if err != nil {
+			return status.Error(codes.Internal, "failed to query stream events")
+		}
+		for _, event := range events {
+			if err := stream.Send(EventFromDomain(event)); err != nil {
+				return err
+			}
+			sent++
+		}
+		if !hasMore || len(events) == 0 || (req.Limit > 0 && sent >= req.Limit) || nextSeq < 1 {
+			return nil
+		}
+		sequence = nextSeq
+	}
+}
+
+func (s *Server) Subscribe(req *SubscribeRequest, stream grpc.ServerStreamingServer[Event]) error {
+	ctx := stream.Context()
+	if req.StreamId == "" {
+		return status.Error(codes.InvalidArgument, "stream_id is required")
+	}
+	if err := s.authorize(ctx, "Subscribe", authz.ScopeEventsRead, req.StreamId); err != nil {
+		return err
+	}
+	sequence := req.FromSequence
+	if sequence <= 0 {
+		latest, err := s.eventStore.GetLatestSequence(ctx, req.StreamId)
+		if err != nil {
+			return status.Error(codes.Internal, "failed to query stream events")
+		}
+		sequence = latest + 1
+	}
+
+	for {
+		events, nextSeq, _, err := s.eventStore.QueryByStream(ctx, req.StreamId, sequence, domain.DirectionForward, readPageSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return status.Error(codes.Internal, "failed to query stream events")
+		}
+		for _, event := range events {
+			if err := stream.Send(EventFromDomain(event)); err != nil {
+				return err
+			}
+		}
+		if len(events) > 0 {
+			sequence = nextSeq
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+func (s *Server) Replay(req *ReplayRequest, stream grpc.ServerStreamingServer[Event]) error {
+	if req.StreamId == "" {
+		return status.Error(codes.InvalidArgument, "stream_id is required")
+	}
+	if err := s.authorize(stream.Context(), "Replay", authz.ScopeReplayRun, req.StreamId); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	eventsCh, errCh := s.replay.Replay(ctx, replayRequest(req))
+	for event := range eventsCh {
+		if err := stream.Send(EventFromDomain(event)); err != nil {
+			cancel()
+			for range eventsCh {
+			}
+			return err
+		}
+	}
+	if err := <-errCh; err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
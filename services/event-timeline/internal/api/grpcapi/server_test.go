@@ -0,0 +1,377 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
+	mw "github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/middleware"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/replay"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+const testSecret = "secret"
+
+type memoryStore struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (s *memoryStore) PutEvent(_ context.Context, event domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.events {
+		if existing.StreamID == event.StreamID && existing.SequenceNumber == event.SequenceNumber {
+			return domain.ErrSequenceConflict
+		}
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memoryStore) GetByEventID(_ context.Context, eventID string) (domain.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range s.events {
+		if event.EventID == eventID {
+			return event, nil
+		}
+	}
+	return domain.Event{}, domain.ErrNotFound
+}
+
+func (s *memoryStore) FindByIdempotencyKey(_ context.Context, streamID, key string) (domain.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range s.events {
+		if event.StreamID == streamID && event.IdempotencyKey == key {
+			return event, nil
+		}
+	}
+	return domain.Event{}, domain.ErrNotFound
+}
+
+func (s *memoryStore) GetLatestSequence(_ context.Context, streamID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	latest := int64(0)
+	for _, event := range s.events {
+		if event.StreamID == streamID && event.SequenceNumber > latest {
+			latest = event.SequenceNumber
+		}
+	}
+	return latest, nil
+}
+
+func (s *memoryStore) QueryByStream(_ context.Context, streamID string, fromSequence int64, _ string, limit int32) ([]domain.Event, int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	filtered := make([]domain.Event, 0)
+	for _, event := range s.events {
+		if event.StreamID == streamID && event.SequenceNumber >= fromSequence {
+			filtered = append(filtered, event)
+		}
+	}
+	hasMore := int32(len(filtered)) > limit
+	if hasMore {
+		filtered = filtered[:limit]
+	}
+	next := fromSequence
+	if len(filtered) > 0 {
+		next = filtered[len(filtered)-1].SequenceNumber + 1
+	}
+	return filtered, next, hasMore, nil
+}
+
+type sequentialIDs struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (g *sequentialIDs) New(time.Time) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.n++
+	return fmt.Sprintf("evt-%d", g.n), nil
+}
+
+func startServer(t *testing.T, store *memoryStore) EventTimelineClient {
+	t.Helper()
+	return startServerWithAccessLog(t, store, nil)
+}
+
+func startServerWithAccessLog(t *testing.T, store *memoryStore, recorder *accesslog.Recorder) EventTimelineClient {
+	t.Helper()
+	metrics := observability.NewMetrics()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := NewServer(Dependencies{
+		Logger:         logger,
+		TokenValidator: mw.NewTokenValidator(mw.TokenValidatorOptions{HMACSecret: testSecret}),
+		Ingest:         ingest.NewService(store, &sequentialIDs{}, clock.MockClock{Current: time.Now().UTC()}, metrics),
+		EventStore:     store,
+		Replay:         replay.NewEngine(store, metrics),
+		PollInterval:   5 * time.Millisecond,
+		AccessLog:      recorder,
+	})
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewEventTimelineClient(conn)
+}
+
+func authContext(t *testing.T) context.Context {
+	t.Helper()
+	return authContextWithClaims(t, jwt.MapClaims{"scope": "events:read events:write replay:run", "streams": "*"})
+}
+
+func authContextWithClaims(t *testing.T, extra jwt.MapClaims) context.Context {
+	t.Helper()
+	claims := jwt.MapClaims{
+		"iss": "aevum",
+		"sub": "producer-1",
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(5 * time.Minute).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(testSecret))
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+signed)
+}
+
+func appendEvent(t *testing.T, ctx context.Context, client EventTimelineClient, streamID, key string) *AppendResponse {
+	t.Helper()
+	resp, err := client.Append(ctx, &AppendRequest{
+		StreamId:       streamID,
+		EventType:      "created",
+		Payload:        []byte(`{"amount":10}`),
+		Metadata:       map[string]string{"source": "grpc"},
+		IdempotencyKey: key,
+		OccurredAt:     timestamppb.New(time.Date(2026, 2, 14, 10, 0, 0, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestAppendAndGetEvent(t *testing.T) {
+	client := startServer(t, &memoryStore{})
+	ctx := authContext(t)
+
+	created := appendEvent(t, ctx, client, "account-1", "idem-1")
+	require.True(t, created.Created)
+	require.Equal(t, int64(1), created.Event.SequenceNumber)
+	require.Equal(t, "grpc", created.Event.Metadata["source"])
+	require.Len(t, created.Event.Hash, 64)
+
+	duplicate := appendEvent(t, ctx, client, "account-1", "idem-1")
+	require.False(t, duplicate.Created)
+	require.Equal(t, created.Event.EventId, duplicate.Event.EventId)
+
+	got, err := client.GetEvent(ctx, &GetEventRequest{EventId: created.Event.EventId})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":10}`, string(got.Event.Payload))
+	require.True(t, got.Event.OccurredAt.AsTime().Equal(time.Date(2026, 2, 14, 10, 0, 0, 0, time.UTC)))
+
+	_, err = client.GetEvent(ctx, &GetEventRequest{EventId: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestRejectsMissingToken(t *testing.T) {
+	client := startServer(t, &memoryStore{})
+
+	_, err := client.GetEvent(context.Background(), &GetEventRequest{EventId: "evt-1"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := client.ReadStream(context.Background(), &ReadStreamRequest{StreamId: "account-1"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestEnforcesScopesAndStreamGrants(t *testing.T) {
+	client := startServer(t, &memoryStore{})
+	created := appendEvent(t, authContext(t), client, "account-1", "idem-1")
+
+	readOnly := authContextWithClaims(t, jwt.MapClaims{"scope": "events:read", "streams": []string{"account-*"}})
+	_, err := client.Append(readOnly, &AppendRequest{StreamId: "account-1", EventType: "created"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.GetEvent(readOnly, &GetEventRequest{EventId: created.Event.EventId})
+	require.NoError(t, err)
+
+	otherStreams := authContextWithClaims(t, jwt.MapClaims{"scope": "events:read", "streams": []string{"orders-*"}})
+	_, err = client.GetEvent(otherStreams, &GetEventRequest{EventId: created.Event.EventId})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	stream, err := client.ReadStream(otherStreams, &ReadStreamRequest{StreamId: "account-1"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAppendBatchValidatesSize(t *testing.T) {
+	client := startServer(t, &memoryStore{})
+	ctx := authContext(t)
+
+	_, err := client.AppendBatch(ctx, &AppendBatchRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	resp, err := client.AppendBatch(ctx, &AppendBatchRequest{Events: []*AppendRequest{
+		{StreamId: "account-1", EventType: "created", Payload: []byte(`{}`), OccurredAt: timestamppb.Now()},
+		{StreamId: "account-1", EventType: "created"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	require.Equal(t, "created", resp.Results[0].Status)
+	require.Equal(t, "invalid", resp.Results[1].Status)
+	require.Nil(t, resp.Results[1].Event)
+}
+
+func readAll(stream grpc.ServerStreamingClient[Event], err error) ([]*Event, error) {
+	if err != nil {
+		return nil, err
+	}
+	var events []*Event
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+}
+
+func TestReadStreamAndReplay(t *testing.T) {
+	store := &memoryStore{}
+	client := startServer(t, store)
+	ctx := authContext(t)
+	for _, key := range []string{"k1", "k2", "k3"} {
+		appendEvent(t, ctx, client, "account-1", key)
+	}
+
+	events, err := readAll(client.ReadStream(ctx, &ReadStreamRequest{StreamId: "account-1"}))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, int64(3), events[2].SequenceNumber)
+
+	limited, err := readAll(client.ReadStream(ctx, &ReadStreamRequest{StreamId: "account-1", FromSequence: 2, Limit: 1}))
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	require.Equal(t, int64(2), limited[0].SequenceNumber)
+
+	replayed, err := readAll(client.Replay(ctx, &ReplayRequest{StreamId: "account-1", EventTypes: []string{"created"}}))
+	require.NoError(t, err)
+	require.Len(t, replayed, 3)
+}
+
+func TestSubscribeDeliversNewEvents(t *testing.T) {
+	store := &memoryStore{}
+	client := startServer(t, store)
+	ctx, cancel := context.WithTimeout(authContext(t), 5*time.Second)
+	defer cancel()
+	appendEvent(t, ctx, client, "account-1", "before")
+
+	stream, err := client.Subscribe(ctx, &SubscribeRequest{StreamId: "account-1"})
+	require.NoError(t, err)
+
+	appended := make(chan error, 1)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, err := client.Append(ctx, &AppendRequest{
+			StreamId:       "account-1",
+			EventType:      "created",
+			Payload:        []byte(`{}`),
+			IdempotencyKey: "after",
+			OccurredAt:     timestamppb.Now(),
+		})
+		appended <- err
+	}()
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.NoError(t, <-appended)
+	require.Equal(t, int64(2), event.SequenceNumber)
+	require.Equal(t, "after", event.IdempotencyKey)
+}
+
+type accessSink struct {
+	mu      sync.Mutex
+	entries []accesslog.Entry
+}
+
+func (s *accessSink) WriteAccessLog(_ context.Context, entries []accesslog.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func (s *accessSink) snapshot() []accesslog.Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]accesslog.Entry{}, s.entries...)
+}
+
+func TestAccessLogRecordsCalls(t *testing.T) {
+	sink := &accessSink{}
+	recorder := accesslog.NewRecorder(accesslog.Options{Sink: sink, FlushInterval: 5 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = recorder.Run(ctx) }()
+	client := startServerWithAccessLog(t, &memoryStore{}, recorder)
+
+	appendEvent(t, authContext(t), client, "account-1", "idem-1")
+	_, err := client.GetEvent(context.Background(), &GetEventRequest{EventId: "evt-1"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = readAll(client.ReadStream(authContext(t), &ReadStreamRequest{StreamId: "account-1"}))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(sink.snapshot()) == 3 }, time.Second, 5*time.Millisecond)
+	entries := sink.snapshot()
+
+	require.Equal(t, accesslog.TransportGRPC, entries[0].Transport)
+	require.Equal(t, "/"+ServiceName+"/Append", entries[0].Route)
+	require.Equal(t, "producer-1", entries[0].Subject)
+	require.Equal(t, []string{"account-1"}, entries[0].StreamIDs)
+	require.Equal(t, int(codes.OK), entries[0].Status)
+
+	require.Empty(t, entries[1].Subject)
+	require.Equal(t, "evt-1", entries[1].EventID)
+	require.Equal(t, int(codes.Unauthenticated), entries[1].Status)
+
+	require.Equal(t, "/"+ServiceName+"/ReadStream", entries[2].Route)
+	require.Equal(t, "producer-1", entries[2].Subject)
+	require.Equal(t, []string{"account-1"}, entries[2].StreamIDs)
+}
@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+)
+
+type AccessLogHandler struct {
+	reader *accesslog.Reader
+}
+
+func NewAccessLogHandler(reader *accesslog.Reader) *AccessLogHandler {
+	return &AccessLogHandler{reader: reader}
+}
+
+func (h *AccessLogHandler) QueryAccessLog(c echo.Context) error {
+	filter := accesslog.Filter{
+		TenantID: c.QueryParam("tenant"),
+		Subject:  c.QueryParam("subject"),
+		StreamID: c.QueryParam("stream_id"),
+	}
+	if filter.TenantID != "" {
+		if err := tenant.Validate(filter.TenantID); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+	if raw := c.QueryParam("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "since must be RFC3339"})
+		}
+		filter.Since = since
+	}
+	if raw := c.QueryParam("before"); raw != "" {
+		before, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || before <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "before must be a positive sequence number"})
+		}
+		filter.Before = before
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "limit must be a positive integer"})
+		}
+		filter.Limit = limit
+	}
+	page, err := h.reader.Query(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, page)
+}
@@ -1,21 +1,35 @@
 package admin
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/apikey"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/export"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/replay"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
 )
 
 type adminEventStore struct {
@@ -24,8 +38,7 @@ type adminEventStore struct {
 	events []domain.Event
 }
 
-func (s *adminEventStore) PutEvent(context.Context, domain.Event) error         { return nil }
-func (s *adminEventStore) PutEventsBatch(context.Context, []domain.Event) error { return nil }
+func (s *adminEventStore) PutEvent(context.Context, domain.Event) error { return nil }
 func (s *adminEventStore) GetByEventID(context.Context, string) (domain.Event, error) {
 	return domain.Event{}, domain.ErrNotFound
 }
@@ -46,15 +59,40 @@ func (s *adminEventStore) QueryByStream(_ context.Context, streamID string, from
 }
 
 type adminStreamStore struct {
-	streams []domain.Stream
-	err     error
+	streams    []domain.Stream
+	err        error
+	backfilled bool
 }
 
-func (s *adminStreamStore) ListStreams(context.Context, int32) ([]domain.Stream, error) {
+// ListStreams returns one stream per page; the cursor is the index of the
+// next stream of the tenant.
+func (s *adminStreamStore) ListStreams(ctx context.Context, cursor string, _ int32) (storage.StreamPage, error) {
 	if s.err != nil {
-		return nil, s.err
+		return storage.StreamPage{}, s.err
 	}
-	return s.streams, nil
+	streams := make([]domain.Stream, 0, len(s.streams))
+	for _, stream := range s.streams {
+		if tenant.IsCrossTenant(ctx) || stream.TenantID == tenant.FromContext(ctx) {
+			streams = append(streams, stream)
+		}
+	}
+	start := 0
+	if cursor != "" {
+		start, _ = strconv.Atoi(cursor)
+	}
+	if start >= len(streams) {
+		return storage.StreamPage{Streams: []domain.Stream{}}, nil
+	}
+	page := storage.StreamPage{Streams: streams[start : start+1]}
+	if start+1 < len(streams) {
+		page.NextCursor = strconv.Itoa(start + 1)
+	}
+	return page, nil
+}
+
+func (s *adminStreamStore) BackfillStreamHeads(context.Context) (int, error) {
+	s.backfilled = true
+	return len(s.streams), s.err
 }
 
 func TestHealthHandlerStatuses(t *testing.T) {
@@ -91,7 +129,7 @@ func TestReadyHandler(t *testing.T) {
 
 func TestStreamsHandler(t *testing.T) {
 	e := echo.New()
-	h := NewStreamsHandler(&adminStreamStore{streams: []domain.Stream{{StreamID: "s1", LatestSequence: 2}}})
+	h := NewStreamsHandler(&adminStreamStore{streams: []domain.Stream{{TenantID: tenant.Default, StreamID: "s1", LatestSequence: 2}}})
 	rec := httptest.NewRecorder()
 	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/admin/streams", nil), rec)
 
@@ -108,6 +146,186 @@ func TestStreamsHandler(t *testing.T) {
 	require.Equal(t, http.StatusInternalServerError, recErr.Code)
 }
 
+func TestStreamsHandlerTenantViews(t *testing.T) {
+	e := echo.New()
+	h := NewStreamsHandler(&adminStreamStore{streams: []domain.Stream{
+		{TenantID: tenant.Default, StreamID: "s1", LatestSequence: 2},
+		{TenantID: "acme", StreamID: "s1", LatestSequence: 5},
+		{TenantID: "acme", StreamID: "s2", LatestSequence: 1},
+	}})
+	serve := func(target string, handle func(echo.Context) error) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, handle(e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)))
+		return rec
+	}
+
+	var listed struct {
+		Streams    []domain.Stream `json:"streams"`
+		NextCursor string          `json:"next_cursor"`
+	}
+	rec := serve("/admin/streams?tenant=acme", h.ListStreams)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Equal(t, []domain.Stream{{TenantID: "acme", StreamID: "s1", LatestSequence: 5}}, listed.Streams)
+	require.Equal(t, "1", listed.NextCursor)
+
+	rec = serve("/admin/streams?tenant=acme&cursor=1", h.ListStreams)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Equal(t, []domain.Stream{{TenantID: "acme", StreamID: "s2", LatestSequence: 1}}, listed.Streams)
+	require.Empty(t, listed.NextCursor)
+
+	rec = serve("/admin/streams?tenant=*&cursor=2", h.ListStreams)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Equal(t, "s2", listed.Streams[0].StreamID)
+
+	require.Equal(t, http.StatusBadRequest, serve("/admin/streams?limit=0", h.ListStreams).Code)
+	require.Equal(t, http.StatusBadRequest, serve("/admin/streams?tenant=Bad%20Tenant", h.ListStreams).Code)
+
+	var summary struct {
+		Tenants []TenantSummary `json:"tenants"`
+	}
+	rec = serve("/admin/tenants", h.ListTenants)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	require.Equal(t, []TenantSummary{
+		{TenantID: "acme", StreamCount: 2, EventCount: 6},
+		{TenantID: tenant.Default, StreamCount: 1, EventCount: 2},
+	}, summary.Tenants)
+}
+
+func TestStreamsHandlerBackfillHeads(t *testing.T) {
+	e := echo.New()
+	store := &adminStreamStore{streams: []domain.Stream{{TenantID: tenant.Default, StreamID: "s1", LatestSequence: 2}}}
+	rec := httptest.NewRecorder()
+	require.NoError(t, NewStreamsHandler(store).BackfillHeads(e.NewContext(httptest.NewRequest(http.MethodPost, "/admin/streams/backfill-heads", nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"streams":1}`, rec.Body.String())
+	require.True(t, store.backfilled)
+}
+
+func TestDataKeyHandlerWithoutEncryption(t *testing.T) {
+	e := echo.New()
+	h := NewDataKeyHandler(nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodDelete, "/admin/streams/s1/data-key", nil), rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("s1")
+
+	require.NoError(t, h.ShredDataKey(ctx))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	rec = httptest.NewRecorder()
+	ctx = e.NewContext(httptest.NewRequest(http.MethodDelete, "/admin/streams/s1/data-key?tenant=Not%20Valid", nil), rec)
+	require.NoError(t, h.ShredDataKey(ctx))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAccessLogHandlerValidatesFilter(t *testing.T) {
+	e := echo.New()
+	h := NewAccessLogHandler(accesslog.NewReader(&adminEventStore{}))
+	query := func(rawQuery string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, h.QueryAccessLog(e.NewContext(httptest.NewRequest(http.MethodGet, "/admin/access-log?"+rawQuery, nil), rec)))
+		return rec
+	}
+
+	require.Equal(t, http.StatusBadRequest, query("since=yesterday").Code)
+	require.Equal(t, http.StatusBadRequest, query("before=-1").Code)
+	require.Equal(t, http.StatusBadRequest, query("limit=none").Code)
+	require.Equal(t, http.StatusBadRequest, query("tenant=Not%20Valid").Code)
+
+	rec := query("tenant=acme&since=2026-01-01T00:00:00Z&limit=10")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"entries":[],"next_before":0}`, rec.Body.String())
+}
+
+type adminAPIKeyStore struct {
+	keys map[string]domain.APIKey
+}
+
+func (s *adminAPIKeyStore) CreateAPIKey(_ context.Context, key domain.APIKey) error {
+	s.keys[key.KeyID] = key
+	return nil
+}
+
+func (s *adminAPIKeyStore) GetAPIKey(_ context.Context, keyID string) (domain.APIKey, error) {
+	key, ok := s.keys[keyID]
+	if !ok {
+		return domain.APIKey{}, domain.ErrNotFound
+	}
+	return key, nil
+}
+
+func (s *adminAPIKeyStore) ListAPIKeys(_ context.Context, tenantID string) ([]domain.APIKey, error) {
+	keys := []domain.APIKey{}
+	for _, key := range s.keys {
+		if tenantID == "" || key.TenantID == tenantID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *adminAPIKeyStore) RevokeAPIKey(_ context.Context, keyID string, at time.Time) (domain.APIKey, error) {
+	key := s.keys[keyID]
+	key.RevokedAt = &at
+	s.keys[keyID] = key
+	return key, nil
+}
+
+func (s *adminAPIKeyStore) RotateAPIKey(_ context.Context, rotated domain.APIKey) error {
+	s.keys[rotated.KeyID] = rotated
+	return nil
+}
+
+func (s *adminAPIKeyStore) TouchAPIKey(context.Context, string, time.Time) error { return nil }
+
+func TestAPIKeyHandlerLifecycle(t *testing.T) {
+	e := echo.New()
+	h := NewAPIKeyHandler(apikey.NewService(apikey.Dependencies{Store: &adminAPIKeyStore{keys: map[string]domain.APIKey{}}}))
+	serve := func(method, target, body, id string, handle func(echo.Context) error) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		}
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		if id != "" {
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(id)
+		}
+		require.NoError(t, handle(ctx))
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/admin/api-keys?tenant=acme", `{"name":"producer","scopes":["events:write"],"streams":["orders-*"]}`, "", h.CreateAPIKey)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.NotContains(t, rec.Body.String(), "secret_hash")
+	var created struct {
+		APIKey domain.APIKey `json:"api_key"`
+		Key    string        `json:"key"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	require.Equal(t, "acme", created.APIKey.TenantID)
+	require.True(t, strings.HasPrefix(created.Key, "ak_"+created.APIKey.KeyID+"_"))
+	id := created.APIKey.KeyID
+
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/admin/api-keys", `{"name":"admin","scopes":["admin"]}`, "", h.CreateAPIKey).Code)
+
+	rec = serve(http.MethodGet, "/admin/api-keys?tenant=acme", "", "", h.ListAPIKeys)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), id)
+	rec = serve(http.MethodGet, "/admin/api-keys", "", "", h.ListAPIKeys)
+	require.JSONEq(t, `{"api_keys":[]}`, rec.Body.String())
+
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/admin/api-keys/"+id+"/rotate?tenant=acme", `{"grace_period":"soon"}`, id, h.RotateAPIKey).Code)
+	rec = serve(http.MethodPost, "/admin/api-keys/"+id+"/rotate?tenant=acme", `{"grace_period":"1h"}`, id, h.RotateAPIKey)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "previous_expires_at")
+
+	require.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/admin/api-keys/"+id, "", id, h.RevokeAPIKey).Code)
+	require.Equal(t, http.StatusOK, serve(http.MethodDelete, "/admin/api-keys/"+id+"?tenant=acme", "", id, h.RevokeAPIKey).Code)
+	require.Equal(t, http.StatusConflict, serve(http.MethodPost, "/admin/api-keys/"+id+"/rotate?tenant=acme", "", id, h.RotateAPIKey).Code)
+}
+
 func TestReplayHandler(t *testing.T) {
 	event, err := domain.NewEvent(domain.NewEventInput{
 		EventID:        "evt-admin",
@@ -149,3 +367,100 @@ func TestMetricsHandler(t *testing.T) {
 	require.Equal(t, http.StatusOK, rec.Code)
 	require.Contains(t, rec.Body.String(), "aevum_active_replays")
 }
+
+type importEventStore struct {
+	adminEventStore
+	mu   sync.Mutex
+	puts int
+}
+
+func (s *importEventStore) PutEvent(context.Context, domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.puts++
+	return nil
+}
+
+type importIDGenerator struct{}
+
+func (importIDGenerator) New(time.Time) (string, error) { return "evt-import", nil }
+
+func TestImportHandlerAcceptsGzipNDJSON(t *testing.T) {
+	store := &importEventStore{}
+	service := ingest.NewService(store, importIDGenerator{}, clock.MockClock{Current: time.Now().UTC()}, observability.NewMetrics())
+	h := NewImportHandler(ingest.NewImporter(service, slog.New(slog.NewTextHandler(io.Discard, nil)), ingest.ImportOptions{}))
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(`{"stream_id":"s-1","event_type":"created","payload":{},"idempotency_key":"k-1","occurred_at":"2025-01-01T00:00:00Z"}` + "\n" + `{"stream_id":"s-1"}` + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/admin/import", &buf)
+	req.Header.Set(echo.HeaderContentEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	require.NoError(t, h.ImportEvents(e.NewContext(req, rec)))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Status  string               `json:"status"`
+		Summary ingest.ImportSummary `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, "completed_with_errors", body.Status)
+	require.Equal(t, int64(1), body.Summary.Created)
+	require.Equal(t, int64(1), body.Summary.Failed)
+	require.Equal(t, int64(2), body.Summary.Errors[0].Line)
+	require.Equal(t, 1, store.puts)
+}
+
+func TestImportHandlerRejectsInvalidGzip(t *testing.T) {
+	service := ingest.NewService(&importEventStore{}, importIDGenerator{}, clock.RealClock{}, observability.NewMetrics())
+	h := NewImportHandler(ingest.NewImporter(service, slog.Default(), ingest.ImportOptions{}))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/admin/import", strings.NewReader("plain"))
+	req.Header.Set(echo.HeaderContentType, "application/gzip")
+	rec := httptest.NewRecorder()
+	require.NoError(t, h.ImportEvents(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestExportHandlerStreamsArchive(t *testing.T) {
+	store := &adminEventStore{events: []domain.Event{
+		{EventID: "evt-1", StreamID: "s-1", SequenceNumber: 1, EventType: "created", Payload: json.RawMessage(`{}`)},
+		{EventID: "evt-2", StreamID: "s-1", SequenceNumber: 2, EventType: "updated", Payload: json.RawMessage(`{}`)},
+	}}
+	h := NewExportHandler(export.NewExporter(store, clock.RealClock{}), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/admin/streams/s-1/export", nil), rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("s-1")
+	require.NoError(t, h.ExportStream(ctx))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+	require.Equal(t, "events.ndjson", archive.File[0].Name)
+	require.Equal(t, export.ManifestFile, archive.File[1].Name)
+}
+
+func TestExportHandlerValidatesRequest(t *testing.T) {
+	h := NewExportHandler(export.NewExporter(&adminEventStore{}, clock.RealClock{}), slog.Default())
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/admin/streams/s-1/export?format=csv", nil), rec)
+	require.NoError(t, h.ExportStream(ctx))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/export", strings.NewReader(`{"stream_ids":["a","a"]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	require.NoError(t, h.ExportStreams(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/apikey"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+)
+
+type APIKeyHandler struct {
+	keys *apikey.Service
+}
+
+type rotateRequest struct {
+	GracePeriod string `json:"grace_period"`
+}
+
+func NewAPIKeyHandler(keys *apikey.Service) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	ctx, err := requestedTenant(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	var in apikey.CreateInput
+	if err := c.Bind(&in); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	key, plaintext, err := h.keys.Create(ctx, in)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.JSON(http.StatusCreated, map[string]any{"api_key": key, "key": plaintext})
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+	if c.QueryParam("tenant") == allTenants {
+		ctx = tenant.WithCrossTenant(ctx)
+	} else {
+		var err error
+		if ctx, err = requestedTenant(c); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+	keys, err := h.keys.List(ctx)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"api_keys": keys})
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c echo.Context) error {
+	ctx, err := requestedTenant(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	key, err := h.keys.Revoke(ctx, c.Param("id"))
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"api_key": key})
+}
+
+func (h *APIKeyHandler) RotateAPIKey(c echo.Context) error {
+	ctx, err := requestedTenant(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	var req rotateRequest
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		}
+	}
+	var grace time.Duration
+	if req.GracePeriod != "" {
+		if grace, err = time.ParseDuration(req.GracePeriod); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "grace_period must be a duration such as 1h"})
+		}
+	}
+	key, plaintext, err := h.keys.Rotate(ctx, c.Param("id"), grace)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"api_key": key, "key": plaintext})
+}
+
+func apiKeyError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "api key not found"})
+	case errors.Is(err, apikey.ErrRevoked):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/integrity"
+)
+
+type CheckpointHandler struct {
+	checkpointer *integrity.Checkpointer
+}
+
+func NewCheckpointHandler(checkpointer *integrity.Checkpointer) *CheckpointHandler {
+	return &CheckpointHandler{checkpointer: checkpointer}
+}
+
+func (h *CheckpointHandler) CreateCheckpoint(c echo.Context) error {
+	checkpoint, created, err := h.checkpointer.CheckpointStream(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, integrity.ErrSigningKeyMissing):
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		case errors.Is(err, domain.ErrSequenceConflict):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if !created {
+		return c.JSON(http.StatusOK, map[string]any{"status": "up_to_date"})
+	}
+	return c.JSON(http.StatusCreated, map[string]any{"status": "created", "checkpoint": checkpoint})
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/encryption"
+)
+
+type DataKeyHandler struct {
+	encryption *encryption.Service
+}
+
+func NewDataKeyHandler(service *encryption.Service) *DataKeyHandler {
+	return &DataKeyHandler{encryption: service}
+}
+
+func (h *DataKeyHandler) ShredDataKey(c echo.Context) error {
+	ctx, err := requestedTenant(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	dataKey, err := h.encryption.Shred(ctx, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, encryption.ErrDisabled):
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		case errors.Is(err, domain.ErrNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "stream has no data key"})
+		case errors.Is(err, domain.ErrValidation):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data_key": dataKey})
+}
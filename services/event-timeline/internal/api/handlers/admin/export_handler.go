@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/export"
+)
+
+const maxExportStreams = 100
+
+type ExportHandler struct {
+	exporter *export.Exporter
+	logger   *slog.Logger
+}
+
+type bulkExportRequest struct {
+	StreamIDs []string `json:"stream_ids"`
+	Format    string   `json:"format"`
+}
+
+func NewExportHandler(exporter *export.Exporter, logger *slog.Logger) *ExportHandler {
+	return &ExportHandler{exporter: exporter, logger: logger}
+}
+
+func (h *ExportHandler) ExportStream(c echo.Context) error {
+	streamID := c.Param("id")
+	format := c.QueryParam("format")
+	if format == "" {
+		format = export.FormatNDJSON
+	}
+	if !export.ValidFormat(format) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be ndjson or parquet"})
+	}
+	return h.stream(c, fmt.Sprintf("stream-%s-export.zip", streamID), []string{streamID}, format)
+}
+
+func (h *ExportHandler) ExportStreams(c echo.Context) error {
+	var req bulkExportRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if req.Format == "" {
+		req.Format = export.FormatNDJSON
+	}
+	if !export.ValidFormat(req.Format) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be ndjson or parquet"})
+	}
+	if len(req.StreamIDs) == 0 || len(req.StreamIDs) > maxExportStreams {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("stream_ids must contain between 1 and %d streams", maxExportStreams)})
+	}
+	seen := make(map[string]struct{}, len(req.StreamIDs))
+	for _, id := range req.StreamIDs {
+		if id == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "stream_ids must not contain empty values"})
+		}
+		if _, dup := seen[id]; dup {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "stream_ids must be unique"})
+		}
+		seen[id] = struct{}{}
+	}
+	return h.stream(c, "streams-export.zip", req.StreamIDs, req.Format)
+}
+
+func (h *ExportHandler) stream(c echo.Context, filename string, streamIDs []string, format string) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/zip")
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	res.WriteHeader(http.StatusOK)
+
+	manifest, err := h.exporter.WriteArchive(c.Request().Context(), res, streamIDs, format)
+	if err != nil {
+		h.logger.Error("stream export failed", slog.String("error", err.Error()), slog.Int64("events_written", manifest.EventCount))
+		return nil
+	}
+	h.logger.Info("stream export completed",
+		slog.Int("streams", len(manifest.Streams)),
+		slog.Int64("events", manifest.EventCount),
+		slog.String("sha256", manifest.ContentSHA256),
+	)
+	return nil
+}
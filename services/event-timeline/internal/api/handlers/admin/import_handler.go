@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+)
+
+type ImportHandler struct {
+	importer *ingest.Importer
+}
+
+func NewImportHandler(importer *ingest.Importer) *ImportHandler {
+	return &ImportHandler{importer: importer}
+}
+
+func (h *ImportHandler) ImportEvents(c echo.Context) error {
+	req := c.Request()
+	var body io.Reader = req.Body
+	if isGzip(req) {
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid gzip body: " + err.Error()})
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	summary, err := h.importer.Import(req.Context(), body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]any{"status": "aborted", "error": err.Error(), "summary": summary})
+	}
+	status := "completed"
+	if summary.Failed > 0 {
+		status = "completed_with_errors"
+	}
+	return c.JSON(http.StatusOK, map[string]any{"status": status, "summary": summary})
+}
+
+func isGzip(req *http.Request) bool {
+	if strings.EqualFold(req.Header.Get(echo.HeaderContentEncoding), "gzip") {
+		return true
+	}
+	contentType := strings.ToLower(req.Header.Get(echo.HeaderContentType))
+	return strings.HasPrefix(contentType, "application/gzip") || strings.HasPrefix(contentType, "application/x-gzip")
+}
@@ -1,25 +1,116 @@
 package admin
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"sort"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
 )
 
+const allTenants = "*"
+
 type StreamsHandler struct {
 	streamStore storage.StreamStore
 }
 
+type TenantSummary struct {
+	TenantID    string `json:"tenant_id"`
+	StreamCount int    `json:"stream_count"`
+	EventCount  int64  `json:"event_count"`
+}
+
 func NewStreamsHandler(streamStore storage.StreamStore) *StreamsHandler {
 	return &StreamsHandler{streamStore: streamStore}
 }
 
 func (h *StreamsHandler) ListStreams(c echo.Context) error {
-	streams, err := h.streamStore.ListStreams(c.Request().Context(), 200)
+	ctx := c.Request().Context()
+	if requested := c.QueryParam("tenant"); requested == allTenants {
+		ctx = tenant.WithCrossTenant(ctx)
+	} else {
+		var err error
+		if ctx, err = requestedTenant(c); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+	limit := int32(200)
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || parsed < 1 || parsed > storage.MaxStreamPageSize {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "limit must be an integer between 1 and 1000"})
+		}
+		limit = int32(parsed)
+	}
+	page, err := h.streamStore.ListStreams(ctx, c.QueryParam("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]any{"streams": page.Streams, "next_cursor": page.NextCursor})
+}
+
+// BackfillHeads writes the head items of streams stored before the stream
+// list was built from heads. It is safe to run again.
+func (h *StreamsHandler) BackfillHeads(c echo.Context) error {
+	backfiller, ok := h.streamStore.(storage.StreamHeadBackfiller)
+	if !ok {
+		return c.JSON(http.StatusNotImplemented, map[string]string{"error": "stream store does not support head backfill"})
+	}
+	count, err := backfiller.BackfillStreamHeads(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]any{"streams": count})
+}
+
+func (h *StreamsHandler) ListTenants(c echo.Context) error {
+	tenants, err := h.tenantSummaries(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, map[string]any{"streams": streams})
+	return c.JSON(http.StatusOK, map[string]any{"tenants": tenants})
+}
+
+func (h *StreamsHandler) tenantSummaries(ctx context.Context) ([]TenantSummary, error) {
+	streams, err := storage.ListAllStreams(tenant.WithCrossTenant(ctx), h.streamStore)
+	if err != nil {
+		return nil, err
+	}
+	byTenant := map[string]*TenantSummary{}
+	for _, stream := range streams {
+		summary, ok := byTenant[stream.TenantID]
+		if !ok {
+			summary = &TenantSummary{TenantID: stream.TenantID}
+			byTenant[stream.TenantID] = summary
+		}
+		summary.StreamCount++
+		summary.EventCount += stream.LatestSequence
+	}
+	tenants := make([]TenantSummary, 0, len(byTenant))
+	for _, summary := range byTenant {
+		tenants = append(tenants, *summary)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].TenantID < tenants[j].TenantID })
+	return tenants, nil
+}
+
+func requestedTenant(c echo.Context) (context.Context, error) {
+	ctx := c.Request().Context()
+	requested := c.QueryParam("tenant")
+	if requested == "" {
+		return ctx, nil
+	}
+	if err := tenant.Validate(requested); err != nil {
+		return nil, err
+	}
+	return tenant.WithTenant(ctx, requested), nil
 }
@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/integrity"
+)
+
+type VerifyHandler struct {
+	verifier *integrity.Verifier
+}
+
+func NewVerifyHandler(verifier *integrity.Verifier) *VerifyHandler {
+	return &VerifyHandler{verifier: verifier}
+}
+
+func (h *VerifyHandler) VerifyStream(c echo.Context) error {
+	report, err := h.verifier.VerifyStream(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/httputil"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+)
+
+func authorizeStream(c *gin.Context, streamID string) bool {
+	principal, ok := authz.StreamAllowed(c.Request.Context(), streamID)
+	if ok {
+		return true
+	}
+	slog.Warn("authorization denied",
+		slog.String("subject", principal.Subject),
+		slog.String("stream_id", streamID),
+		slog.String("method", c.Request.Method),
+		slog.String("path", c.FullPath()),
+	)
+	httputil.Forbidden(c, "stream_forbidden", "token is not granted access to stream "+streamID)
+	return false
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -23,10 +24,15 @@ func (h *BatchIngestHandler) IngestBatch(c *gin.Context) {
 		httputil.BadRequest(c, "invalid_request", err.Error())
 		return
 	}
-	if len(req) == 0 || len(req) > 25 {
-		httputil.BadRequest(c, "invalid_batch_size", "batch size must be between 1 and 25")
+	if len(req) == 0 || len(req) > ingest.MaxBatchSize {
+		httputil.BadRequest(c, "invalid_batch_size", fmt.Sprintf("batch size must be between 1 and %d", ingest.MaxBatchSize))
 		return
 	}
+	for _, in := range req {
+		if !authorizeStream(c, in.StreamID) {
+			return
+		}
+	}
 	results := h.service.BatchIngest(c.Request.Context(), req)
 	c.JSON(http.StatusOK, gin.H{"results": results})
 }
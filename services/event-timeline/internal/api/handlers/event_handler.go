@@ -29,5 +29,8 @@ func (h *EventHandler) GetByID(c *gin.Context) {
 		httputil.Internal(c, "event_fetch_failed", "failed to fetch event")
 		return
 	}
+	if !authorizeStream(c, event.StreamID) {
+		return
+	}
 	c.JSON(200, gin.H{"event": event})
 }
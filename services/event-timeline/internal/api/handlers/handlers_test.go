@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -13,9 +15,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/integrity"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
 )
 
@@ -37,19 +42,6 @@ func (s *testEventStore) PutEvent(_ context.Context, event domain.Event) error {
 	return nil
 }
 
-func (s *testEventStore) PutEventsBatch(_ context.Context, events []domain.Event) error {
-	s.events = append(s.events, events...)
-	for _, event := range events {
-		if event.IdempotencyKey != "" {
-			if s.byIdem == nil {
-				s.byIdem = map[string]domain.Event{}
-			}
-			s.byIdem[event.StreamID+"#"+event.IdempotencyKey] = event
-		}
-	}
-	return nil
-}
-
 func (s *testEventStore) GetByEventID(_ context.Context, eventID string) (domain.Event, error) {
 	if s.getByEventErr != nil {
 		return domain.Event{}, s.getByEventErr
@@ -111,8 +103,9 @@ type captureStreamStore struct {
 
 type failingPutStore struct{}
 
-func (f *failingPutStore) PutEvent(context.Context, domain.Event) error { return errors.New("put failed") }
-func (f *failingPutStore) PutEventsBatch(context.Context, []domain.Event) error { return nil }
+func (f *failingPutStore) PutEvent(context.Context, domain.Event) error {
+	return errors.New("put failed")
+}
 func (f *failingPutStore) GetByEventID(context.Context, string) (domain.Event, error) {
 	return domain.Event{}, domain.ErrNotFound
 }
@@ -124,8 +117,7 @@ func (f *failingPutStore) QueryByStream(context.Context, string, int64, string,
 	return nil, 0, false, nil
 }
 
-func (s *captureStreamStore) PutEvent(context.Context, domain.Event) error         { return nil }
-func (s *captureStreamStore) PutEventsBatch(context.Context, []domain.Event) error { return nil }
+func (s *captureStreamStore) PutEvent(context.Context, domain.Event) error { return nil }
 func (s *captureStreamStore) GetByEventID(context.Context, string) (domain.Event, error) {
 	return domain.Event{}, domain.ErrNotFound
 }
@@ -145,12 +137,19 @@ func newIngestService(store *testEventStore) *ingest.Service {
 	return ingest.NewService(store, fixedGenerator{}, clock.MockClock{Current: time.Now().UTC()}, observability.NewMetrics())
 }
 
+// grantAllStreams runs requests as a principal granted every stream
+func grantAllStreams(c *gin.Context) {
+	principal := authz.Principal{Subject: "test", StreamPatterns: []string{"*"}}
+	c.Request = c.Request.WithContext(authz.WithPrincipal(c.Request.Context(), principal))
+}
+
 func TestIngestHandlerIngestSuccess(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	store := &testEventStore{byIdem: map[string]domain.Event{}}
 	handler := NewIngestHandler(newIngestService(store))
 
 	r := gin.New()
+	r.Use(grantAllStreams)
 	r.POST("/events", handler.Ingest)
 
 	body := `{"stream_id":"stream-1","event_type":"created","payload":{"v":1},"occurred_at":"2026-02-14T10:00:00Z","idempotency_key":"idem-1"}`
@@ -182,6 +181,7 @@ func TestIngestHandlerReturnsInternalOnStoreError(t *testing.T) {
 	service := ingest.NewService(&failingPutStore{}, fixedGenerator{}, clock.MockClock{Current: time.Now().UTC()}, observability.NewMetrics())
 	handler := NewIngestHandler(service)
 	r := gin.New()
+	r.Use(grantAllStreams)
 	r.POST("/events", handler.Ingest)
 
 	body := `{"stream_id":"stream-1","event_type":"created","payload":{"v":1},"occurred_at":"2026-02-14T10:00:00Z","idempotency_key":"idem-1"}`
@@ -307,3 +307,127 @@ func TestStreamHandlerBackwardDefaultsToLatestSequence(t *testing.T) {
 	require.Equal(t, int64(5), store.fromSequence)
 	require.Equal(t, domain.DirectionBackward, store.direction)
 }
+
+func TestStreamHandlerStartsAtFromSequence(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &captureStreamStore{}
+	r := gin.New()
+	r.GET("/streams/:streamId/events", NewStreamHandler(store).GetByStream)
+
+	for _, target := range []string{"/streams/s1/events?from_sequence=3", "/streams/s1/events?from_sequence=3&direction=backward"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, int64(3), store.fromSequence, target)
+	}
+
+	cursor := domain.Cursor{StreamID: "s1", Sequence: 8, Direction: domain.DirectionForward}.Encode()
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/streams/s1/events?from_sequence=3&cursor="+cursor, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(8), store.fromSequence, "a cursor continues past from_sequence")
+
+	for _, raw := range []string{"0", "-1", "abc"} {
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/streams/s1/events?from_sequence="+raw, nil))
+		require.Equal(t, http.StatusBadRequest, rec.Code, raw)
+		require.Contains(t, rec.Body.String(), "invalid_from_sequence")
+	}
+}
+
+type emptyCheckpointStore struct{}
+
+func (emptyCheckpointStore) PutCheckpoint(context.Context, domain.Checkpoint) error { return nil }
+func (emptyCheckpointStore) LatestCheckpoint(context.Context, string) (domain.Checkpoint, error) {
+	return domain.Checkpoint{}, domain.ErrNotFound
+}
+func (emptyCheckpointStore) FindCheckpoint(context.Context, string, int64) (domain.Checkpoint, error) {
+	return domain.Checkpoint{}, domain.ErrNotFound
+}
+
+func TestProofHandlerNotFoundCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &testEventStore{events: []domain.Event{{EventID: "evt-1", StreamID: "stream-1", SequenceNumber: 1}}}
+	handler := NewProofHandler(integrity.NewCheckpointer(integrity.CheckpointerDependencies{
+		EventStore:      store,
+		CheckpointStore: emptyCheckpointStore{},
+	}))
+	r := gin.New()
+	r.GET("/events/:eventId/proof", handler.GetProof)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/unknown/proof", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Contains(t, rec.Body.String(), "event_not_found")
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/evt-1/proof", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Contains(t, rec.Body.String(), "event_not_checkpointed")
+}
+
+type testStreamStore struct {
+	streams []domain.Stream
+	err     error
+}
+
+// ListStreams pages by position; the cursor is the index of the next stream.
+func (s testStreamStore) ListStreams(_ context.Context, cursor string, limit int32) (storage.StreamPage, error) {
+	if s.err != nil {
+		return storage.StreamPage{}, s.err
+	}
+	start := 0
+	if cursor != "" {
+		var err error
+		if start, err = strconv.Atoi(cursor); err != nil {
+			return storage.StreamPage{}, fmt.Errorf("bad cursor: %w", domain.ErrValidation)
+		}
+	}
+	end := min(start+int(limit), len(s.streams))
+	page := storage.StreamPage{Streams: s.streams[start:end]}
+	if end < len(s.streams) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+func TestStreamListHandlerFiltersByStreamGrants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := testStreamStore{streams: []domain.Stream{
+		{TenantID: "acme", StreamID: "orders-2", LatestSequence: 4},
+		{TenantID: "acme", StreamID: "payments-1", LatestSequence: 9},
+		{TenantID: "acme", StreamID: "orders-1", LatestSequence: 2},
+	}}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		principal := authz.PrincipalFromClaims(map[string]any{"sub": "reader", "streams": []any{"orders-*"}})
+		c.Request = c.Request.WithContext(authz.WithPrincipal(c.Request.Context(), principal))
+	})
+	r.GET("/api/v1/streams", NewStreamListHandler(store).List)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/streams", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"streams":[{"stream_id":"orders-2","latest_sequence":4},{"stream_id":"orders-1","latest_sequence":2}],"next_cursor":"","has_more":false}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/streams?limit=2", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"streams":[{"stream_id":"orders-2","latest_sequence":4}],"next_cursor":"2","has_more":true}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/streams?limit=2&cursor=2", nil))
+	require.JSONEq(t, `{"streams":[{"stream_id":"orders-1","latest_sequence":2}],"next_cursor":"","has_more":false}`, rec.Body.String())
+
+	for _, query := range []string{"limit=0", "limit=1001", "limit=abc", "cursor=abc"} {
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/streams?"+query, nil))
+		require.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	r = gin.New()
+	r.GET("/api/v1/streams", NewStreamListHandler(testStreamStore{err: errors.New("scan failed")}).List)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/streams", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -26,12 +26,19 @@ func (h *IngestHandler) Ingest(c *gin.Context) {
 		httputil.BadRequest(c, "invalid_request", err.Error())
 		return
 	}
+	if !authorizeStream(c, req.StreamID) {
+		return
+	}
 	event, created, err := h.service.Ingest(c.Request.Context(), req)
 	if err != nil {
 		if errors.Is(err, domain.ErrValidation) {
 			httputil.BadRequest(c, "validation_failed", err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrKeyShredded) {
+			httputil.WriteError(c, http.StatusConflict, "stream_shredded", "stream data key was shredded; encrypted fields can no longer be written")
+			return
+		}
 		slog.Error("ingest failed", slog.String("error", err.Error()))
 		httputil.Internal(c, "ingest_failed", "failed to ingest event")
 		return
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/httputil"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/integrity"
+)
+
+type ProofHandler struct {
+	checkpointer *integrity.Checkpointer
+}
+
+func NewProofHandler(checkpointer *integrity.Checkpointer) *ProofHandler {
+	return &ProofHandler{checkpointer: checkpointer}
+}
+
+func (h *ProofHandler) GetProof(c *gin.Context) {
+	proof, err := h.checkpointer.Proof(c.Request.Context(), c.Param("eventId"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			httputil.NotFound(c, "event_not_found", "event not found")
+		case errors.Is(err, integrity.ErrNotCheckpointed):
+			httputil.NotFound(c, "event_not_checkpointed", "event is not yet covered by a checkpoint")
+		case errors.Is(err, integrity.ErrCheckpointInvalid):
+			httputil.Internal(c, "checkpoint_invalid", "stored events do not match the signed checkpoint")
+		default:
+			httputil.Internal(c, "proof_failed", "failed to build inclusion proof")
+		}
+		return
+	}
+	if !authorizeStream(c, proof.StreamID) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"proof": proof})
+}
@@ -40,8 +40,20 @@ func (h *StreamHandler) GetByStream(c *gin.Context) {
 		httputil.BadRequest(c, "invalid_direction", "direction must be forward or backward")
 		return
 	}
+	// from_sequence starts the first page at a sequence, the first event read
+	// in either direction; a cursor continues from where it left off instead
 	fromSeq := int64(1)
-	if direction == domain.DirectionBackward {
+	fromRequested := false
+	if raw := c.Query("from_sequence"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed < 1 {
+			httputil.BadRequest(c, "invalid_from_sequence", "from_sequence must be a positive integer")
+			return
+		}
+		fromSeq = parsed
+		fromRequested = true
+	}
+	if direction == domain.DirectionBackward && !fromRequested {
 		latest, err := h.eventStore.GetLatestSequence(c.Request.Context(), streamID)
 		if err != nil {
 			httputil.Internal(c, "stream_query_failed", "failed to query stream events")
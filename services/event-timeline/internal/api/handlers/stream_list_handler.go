@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/httputil"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+)
+
+type StreamListHandler struct {
+	streamStore storage.StreamStore
+}
+
+func NewStreamListHandler(streamStore storage.StreamStore) *StreamListHandler {
+	return &StreamListHandler{streamStore: streamStore}
+}
+
+func (h *StreamListHandler) List(c *gin.Context) {
+	limit := int32(storage.DefaultStreamPageSize)
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || parsed < 1 || parsed > storage.MaxStreamPageSize {
+			httputil.BadRequest(c, "invalid_limit", "limit must be an integer between 1 and 1000")
+			return
+		}
+		limit = int32(parsed)
+	}
+	page, err := h.streamStore.ListStreams(c.Request.Context(), c.Query("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			httputil.BadRequest(c, "invalid_cursor", "cursor is not valid for this tenant")
+			return
+		}
+		httputil.Internal(c, "stream_list_failed", "failed to list streams")
+		return
+	}
+	visible := make([]domain.Stream, 0, len(page.Streams))
+	for _, stream := range page.Streams {
+		if _, ok := authz.StreamAllowed(c.Request.Context(), stream.StreamID); !ok {
+			continue
+		}
+		stream.TenantID = ""
+		visible = append(visible, stream)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"streams":     visible,
+		"next_cursor": page.NextCursor,
+		"has_more":    page.NextCursor != "",
+	})
+}
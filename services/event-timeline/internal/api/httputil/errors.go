@@ -34,6 +34,10 @@ func Unauthorized(c *gin.Context, code, message string) {
 	WriteError(c, http.StatusUnauthorized, code, message)
 }
 
+func Forbidden(c *gin.Context, code, message string) {
+	WriteError(c, http.StatusForbidden, code, message)
+}
+
 func TooManyRequests(c *gin.Context, code, message string) {
 	WriteError(c, http.StatusTooManyRequests, code, message)
 }
@@ -37,6 +37,7 @@ func TestErrorHelpersUseExpectedStatusCodes(t *testing.T) {
 	}{
 		{name: "bad request", call: func(c *gin.Context) { BadRequest(c, "code", "msg") }, status: http.StatusBadRequest},
 		{name: "unauthorized", call: func(c *gin.Context) { Unauthorized(c, "code", "msg") }, status: http.StatusUnauthorized},
+		{name: "forbidden", call: func(c *gin.Context) { Forbidden(c, "code", "msg") }, status: http.StatusForbidden},
 		{name: "too many", call: func(c *gin.Context) { TooManyRequests(c, "code", "msg") }, status: http.StatusTooManyRequests},
 		{name: "internal", call: func(c *gin.Context) { Internal(c, "code", "msg") }, status: http.StatusInternalServerError},
 		{name: "not found", call: func(c *gin.Context) { NotFound(c, "code", "msg") }, status: http.StatusNotFound},
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+)
+
+const accessLogBodyLimit = 64 << 10
+
+// Request bodies are copied as the handler reads them, up to
+// accessLogBodyLimit, so stream IDs can be logged without a second read.
+type cappedBuffer struct {
+	bytes.Buffer
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := accessLogBodyLimit - b.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func AccessLog(recorder *accesslog.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if recorder == nil {
+			c.Next()
+			return
+		}
+		var body *cappedBuffer
+		if c.Request.Body != nil && c.Request.Method != http.MethodGet {
+			body = &cappedBuffer{}
+			c.Request.Body = teeReadCloser{Reader: io.TeeReader(c.Request.Body, body), Closer: c.Request.Body}
+		}
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		entry := accessEntry(c.Request, accesslog.TransportPublic, route, c.Writer.Status(), start)
+		entry.RequestID = c.GetString(RequestIDContextKey)
+		entry.EventID = c.Param("eventId")
+		if streamID := c.Param("streamId"); streamID != "" {
+			entry.StreamIDs = []string{streamID}
+		} else if body != nil && !body.truncated {
+			entry.StreamIDs = bodyStreams(body.Bytes())
+		}
+		recorder.Record(entry)
+	}
+}
+
+var unauditedAdminRoutes = map[string]bool{
+	"/admin/health":  true,
+	"/admin/ready":   true,
+	"/admin/metrics": true,
+}
+
+func EchoAccessLog(recorder *accesslog.Recorder) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if recorder == nil || unauditedAdminRoutes[c.Path()] {
+				return next(c)
+			}
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// Let Echo write the error now so the logged status is final.
+				c.Error(err)
+			}
+			entry := accessEntry(c.Request(), accesslog.TransportAdmin, c.Path(), c.Response().Status, start)
+			entry.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+			if streamID := c.Param("id"); streamID != "" && strings.HasPrefix(c.Path(), "/admin/streams/") {
+				entry.StreamIDs = []string{streamID}
+			}
+			recorder.Record(entry)
+			return nil
+		}
+	}
+}
+
+func accessEntry(r *http.Request, transport, route string, status int, at time.Time) accesslog.Entry {
+	principal, _ := authz.FromContext(r.Context())
+	return accesslog.Entry{
+		Time:      at.UTC(),
+		Transport: transport,
+		Subject:   principal.Subject,
+		TenantID:  tenant.FromContext(r.Context()),
+		Method:    r.Method,
+		Route:     route,
+		Status:    status,
+	}
+}
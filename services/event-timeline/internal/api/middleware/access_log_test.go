@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
+)
+
+type accessSink struct {
+	mu      sync.Mutex
+	entries []accesslog.Entry
+}
+
+func (s *accessSink) WriteAccessLog(_ context.Context, entries []accesslog.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+// recordAccess runs serve against a fresh recorder and returns what it flushed.
+func recordAccess(t *testing.T, serve func(*accesslog.Recorder)) []accesslog.Entry {
+	t.Helper()
+	sink := &accessSink{}
+	recorder := accesslog.NewRecorder(accesslog.Options{Sink: sink, FlushInterval: time.Hour})
+	serve(recorder)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, recorder.Run(ctx))
+	return sink.entries
+}
+
+func accessToken(t *testing.T) string {
+	return "Bearer " + makeToken(t, jwt.SigningMethodHS256, "secret", jwt.MapClaims{
+		"iss":       "aevum",
+		"sub":       "user-1",
+		"tenant_id": "acme",
+		"iat":       time.Now().Unix(),
+		"exp":       time.Now().Add(5 * time.Minute).Unix(),
+	})
+}
+
+func TestAccessLogRecordsSubjectRouteAndBodyStreams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	entries := recordAccess(t, func(recorder *accesslog.Recorder) {
+		r := gin.New()
+		r.Use(RequestID())
+		r.Use(AccessLog(recorder))
+		r.Use(JWTAuth(NewTokenValidator(TokenValidatorOptions{HMACSecret: "secret", TenantClaim: "tenant_id"})))
+		r.POST("/api/v1/events/batch", func(c *gin.Context) {
+			_, _ = io.ReadAll(c.Request.Body)
+			c.Status(http.StatusCreated)
+		})
+		r.GET("/api/v1/events/:eventId", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+		body := `[{"stream_id":"orders"},{"stream_id":"invoices"},{"stream_id":"orders"}]`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/events/batch", strings.NewReader(body))
+		req.Header.Set("Authorization", accessToken(t))
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/events/evt-1", nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	})
+
+	require.Len(t, entries, 2)
+	write := entries[0]
+	require.Equal(t, accesslog.TransportPublic, write.Transport)
+	require.Equal(t, "user-1", write.Subject)
+	require.Equal(t, "acme", write.TenantID)
+	require.Equal(t, "/api/v1/events/batch", write.Route)
+	require.Equal(t, []string{"orders", "invoices"}, write.StreamIDs)
+	require.Equal(t, http.StatusCreated, write.Status)
+	require.NotEmpty(t, write.RequestID)
+
+	rejected := entries[1]
+	require.Empty(t, rejected.Subject)
+	require.Equal(t, "/api/v1/events/:eventId", rejected.Route)
+	require.Equal(t, "evt-1", rejected.EventID)
+	require.Equal(t, http.StatusUnauthorized, rejected.Status)
+}
+
+func TestEchoAccessLogRecordsAdminCalls(t *testing.T) {
+	entries := recordAccess(t, func(recorder *accesslog.Recorder) {
+		e := echo.New()
+		e.Use(EchoAccessLog(recorder))
+		auth := EchoJWTAuth(NewTokenValidator(TokenValidatorOptions{HMACSecret: "secret"}))
+		e.GET("/admin/health", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		e.GET("/admin/streams/:id/verify", func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusConflict, "chain broken")
+		}, auth)
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/health", nil))
+		req := httptest.NewRequest(http.MethodGet, "/admin/streams/orders/verify", nil)
+		req.Header.Set("Authorization", accessToken(t))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	require.Len(t, entries, 1)
+	require.Equal(t, accesslog.TransportAdmin, entries[0].Transport)
+	require.Equal(t, "user-1", entries[0].Subject)
+	require.Equal(t, "/admin/streams/:id/verify", entries[0].Route)
+	require.Equal(t, []string{"orders"}, entries[0].StreamIDs)
+	require.Equal(t, http.StatusConflict, entries[0].Status)
+}
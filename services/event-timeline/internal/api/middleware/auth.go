@@ -1,64 +1,227 @@
 package middleware
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/httputil"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/apikey"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/mtls"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
 )
 
-const ClaimsContextKey = "jwt_claims"
+const (
+	ClaimsContextKey = "jwt_claims"
+	APIKeyHeader     = "X-API-Key"
+)
 
-func JWTAuth(secret string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			httputil.Unauthorized(c, "missing_token", "missing bearer token")
-			c.Abort()
-			return
+var requiredClaims = []string{"iss", "sub", "exp", "iat"}
+
+type AuthError struct {
+	Code    string
+	Message string
+}
+
+func (e *AuthError) Error() string {
+	return e.Message
+}
+
+func ParseBearerToken(authHeader string) (string, error) {
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		return "", &AuthError{Code: "missing_token", Message: "missing bearer token"}
+	}
+	return strings.TrimPrefix(authHeader, "Bearer "), nil
+}
+
+type TokenValidatorOptions struct {
+	HMACSecret  string
+	KeySet      *KeySet
+	Audience    string
+	ClockSkew   time.Duration
+	TenantClaim string
+	// Requests without a bearer token authenticate as the identity mapped
+	// from their client certificate SAN, when one matches.
+	CertIdentities *mtls.Identities
+	APIKeys        APIKeyVerifier
+}
+
+type APIKeyVerifier interface {
+	Verify(ctx context.Context, presented string) (domain.APIKey, error)
+}
+
+type TokenValidator struct {
+	secret      []byte
+	keys        *KeySet
+	parser      *jwt.Parser
+	tenantClaim string
+	identities  *mtls.Identities
+	apiKeys     APIKeyVerifier
+}
+
+func NewTokenValidator(opts TokenValidatorOptions) *TokenValidator {
+	methods := make([]string, 0, 4)
+	if opts.HMACSecret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if opts.KeySet != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg(), jwt.SigningMethodEdDSA.Alg())
+	}
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(opts.ClockSkew),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+	return &TokenValidator{
+		secret:      []byte(opts.HMACSecret),
+		keys:        opts.KeySet,
+		parser:      jwt.NewParser(parserOpts...),
+		tenantClaim: opts.TenantClaim,
+		identities:  opts.CertIdentities,
+		apiKeys:     opts.APIKeys,
+	}
+}
+
+func (v *TokenValidator) Validate(ctx context.Context, tokenStr string) (jwt.MapClaims, error) {
+	token, err := v.parser.Parse(tokenStr, func(token *jwt.Token) (any, error) {
+		if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return v.secret, nil
 		}
-		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (any, error) {
-			if token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
-				return nil, errors.New("unexpected signing algorithm")
-			}
-			return []byte(secret), nil
-		})
-		if err != nil || !token.Valid {
-			httputil.Unauthorized(c, "invalid_token", "invalid token")
-			c.Abort()
-			return
+		if v.keys == nil {
+			return nil, errors.New("unexpected signing algorithm")
 		}
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			httputil.Unauthorized(c, "invalid_claims", "invalid token claims")
-			c.Abort()
-			return
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.Key(ctx, kid, token.Method.Alg())
+	})
+	if err != nil || !token.Valid {
+		return nil, tokenError(err)
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, &AuthError{Code: "invalid_claims", Message: "invalid token claims"}
+	}
+	for _, name := range requiredClaims {
+		if _, ok := claims[name]; !ok {
+			return nil, &AuthError{Code: "missing_claim", Message: "missing " + name + " claim"}
 		}
-		if _, ok := claims["iss"]; !ok {
-			httputil.Unauthorized(c, "missing_claim", "missing iss claim")
-			c.Abort()
-			return
+	}
+	return claims, nil
+}
+
+func (v *TokenValidator) Authenticate(ctx context.Context, tokenStr string) (context.Context, jwt.MapClaims, error) {
+	claims, err := v.Validate(ctx, tokenStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	tenantID := tenant.Default
+	if raw, ok := claims[v.tenantClaim]; ok && v.tenantClaim != "" {
+		id, _ := raw.(string)
+		if tenant.Validate(id) != nil {
+			return nil, nil, &AuthError{Code: "invalid_tenant", Message: "invalid " + v.tenantClaim + " claim"}
 		}
-		if _, ok := claims["sub"]; !ok {
-			httputil.Unauthorized(c, "missing_claim", "missing sub claim")
-			c.Abort()
+		tenantID = id
+	}
+	ctx = authz.WithPrincipal(ctx, authz.PrincipalFromClaims(claims))
+	return tenant.WithTenant(ctx, tenantID), claims, nil
+}
+
+func (v *TokenValidator) AuthenticateAPIKey(ctx context.Context, presented string) (context.Context, jwt.MapClaims, error) {
+	if v.apiKeys == nil {
+		return nil, nil, &AuthError{Code: "invalid_api_key", Message: "api keys are not accepted"}
+	}
+	key, err := v.apiKeys.Verify(ctx, presented)
+	switch {
+	case errors.Is(err, apikey.ErrRevoked):
+		return nil, nil, &AuthError{Code: "api_key_revoked", Message: "api key revoked"}
+	case errors.Is(err, apikey.ErrExpired):
+		return nil, nil, &AuthError{Code: "api_key_expired", Message: "api key expired"}
+	case errors.Is(err, apikey.ErrInvalidKey):
+		return nil, nil, &AuthError{Code: "invalid_api_key", Message: "invalid api key"}
+	case err != nil:
+		return nil, nil, err
+	}
+	claims := jwt.MapClaims(apikey.Claims(key, v.tenantClaim))
+	ctx = authz.WithPrincipal(ctx, authz.PrincipalFromClaims(claims))
+	return tenant.WithTenant(ctx, key.TenantID), claims, nil
+}
+
+func (v *TokenValidator) AuthenticateCertificate(ctx context.Context, state *tls.ConnectionState) (context.Context, bool) {
+	return v.identities.Authenticate(ctx, state)
+}
+
+func tokenError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return &AuthError{Code: "token_expired", Message: "token expired"}
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return &AuthError{Code: "invalid_audience", Message: "token audience not accepted"}
+	case errors.Is(err, ErrUnknownKey):
+		return &AuthError{Code: "unknown_key", Message: "token signing key not recognized"}
+	default:
+		return &AuthError{Code: "invalid_token", Message: "invalid token"}
+	}
+}
+
+func JWTAuth(validator *TokenValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if presented := c.GetHeader(APIKeyHeader); presented != "" && c.GetHeader("Authorization") == "" {
+			ctx, claims, err := validator.AuthenticateAPIKey(c.Request.Context(), presented)
+			var authErr *AuthError
+			if err != nil && !errors.As(err, &authErr) {
+				httputil.WriteError(c, http.StatusServiceUnavailable, "auth_unavailable", "api key lookup failed")
+				c.Abort()
+				return
+			}
+			if err != nil {
+				abortUnauthorized(c, err)
+				return
+			}
+			c.Set(ClaimsContextKey, claims)
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
 			return
 		}
-		if _, ok := claims["exp"]; !ok {
-			httputil.Unauthorized(c, "missing_claim", "missing exp claim")
-			c.Abort()
+		if c.GetHeader("Authorization") == "" {
+			if ctx, ok := validator.AuthenticateCertificate(c.Request.Context(), c.Request.TLS); ok {
+				c.Request = c.Request.WithContext(ctx)
+				c.Next()
+				return
+			}
+		}
+		tokenStr, err := ParseBearerToken(c.GetHeader("Authorization"))
+		if err != nil {
+			abortUnauthorized(c, err)
 			return
 		}
-		if _, ok := claims["iat"]; !ok {
-			httputil.Unauthorized(c, "missing_claim", "missing iat claim")
-			c.Abort()
+		ctx, claims, err := validator.Authenticate(c.Request.Context(), tokenStr)
+		if err != nil {
+			abortUnauthorized(c, err)
 			return
 		}
 		c.Set(ClaimsContextKey, claims)
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
+
+func abortUnauthorized(c *gin.Context, err error) {
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		httputil.Unauthorized(c, authErr.Code, authErr.Message)
+	} else {
+		httputil.Unauthorized(c, "invalid_token", "invalid token")
+	}
+	c.Abort()
+}
@@ -1,6 +1,10 @@
 package middleware
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,6 +13,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/apikey"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/mtls"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
 )
 
 func makeToken(t *testing.T, method jwt.SigningMethod, secret string, claims jwt.MapClaims) string {
@@ -22,7 +32,7 @@ func makeToken(t *testing.T, method jwt.SigningMethod, secret string, claims jwt
 func TestJWTAuthRejectsMissingToken(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	r.Use(JWTAuth("secret"))
+	r.Use(JWTAuth(NewTokenValidator(TokenValidatorOptions{HMACSecret: "secret"})))
 	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
 
 	rec := httptest.NewRecorder()
@@ -34,7 +44,7 @@ func TestJWTAuthRejectsMissingToken(t *testing.T) {
 func TestJWTAuthRejectsWrongSigningMethod(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	r.Use(JWTAuth("secret"))
+	r.Use(JWTAuth(NewTokenValidator(TokenValidatorOptions{HMACSecret: "secret"})))
 	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
 
 	claims := jwt.MapClaims{
@@ -56,7 +66,7 @@ func TestJWTAuthRejectsWrongSigningMethod(t *testing.T) {
 func TestJWTAuthAllowsValidTokenAndSetsClaims(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	r.Use(JWTAuth("secret"))
+	r.Use(JWTAuth(NewTokenValidator(TokenValidatorOptions{HMACSecret: "secret"})))
 	r.GET("/", func(c *gin.Context) {
 		claims, ok := c.Get(ClaimsContextKey)
 		if !ok {
@@ -86,3 +96,103 @@ func TestJWTAuthAllowsValidTokenAndSetsClaims(t *testing.T) {
 
 	require.Equal(t, http.StatusOK, rec.Code)
 }
+
+func TestAuthenticateDerivesTenantFromClaim(t *testing.T) {
+	validator := NewTokenValidator(TokenValidatorOptions{HMACSecret: "secret", TenantClaim: "org"})
+	claims := jwt.MapClaims{"iss": "aevum", "sub": "user-1", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Minute).Unix()}
+
+	ctx, _, err := validator.Authenticate(context.Background(), makeToken(t, jwt.SigningMethodHS256, "secret", claims))
+	require.NoError(t, err)
+	require.Equal(t, tenant.Default, tenant.FromContext(ctx))
+
+	claims["org"] = "acme"
+	ctx, _, err = validator.Authenticate(context.Background(), makeToken(t, jwt.SigningMethodHS256, "secret", claims))
+	require.NoError(t, err)
+	require.Equal(t, "acme", tenant.FromContext(ctx))
+
+	claims["org"] = "T#acme"
+	_, _, err = validator.Authenticate(context.Background(), makeToken(t, jwt.SigningMethodHS256, "secret", claims))
+	var authErr *AuthError
+	require.ErrorAs(t, err, &authErr)
+	require.Equal(t, "invalid_tenant", authErr.Code)
+}
+
+func TestJWTAuthAcceptsMappedClientCertificate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	identities, err := mtls.NewIdentities([]mtls.Identity{{SAN: "query-audit.aevum.svc", Subject: "query-audit", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"*"}}})
+	require.NoError(t, err)
+	r := gin.New()
+	r.Use(JWTAuth(NewTokenValidator(TokenValidatorOptions{HMACSecret: "secret", CertIdentities: identities})))
+	r.GET("/", func(c *gin.Context) {
+		principal, _ := authz.FromContext(c.Request.Context())
+		c.String(http.StatusOK, principal.Subject)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{DNSNames: []string{"query-audit.aevum.svc"}}}}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "query-audit", rec.Body.String())
+
+	req.Header.Set("Authorization", "Bearer broken")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code, "a bearer token takes precedence over the certificate")
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{DNSNames: []string{"unknown.aevum.svc"}}}}
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+type fakeAPIKeys map[string]error
+
+func (f fakeAPIKeys) Verify(_ context.Context, presented string) (domain.APIKey, error) {
+	if err, ok := f[presented]; ok {
+		return domain.APIKey{}, err
+	}
+	return domain.APIKey{KeyID: "k1", TenantID: "acme", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"orders"}}, nil
+}
+
+func TestJWTAuthAcceptsAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys := fakeAPIKeys{
+		"revoked":     apikey.ErrRevoked,
+		"wrong":       apikey.ErrInvalidKey,
+		"unavailable": errors.New("dynamodb unavailable"),
+	}
+	r := gin.New()
+	r.Use(JWTAuth(NewTokenValidator(TokenValidatorOptions{HMACSecret: "secret", TenantClaim: "tenant_id", APIKeys: keys})))
+	r.GET("/", func(c *gin.Context) {
+		principal, _ := authz.FromContext(c.Request.Context())
+		claims := c.MustGet(ClaimsContextKey).(jwt.MapClaims)
+		require.Equal(t, "acme", claims["tenant_id"])
+		require.True(t, principal.HasScope(authz.ScopeEventsRead))
+		require.False(t, principal.CanAccessStream("payments"))
+		c.String(http.StatusOK, principal.Subject+" "+tenant.FromContext(c.Request.Context()))
+	})
+
+	serve := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(APIKeyHeader, key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("ak_k1_secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "apikey:k1 acme", rec.Body.String())
+
+	rec = serve("revoked")
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Contains(t, rec.Body.String(), "api_key_revoked")
+
+	rec = serve("wrong")
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Contains(t, rec.Body.String(), "invalid_api_key")
+
+	require.Equal(t, http.StatusServiceUnavailable, serve("unavailable").Code)
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/httputil"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+)
+
+func RequireScope(logger *slog.Logger, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := authz.FromContext(c.Request.Context())
+		if !ok || !principal.HasScope(scope) {
+			logger.Warn("authorization denied",
+				slog.String("subject", principal.Subject),
+				slog.String("scope", scope),
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.FullPath()),
+			)
+			httputil.Forbidden(c, "insufficient_scope", "token lacks required scope "+scope)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func RequireStreamParam(logger *slog.Logger, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		streamID := c.Param(param)
+		if principal, ok := authz.StreamAllowed(c.Request.Context(), streamID); !ok {
+			logger.Warn("authorization denied",
+				slog.String("subject", principal.Subject),
+				slog.String("stream_id", streamID),
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.FullPath()),
+			)
+			httputil.Forbidden(c, "stream_forbidden", "token is not granted access to stream "+streamID)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/httputil"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+)
+
+func EchoJWTAuth(validator *TokenValidator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("Authorization") == "" {
+				if ctx, ok := validator.AuthenticateCertificate(c.Request().Context(), c.Request().TLS); ok {
+					c.SetRequest(c.Request().WithContext(ctx))
+					return next(c)
+				}
+			}
+			tokenStr, err := ParseBearerToken(c.Request().Header.Get("Authorization"))
+			if err == nil {
+				var ctx context.Context
+				ctx, _, err = validator.Authenticate(c.Request().Context(), tokenStr)
+				if err == nil {
+					c.SetRequest(c.Request().WithContext(ctx))
+					return next(c)
+				}
+			}
+			var authErr *AuthError
+			if !errors.As(err, &authErr) {
+				authErr = &AuthError{Code: "invalid_token", Message: "invalid token"}
+			}
+			return c.JSON(http.StatusUnauthorized, echoError(c, authErr.Code, authErr.Message))
+		}
+	}
+}
+
+func EchoRequireScope(logger *slog.Logger, scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, ok := authz.FromContext(c.Request().Context())
+			if ok && principal.HasScope(scope) {
+				return next(c)
+			}
+			logger.Warn("authorization denied",
+				slog.String("subject", principal.Subject),
+				slog.String("scope", scope),
+				slog.String("method", c.Request().Method),
+				slog.String("path", c.Path()),
+			)
+			return c.JSON(http.StatusForbidden, echoError(c, "insufficient_scope", "token lacks required scope "+scope))
+		}
+	}
+}
+
+func echoError(c echo.Context, code, message string) httputil.ErrorBody {
+	return httputil.ErrorBody{Error: httputil.ErrorEnvelope{
+		Code:      code,
+		Message:   message,
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+	}}
+}
@@ -0,0 +1,329 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+const (
+	DefaultJWKSRefreshInterval = 5 * time.Minute
+	DefaultJWKSRotationGrace   = 15 * time.Minute
+	jwksMinRefreshGap          = 10 * time.Second
+	jwksMaxBodyBytes           = 1 << 20
+)
+
+var (
+	ErrUnknownKey     = errors.New("unknown signing key")
+	errUnsupportedKey = errors.New("unsupported key")
+)
+
+type JWKSOptions struct {
+	URL             string
+	File            string
+	RefreshInterval time.Duration
+	RotationGrace   time.Duration
+	HTTPClient      *http.Client
+	Clock           clock.Clock
+}
+
+type KeySet struct {
+	opts        JWKSOptions
+	refreshMu   sync.Mutex
+	mu          sync.RWMutex
+	keys        map[string]jsonWebKey
+	retired     map[string]retiredKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+type jsonWebKey struct {
+	kid string
+	alg string
+	key any
+}
+
+type retiredKey struct {
+	key   jsonWebKey
+	until time.Time
+}
+
+type rawJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func NewKeySet(ctx context.Context, opts JWKSOptions) (*KeySet, error) {
+	if (opts.URL == "") == (opts.File == "") {
+		return nil, errors.New("exactly one of jwks url or file must be set")
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultJWKSRefreshInterval
+	}
+	if opts.RotationGrace < 0 {
+		opts.RotationGrace = 0
+	} else if opts.RotationGrace == 0 {
+		opts.RotationGrace = DefaultJWKSRotationGrace
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.Clock == nil {
+		opts.Clock = clock.RealClock{}
+	}
+	ks := &KeySet{opts: opts, keys: map[string]jsonWebKey{}, retired: map[string]retiredKey{}}
+	if err := ks.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+func (k *KeySet) Refresh(ctx context.Context) error {
+	k.refreshMu.Lock()
+	defer k.refreshMu.Unlock()
+	now := k.opts.Clock.Now()
+
+	k.mu.Lock()
+	k.lastAttempt = now
+	k.mu.Unlock()
+
+	raw, err := k.load(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(raw)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for kid, key := range k.keys {
+		if _, ok := keys[kid]; !ok && k.opts.RotationGrace > 0 {
+			k.retired[kid] = retiredKey{key: key, until: now.Add(k.opts.RotationGrace)}
+		}
+	}
+	for kid, retired := range k.retired {
+		if _, ok := keys[kid]; ok || !now.Before(retired.until) {
+			delete(k.retired, kid)
+		}
+	}
+	k.keys = keys
+	k.fetchedAt = now
+	return nil
+}
+
+func (k *KeySet) Key(ctx context.Context, kid, alg string) (any, error) {
+	now := k.opts.Clock.Now()
+	k.mu.RLock()
+	stale := now.Sub(k.fetchedAt) >= k.opts.RefreshInterval
+	k.mu.RUnlock()
+	if stale {
+		_ = k.refreshIfDue(ctx, now)
+	}
+
+	if key, ok := k.lookup(kid, now); ok {
+		return checkKeyAlg(key, alg)
+	}
+	if err := k.refreshIfDue(ctx, now); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrUnknownKey, kid, err)
+	}
+	if key, ok := k.lookup(kid, now); ok {
+		return checkKeyAlg(key, alg)
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+}
+
+func (k *KeySet) refreshIfDue(ctx context.Context, now time.Time) error {
+	k.mu.RLock()
+	due := now.Sub(k.lastAttempt) >= jwksMinRefreshGap
+	k.mu.RUnlock()
+	if !due {
+		return nil
+	}
+	return k.Refresh(ctx)
+}
+
+func (k *KeySet) lookup(kid string, now time.Time) (jsonWebKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if kid == "" {
+		if len(k.keys) == 1 {
+			for _, key := range k.keys {
+				return key, true
+			}
+		}
+		return jsonWebKey{}, false
+	}
+	if key, ok := k.keys[kid]; ok {
+		return key, true
+	}
+	if retired, ok := k.retired[kid]; ok && now.Before(retired.until) {
+		return retired.key, true
+	}
+	return jsonWebKey{}, false
+}
+
+func (k *KeySet) load(ctx context.Context) ([]byte, error) {
+	if k.opts.File != "" {
+		raw, err := os.ReadFile(k.opts.File)
+		if err != nil {
+			return nil, fmt.Errorf("read jwks file: %w", err)
+		}
+		return raw, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.opts.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build jwks request: %w", err)
+	}
+	resp, err := k.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, jwksMaxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read jwks response: %w", err)
+	}
+	return raw, nil
+}
+
+func parseJWKS(raw []byte) (map[string]jsonWebKey, error) {
+	var doc struct {
+		Keys []rawJWK `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+	keys := make(map[string]jsonWebKey, len(doc.Keys))
+	for i, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseJWK(jwk)
+		if errors.Is(err, errUnsupportedKey) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %d (%q): %w", i, jwk.Kid, err)
+		}
+		if _, dup := keys[jwk.Kid]; dup {
+			return nil, fmt.Errorf("jwks contains duplicate kid %q", jwk.Kid)
+		}
+		keys[jwk.Kid] = jsonWebKey{kid: jwk.Kid, alg: jwk.Alg, key: key}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no signing keys")
+	}
+	return keys, nil
+}
+
+func parseJWK(jwk rawJWK) (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeSegment(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode n: %w", err)
+		}
+		e, err := decodeSegment(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode e: %w", err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("ec curve %q: %w", jwk.Crv, errUnsupportedKey)
+		}
+		x, err := decodeSegment(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+		y, err := decodeSegment(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode y: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if _, err := key.ECDH(); err != nil {
+			return nil, errors.New("ec point is not on curve")
+		}
+		return key, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("okp curve %q: %w", jwk.Crv, errUnsupportedKey)
+		}
+		x, err := decodeSegment(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key length")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("key type %q: %w", jwk.Kty, errUnsupportedKey)
+	}
+}
+
+func checkKeyAlg(key jsonWebKey, alg string) (any, error) {
+	if key.alg != "" && key.alg != alg {
+		return nil, fmt.Errorf("key %q is restricted to %s", key.kid, key.alg)
+	}
+	switch typed := key.key.(type) {
+	case *rsa.PublicKey:
+		if alg == "RS256" {
+			return typed, nil
+		}
+	case *ecdsa.PublicKey:
+		if alg == "ES256" && typed.Curve == elliptic.P256() {
+			return typed, nil
+		}
+	case ed25519.PublicKey:
+		if alg == "EdDSA" {
+			return typed, nil
+		}
+	}
+	return nil, fmt.Errorf("key %q cannot verify %s", key.kid, alg)
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty value")
+	}
+	return base64.RawURLEncoding.DecodeString(s)
+}
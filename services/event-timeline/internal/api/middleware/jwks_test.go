@@ -0,0 +1,238 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type stepClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *stepClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type testSigner struct {
+	kid    string
+	method jwt.SigningMethod
+	key    crypto.Signer
+	jwk    map[string]string
+}
+
+func rsaSigner(t *testing.T, kid string) testSigner {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return testSigner{kid: kid, method: jwt.SigningMethodRS256, key: key, jwk: map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+		"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+	}}
+}
+
+func ecSigner(t *testing.T, kid string) testSigner {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return testSigner{kid: kid, method: jwt.SigningMethodES256, key: key, jwk: map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": b64(key.X.FillBytes(make([]byte, 32))), "y": b64(key.Y.FillBytes(make([]byte, 32))),
+	}}
+}
+
+func edSigner(t *testing.T, kid string) testSigner {
+	t.Helper()
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return testSigner{kid: kid, method: jwt.SigningMethodEdDSA, key: key, jwk: map[string]string{
+		"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(pub),
+	}}
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func (s testSigner) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(s.method, claims)
+	token.Header["kid"] = s.kid
+	signed, err := token.SignedString(s.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func writeJWKS(t *testing.T, path string, signers ...testSigner) {
+	t.Helper()
+	keys := make([]map[string]string, 0, len(signers))
+	for _, s := range signers {
+		keys = append(keys, s.jwk)
+	}
+	raw, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, raw, 0o600))
+}
+
+func validClaims(now time.Time) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss": "aevum-idp",
+		"sub": "service-1",
+		"aud": "event-timeline",
+		"iat": now.Unix(),
+		"exp": now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+func TestTokenValidatorAcceptsJWKSAlgorithms(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	signers := []testSigner{rsaSigner(t, "rsa-1"), ecSigner(t, "ec-1"), edSigner(t, "ed-1")}
+	writeJWKS(t, path, signers...)
+
+	keys, err := NewKeySet(context.Background(), JWKSOptions{File: path})
+	require.NoError(t, err)
+	validator := NewTokenValidator(TokenValidatorOptions{KeySet: keys, Audience: "event-timeline"})
+
+	for _, signer := range signers {
+		claims, err := validator.Validate(context.Background(), signer.sign(t, validClaims(time.Now())))
+		require.NoError(t, err, signer.kid)
+		require.Equal(t, "service-1", claims["sub"])
+	}
+
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims(time.Now()))
+	signed, err := hs.SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = validator.Validate(context.Background(), signed)
+	require.Error(t, err, "HS256 must be rejected without a shared secret")
+}
+
+func TestTokenValidatorChecksAudienceAndClockSkew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	signer := edSigner(t, "ed-1")
+	writeJWKS(t, path, signer)
+	keys, err := NewKeySet(context.Background(), JWKSOptions{File: path})
+	require.NoError(t, err)
+	validator := NewTokenValidator(TokenValidatorOptions{KeySet: keys, Audience: "event-timeline", ClockSkew: 30 * time.Second})
+
+	claims := validClaims(time.Now())
+	claims["aud"] = "query-audit"
+	_, err = validator.Validate(context.Background(), signer.sign(t, claims))
+	var authErr *AuthError
+	require.ErrorAs(t, err, &authErr)
+	require.Equal(t, "invalid_audience", authErr.Code)
+
+	claims = validClaims(time.Now().Add(20 * time.Second))
+	_, err = validator.Validate(context.Background(), signer.sign(t, claims))
+	require.NoError(t, err, "iat within skew must be accepted")
+
+	claims = validClaims(time.Now())
+	claims["exp"] = time.Now().Add(-10 * time.Second).Unix()
+	_, err = validator.Validate(context.Background(), signer.sign(t, claims))
+	require.NoError(t, err, "exp within skew must be accepted")
+
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	_, err = validator.Validate(context.Background(), signer.sign(t, claims))
+	require.ErrorAs(t, err, &authErr)
+	require.Equal(t, "token_expired", authErr.Code)
+}
+
+func TestKeySetRotationKeepsRetiredKeysDuringGrace(t *testing.T) {
+	clk := &stepClock{now: time.Now()}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	oldKey := ecSigner(t, "2026-01")
+	newKey := ecSigner(t, "2026-02")
+	writeJWKS(t, path, oldKey)
+
+	keys, err := NewKeySet(context.Background(), JWKSOptions{File: path, RotationGrace: 10 * time.Minute, Clock: clk})
+	require.NoError(t, err)
+	validator := NewTokenValidator(TokenValidatorOptions{KeySet: keys})
+	oldToken := oldKey.sign(t, validClaims(time.Now()))
+
+	writeJWKS(t, path, newKey)
+	clk.Advance(time.Minute)
+	_, err = validator.Validate(context.Background(), newKey.sign(t, validClaims(time.Now())))
+	require.NoError(t, err, "unknown kid must trigger a refresh")
+
+	_, err = validator.Validate(context.Background(), oldToken)
+	require.NoError(t, err, "retired key must be accepted during the grace period")
+
+	clk.Advance(10 * time.Minute)
+	require.NoError(t, keys.Refresh(context.Background()))
+	_, err = validator.Validate(context.Background(), oldToken)
+	var authErr *AuthError
+	require.ErrorAs(t, err, &authErr)
+	require.Equal(t, "unknown_key", authErr.Code)
+}
+
+func TestKeySetCachesURLAndRateLimitsRefresh(t *testing.T) {
+	clk := &stepClock{now: time.Now()}
+	signer := rsaSigner(t, "rsa-1")
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{signer.jwk}})
+	}))
+	defer srv.Close()
+
+	keys, err := NewKeySet(context.Background(), JWKSOptions{URL: srv.URL, RefreshInterval: time.Hour, Clock: clk})
+	require.NoError(t, err)
+	validator := NewTokenValidator(TokenValidatorOptions{KeySet: keys})
+
+	for i := 0; i < 3; i++ {
+		_, err := validator.Validate(context.Background(), signer.sign(t, validClaims(time.Now())))
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), fetches.Load())
+
+	unknown := rsaSigner(t, "rsa-2")
+	clk.Advance(jwksMinRefreshGap)
+	for i := 0; i < 3; i++ {
+		_, err := validator.Validate(context.Background(), unknown.sign(t, validClaims(time.Now())))
+		require.Error(t, err)
+	}
+	require.Equal(t, int32(2), fetches.Load(), "unknown kids refresh at most once per gap")
+
+	clk.Advance(time.Hour)
+	_, err = validator.Validate(context.Background(), signer.sign(t, validClaims(time.Now())))
+	require.NoError(t, err)
+	require.Equal(t, int32(3), fetches.Load(), "stale key set is refreshed")
+}
+
+func TestKeySetRejectsKeyAlgorithmMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	signer := rsaSigner(t, "rsa-1")
+	writeJWKS(t, path, signer)
+	keys, err := NewKeySet(context.Background(), JWKSOptions{File: path})
+	require.NoError(t, err)
+
+	_, err = keys.Key(context.Background(), "rsa-1", "EdDSA")
+	require.Error(t, err)
+
+	_, err = NewKeySet(context.Background(), JWKSOptions{File: filepath.Join(t.TempDir(), "missing.json")})
+	require.Error(t, err)
+}
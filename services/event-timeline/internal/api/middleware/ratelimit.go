@@ -1,54 +1,215 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
-	"sync"
-	"time"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/httputil"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ratelimit"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
 )
 
-type visitor struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
+const (
+	ipFallbackPolicy      = "ip-fallback"
+	subjectFallbackPolicy = "subject-fallback"
+	rateLimitDecisionKey  = "rate_limit_decision"
+	unauthenticatedTenant = "none"
+)
+
+type RateLimiterOptions struct {
+	Policies []ratelimit.Policy
+	Fallback ratelimit.Limit
+	Backend  ratelimit.Backend
+	Metrics  *observability.Metrics
+	Logger   *slog.Logger
+	Clock    clock.Clock
+}
+
+type RateLimiter struct {
+	policies []ratelimit.Policy
+	fallback ratelimit.Limit
+	backend  ratelimit.Backend
+	local    *ratelimit.MemoryBackend
+	metrics  *observability.Metrics
+	logger   *slog.Logger
 }
 
-func RateLimit(requestsPerSecond float64, burst int) gin.HandlerFunc {
-	visitors := map[string]*visitor{}
-	var mu sync.Mutex
+func NewRateLimiter(opts RateLimiterOptions) *RateLimiter {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	local := ratelimit.NewMemoryBackend(opts.Clock)
+	if opts.Backend == nil {
+		opts.Backend = local
+	}
+	return &RateLimiter{
+		policies: opts.Policies,
+		fallback: opts.Fallback,
+		backend:  opts.Backend,
+		local:    local,
+		metrics:  opts.Metrics,
+		logger:   opts.Logger,
+	}
+}
 
-	go func() {
-		ticker := time.NewTicker(1 * time.Minute)
-		for range ticker.C {
-			mu.Lock()
-			for ip, v := range visitors {
-				if time.Since(v.lastSeen) > 3*time.Minute {
-					delete(visitors, ip)
+// Routes enforces the policies that apply to routes. Routes no policy covers
+// use the fallback limit per subject, and only requests without a subject use
+// it per client IP, so callers behind one load balancer do not share a quota.
+func (r *RateLimiter) Routes(routes string) gin.HandlerFunc {
+	policies := make([]ratelimit.Policy, 0, len(r.policies))
+	for _, policy := range r.policies {
+		if policy.Applies(routes) {
+			policies = append(policies, policy)
+		}
+	}
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tenantID := tenant.FromContext(ctx)
+		var requests []ratelimit.Request
+		principal, authenticated := authz.FromContext(ctx)
+		if authenticated {
+			for _, policy := range policies {
+				for _, key := range r.keys(c, policy.Key, tenantID) {
+					requests = append(requests, ratelimit.Request{Policy: policy.Name, Key: key.bucket, Limit: policy.LimitFor(key.override)})
 				}
 			}
-			mu.Unlock()
+		} else {
+			tenantID = unauthenticatedTenant
 		}
-	}()
-
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		mu.Lock()
-		if _, ok := visitors[ip]; !ok {
-			visitors[ip] = &visitor{limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst)}
-		}
-		visitors[ip].lastSeen = time.Now()
-		limiter := visitors[ip].limiter
-		mu.Unlock()
-
-		if !limiter.Allow() {
-			c.Header("Retry-After", "1")
-			httputil.TooManyRequests(c, "rate_limited", fmt.Sprintf("rate limit exceeded for %s", ip))
-			c.Abort()
+		if len(requests) == 0 {
+			requests = []ratelimit.Request{r.fallbackRequest(c, tenantID, principal.Subject)}
+		}
+		if !r.apply(c, routes, tenantID, r.take(c, requests)) {
 			return
 		}
 		c.Next()
 	}
 }
+
+func (r *RateLimiter) fallbackRequest(c *gin.Context, tenantID, subject string) ratelimit.Request {
+	if subject != "" {
+		return ratelimit.Request{Policy: subjectFallbackPolicy, Key: tenantID + "#" + subject, Limit: r.fallback}
+	}
+	return ratelimit.Request{Policy: ipFallbackPolicy, Key: c.ClientIP(), Limit: r.fallback}
+}
+
+func (r *RateLimiter) take(c *gin.Context, requests []ratelimit.Request) ratelimit.Decision {
+	decision, err := r.backend.TakeAll(c.Request.Context(), requests)
+	if err == nil {
+		return decision
+	}
+	r.logger.Warn("rate limit backend unavailable, using local limits", slog.String("error", err.Error()))
+	if r.metrics != nil {
+		r.metrics.RateLimitBackendErrors.Inc()
+	}
+	decision, _ = r.local.TakeAll(c.Request.Context(), requests)
+	return decision
+}
+
+type limitKey struct {
+	bucket   string
+	override string
+}
+
+func (r *RateLimiter) keys(c *gin.Context, keyType, tenantID string) []limitKey {
+	principal, _ := authz.FromContext(c.Request.Context())
+	switch keyType {
+	case ratelimit.KeySubject:
+		if principal.Subject != "" {
+			return []limitKey{{bucket: tenantID + "#" + principal.Subject, override: principal.Subject}}
+		}
+	case ratelimit.KeyTenant:
+		return []limitKey{{bucket: tenantID, override: tenantID}}
+	case ratelimit.KeyStream:
+		streams := requestStreams(c)
+		if len(streams) > 0 {
+			keys := make([]limitKey, 0, len(streams))
+			for _, streamID := range streams {
+				keys = append(keys, limitKey{bucket: tenantID + "#" + streamID, override: streamID})
+			}
+			return keys
+		}
+	}
+	ip := c.ClientIP()
+	return []limitKey{{bucket: "ip#" + ip, override: ip}}
+}
+
+func (r *RateLimiter) apply(c *gin.Context, routes, tenantID string, decision ratelimit.Decision) bool {
+	if previous, ok := c.Get(rateLimitDecisionKey); ok {
+		decision = ratelimit.MoreRestrictive(previous.(ratelimit.Decision), decision)
+	}
+	c.Set(rateLimitDecisionKey, decision)
+	c.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(int(decision.Reset.Seconds())))
+	if decision.Allowed {
+		return true
+	}
+
+	if r.metrics != nil {
+		r.metrics.RecordRateLimited(decision.Policy, routes, tenantID)
+	}
+	r.logger.Debug("rate limited",
+		slog.String("policy", decision.Policy),
+		slog.String("tenant", tenantID),
+		slog.String("path", c.FullPath()),
+	)
+	retryAfter := int(decision.RetryAfter.Seconds())
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	httputil.TooManyRequests(c, "rate_limited", fmt.Sprintf("rate limit exceeded for policy %s", decision.Policy))
+	c.Abort()
+	return false
+}
+
+func requestStreams(c *gin.Context) []string {
+	if streamID := c.Param("streamId"); streamID != "" {
+		return []string{streamID}
+	}
+	if c.Request.Body == nil || c.Request.Method == http.MethodGet {
+		return nil
+	}
+	raw, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+	if err != nil {
+		return nil
+	}
+	return bodyStreams(raw)
+}
+
+func bodyStreams(raw []byte) []string {
+	type streamRef struct {
+		StreamID string `json:"stream_id"`
+	}
+	var refs []streamRef
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		_ = json.Unmarshal(trimmed, &refs)
+	} else {
+		var single streamRef
+		if json.Unmarshal(trimmed, &single) == nil {
+			refs = append(refs, single)
+		}
+	}
+	seen := map[string]bool{}
+	streams := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref.StreamID != "" && !seen[ref.StreamID] {
+			seen[ref.StreamID] = true
+			streams = append(streams, ref.StreamID)
+		}
+	}
+	return streams
+}
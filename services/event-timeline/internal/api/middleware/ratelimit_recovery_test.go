@@ -1,20 +1,31 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ratelimit"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
 )
 
 func TestRateLimitBlocksExcessRequests(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	r.Use(RateLimit(0.1, 1))
+	r.Use(NewRateLimiter(RateLimiterOptions{Fallback: ratelimit.Limit{Rate: 0.1, Burst: 1}}).Routes(ratelimit.RoutesAll))
 	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
 
 	req1 := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -22,13 +33,15 @@ func TestRateLimitBlocksExcessRequests(t *testing.T) {
 	rec1 := httptest.NewRecorder()
 	r.ServeHTTP(rec1, req1)
 	require.Equal(t, http.StatusOK, rec1.Code)
+	require.Equal(t, "1", rec1.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "0", rec1.Header().Get("RateLimit-Remaining"))
 
 	req2 := httptest.NewRequest(http.MethodGet, "/", nil)
 	req2.RemoteAddr = "10.0.0.1:1234"
 	rec2 := httptest.NewRecorder()
 	r.ServeHTTP(rec2, req2)
 	require.Equal(t, http.StatusTooManyRequests, rec2.Code)
-	require.Equal(t, "1", rec2.Header().Get("Retry-After"))
+	require.Equal(t, "10", rec2.Header().Get("Retry-After"))
 }
 
 func TestRecoveryHandlesPanics(t *testing.T) {
@@ -47,3 +60,97 @@ func TestRecoveryHandlesPanics(t *testing.T) {
 	require.Equal(t, http.StatusInternalServerError, rec.Code)
 	require.Contains(t, rec.Body.String(), "internal_error")
 }
+
+func TestRateLimiterPoliciesKeyedByTenantAndStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := observability.NewMetrics()
+	limiter := NewRateLimiter(RateLimiterOptions{
+		Policies: []ratelimit.Policy{
+			{Name: "ingest-tenant", Routes: ratelimit.RoutesIngest, Key: ratelimit.KeyTenant, Rate: 0.1, Burst: 3,
+				Overrides: map[string]ratelimit.Limit{"acme": {Rate: 0.1, Burst: 10}}},
+			{Name: "ingest-stream", Routes: ratelimit.RoutesIngest, Key: ratelimit.KeyStream, Rate: 0.1, Burst: 2},
+			{Name: "read-subject", Routes: ratelimit.RoutesRead, Key: ratelimit.KeySubject, Rate: 0.1, Burst: 1},
+		},
+		Fallback: ratelimit.Limit{Rate: 100, Burst: 100},
+		Metrics:  metrics,
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Clock:    &stepClock{now: time.Now()},
+	})
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx := tenant.WithTenant(c.Request.Context(), c.GetHeader("X-Tenant"))
+		ctx = authz.WithPrincipal(ctx, authz.Principal{Subject: c.GetHeader("X-Subject")})
+		c.Request = c.Request.WithContext(ctx)
+	})
+	r.POST("/events", limiter.Routes(ratelimit.RoutesIngest), func(c *gin.Context) {
+		var body struct {
+			StreamID string `json:"stream_id"`
+		}
+		require.NoError(t, c.ShouldBindJSON(&body), "body must still be readable")
+		c.Status(http.StatusCreated)
+	})
+	r.GET("/streams/:streamId/events", limiter.Routes(ratelimit.RoutesRead), func(c *gin.Context) { c.Status(http.StatusOK) })
+	serve := func(method, path, tenantID, subject, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-Tenant", tenantID)
+		req.Header.Set("X-Subject", subject)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusCreated, serve(http.MethodPost, "/events", "globex", "svc", `{"stream_id":"s1"}`).Code)
+	rec := serve(http.MethodPost, "/events", "globex", "svc", `{"stream_id":"s1"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	rec = serve(http.MethodPost, "/events", "globex", "svc", `{"stream_id":"s1"}`)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Contains(t, rec.Body.String(), "ingest-stream")
+
+	require.Equal(t, http.StatusCreated, serve(http.MethodPost, "/events", "globex", "svc", `{"stream_id":"s2"}`).Code)
+	rec = serve(http.MethodPost, "/events", "globex", "svc", `{"stream_id":"s3"}`)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code, "globex exhausted its tenant quota")
+	require.Contains(t, rec.Body.String(), "ingest-tenant")
+
+	require.Equal(t, http.StatusCreated, serve(http.MethodPost, "/events", "acme", "svc", `{"stream_id":"s1"}`).Code,
+		"streams are counted per tenant")
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusCreated, serve(http.MethodPost, "/events", "acme", "svc", `{"stream_id":"s`+strconv.Itoa(i+2)+`"}`).Code,
+			"acme override raises the tenant quota")
+	}
+
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, "/streams/s1/events", "globex", "reader", "").Code)
+	require.Equal(t, http.StatusTooManyRequests, serve(http.MethodGet, "/streams/s1/events", "globex", "reader", "").Code)
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, "/streams/s1/events", "globex", "other-reader", "").Code)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.RateLimitedTotal.WithLabelValues("ingest-stream", ratelimit.RoutesIngest, "globex"))+
+		testutil.ToFloat64(metrics.RateLimitedTotal.WithLabelValues("ingest-tenant", ratelimit.RoutesIngest, "globex")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.RateLimitedTotal.WithLabelValues("read-subject", ratelimit.RoutesRead, "globex")))
+}
+
+type failingBackend struct{}
+
+func (failingBackend) TakeAll(context.Context, []ratelimit.Request) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, errors.New("connection refused")
+}
+
+func TestRateLimiterFallsBackToLocalLimitsWhenBackendFails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := observability.NewMetrics()
+	r := gin.New()
+	r.Use(NewRateLimiter(RateLimiterOptions{
+		Fallback: ratelimit.Limit{Rate: 0.1, Burst: 1},
+		Backend:  failingBackend{},
+		Metrics:  metrics,
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Routes(ratelimit.RoutesAll))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.RateLimitBackendErrors))
+}
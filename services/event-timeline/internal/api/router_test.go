@@ -2,25 +2,41 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/accesslog"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/handlers"
 	adminhandlers "github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/handlers/admin"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/httputil"
+	mw "github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/api/middleware"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/apikey"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/export"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/integrity"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ratelimit"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/replay"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
 )
 
 type routerEventStore struct{}
 
-func (routerEventStore) PutEvent(context.Context, domain.Event) error         { return nil }
-func (routerEventStore) PutEventsBatch(context.Context, []domain.Event) error { return nil }
+func (routerEventStore) PutEvent(context.Context, domain.Event) error { return nil }
 func (routerEventStore) GetByEventID(context.Context, string) (domain.Event, error) {
 	return domain.Event{}, domain.ErrNotFound
 }
@@ -34,8 +50,8 @@ func (routerEventStore) QueryByStream(context.Context, string, int64, string, in
 
 type routerStreamStore struct{}
 
-func (routerStreamStore) ListStreams(context.Context, int32) ([]domain.Stream, error) {
-	return nil, nil
+func (routerStreamStore) ListStreams(context.Context, string, int32) (storage.StreamPage, error) {
+	return storage.StreamPage{}, nil
 }
 
 type fixedRouterGenerator struct{}
@@ -48,15 +64,16 @@ func TestNewGinRouterRegistersRoutes(t *testing.T) {
 	service := ingest.NewService(store, fixedRouterGenerator{}, clock.MockClock{Current: time.Now().UTC()}, metrics)
 
 	router := NewGinRouter(GinDependencies{
-		Logger:      slog.Default(),
-		Metrics:     metrics,
-		JWTSecret:   "secret",
-		RatePerSec:  10,
-		RateBurst:   10,
-		Ingest:      handlers.NewIngestHandler(service),
-		BatchIngest: handlers.NewBatchIngestHandler(service),
-		Stream:      handlers.NewStreamHandler(store),
-		Event:       handlers.NewEventHandler(store),
+		Logger:         slog.Default(),
+		Metrics:        metrics,
+		TokenValidator: mw.NewTokenValidator(mw.TokenValidatorOptions{HMACSecret: "secret"}),
+		RateLimiter:    mw.NewRateLimiter(mw.RateLimiterOptions{Fallback: ratelimit.Limit{Rate: 10, Burst: 10}}),
+		Ingest:         handlers.NewIngestHandler(service),
+		BatchIngest:    handlers.NewBatchIngestHandler(service),
+		Stream:         handlers.NewStreamHandler(store),
+		StreamList:     handlers.NewStreamListHandler(routerStreamStore{}),
+		Event:          handlers.NewEventHandler(store),
+		Proof:          handlers.NewProofHandler(integrity.NewCheckpointer(integrity.CheckpointerDependencies{EventStore: store})),
 	})
 
 	routes := router.Routes()
@@ -64,18 +81,181 @@ func TestNewGinRouterRegistersRoutes(t *testing.T) {
 }
 
 func TestNewEchoRouterRegistersAdminRoutes(t *testing.T) {
+	router := newTestEchoRouter()
+
+	routes := router.Routes()
+	require.NotEmpty(t, routes)
+	paths := make([]string, 0, len(routes))
+	for _, route := range routes {
+		paths = append(paths, route.Method+" "+route.Path)
+	}
+	require.Contains(t, paths, "POST /admin/import")
+	require.Contains(t, paths, "GET /admin/streams/:id/export")
+	require.Contains(t, paths, "POST /admin/export")
+	require.Contains(t, paths, "GET /admin/streams/:id/verify")
+	require.Contains(t, paths, "GET /admin/tenants")
+	require.Contains(t, paths, "DELETE /admin/streams/:id/data-key")
+	require.Contains(t, paths, "POST /admin/streams/:id/checkpoints")
+	require.Contains(t, paths, "GET /admin/access-log")
+	require.Contains(t, paths, "POST /admin/api-keys")
+	require.Contains(t, paths, "GET /admin/api-keys")
+	require.Contains(t, paths, "DELETE /admin/api-keys/:id")
+	require.Contains(t, paths, "POST /admin/api-keys/:id/rotate")
+}
+
+func newTestEchoRouter() *echo.Echo {
 	metrics := observability.NewMetrics()
 	store := routerEventStore{}
 	engine := replay.NewEngine(store, metrics)
 
-	router := NewEchoRouter(EchoDependencies{
-		Health:  adminhandlers.NewHealthHandler(store),
-		Ready:   adminhandlers.NewReadyHandler(),
-		Replay:  adminhandlers.NewReplayHandler(engine),
-		Streams: adminhandlers.NewStreamsHandler(routerStreamStore{}),
-		Metrics: adminhandlers.NewMetricsHandler(metrics),
+	return NewEchoRouter(EchoDependencies{
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		TokenValidator: mw.NewTokenValidator(mw.TokenValidatorOptions{HMACSecret: "secret"}),
+		Health:         adminhandlers.NewHealthHandler(store),
+		Ready:          adminhandlers.NewReadyHandler(),
+		Replay:         adminhandlers.NewReplayHandler(engine),
+		Streams:        adminhandlers.NewStreamsHandler(routerStreamStore{}),
+		Metrics:        adminhandlers.NewMetricsHandler(metrics),
+		Import:         adminhandlers.NewImportHandler(ingest.NewImporter(ingest.NewService(store, fixedRouterGenerator{}, clock.RealClock{}, metrics), slog.Default(), ingest.ImportOptions{})),
+		Export:         adminhandlers.NewExportHandler(export.NewExporter(store, clock.RealClock{}), slog.Default()),
+		Verify:         adminhandlers.NewVerifyHandler(integrity.NewVerifier(store)),
+		Checkpoint:     adminhandlers.NewCheckpointHandler(integrity.NewCheckpointer(integrity.CheckpointerDependencies{EventStore: store})),
+		DataKey:        adminhandlers.NewDataKeyHandler(nil),
+		AccessLog:      adminhandlers.NewAccessLogHandler(accesslog.NewReader(store)),
+		APIKeys:        adminhandlers.NewAPIKeyHandler(apikey.NewService(apikey.Dependencies{})),
 	})
+}
 
-	routes := router.Routes()
-	require.NotEmpty(t, routes)
+func signedToken(t *testing.T, extra jwt.MapClaims) string {
+	t.Helper()
+	claims := jwt.MapClaims{
+		"iss": "aevum",
+		"sub": "user-1",
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(5 * time.Minute).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	return "Bearer " + signed
+}
+
+func TestEchoAdminRoutesRequireScopes(t *testing.T) {
+	router := newTestEchoRouter()
+	serve := func(method, path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, "/admin/ready", "").Code)
+	require.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/admin/streams", "").Code)
+
+	rec := serve(http.MethodGet, "/admin/streams", signedToken(t, jwt.MapClaims{"scope": "events:read"}))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	var body httputil.ErrorBody
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, "insufficient_scope", body.Error.Code)
+
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, "/admin/streams", signedToken(t, jwt.MapClaims{"scope": "admin"})).Code)
+
+	for _, scope := range []string{"admin", "admin events:decrypt", "admin pii:read"} {
+		rec = serve(http.MethodGet, "/admin/streams/orders/export", signedToken(t, jwt.MapClaims{"scope": scope}))
+		require.Equal(t, http.StatusForbidden, rec.Code, "export with scope %q", scope)
+	}
+	rec = serve(http.MethodGet, "/admin/streams/orders/export", signedToken(t, jwt.MapClaims{"scope": "admin events:decrypt pii:read"}))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+}
+
+func TestGinRoutesEnforceScopesAndStreamGrants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := observability.NewMetrics()
+	store := routerEventStore{}
+	service := ingest.NewService(store, fixedRouterGenerator{}, clock.MockClock{Current: time.Now().UTC()}, metrics)
+	router := NewGinRouter(GinDependencies{
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Metrics:        metrics,
+		TokenValidator: mw.NewTokenValidator(mw.TokenValidatorOptions{HMACSecret: "secret"}),
+		RateLimiter:    mw.NewRateLimiter(mw.RateLimiterOptions{Fallback: ratelimit.Limit{Rate: 100, Burst: 100}}),
+		Ingest:         handlers.NewIngestHandler(service),
+		BatchIngest:    handlers.NewBatchIngestHandler(service),
+		Stream:         handlers.NewStreamHandler(store),
+		StreamList:     handlers.NewStreamListHandler(routerStreamStore{}),
+		Event:          handlers.NewEventHandler(store),
+		Proof:          handlers.NewProofHandler(integrity.NewCheckpointer(integrity.CheckpointerDependencies{EventStore: store})),
+	})
+	serve := func(method, path, body, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", auth)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	event := `{"stream_id":"orders-1","event_type":"created","payload":{},"idempotency_key":"k","occurred_at":"2026-02-12T10:00:00Z"}`
+
+	reader := signedToken(t, jwt.MapClaims{"scope": "events:read", "streams": []string{"account-*"}})
+	rec := serve(http.MethodPost, "/api/v1/events", event, reader)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Contains(t, rec.Body.String(), "insufficient_scope")
+
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/streams/account-1/events", "", reader).Code)
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/streams", "", reader).Code)
+	rec = serve(http.MethodGet, "/api/v1/streams/orders-1/events", "", reader)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Contains(t, rec.Body.String(), "stream_forbidden")
+
+	writer := signedToken(t, jwt.MapClaims{"scope": []string{"events:write"}, "streams": []string{"account-*"}})
+	rec = serve(http.MethodPost, "/api/v1/events", event, writer)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Contains(t, rec.Body.String(), "stream_forbidden")
+
+	writer = signedToken(t, jwt.MapClaims{"scope": "events:write"})
+	rec = serve(http.MethodPost, "/api/v1/events", event, writer)
+	require.Equal(t, http.StatusForbidden, rec.Code, "a token without a streams claim is granted no streams")
+	require.Contains(t, rec.Body.String(), "stream_forbidden")
+
+	writer = signedToken(t, jwt.MapClaims{"scope": "events:write", "streams": "*"})
+	require.Equal(t, http.StatusCreated, serve(http.MethodPost, "/api/v1/events", event, writer).Code)
+}
+
+func TestGinRateLimitsCallersBehindOneIPSeparately(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := observability.NewMetrics()
+	store := routerEventStore{}
+	router := NewGinRouter(GinDependencies{
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Metrics:        metrics,
+		TokenValidator: mw.NewTokenValidator(mw.TokenValidatorOptions{HMACSecret: "secret"}),
+		RateLimiter:    mw.NewRateLimiter(mw.RateLimiterOptions{Fallback: ratelimit.Limit{Rate: 0.01, Burst: 2}}),
+		Stream:         handlers.NewStreamHandler(store),
+		StreamList:     handlers.NewStreamListHandler(routerStreamStore{}),
+		Event:          handlers.NewEventHandler(store),
+	})
+	serve := func(auth string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/streams/orders-1/events", nil)
+		req.RemoteAddr = "10.0.0.1:443"
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 10; i++ {
+		token := signedToken(t, jwt.MapClaims{"sub": fmt.Sprintf("client-%d", i), "scope": "events:read", "streams": "*"})
+		require.Equal(t, http.StatusOK, serve(token), "client-%d shares the load balancer IP but has its own bucket", i)
+		require.Equal(t, http.StatusOK, serve(token))
+	}
+
+	token := signedToken(t, jwt.MapClaims{"sub": "client-0", "scope": "events:read", "streams": "*"})
+	require.Equal(t, http.StatusTooManyRequests, serve(token), "each subject is still limited")
+	require.Equal(t, http.StatusUnauthorized, serve(""))
 }
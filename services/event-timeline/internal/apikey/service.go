@@ -0,0 +1,308 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/identifier"
+)
+
+const (
+	keyPrefix            = "ak"
+	secretSize           = 32
+	SubjectPrefix        = "apikey:"
+	Issuer               = "aevum-api-key"
+	DefaultTouchInterval = time.Minute
+	MaxGracePeriod       = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidKey = errors.New("invalid api key")
+	ErrRevoked    = errors.New("api key revoked")
+	ErrExpired    = errors.New("api key expired")
+)
+
+// Keys only authenticate the public API, so admin and replay scopes
+// are not offered.
+var knownScopes = map[string]bool{
+	authz.ScopeEventsWrite: true,
+	authz.ScopeEventsRead:  true,
+	authz.ScopeDecrypt:     true,
+	authz.ScopePIIRead:     true,
+}
+
+type CreateInput struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	Streams   []string   `json:"streams"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type Dependencies struct {
+	Store         storage.APIKeyStore
+	IDs           identifier.Generator
+	Clock         clock.Clock
+	Logger        *slog.Logger
+	TouchInterval time.Duration
+}
+
+type Service struct {
+	store         storage.APIKeyStore
+	ids           identifier.Generator
+	clock         clock.Clock
+	logger        *slog.Logger
+	touchInterval time.Duration
+
+	mu      sync.Mutex
+	touched map[string]time.Time
+}
+
+func NewService(deps Dependencies) *Service {
+	if deps.IDs == nil {
+		deps.IDs = identifier.NewULIDGenerator()
+	}
+	if deps.Clock == nil {
+		deps.Clock = clock.RealClock{}
+	}
+	if deps.Logger == nil {
+		deps.Logger = slog.Default()
+	}
+	if deps.TouchInterval <= 0 {
+		deps.TouchInterval = DefaultTouchInterval
+	}
+	return &Service{
+		store:         deps.Store,
+		ids:           deps.IDs,
+		clock:         deps.Clock,
+		logger:        deps.Logger,
+		touchInterval: deps.TouchInterval,
+		touched:       map[string]time.Time{},
+	}
+}
+
+// Create returns the stored key and the only copy of the plaintext key.
+func (s *Service) Create(ctx context.Context, in CreateInput) (domain.APIKey, string, error) {
+	now := s.clock.Now().UTC()
+	if err := validateInput(in, now); err != nil {
+		return domain.APIKey{}, "", err
+	}
+	keyID, err := s.ids.New(now)
+	if err != nil {
+		return domain.APIKey{}, "", fmt.Errorf("generate api key id: %w", err)
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	key := domain.APIKey{
+		KeyID:      keyID,
+		TenantID:   tenant.FromContext(ctx),
+		Name:       in.Name,
+		Scopes:     in.Scopes,
+		Streams:    in.Streams,
+		SecretHash: hashSecret(secret),
+		CreatedAt:  now,
+		ExpiresAt:  in.ExpiresAt,
+	}
+	if err := s.store.CreateAPIKey(ctx, key); err != nil {
+		return domain.APIKey{}, "", err
+	}
+	return key, formatKey(keyID, secret), nil
+}
+
+func (s *Service) List(ctx context.Context) ([]domain.APIKey, error) {
+	tenantID := tenant.FromContext(ctx)
+	if tenant.IsCrossTenant(ctx) {
+		tenantID = ""
+	}
+	return s.store.ListAPIKeys(ctx, tenantID)
+}
+
+func (s *Service) Revoke(ctx context.Context, keyID string) (domain.APIKey, error) {
+	if _, err := s.get(ctx, keyID); err != nil {
+		return domain.APIKey{}, err
+	}
+	return s.store.RevokeAPIKey(ctx, keyID, s.clock.Now())
+}
+
+// Rotate issues a new secret for the key. The old secret stays valid for
+// grace, so clients can switch over; a zero grace invalidates it at once.
+func (s *Service) Rotate(ctx context.Context, keyID string, grace time.Duration) (domain.APIKey, string, error) {
+	if grace < 0 || grace > MaxGracePeriod {
+		return domain.APIKey{}, "", fmt.Errorf("grace period must be between 0 and %s: %w", MaxGracePeriod, domain.ErrValidation)
+	}
+	key, err := s.get(ctx, keyID)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	if key.Revoked() {
+		return domain.APIKey{}, "", fmt.Errorf("api key %s: %w", keyID, ErrRevoked)
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	now := s.clock.Now().UTC()
+	key.PreviousSecretHash = ""
+	key.PreviousExpiresAt = nil
+	if grace > 0 {
+		previousExpires := now.Add(grace)
+		key.PreviousSecretHash = key.SecretHash
+		key.PreviousExpiresAt = &previousExpires
+	}
+	key.SecretHash = hashSecret(secret)
+	key.RotatedAt = &now
+	if err := s.store.RotateAPIKey(ctx, key); err != nil {
+		return domain.APIKey{}, "", err
+	}
+	return key, formatKey(keyID, secret), nil
+}
+
+// Verify checks a presented key and returns the stored key it belongs to.
+func (s *Service) Verify(ctx context.Context, presented string) (domain.APIKey, error) {
+	keyID, secret, ok := parseKey(presented)
+	if !ok {
+		return domain.APIKey{}, ErrInvalidKey
+	}
+	key, err := s.store.GetAPIKey(ctx, keyID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.APIKey{}, ErrInvalidKey
+	}
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	now := s.clock.Now()
+	hash := hashSecret(secret)
+	matches := hashEqual(hash, key.SecretHash) ||
+		(key.PreviousSecretHash != "" && key.PreviousExpiresAt != nil && now.Before(*key.PreviousExpiresAt) && hashEqual(hash, key.PreviousSecretHash))
+	switch {
+	case !matches:
+		return domain.APIKey{}, ErrInvalidKey
+	case key.Revoked():
+		return domain.APIKey{}, ErrRevoked
+	case key.Expired(now):
+		return domain.APIKey{}, ErrExpired
+	}
+	s.touch(ctx, key.KeyID, now)
+	return key, nil
+}
+
+// Claims mirror the JWT claims the authorization code reads, so API key
+// requests pass through the same scope and stream checks as tokens.
+func Claims(key domain.APIKey, tenantClaim string) map[string]any {
+	claims := map[string]any{
+		"iss":   Issuer,
+		"sub":   SubjectPrefix + key.KeyID,
+		"scope": strings.Join(key.Scopes, " "),
+	}
+	streams := make([]any, 0, len(key.Streams))
+	for _, stream := range key.Streams {
+		streams = append(streams, stream)
+	}
+	claims["streams"] = streams
+	if tenantClaim != "" {
+		claims[tenantClaim] = key.TenantID
+	}
+	return claims
+}
+
+func (s *Service) get(ctx context.Context, keyID string) (domain.APIKey, error) {
+	key, err := s.store.GetAPIKey(ctx, keyID)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	if key.TenantID != tenant.FromContext(ctx) && !tenant.IsCrossTenant(ctx) {
+		return domain.APIKey{}, fmt.Errorf("api key not found: %w", domain.ErrNotFound)
+	}
+	return key, nil
+}
+
+// Last-used timestamps are written at most once per touchInterval per key
+// and replica, so busy keys do not cost a write per request.
+func (s *Service) touch(ctx context.Context, keyID string, now time.Time) {
+	s.mu.Lock()
+	if last, ok := s.touched[keyID]; ok && now.Sub(last) < s.touchInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.touched[keyID] = now
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+	defer cancel()
+	if err := s.store.TouchAPIKey(ctx, keyID, now); err != nil {
+		s.logger.Warn("api key last-used update failed", slog.String("key_id", keyID), slog.String("error", err.Error()))
+	}
+}
+
+func validateInput(in CreateInput, now time.Time) error {
+	if strings.TrimSpace(in.Name) == "" {
+		return fmt.Errorf("name is required: %w", domain.ErrValidation)
+	}
+	if len(in.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required: %w", domain.ErrValidation)
+	}
+	for _, scope := range in.Scopes {
+		if !knownScopes[scope] {
+			return fmt.Errorf("unknown scope %q: %w", scope, domain.ErrValidation)
+		}
+	}
+	if len(in.Streams) == 0 {
+		return fmt.Errorf("at least one stream pattern is required, \"*\" allows all streams: %w", domain.ErrValidation)
+	}
+	for _, stream := range in.Streams {
+		if stream == "" {
+			return fmt.Errorf("stream patterns must not be empty: %w", domain.ErrValidation)
+		}
+	}
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(now) {
+		return fmt.Errorf("expires_at must be in the future: %w", domain.ErrValidation)
+	}
+	return nil
+}
+
+func newSecret() (string, error) {
+	raw := make([]byte, secretSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate api key secret: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Secrets are 256 random bits, so an unsalted SHA-256 is enough; a slow
+// password hash would only add latency to every request.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func formatKey(keyID, secret string) string {
+	return keyPrefix + "_" + keyID + "_" + secret
+}
+
+func parseKey(presented string) (string, string, bool) {
+	parts := strings.SplitN(presented, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
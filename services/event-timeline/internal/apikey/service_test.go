@@ -0,0 +1,245 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	keys    map[string]domain.APIKey
+	touches int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: map[string]domain.APIKey{}}
+}
+
+func (s *memoryStore) CreateAPIKey(_ context.Context, key domain.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.KeyID] = key
+	return nil
+}
+
+func (s *memoryStore) GetAPIKey(_ context.Context, keyID string) (domain.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[keyID]
+	if !ok {
+		return domain.APIKey{}, domain.ErrNotFound
+	}
+	return key, nil
+}
+
+func (s *memoryStore) ListAPIKeys(_ context.Context, tenantID string) ([]domain.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := []domain.APIKey{}
+	for _, key := range s.keys {
+		if tenantID == "" || key.TenantID == tenantID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *memoryStore) RevokeAPIKey(_ context.Context, keyID string, at time.Time) (domain.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := s.keys[keyID]
+	key.RevokedAt = &at
+	s.keys[keyID] = key
+	return key, nil
+}
+
+func (s *memoryStore) RotateAPIKey(_ context.Context, rotated domain.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[rotated.KeyID] = rotated
+	return nil
+}
+
+func (s *memoryStore) TouchAPIKey(_ context.Context, keyID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := s.keys[keyID]
+	key.LastUsedAt = &at
+	s.keys[keyID] = key
+	s.touches++
+	return nil
+}
+
+type sequentialIDs struct{ n int }
+
+func (g *sequentialIDs) New(time.Time) (string, error) {
+	g.n++
+	return fmt.Sprintf("KEY%d", g.n), nil
+}
+
+func newTestService(store *memoryStore, clk *clock.MockClock) *Service {
+	return NewService(Dependencies{Store: store, IDs: &sequentialIDs{}, Clock: clk})
+}
+
+func TestCreateAndVerify(t *testing.T) {
+	store := newMemoryStore()
+	clk := &clock.MockClock{Current: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	service := newTestService(store, clk)
+	acme := tenant.WithTenant(context.Background(), "acme")
+
+	key, plaintext, err := service.Create(acme, CreateInput{Name: "producer", Scopes: []string{authz.ScopeEventsWrite}, Streams: []string{"orders-*"}})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(plaintext, "ak_KEY1_"))
+	require.Equal(t, "acme", key.TenantID)
+	require.NotContains(t, store.keys["KEY1"].SecretHash, strings.TrimPrefix(plaintext, "ak_KEY1_"))
+
+	verified, err := service.Verify(context.Background(), plaintext)
+	require.NoError(t, err)
+	require.Equal(t, "KEY1", verified.KeyID)
+	require.NotNil(t, store.keys["KEY1"].LastUsedAt)
+
+	principal := authz.PrincipalFromClaims(Claims(verified, "tenant_id"))
+	require.Equal(t, "apikey:KEY1", principal.Subject)
+	require.True(t, principal.HasScope(authz.ScopeEventsWrite))
+	require.False(t, principal.HasScope(authz.ScopeEventsRead))
+	require.True(t, principal.CanAccessStream("orders-1"))
+	require.False(t, principal.CanAccessStream("payments-1"))
+
+	_, err = service.Verify(context.Background(), plaintext+"x")
+	require.ErrorIs(t, err, ErrInvalidKey)
+	_, err = service.Verify(context.Background(), "ak_MISSING_secret")
+	require.ErrorIs(t, err, ErrInvalidKey)
+	_, err = service.Verify(context.Background(), "not-a-key")
+	require.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestCreateValidatesInput(t *testing.T) {
+	clk := &clock.MockClock{Current: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	service := newTestService(newMemoryStore(), clk)
+	past := clk.Current.Add(-time.Hour)
+
+	for _, in := range []CreateInput{
+		{Scopes: []string{authz.ScopeEventsRead}},
+		{Name: "no scopes"},
+		{Name: "admin", Scopes: []string{authz.ScopeAdmin}},
+		{Name: "streams omitted", Scopes: []string{authz.ScopeEventsRead}},
+		{Name: "no streams", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{}},
+		{Name: "empty stream", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{""}},
+		{Name: "expired", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"*"}, ExpiresAt: &past},
+	} {
+		_, _, err := service.Create(context.Background(), in)
+		require.ErrorIs(t, err, domain.ErrValidation, in.Name)
+	}
+}
+
+func TestRotateKeepsPreviousSecretForGracePeriod(t *testing.T) {
+	store := newMemoryStore()
+	clk := &clock.MockClock{Current: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	service := newTestService(store, clk)
+	ctx := context.Background()
+
+	_, original, err := service.Create(ctx, CreateInput{Name: "producer", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"*"}})
+	require.NoError(t, err)
+
+	_, rotated, err := service.Rotate(ctx, "KEY1", time.Hour)
+	require.NoError(t, err)
+	require.NotEqual(t, original, rotated)
+
+	_, err = service.Verify(ctx, original)
+	require.NoError(t, err)
+	_, err = service.Verify(ctx, rotated)
+	require.NoError(t, err)
+
+	clk.Current = clk.Current.Add(time.Hour)
+	_, err = service.Verify(ctx, original)
+	require.ErrorIs(t, err, ErrInvalidKey)
+
+	_, immediate, err := service.Rotate(ctx, "KEY1", 0)
+	require.NoError(t, err)
+	_, err = service.Verify(ctx, rotated)
+	require.ErrorIs(t, err, ErrInvalidKey)
+	_, err = service.Verify(ctx, immediate)
+	require.NoError(t, err)
+
+	_, _, err = service.Rotate(ctx, "KEY1", 30*24*time.Hour)
+	require.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestRevokedAndExpiredKeysAreRejected(t *testing.T) {
+	store := newMemoryStore()
+	clk := &clock.MockClock{Current: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	service := newTestService(store, clk)
+	ctx := context.Background()
+	expires := clk.Current.Add(time.Hour)
+
+	_, revoked, err := service.Create(ctx, CreateInput{Name: "a", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"*"}})
+	require.NoError(t, err)
+	_, expiring, err := service.Create(ctx, CreateInput{Name: "b", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"*"}, ExpiresAt: &expires})
+	require.NoError(t, err)
+
+	_, err = service.Revoke(ctx, "KEY1")
+	require.NoError(t, err)
+	_, err = service.Verify(ctx, revoked)
+	require.ErrorIs(t, err, ErrRevoked)
+	_, _, err = service.Rotate(ctx, "KEY1", 0)
+	require.ErrorIs(t, err, ErrRevoked)
+
+	clk.Current = expires
+	_, err = service.Verify(ctx, expiring)
+	require.ErrorIs(t, err, ErrExpired)
+}
+
+func TestManagementIsScopedToTenant(t *testing.T) {
+	store := newMemoryStore()
+	service := newTestService(store, &clock.MockClock{Current: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)})
+	acme := tenant.WithTenant(context.Background(), "acme")
+	globex := tenant.WithTenant(context.Background(), "globex")
+
+	_, _, err := service.Create(acme, CreateInput{Name: "a", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"*"}})
+	require.NoError(t, err)
+	_, _, err = service.Create(globex, CreateInput{Name: "b", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"*"}})
+	require.NoError(t, err)
+
+	keys, err := service.List(acme)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	keys, err = service.List(tenant.WithCrossTenant(context.Background()))
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	_, err = service.Revoke(globex, "KEY1")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, _, err = service.Rotate(globex, "KEY1", 0)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestLastUsedIsWrittenOncePerInterval(t *testing.T) {
+	store := newMemoryStore()
+	clk := &clock.MockClock{Current: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	service := newTestService(store, clk)
+	_, plaintext, err := service.Create(context.Background(), CreateInput{Name: "a", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"*"}})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Verify(context.Background(), plaintext)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, store.touches)
+
+	clk.Current = clk.Current.Add(DefaultTouchInterval)
+	_, err = service.Verify(context.Background(), plaintext)
+	require.NoError(t, err)
+	require.Equal(t, 2, store.touches)
+	require.Equal(t, clk.Current, *store.keys["KEY1"].LastUsedAt)
+}
@@ -0,0 +1,110 @@
+package authz
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	ScopeEventsWrite = "events:write"
+	ScopeEventsRead  = "events:read"
+	ScopeDecrypt     = "events:decrypt"
+	ScopePIIRead     = "pii:read"
+	ScopeReplayRun   = "replay:run"
+	ScopeAdmin       = "admin"
+)
+
+type Principal struct {
+	Subject        string
+	Scopes         []string
+	StreamPatterns []string
+}
+
+type principalContextKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(Principal)
+	return p, ok
+}
+
+func PrincipalFromClaims(claims map[string]any) Principal {
+	subject, _ := claims["sub"].(string)
+	scopes := claimStrings(claims["scope"])
+	scopes = append(scopes, claimStrings(claims["scp"])...)
+	return Principal{
+		Subject:        subject,
+		Scopes:         scopes,
+		StreamPatterns: claimStrings(claims["streams"]),
+	}
+}
+
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessStream fails closed: streams are only granted by patterns, so a
+// principal without a streams claim has no streams and "*" grants them all.
+func (p Principal) CanAccessStream(streamID string) bool {
+	for _, pattern := range p.StreamPatterns {
+		if matchPattern(pattern, streamID) {
+			return true
+		}
+	}
+	return false
+}
+
+func StreamAllowed(ctx context.Context, streamID string) (Principal, bool) {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return Principal{}, false
+	}
+	return p, p.CanAccessStream(streamID)
+}
+
+func claimStrings(v any) []string {
+	switch typed := v.(type) {
+	case string:
+		return strings.Fields(typed)
+	case []string:
+		return typed
+	case []any:
+		out := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func matchPattern(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(value, part)
+		if idx < 0 {
+			return false
+		}
+		value = value[idx+len(part):]
+	}
+	return strings.HasSuffix(value, last)
+}
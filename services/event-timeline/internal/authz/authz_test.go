@@ -0,0 +1,60 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrincipalFromClaims(t *testing.T) {
+	p := PrincipalFromClaims(map[string]any{
+		"sub":     "svc-1",
+		"scope":   "events:read events:write",
+		"scp":     []any{"replay:run"},
+		"streams": []any{"account-*", "orders-42"},
+	})
+	require.Equal(t, "svc-1", p.Subject)
+	require.True(t, p.HasScope(ScopeEventsRead))
+	require.True(t, p.HasScope(ScopeEventsWrite))
+	require.True(t, p.HasScope(ScopeReplayRun))
+	require.False(t, p.HasScope(ScopeAdmin))
+	require.True(t, p.CanAccessStream("account-123"))
+	require.True(t, p.CanAccessStream("orders-42"))
+	require.False(t, p.CanAccessStream("orders-420"))
+}
+
+func TestStreamGrants(t *testing.T) {
+	require.False(t, Principal{}.CanAccessStream("anything"), "no streams claim grants no streams")
+	require.False(t, PrincipalFromClaims(map[string]any{"scope": "events:read"}).CanAccessStream("a"))
+	require.False(t, PrincipalFromClaims(map[string]any{"streams": []any{}}).CanAccessStream("a"))
+	require.True(t, PrincipalFromClaims(map[string]any{"streams": "*"}).CanAccessStream("anything"))
+
+	cases := []struct {
+		pattern, stream string
+		want            bool
+	}{
+		{"*", "x", true},
+		{"account-*", "account-", true},
+		{"account-*", "acc", false},
+		{"*-eu", "orders-eu", true},
+		{"*-eu", "orders-us", false},
+		{"tenant-*-orders-*", "tenant-a-orders-1", true},
+		{"tenant-*-orders-*", "tenant-a-payments-1", false},
+		{"a*b", "ab", true},
+		{"a*bb", "ab", false},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.want, matchPattern(tc.pattern, tc.stream), "%s ~ %s", tc.pattern, tc.stream)
+	}
+}
+
+func TestStreamAllowedWithoutPrincipal(t *testing.T) {
+	_, ok := StreamAllowed(context.Background(), "orders-1")
+	require.False(t, ok, "requests without a principal are denied")
+
+	ctx := WithPrincipal(context.Background(), Principal{Subject: "svc", StreamPatterns: []string{"account-*"}})
+	p, ok := StreamAllowed(ctx, "orders-1")
+	require.False(t, ok)
+	require.Equal(t, "svc", p.Subject)
+}
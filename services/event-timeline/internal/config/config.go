@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
 	LogLevel        string
 	GinPort         int
 	EchoPort        int
+	GRPCPort        int
 	DynamoEndpoint  string
 	DynamoTable     string
 	AWSRegion       string
@@ -17,6 +19,35 @@ type Config struct {
 	OTELEndpoint    string
 	RateLimitBurst  int
 	RateLimitPerSec float64
+	ImportWorkers   int
+
+	RateLimitPolicyFile string
+	RateLimitRedisURL   string
+
+	JWKSURL             string
+	JWKSFile            string
+	JWKSRefreshInterval time.Duration
+	JWTAudience         string
+	JWTClockSkew        time.Duration
+	TenantClaim         string
+
+	CheckpointSigningKey string
+	CheckpointInterval   time.Duration
+
+	EncryptionMasterKey  string
+	EncryptionPolicyFile string
+
+	RedactionPolicyFile string
+
+	TLSCertFile        string
+	TLSKeyFile         string
+	TLSClientCAFile    string
+	TLSClientAuth      string
+	MTLSIdentitiesFile string
+
+	AccessLogEnabled       bool
+	AccessLogBufferSize    int
+	AccessLogFlushInterval time.Duration
 }
 
 func Load() (Config, error) {
@@ -24,6 +55,7 @@ func Load() (Config, error) {
 		LogLevel:        getEnv("AEVUM_LOG_LEVEL", "info"),
 		GinPort:         getEnvInt("AEVUM_GIN_PORT", 8080),
 		EchoPort:        getEnvInt("AEVUM_ECHO_PORT", 9090),
+		GRPCPort:        getEnvInt("AEVUM_GRPC_PORT", 50051),
 		DynamoEndpoint:  os.Getenv("AEVUM_DYNAMODB_ENDPOINT"),
 		DynamoTable:     getEnv("AEVUM_DYNAMODB_TABLE", "aevum-events"),
 		AWSRegion:       getEnv("AEVUM_AWS_REGION", "eu-central-1"),
@@ -31,16 +63,78 @@ func Load() (Config, error) {
 		OTELEndpoint:    getEnv("AEVUM_OTEL_ENDPOINT", "localhost:4317"),
 		RateLimitBurst:  getEnvInt("AEVUM_RATE_LIMIT_BURST", 100),
 		RateLimitPerSec: float64(getEnvInt("AEVUM_RATE_LIMIT_RATE", 50)),
+		ImportWorkers:   getEnvInt("AEVUM_IMPORT_WORKERS", 8),
+
+		RateLimitPolicyFile: os.Getenv("AEVUM_RATE_LIMIT_POLICY_FILE"),
+		RateLimitRedisURL:   os.Getenv("AEVUM_RATE_LIMIT_REDIS_URL"),
+
+		JWKSURL:             os.Getenv("AEVUM_JWKS_URL"),
+		JWKSFile:            os.Getenv("AEVUM_JWKS_FILE"),
+		JWKSRefreshInterval: getEnvDuration("AEVUM_JWKS_REFRESH_INTERVAL", 5*time.Minute),
+		JWTAudience:         os.Getenv("AEVUM_JWT_AUDIENCE"),
+		JWTClockSkew:        getEnvDuration("AEVUM_JWT_CLOCK_SKEW", 30*time.Second),
+		TenantClaim:         getEnv("AEVUM_TENANT_CLAIM", "tenant_id"),
+
+		CheckpointSigningKey: os.Getenv("AEVUM_CHECKPOINT_SIGNING_KEY"),
+		CheckpointInterval:   getEnvDuration("AEVUM_CHECKPOINT_INTERVAL", time.Hour),
+
+		EncryptionMasterKey:  os.Getenv("AEVUM_ENCRYPTION_MASTER_KEY"),
+		EncryptionPolicyFile: os.Getenv("AEVUM_ENCRYPTION_POLICY_FILE"),
+
+		RedactionPolicyFile: os.Getenv("AEVUM_REDACTION_POLICY_FILE"),
+
+		TLSCertFile:        os.Getenv("AEVUM_TLS_CERT_FILE"),
+		TLSKeyFile:         os.Getenv("AEVUM_TLS_KEY_FILE"),
+		TLSClientCAFile:    os.Getenv("AEVUM_TLS_CLIENT_CA_FILE"),
+		TLSClientAuth:      getEnv("AEVUM_TLS_CLIENT_AUTH", "request"),
+		MTLSIdentitiesFile: os.Getenv("AEVUM_MTLS_IDENTITIES_FILE"),
+
+		AccessLogEnabled:       getEnvBool("AEVUM_ACCESS_LOG_ENABLED", true),
+		AccessLogBufferSize:    getEnvInt("AEVUM_ACCESS_LOG_BUFFER_SIZE", 1024),
+		AccessLogFlushInterval: getEnvDuration("AEVUM_ACCESS_LOG_FLUSH_INTERVAL", time.Second),
+	}
+	if cfg.JWTSecret == "" && cfg.JWKSURL == "" && cfg.JWKSFile == "" {
+		return Config{}, fmt.Errorf("missing required env var AEVUM_JWT_SECRET, AEVUM_JWKS_URL or AEVUM_JWKS_FILE")
 	}
-	if cfg.JWTSecret == "" {
-		return Config{}, fmt.Errorf("missing required env var AEVUM_JWT_SECRET")
+	if cfg.JWKSURL != "" && cfg.JWKSFile != "" {
+		return Config{}, fmt.Errorf("AEVUM_JWKS_URL and AEVUM_JWKS_FILE are mutually exclusive")
 	}
-	if cfg.GinPort <= 0 || cfg.EchoPort <= 0 {
+	if cfg.JWKSRefreshInterval <= 0 {
+		return Config{}, fmt.Errorf("jwks refresh interval must be greater than zero")
+	}
+	if cfg.JWTClockSkew < 0 {
+		return Config{}, fmt.Errorf("jwt clock skew must not be negative")
+	}
+	if cfg.GinPort <= 0 || cfg.EchoPort <= 0 || cfg.GRPCPort <= 0 {
 		return Config{}, fmt.Errorf("invalid ports configured")
 	}
 	if cfg.RateLimitBurst <= 0 || cfg.RateLimitPerSec <= 0 {
 		return Config{}, fmt.Errorf("rate limit values must be greater than zero")
 	}
+	if cfg.ImportWorkers <= 0 {
+		return Config{}, fmt.Errorf("import workers must be greater than zero")
+	}
+	if cfg.CheckpointInterval < 0 {
+		return Config{}, fmt.Errorf("checkpoint interval must not be negative")
+	}
+	if cfg.EncryptionPolicyFile != "" && cfg.EncryptionMasterKey == "" {
+		return Config{}, fmt.Errorf("AEVUM_ENCRYPTION_POLICY_FILE requires AEVUM_ENCRYPTION_MASTER_KEY")
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return Config{}, fmt.Errorf("AEVUM_TLS_CERT_FILE and AEVUM_TLS_KEY_FILE must be set together")
+	}
+	if cfg.TLSClientCAFile != "" && cfg.TLSCertFile == "" {
+		return Config{}, fmt.Errorf("AEVUM_TLS_CLIENT_CA_FILE requires AEVUM_TLS_CERT_FILE")
+	}
+	if cfg.TLSClientAuth != "request" && cfg.TLSClientAuth != "require" {
+		return Config{}, fmt.Errorf("AEVUM_TLS_CLIENT_AUTH must be request or require")
+	}
+	if cfg.MTLSIdentitiesFile != "" && cfg.TLSClientCAFile == "" {
+		return Config{}, fmt.Errorf("AEVUM_MTLS_IDENTITIES_FILE requires AEVUM_TLS_CLIENT_CA_FILE")
+	}
+	if cfg.AccessLogBufferSize <= 0 || cfg.AccessLogFlushInterval <= 0 {
+		return Config{}, fmt.Errorf("access log buffer size and flush interval must be greater than zero")
+	}
 	if cfg.DynamoTable == "" {
 		return Config{}, fmt.Errorf("dynamodb table must not be empty")
 	}
@@ -69,3 +163,27 @@ func getEnvInt(key string, fallback int) int {
 	}
 	return parsed
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -21,3 +22,19 @@ func TestGetEnvIntFallbackOnInvalid(t *testing.T) {
 	t.Setenv("AEVUM_TEST_INT", "17")
 	require.Equal(t, 17, getEnvInt("AEVUM_TEST_INT", 42))
 }
+
+func TestGetEnvDurationFallbackOnInvalid(t *testing.T) {
+	t.Setenv("AEVUM_TEST_DURATION", "soon")
+	require.Equal(t, time.Hour, getEnvDuration("AEVUM_TEST_DURATION", time.Hour))
+
+	t.Setenv("AEVUM_TEST_DURATION", "15m")
+	require.Equal(t, 15*time.Minute, getEnvDuration("AEVUM_TEST_DURATION", time.Hour))
+}
+
+func TestGetEnvBoolFallbackOnInvalid(t *testing.T) {
+	t.Setenv("AEVUM_TEST_BOOL", "maybe")
+	require.True(t, getEnvBool("AEVUM_TEST_BOOL", true))
+
+	t.Setenv("AEVUM_TEST_BOOL", "false")
+	require.False(t, getEnvBool("AEVUM_TEST_BOOL", true))
+}
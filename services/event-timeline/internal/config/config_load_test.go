@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -10,6 +11,7 @@ func TestLoadSuccess(t *testing.T) {
 	t.Setenv("AEVUM_JWT_SECRET", "secret")
 	t.Setenv("AEVUM_GIN_PORT", "8081")
 	t.Setenv("AEVUM_ECHO_PORT", "9091")
+	t.Setenv("AEVUM_GRPC_PORT", "50052")
 	t.Setenv("AEVUM_RATE_LIMIT_BURST", "120")
 	t.Setenv("AEVUM_RATE_LIMIT_RATE", "75")
 	t.Setenv("AEVUM_DYNAMODB_TABLE", "events")
@@ -19,6 +21,7 @@ func TestLoadSuccess(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 8081, cfg.GinPort)
 	require.Equal(t, 9091, cfg.EchoPort)
+	require.Equal(t, 50052, cfg.GRPCPort)
 	require.Equal(t, 120, cfg.RateLimitBurst)
 	require.Equal(t, float64(75), cfg.RateLimitPerSec)
 	require.Equal(t, "events", cfg.DynamoTable)
@@ -31,6 +34,50 @@ func TestLoadValidationErrors(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("encryption policy without master key", func(t *testing.T) {
+		t.Setenv("AEVUM_JWT_SECRET", "secret")
+		t.Setenv("AEVUM_ENCRYPTION_POLICY_FILE", "/etc/aevum/encryption.json")
+		_, err := Load()
+		require.Error(t, err)
+	})
+
+	t.Run("tls key without certificate", func(t *testing.T) {
+		t.Setenv("AEVUM_JWT_SECRET", "secret")
+		t.Setenv("AEVUM_TLS_KEY_FILE", "/etc/aevum/tls.key")
+		_, err := Load()
+		require.Error(t, err)
+	})
+
+	t.Run("mtls identities without client ca", func(t *testing.T) {
+		t.Setenv("AEVUM_JWT_SECRET", "secret")
+		t.Setenv("AEVUM_TLS_CERT_FILE", "/etc/aevum/tls.crt")
+		t.Setenv("AEVUM_TLS_KEY_FILE", "/etc/aevum/tls.key")
+		t.Setenv("AEVUM_MTLS_IDENTITIES_FILE", "/etc/aevum/identities.json")
+		_, err := Load()
+		require.Error(t, err)
+
+		t.Setenv("AEVUM_TLS_CLIENT_CA_FILE", "/etc/aevum/ca.crt")
+		cfg, err := Load()
+		require.NoError(t, err)
+		require.Equal(t, "request", cfg.TLSClientAuth)
+
+		t.Setenv("AEVUM_TLS_CLIENT_AUTH", "optional")
+		_, err = Load()
+		require.Error(t, err)
+	})
+
+	t.Run("access log defaults and validation", func(t *testing.T) {
+		t.Setenv("AEVUM_JWT_SECRET", "secret")
+		cfg, err := Load()
+		require.NoError(t, err)
+		require.True(t, cfg.AccessLogEnabled)
+		require.Equal(t, 1024, cfg.AccessLogBufferSize)
+
+		t.Setenv("AEVUM_ACCESS_LOG_BUFFER_SIZE", "0")
+		_, err = Load()
+		require.Error(t, err)
+	})
+
 	t.Run("invalid ports", func(t *testing.T) {
 		t.Setenv("AEVUM_JWT_SECRET", "secret")
 		t.Setenv("AEVUM_GIN_PORT", "0")
@@ -47,6 +94,25 @@ func TestLoadValidationErrors(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("jwks replaces shared secret", func(t *testing.T) {
+		t.Setenv("AEVUM_JWT_SECRET", "")
+		t.Setenv("AEVUM_JWKS_FILE", "/etc/aevum/jwks.json")
+		t.Setenv("AEVUM_JWT_AUDIENCE", "event-timeline")
+		cfg, err := Load()
+		require.NoError(t, err)
+		require.Equal(t, "/etc/aevum/jwks.json", cfg.JWKSFile)
+		require.Equal(t, "event-timeline", cfg.JWTAudience)
+		require.Equal(t, 30*time.Second, cfg.JWTClockSkew)
+	})
+
+	t.Run("jwks url and file are exclusive", func(t *testing.T) {
+		t.Setenv("AEVUM_JWT_SECRET", "secret")
+		t.Setenv("AEVUM_JWKS_FILE", "/etc/aevum/jwks.json")
+		t.Setenv("AEVUM_JWKS_URL", "https://idp.example.com/jwks.json")
+		_, err := Load()
+		require.Error(t, err)
+	})
+
 	t.Run("empty otel endpoint uses fallback", func(t *testing.T) {
 		t.Setenv("AEVUM_JWT_SECRET", "secret")
 		t.Setenv("AEVUM_OTEL_ENDPOINT", "")
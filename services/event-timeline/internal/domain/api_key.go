@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type APIKey struct {
+	KeyID      string     `json:"key_id" dynamodbav:"KeyID"`
+	TenantID   string     `json:"tenant_id" dynamodbav:"TenantID"`
+	Name       string     `json:"name" dynamodbav:"Name"`
+	Scopes     []string   `json:"scopes" dynamodbav:"Scopes"`
+	Streams    []string   `json:"streams,omitempty" dynamodbav:"Streams,omitempty"`
+	SecretHash string     `json:"-" dynamodbav:"SecretHash"`
+	CreatedAt  time.Time  `json:"created_at" dynamodbav:"CreatedAt"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" dynamodbav:"ExpiresAt,omitempty"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty" dynamodbav:"RotatedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" dynamodbav:"RevokedAt,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" dynamodbav:"LastUsedAt,omitempty"`
+	// After a rotation the previous secret keeps working until
+	// PreviousExpiresAt so clients can roll over without downtime.
+	PreviousSecretHash string     `json:"-" dynamodbav:"PreviousSecretHash,omitempty"`
+	PreviousExpiresAt  *time.Time `json:"previous_expires_at,omitempty" dynamodbav:"PreviousExpiresAt,omitempty"`
+}
+
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+type canonicalEvent struct {
+	EventID        string            `json:"event_id"`
+	StreamID       string            `json:"stream_id"`
+	SequenceNumber int64             `json:"sequence_number"`
+	EventType      string            `json:"event_type"`
+	Payload        json.RawMessage   `json:"payload"`
+	Metadata       map[string]string `json:"metadata"`
+	IdempotencyKey string            `json:"idempotency_key"`
+	OccurredAt     string            `json:"occurred_at"`
+	IngestedAt     string            `json:"ingested_at"`
+	SchemaVersion  int               `json:"schema_version"`
+	PrevHash       string            `json:"prev_hash"`
+}
+
+func ChainEvent(event Event, prevHash string) (Event, error) {
+	event.PrevHash = prevHash
+	hash, err := ComputeEventHash(event)
+	if err != nil {
+		return Event{}, err
+	}
+	event.Hash = hash
+	return event, nil
+}
+
+func ComputeEventHash(event Event) (string, error) {
+	canonical, err := CanonicalEventJSON(event)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func CanonicalEventJSON(event Event) ([]byte, error) {
+	payload, err := canonicalPayload(event.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize payload of event %s: %w", event.EventID, err)
+	}
+	metadata := event.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return json.Marshal(canonicalEvent{
+		EventID:        event.EventID,
+		StreamID:       event.StreamID,
+		SequenceNumber: event.SequenceNumber,
+		EventType:      event.EventType,
+		Payload:        payload,
+		Metadata:       metadata,
+		IdempotencyKey: event.IdempotencyKey,
+		OccurredAt:     event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		IngestedAt:     event.IngestedAt.UTC().Format(time.RFC3339Nano),
+		SchemaVersion:  event.SchemaVersion,
+		PrevHash:       event.PrevHash,
+	})
+}
+
+func canonicalPayload(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
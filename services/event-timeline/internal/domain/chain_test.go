@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func chainTestEvent(t *testing.T, payload string) Event {
+	t.Helper()
+	event, err := NewEvent(NewEventInput{
+		EventID:        "evt-1",
+		StreamID:       "stream-1",
+		SequenceNumber: 1,
+		EventType:      "created",
+		Payload:        json.RawMessage(payload),
+		OccurredAt:     time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC),
+		IngestedAt:     time.Date(2026, 2, 14, 12, 0, 1, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	return event
+}
+
+func TestComputeEventHashIsCanonical(t *testing.T) {
+	a, err := ChainEvent(chainTestEvent(t, `{"b":1,"a":[1,2]}`), GenesisHash)
+	require.NoError(t, err)
+	b, err := ChainEvent(chainTestEvent(t, `{ "a": [1, 2], "b": 1 }`), GenesisHash)
+	require.NoError(t, err)
+	require.Len(t, a.Hash, 64)
+	require.Equal(t, a.Hash, b.Hash)
+	require.Equal(t, GenesisHash, a.PrevHash)
+}
+
+func TestComputeEventHashCoversFields(t *testing.T) {
+	base, err := ChainEvent(chainTestEvent(t, `{"amount":10}`), GenesisHash)
+	require.NoError(t, err)
+
+	tampered := base
+	tampered.Payload = json.RawMessage(`{"amount":11}`)
+	hash, err := ComputeEventHash(tampered)
+	require.NoError(t, err)
+	require.NotEqual(t, base.Hash, hash)
+
+	relinked, err := ChainEvent(base, base.Hash)
+	require.NoError(t, err)
+	require.NotEqual(t, base.Hash, relinked.Hash)
+
+	_, err = ComputeEventHash(Event{Payload: json.RawMessage(`{broken`)})
+	require.Error(t, err)
+}
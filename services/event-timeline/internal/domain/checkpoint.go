@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type Checkpoint struct {
+	CheckpointID string    `json:"checkpoint_id" dynamodbav:"CheckpointID"`
+	StreamID     string    `json:"stream_id" dynamodbav:"StreamID"`
+	FromSequence int64     `json:"from_sequence" dynamodbav:"FromSequence"`
+	ToSequence   int64     `json:"to_sequence" dynamodbav:"ToSequence"`
+	TreeSize     int64     `json:"tree_size" dynamodbav:"TreeSize"`
+	RootHash     string    `json:"root_hash" dynamodbav:"RootHash"`
+	KeyID        string    `json:"key_id" dynamodbav:"KeyID"`
+	PublicKey    string    `json:"public_key" dynamodbav:"PublicKey"`
+	Signature    string    `json:"signature" dynamodbav:"Signature"`
+	CreatedAt    time.Time `json:"created_at" dynamodbav:"CreatedAt"`
+}
+
+func (c Checkpoint) SigningPayload() []byte {
+	return []byte(fmt.Sprintf("aevum-checkpoint-v1\n%s\n%d\n%d\n%d\n%s\n%s\n",
+		c.StreamID,
+		c.FromSequence,
+		c.ToSequence,
+		c.TreeSize,
+		c.RootHash,
+		c.CreatedAt.UTC().Format(time.RFC3339Nano),
+	))
+}
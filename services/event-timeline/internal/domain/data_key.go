@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+type DataKey struct {
+	StreamID    string     `json:"stream_id" dynamodbav:"StreamID"`
+	KeyID       string     `json:"key_id" dynamodbav:"KeyID"`
+	MasterKeyID string     `json:"master_key_id" dynamodbav:"MasterKeyID"`
+	WrappedKey  []byte     `json:"-" dynamodbav:"WrappedKey,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" dynamodbav:"CreatedAt"`
+	ShreddedAt  *time.Time `json:"shredded_at,omitempty" dynamodbav:"ShreddedAt,omitempty"`
+}
+
+func (k DataKey) Shredded() bool {
+	return k.ShreddedAt != nil
+}
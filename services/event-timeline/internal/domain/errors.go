@@ -8,4 +8,6 @@ var (
 	ErrSequenceConflict = errors.New("sequence conflict")
 	ErrIdempotencyConflict = errors.New("idempotency conflict")
 	ErrUnauthorized     = errors.New("unauthorized")
+	ErrKeyShredded      = errors.New("data key shredded")
+	ErrKeyExists        = errors.New("data key already exists")
 )
@@ -8,6 +8,7 @@ import (
 
 type Event struct {
 	EventID        string            `json:"event_id" dynamodbav:"PK"`
+	TenantID       string            `json:"tenant_id,omitempty" dynamodbav:"-"`
 	SK             string            `json:"-" dynamodbav:"SK"`
 	StreamID       string            `json:"stream_id" dynamodbav:"GSI1PK"`
 	SequenceNumber int64             `json:"sequence_number" dynamodbav:"GSI1SK"`
@@ -19,6 +20,8 @@ type Event struct {
 	OccurredAt     time.Time         `json:"occurred_at"`
 	IngestedAt     time.Time         `json:"ingested_at"`
 	SchemaVersion  int               `json:"schema_version"`
+	PrevHash       string            `json:"prev_hash,omitempty" dynamodbav:"PrevHash,omitempty"`
+	Hash           string            `json:"hash,omitempty" dynamodbav:"Hash,omitempty"`
 }
 
 type NewEventInput struct {
@@ -1,6 +1,7 @@
 package domain
 
 type Stream struct {
+	TenantID       string `json:"tenant_id,omitempty"`
 	StreamID       string `json:"stream_id"`
 	LatestSequence int64  `json:"latest_sequence"`
 }
@@ -0,0 +1,55 @@
+package encryption
+
+import (
+	"context"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+)
+
+type DecryptingEventStore struct {
+	storage.EventStore
+	service *Service
+}
+
+func NewDecryptingEventStore(store storage.EventStore, service *Service) storage.EventStore {
+	if !service.Enabled() {
+		return store
+	}
+	return &DecryptingEventStore{EventStore: store, service: service}
+}
+
+func (s *DecryptingEventStore) GetByEventID(ctx context.Context, eventID string) (domain.Event, error) {
+	event, err := s.EventStore.GetByEventID(ctx, eventID)
+	if err != nil || !canDecrypt(ctx) {
+		return event, err
+	}
+	return s.service.DecryptEvent(ctx, event)
+}
+
+func (s *DecryptingEventStore) FindByIdempotencyKey(ctx context.Context, streamID, key string) (domain.Event, error) {
+	event, err := s.EventStore.FindByIdempotencyKey(ctx, streamID, key)
+	if err != nil || !canDecrypt(ctx) {
+		return event, err
+	}
+	return s.service.DecryptEvent(ctx, event)
+}
+
+func (s *DecryptingEventStore) QueryByStream(ctx context.Context, streamID string, fromSequence int64, direction string, limit int32) ([]domain.Event, int64, bool, error) {
+	events, next, hasMore, err := s.EventStore.QueryByStream(ctx, streamID, fromSequence, direction, limit)
+	if err != nil || !canDecrypt(ctx) {
+		return events, next, hasMore, err
+	}
+	for i := range events {
+		if events[i], err = s.service.DecryptEvent(ctx, events[i]); err != nil {
+			return nil, 0, false, err
+		}
+	}
+	return events, next, hasMore, nil
+}
+
+func canDecrypt(ctx context.Context) bool {
+	principal, ok := authz.FromContext(ctx)
+	return ok && principal.HasScope(authz.ScopeDecrypt)
+}
@@ -0,0 +1,92 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+const masterKeySize = 32
+
+type KeyWrapper interface {
+	KeyID() string
+	Wrap(plaintext, aad []byte) ([]byte, error)
+	Unwrap(wrapped, aad []byte) ([]byte, error)
+}
+
+type LocalKMS struct {
+	keyID string
+	aead  cipher.AEAD
+}
+
+func ParseMasterKey(encoded string) (*LocalKMS, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode encryption master key: %w", err)
+	}
+	if len(raw) != masterKeySize {
+		return nil, fmt.Errorf("encryption master key must be %d bytes", masterKeySize)
+	}
+	return NewLocalKMS(raw)
+}
+
+func NewLocalKMS(key []byte) (*LocalKMS, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &LocalKMS{keyID: hex.EncodeToString(sum[:8]), aead: aead}, nil
+}
+
+func (k *LocalKMS) KeyID() string {
+	return k.keyID
+}
+
+func (k *LocalKMS) Wrap(plaintext, aad []byte) ([]byte, error) {
+	return seal(k.aead, plaintext, aad)
+}
+
+func (k *LocalKMS) Unwrap(wrapped, aad []byte) ([]byte, error) {
+	return open(k.aead, wrapped, aad)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("init aes: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("init gcm: %w", err)
+	}
+	return aead, nil
+}
+
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,50 @@
+package encryption
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const anyEventType = "*"
+
+type Policy struct {
+	Fields map[string][]string `json:"fields"`
+}
+
+func LoadPolicy(path string) (Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, fmt.Errorf("read encryption policy: %w", err)
+	}
+	var policy Policy
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		return Policy{}, fmt.Errorf("decode encryption policy: %w", err)
+	}
+	for eventType, paths := range policy.Fields {
+		for _, path := range paths {
+			if path == "" || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+				return Policy{}, fmt.Errorf("encryption policy %q: invalid path %q", eventType, path)
+			}
+		}
+	}
+	return policy, nil
+}
+
+func (p Policy) PathsFor(eventType string) []string {
+	paths := append([]string{}, p.Fields[anyEventType]...)
+	if eventType != anyEventType {
+		paths = append(paths, p.Fields[eventType]...)
+	}
+	return paths
+}
+
+func (p Policy) Empty() bool {
+	for _, paths := range p.Fields {
+		if len(paths) > 0 {
+			return false
+		}
+	}
+	return true
+}
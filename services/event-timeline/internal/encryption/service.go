@@ -0,0 +1,386 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+const (
+	EnvelopeField      = "$enc"
+	envelopeVersion    = "v1"
+	dataKeySize        = 32
+	DefaultKeyCacheTTL = time.Minute
+)
+
+var ErrDisabled = errors.New("payload encryption is not configured")
+
+type Dependencies struct {
+	Keys        storage.DataKeyStore
+	KMS         KeyWrapper
+	Policy      Policy
+	Clock       clock.Clock
+	KeyCacheTTL time.Duration
+}
+
+type Service struct {
+	keys     storage.DataKeyStore
+	kms      KeyWrapper
+	policy   Policy
+	clock    clock.Clock
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedKey
+}
+
+type cachedKey struct {
+	keyID   string
+	aead    cipher.AEAD
+	expires time.Time
+}
+
+func NewService(deps Dependencies) *Service {
+	if deps.Clock == nil {
+		deps.Clock = clock.RealClock{}
+	}
+	if deps.KeyCacheTTL <= 0 {
+		deps.KeyCacheTTL = DefaultKeyCacheTTL
+	}
+	return &Service{
+		keys:     deps.Keys,
+		kms:      deps.KMS,
+		policy:   deps.Policy,
+		clock:    deps.Clock,
+		cacheTTL: deps.KeyCacheTTL,
+		cache:    map[string]cachedKey{},
+	}
+}
+
+func (s *Service) Enabled() bool {
+	return s != nil && s.kms != nil && s.keys != nil
+}
+
+func (s *Service) EncryptPayload(ctx context.Context, streamID, eventType string, payload json.RawMessage) (json.RawMessage, error) {
+	if !s.Enabled() {
+		return payload, nil
+	}
+	paths := s.policy.PathsFor(eventType)
+	if len(paths) == 0 {
+		return payload, nil
+	}
+	doc, err := decodePayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var key cachedKey
+	changed := false
+	for _, path := range paths {
+		doc, err = transformPath(doc, strings.Split(path, "."), func(value any) (any, error) {
+			if isEnvelope(value) {
+				return value, nil
+			}
+			if key.aead == nil {
+				if key, err = s.dataKey(ctx, streamID, true); err != nil {
+					return nil, err
+				}
+			}
+			plaintext, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("marshal field %s: %w", path, err)
+			}
+			sealed, err := seal(key.aead, plaintext, fieldAAD(ctx, streamID, path))
+			if err != nil {
+				return nil, err
+			}
+			changed = true
+			return map[string]any{EnvelopeField: envelopeVersion + ":" + key.keyID + ":" + base64.RawStdEncoding.EncodeToString(sealed)}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !changed {
+		return payload, nil
+	}
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	return encoded, nil
+}
+
+func (s *Service) DecryptEvent(ctx context.Context, event domain.Event) (domain.Event, error) {
+	if !s.Enabled() || !bytes.Contains(event.Payload, []byte(`"`+EnvelopeField+`"`)) {
+		return event, nil
+	}
+	if event.TenantID != "" {
+		ctx = tenant.WithTenant(ctx, event.TenantID)
+	}
+	doc, err := decodePayload(event.Payload)
+	if err != nil {
+		return domain.Event{}, err
+	}
+	var (
+		key      cachedKey
+		keyErr   error
+		unusable bool
+	)
+	doc, err = walkEnvelopes(doc, nil, func(path []string, envelope string) (any, bool, error) {
+		if unusable {
+			return nil, false, nil
+		}
+		keyID, sealed, err := parseEnvelope(envelope)
+		if err != nil {
+			return nil, false, err
+		}
+		if key.aead == nil {
+			key, keyErr = s.dataKey(ctx, event.StreamID, false)
+			if errors.Is(keyErr, domain.ErrKeyShredded) || errors.Is(keyErr, domain.ErrNotFound) {
+				unusable = true
+				return nil, false, nil
+			}
+			if keyErr != nil {
+				return nil, false, keyErr
+			}
+		}
+		if keyID != key.keyID {
+			return nil, false, fmt.Errorf("field %s was encrypted with unknown data key %s", strings.Join(path, "."), keyID)
+		}
+		plaintext, err := open(key.aead, sealed, fieldAAD(ctx, event.StreamID, strings.Join(path, ".")))
+		if err != nil {
+			return nil, false, fmt.Errorf("field %s: %w", strings.Join(path, "."), err)
+		}
+		var value any
+		if err := unmarshalUseNumber(plaintext, &value); err != nil {
+			return nil, false, fmt.Errorf("decode field %s: %w", strings.Join(path, "."), err)
+		}
+		return value, true, nil
+	})
+	if err != nil {
+		return domain.Event{}, err
+	}
+	if unusable {
+		return event, nil
+	}
+	decoded, err := json.Marshal(doc)
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+	event.Payload = decoded
+	return event, nil
+}
+
+func (s *Service) Shred(ctx context.Context, streamID string) (domain.DataKey, error) {
+	if !s.Enabled() {
+		return domain.DataKey{}, ErrDisabled
+	}
+	dataKey, err := s.keys.ShredDataKey(ctx, streamID, s.clock.Now())
+	s.mu.Lock()
+	delete(s.cache, cacheKey(ctx, streamID))
+	s.mu.Unlock()
+	if err != nil {
+		return domain.DataKey{}, err
+	}
+	return dataKey, nil
+}
+
+// The key record is read on every call so a shred on another replica applies at once.
+func (s *Service) dataKey(ctx context.Context, streamID string, create bool) (cachedKey, error) {
+	id := cacheKey(ctx, streamID)
+	dataKey, err := s.keys.GetDataKey(ctx, streamID)
+	if errors.Is(err, domain.ErrNotFound) && create {
+		dataKey, err = s.createDataKey(ctx, streamID)
+	}
+	if err != nil {
+		return cachedKey{}, err
+	}
+	if dataKey.Shredded() {
+		s.mu.Lock()
+		delete(s.cache, id)
+		s.mu.Unlock()
+		return cachedKey{}, fmt.Errorf("stream %s: %w", streamID, domain.ErrKeyShredded)
+	}
+
+	now := s.clock.Now()
+	s.mu.Lock()
+	cached, ok := s.cache[id]
+	s.mu.Unlock()
+	if ok && cached.keyID == dataKey.KeyID && now.Before(cached.expires) {
+		return cached, nil
+	}
+	if dataKey.MasterKeyID != s.kms.KeyID() {
+		return cachedKey{}, fmt.Errorf("data key of stream %s is wrapped by unknown master key %s", streamID, dataKey.MasterKeyID)
+	}
+	raw, err := s.kms.Unwrap(dataKey.WrappedKey, dataKeyAAD(ctx, streamID, dataKey.KeyID))
+	if err != nil {
+		return cachedKey{}, fmt.Errorf("unwrap data key: %w", err)
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return cachedKey{}, err
+	}
+	cached = cachedKey{keyID: dataKey.KeyID, aead: aead, expires: now.Add(s.cacheTTL)}
+	s.mu.Lock()
+	s.cache[id] = cached
+	s.mu.Unlock()
+	return cached, nil
+}
+
+func (s *Service) createDataKey(ctx context.Context, streamID string) (domain.DataKey, error) {
+	raw := make([]byte, dataKeySize)
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return domain.DataKey{}, fmt.Errorf("generate data key: %w", err)
+	}
+	if _, err := rand.Read(idBytes); err != nil {
+		return domain.DataKey{}, fmt.Errorf("generate data key id: %w", err)
+	}
+	keyID := hex.EncodeToString(idBytes)
+	wrapped, err := s.kms.Wrap(raw, dataKeyAAD(ctx, streamID, keyID))
+	if err != nil {
+		return domain.DataKey{}, fmt.Errorf("wrap data key: %w", err)
+	}
+	dataKey := domain.DataKey{
+		StreamID:    streamID,
+		KeyID:       keyID,
+		MasterKeyID: s.kms.KeyID(),
+		WrappedKey:  wrapped,
+		CreatedAt:   s.clock.Now(),
+	}
+	err = s.keys.CreateDataKey(ctx, dataKey)
+	if errors.Is(err, domain.ErrKeyExists) {
+		return s.keys.GetDataKey(ctx, streamID)
+	}
+	if err != nil {
+		return domain.DataKey{}, err
+	}
+	return dataKey, nil
+}
+
+func cacheKey(ctx context.Context, streamID string) string {
+	return tenant.FromContext(ctx) + "#" + streamID
+}
+
+func dataKeyAAD(ctx context.Context, streamID, keyID string) []byte {
+	return []byte("aevum-dek-v1\n" + tenant.FromContext(ctx) + "\n" + streamID + "\n" + keyID)
+}
+
+func fieldAAD(ctx context.Context, streamID, path string) []byte {
+	return []byte("aevum-field-v1\n" + tenant.FromContext(ctx) + "\n" + streamID + "\n" + path)
+}
+
+func decodePayload(payload json.RawMessage) (any, error) {
+	var doc any
+	if err := unmarshalUseNumber(payload, &doc); err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+	return doc, nil
+}
+
+func unmarshalUseNumber(raw []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func transformPath(value any, segments []string, fn func(any) (any, error)) (any, error) {
+	if len(segments) == 0 {
+		return fn(value)
+	}
+	switch typed := value.(type) {
+	case map[string]any:
+		if isEnvelope(typed) {
+			return typed, nil
+		}
+		child, ok := typed[segments[0]]
+		if !ok {
+			return typed, nil
+		}
+		updated, err := transformPath(child, segments[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		typed[segments[0]] = updated
+		return typed, nil
+	case []any:
+		for i, item := range typed {
+			updated, err := transformPath(item, segments, fn)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = updated
+		}
+		return typed, nil
+	default:
+		return value, nil
+	}
+}
+
+func walkEnvelopes(value any, path []string, fn func(path []string, envelope string) (any, bool, error)) (any, error) {
+	switch typed := value.(type) {
+	case map[string]any:
+		if isEnvelope(typed) {
+			replacement, ok, err := fn(path, typed[EnvelopeField].(string))
+			if err != nil || !ok {
+				return typed, err
+			}
+			return replacement, nil
+		}
+		for key, child := range typed {
+			updated, err := walkEnvelopes(child, append(path[:len(path):len(path)], key), fn)
+			if err != nil {
+				return nil, err
+			}
+			typed[key] = updated
+		}
+		return typed, nil
+	case []any:
+		for i, item := range typed {
+			updated, err := walkEnvelopes(item, path, fn)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = updated
+		}
+		return typed, nil
+	default:
+		return value, nil
+	}
+}
+
+func isEnvelope(value any) bool {
+	obj, ok := value.(map[string]any)
+	if !ok || len(obj) != 1 {
+		return false
+	}
+	_, ok = obj[EnvelopeField].(string)
+	return ok
+}
+
+func parseEnvelope(envelope string) (string, []byte, error) {
+	parts := strings.SplitN(envelope, ":", 3)
+	if len(parts) != 3 || parts[0] != envelopeVersion {
+		return "", nil, fmt.Errorf("unsupported encrypted field format")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, fmt.Errorf("decode encrypted field: %w", err)
+	}
+	return parts[1], sealed, nil
+}
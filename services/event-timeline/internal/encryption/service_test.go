@@ -0,0 +1,244 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+type memoryKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]domain.DataKey
+}
+
+func newMemoryKeyStore() *memoryKeyStore {
+	return &memoryKeyStore{keys: map[string]domain.DataKey{}}
+}
+
+func (s *memoryKeyStore) id(ctx context.Context, streamID string) string {
+	return tenant.FromContext(ctx) + "#" + streamID
+}
+
+func (s *memoryKeyStore) GetDataKey(ctx context.Context, streamID string) (domain.DataKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[s.id(ctx, streamID)]
+	if !ok {
+		return domain.DataKey{}, domain.ErrNotFound
+	}
+	return key, nil
+}
+
+func (s *memoryKeyStore) CreateDataKey(ctx context.Context, key domain.DataKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[s.id(ctx, key.StreamID)]; ok {
+		return domain.ErrKeyExists
+	}
+	s.keys[s.id(ctx, key.StreamID)] = key
+	return nil
+}
+
+func (s *memoryKeyStore) ShredDataKey(ctx context.Context, streamID string, at time.Time) (domain.DataKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[s.id(ctx, streamID)]
+	if !ok {
+		return domain.DataKey{}, domain.ErrNotFound
+	}
+	if !key.Shredded() {
+		key.ShreddedAt = &at
+		key.WrappedKey = nil
+		s.keys[s.id(ctx, streamID)] = key
+	}
+	return key, nil
+}
+
+func testMasterKey(t *testing.T) *LocalKMS {
+	t.Helper()
+	kms, err := ParseMasterKey(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	require.NoError(t, err)
+	return kms
+}
+
+func newTestService(t *testing.T, keys *memoryKeyStore) *Service {
+	t.Helper()
+	return NewService(Dependencies{
+		Keys: keys,
+		KMS:  testMasterKey(t),
+		Policy: Policy{Fields: map[string][]string{
+			"*":              {"customer.email"},
+			"card_added":     {"card.number", "holders.ssn"},
+			"not_configured": nil,
+		}},
+		Clock: clock.MockClock{Current: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+	})
+}
+
+const cardPayload = `{"customer":{"email":"ada@example.com","tier":"gold"},"card":{"number":"4111111111111111","brand":"visa"},"holders":[{"ssn":"123-45-6789","name":"Ada"},{"ssn":987654321,"name":"Bob"}],"amount":12.50}`
+
+func TestEncryptPayloadSealsConfiguredPaths(t *testing.T) {
+	keys := newMemoryKeyStore()
+	service := newTestService(t, keys)
+	ctx := context.Background()
+
+	encrypted, err := service.EncryptPayload(ctx, "cards-1", "card_added", json.RawMessage(cardPayload))
+	require.NoError(t, err)
+	for _, secret := range []string{"ada@example.com", "4111111111111111", "123-45-6789", "987654321"} {
+		require.NotContains(t, string(encrypted), secret)
+	}
+	for _, visible := range []string{"gold", "visa", "Ada", "Bob", "12.50"} {
+		require.Contains(t, string(encrypted), visible)
+	}
+	require.Len(t, keys.keys, 1)
+
+	again, err := service.EncryptPayload(ctx, "cards-1", "card_added", encrypted)
+	require.NoError(t, err)
+	require.Equal(t, string(encrypted), string(again), "sealed fields are not sealed twice")
+
+	event, err := service.DecryptEvent(ctx, domain.Event{StreamID: "cards-1", Payload: encrypted})
+	require.NoError(t, err)
+	require.JSONEq(t, cardPayload, string(event.Payload))
+
+	plain, err := service.EncryptPayload(ctx, "other", "not_configured", json.RawMessage(`{"card":{"number":"1"}}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"card":{"number":"1"}}`, string(plain))
+	require.Len(t, keys.keys, 1, "no data key is created when nothing is sealed")
+}
+
+func TestCiphertextIsBoundToTenantAndStream(t *testing.T) {
+	keys := newMemoryKeyStore()
+	service := newTestService(t, keys)
+	acme := tenant.WithTenant(context.Background(), "acme")
+
+	encrypted, err := service.EncryptPayload(acme, "s1", "signup", json.RawMessage(`{"customer":{"email":"a@acme.test"}}`))
+	require.NoError(t, err)
+
+	event, err := service.DecryptEvent(context.Background(), domain.Event{TenantID: "acme", StreamID: "s1", Payload: encrypted})
+	require.NoError(t, err)
+	require.Contains(t, string(event.Payload), "a@acme.test")
+
+	_, err = service.EncryptPayload(context.Background(), "s1", "signup", json.RawMessage(`{"customer":{"email":"x"}}`))
+	require.NoError(t, err)
+	require.Len(t, keys.keys, 2, "each tenant stream gets its own data key")
+
+	_, err = service.DecryptEvent(context.Background(), domain.Event{StreamID: "s1", Payload: encrypted})
+	require.Error(t, err, "ciphertext copied to another tenant's stream must not decrypt")
+}
+
+func TestShredMakesFieldsUnreadable(t *testing.T) {
+	keys := newMemoryKeyStore()
+	service := newTestService(t, keys)
+	ctx := context.Background()
+	encrypted, err := service.EncryptPayload(ctx, "s1", "signup", json.RawMessage(`{"customer":{"email":"a@b.test"}}`))
+	require.NoError(t, err)
+
+	shredded, err := service.Shred(ctx, "s1")
+	require.NoError(t, err)
+	require.True(t, shredded.Shredded())
+	require.Empty(t, keys.keys["default#s1"].WrappedKey)
+
+	event, err := service.DecryptEvent(ctx, domain.Event{StreamID: "s1", Payload: encrypted})
+	require.NoError(t, err)
+	require.Equal(t, string(encrypted), string(event.Payload), "shredded fields stay sealed")
+
+	_, err = service.EncryptPayload(ctx, "s1", "signup", json.RawMessage(`{"customer":{"email":"new@b.test"}}`))
+	require.ErrorIs(t, err, domain.ErrKeyShredded)
+
+	_, err = service.Shred(ctx, "never-encrypted")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = (*Service)(nil).Shred(ctx, "s1")
+	require.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestShredOnOneReplicaStopsTheOthers(t *testing.T) {
+	keys := newMemoryKeyStore()
+	writer := newTestService(t, keys)
+	reader := newTestService(t, keys)
+	ctx := context.Background()
+
+	encrypted, err := writer.EncryptPayload(ctx, "s1", "signup", json.RawMessage(`{"customer":{"email":"a@b.test"}}`))
+	require.NoError(t, err)
+	event, err := reader.DecryptEvent(ctx, domain.Event{StreamID: "s1", Payload: encrypted})
+	require.NoError(t, err)
+	require.Contains(t, string(event.Payload), "a@b.test", "both replicas hold the key in their cache")
+
+	_, err = reader.Shred(ctx, "s1")
+	require.NoError(t, err)
+
+	_, err = writer.EncryptPayload(ctx, "s1", "signup", json.RawMessage(`{"customer":{"email":"new@b.test"}}`))
+	require.ErrorIs(t, err, domain.ErrKeyShredded, "a cached key is not used once the store holds the tombstone")
+	event, err = writer.DecryptEvent(ctx, domain.Event{StreamID: "s1", Payload: encrypted})
+	require.NoError(t, err)
+	require.Equal(t, string(encrypted), string(event.Payload), "the other replica stops decrypting at once")
+}
+
+type staticEventStore struct {
+	domain.Event
+}
+
+func (s staticEventStore) PutEvent(context.Context, domain.Event) error { return nil }
+func (s staticEventStore) GetByEventID(context.Context, string) (domain.Event, error) {
+	return s.Event, nil
+}
+func (s staticEventStore) FindByIdempotencyKey(context.Context, string, string) (domain.Event, error) {
+	return s.Event, nil
+}
+func (s staticEventStore) GetLatestSequence(context.Context, string) (int64, error) { return 1, nil }
+func (s staticEventStore) QueryByStream(context.Context, string, int64, string, int32) ([]domain.Event, int64, bool, error) {
+	return []domain.Event{s.Event}, 2, false, nil
+}
+
+func TestDecryptingEventStoreRequiresDecryptScope(t *testing.T) {
+	service := newTestService(t, newMemoryKeyStore())
+	encrypted, err := service.EncryptPayload(context.Background(), "s1", "signup", json.RawMessage(`{"customer":{"email":"a@b.test"}}`))
+	require.NoError(t, err)
+	store := NewDecryptingEventStore(staticEventStore{domain.Event{StreamID: "s1", Payload: encrypted}}, service)
+
+	reader := authz.WithPrincipal(context.Background(), authz.Principal{Scopes: []string{authz.ScopeEventsRead}})
+	event, err := store.GetByEventID(reader, "evt-1")
+	require.NoError(t, err)
+	require.NotContains(t, string(event.Payload), "a@b.test")
+
+	decrypter := authz.WithPrincipal(context.Background(), authz.Principal{Scopes: []string{authz.ScopeEventsRead, authz.ScopeDecrypt}})
+	event, err = store.GetByEventID(decrypter, "evt-1")
+	require.NoError(t, err)
+	require.Contains(t, string(event.Payload), "a@b.test")
+	events, _, _, err := store.QueryByStream(decrypter, "s1", 1, domain.DirectionForward, 10)
+	require.NoError(t, err)
+	require.Contains(t, string(events[0].Payload), "a@b.test")
+
+	plain := staticEventStore{}
+	require.Equal(t, plain, NewDecryptingEventStore(plain, nil), "without a master key reads are not wrapped")
+}
+
+func TestLoadPolicyAndMasterKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"fields":{"*":["customer.email"],"card_added":["card.number"]}}`), 0o600))
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"customer.email", "card.number"}, policy.PathsFor("card_added"))
+	require.Equal(t, []string{"customer.email"}, policy.PathsFor("signup"))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"fields":{"*":["customer..email"]}}`), 0o600))
+	_, err = LoadPolicy(path)
+	require.Error(t, err)
+
+	kms, err := ParseMasterKey("")
+	require.NoError(t, err)
+	require.Nil(t, kms)
+	_, err = ParseMasterKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	require.Error(t, err)
+}
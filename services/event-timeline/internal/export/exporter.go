@@ -0,0 +1,166 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+const (
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+
+	ManifestFile = "manifest.json"
+
+	defaultPageSize = int32(200)
+)
+
+type StreamManifest struct {
+	StreamID      string `json:"stream_id"`
+	EventCount    int64  `json:"event_count"`
+	FirstSequence int64  `json:"first_sequence"`
+	LastSequence  int64  `json:"last_sequence"`
+}
+
+type Manifest struct {
+	Format        string           `json:"format"`
+	File          string           `json:"file"`
+	EventCount    int64            `json:"event_count"`
+	ContentSHA256 string           `json:"content_sha256"`
+	ExportedAt    time.Time        `json:"exported_at"`
+	Streams       []StreamManifest `json:"streams"`
+}
+
+type eventWriter interface {
+	Write(event domain.Event) error
+	Close() error
+}
+
+type Exporter struct {
+	eventStore storage.EventStore
+	clock      clock.Clock
+	pageSize   int32
+}
+
+func NewExporter(eventStore storage.EventStore, c clock.Clock) *Exporter {
+	return &Exporter{eventStore: eventStore, clock: c, pageSize: defaultPageSize}
+}
+
+func ValidFormat(format string) bool {
+	return format == FormatNDJSON || format == FormatParquet
+}
+
+func EventsFile(format string) string {
+	return "events." + format
+}
+
+func (e *Exporter) WriteArchive(ctx context.Context, w io.Writer, streamIDs []string, format string) (Manifest, error) {
+	archive := zip.NewWriter(w)
+	entry, err := archive.Create(EventsFile(format))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("create archive entry: %w", err)
+	}
+	manifest, err := e.Export(ctx, entry, streamIDs, format)
+	if err != nil {
+		return manifest, err
+	}
+	manifestEntry, err := archive.Create(ManifestFile)
+	if err != nil {
+		return manifest, fmt.Errorf("create manifest entry: %w", err)
+	}
+	encoder := json.NewEncoder(manifestEntry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return manifest, fmt.Errorf("write manifest: %w", err)
+	}
+	if err := archive.Close(); err != nil {
+		return manifest, fmt.Errorf("close archive: %w", err)
+	}
+	return manifest, nil
+}
+
+func (e *Exporter) Export(ctx context.Context, w io.Writer, streamIDs []string, format string) (Manifest, error) {
+	if !ValidFormat(format) {
+		return Manifest{}, fmt.Errorf("unsupported export format %q: %w", format, domain.ErrValidation)
+	}
+	digest := sha256.New()
+	out := newEventWriter(io.MultiWriter(w, digest), format)
+
+	manifest := Manifest{
+		Format:     format,
+		File:       EventsFile(format),
+		ExportedAt: e.clock.Now().UTC(),
+		Streams:    make([]StreamManifest, 0, len(streamIDs)),
+	}
+	for _, streamID := range streamIDs {
+		stream, err := e.exportStream(ctx, out, streamID)
+		if err != nil {
+			return manifest, err
+		}
+		manifest.Streams = append(manifest.Streams, stream)
+		manifest.EventCount += stream.EventCount
+	}
+	if err := out.Close(); err != nil {
+		return manifest, fmt.Errorf("finish export: %w", err)
+	}
+	manifest.ContentSHA256 = hexDigest(digest)
+	return manifest, nil
+}
+
+func (e *Exporter) exportStream(ctx context.Context, out eventWriter, streamID string) (StreamManifest, error) {
+	result := StreamManifest{StreamID: streamID}
+	sequence := int64(1)
+	for {
+		events, nextSeq, hasMore, err := e.eventStore.QueryByStream(ctx, streamID, sequence, domain.DirectionForward, e.pageSize)
+		if err != nil {
+			return result, fmt.Errorf("query stream %s: %w", streamID, err)
+		}
+		for _, event := range events {
+			if err := out.Write(event); err != nil {
+				return result, fmt.Errorf("write event %s: %w", event.EventID, err)
+			}
+			if result.EventCount == 0 {
+				result.FirstSequence = event.SequenceNumber
+			}
+			result.LastSequence = event.SequenceNumber
+			result.EventCount++
+		}
+		if !hasMore || len(events) == 0 {
+			return result, nil
+		}
+		sequence = nextSeq
+	}
+}
+
+func newEventWriter(w io.Writer, format string) eventWriter {
+	if format == FormatParquet {
+		return newParquetWriter(w)
+	}
+	return &ndjsonWriter{encoder: json.NewEncoder(w)}
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(event domain.Event) error {
+	return n.encoder.Encode(event)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+func hexDigest(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
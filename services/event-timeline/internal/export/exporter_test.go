@@ -0,0 +1,391 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/ingest"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+type memoryStore struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (s *memoryStore) PutEvent(_ context.Context, event domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memoryStore) GetByEventID(context.Context, string) (domain.Event, error) {
+	return domain.Event{}, domain.ErrNotFound
+}
+
+func (s *memoryStore) FindByIdempotencyKey(_ context.Context, streamID, key string) (domain.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range s.events {
+		if event.StreamID == streamID && event.IdempotencyKey == key {
+			return event, nil
+		}
+	}
+	return domain.Event{}, domain.ErrNotFound
+}
+
+func (s *memoryStore) GetLatestSequence(_ context.Context, streamID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	latest := int64(0)
+	for _, event := range s.events {
+		if event.StreamID == streamID && event.SequenceNumber > latest {
+			latest = event.SequenceNumber
+		}
+	}
+	return latest, nil
+}
+
+func (s *memoryStore) QueryByStream(_ context.Context, streamID string, from int64, _ string, limit int32) ([]domain.Event, int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]domain.Event, 0)
+	for _, event := range s.events {
+		if event.StreamID == streamID && event.SequenceNumber >= from {
+			out = append(out, event)
+		}
+	}
+	hasMore := int32(len(out)) > limit
+	if hasMore {
+		out = out[:limit]
+	}
+	next := from
+	if len(out) > 0 {
+		next = out[len(out)-1].SequenceNumber + 1
+	}
+	return out, next, hasMore, nil
+}
+
+func seededStore(t *testing.T, streams map[string]int) *memoryStore {
+	t.Helper()
+	store := &memoryStore{}
+	for streamID, count := range streams {
+		for seq := 1; seq <= count; seq++ {
+			event, err := domain.NewEvent(domain.NewEventInput{
+				EventID:        fmt.Sprintf("%s-evt-%d", streamID, seq),
+				StreamID:       streamID,
+				SequenceNumber: int64(seq),
+				EventType:      "created",
+				Payload:        json.RawMessage(fmt.Sprintf(`{"n":%d}`, seq)),
+				Metadata:       map[string]string{"source": "test"},
+				OccurredAt:     time.Date(2025, 1, 1, 0, 0, seq, 0, time.UTC),
+				IngestedAt:     time.Date(2025, 1, 2, 0, 0, seq, 0, time.UTC),
+			})
+			require.NoError(t, err)
+			store.events = append(store.events, event)
+		}
+	}
+	return store
+}
+
+func TestExportNDJSONManifestAndReimport(t *testing.T) {
+	store := seededStore(t, map[string]int{"orders": 5, "payments": 2})
+	exporter := NewExporter(store, clock.MockClock{Current: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)})
+	exporter.pageSize = 2
+
+	var buf bytes.Buffer
+	manifest, err := exporter.Export(context.Background(), &buf, []string{"orders", "payments", "missing"}, FormatNDJSON)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(buf.Bytes())
+	require.Equal(t, hex.EncodeToString(sum[:]), manifest.ContentSHA256)
+	require.Equal(t, int64(7), manifest.EventCount)
+	require.Equal(t, []StreamManifest{
+		{StreamID: "orders", EventCount: 5, FirstSequence: 1, LastSequence: 5},
+		{StreamID: "payments", EventCount: 2, FirstSequence: 1, LastSequence: 2},
+		{StreamID: "missing"},
+	}, manifest.Streams)
+
+	target := &memoryStore{}
+	service := ingest.NewService(target, sequentialIDs(), clock.RealClock{}, observability.NewMetrics())
+	importer := ingest.NewImporter(service, slog.New(slog.NewTextHandler(io.Discard, nil)), ingest.ImportOptions{Workers: 2})
+	summary, err := importer.Import(context.Background(), bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, int64(7), summary.Created)
+
+	rerun, err := importer.Import(context.Background(), bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, int64(7), rerun.Duplicates)
+
+	for _, original := range store.events {
+		var found bool
+		for _, imported := range target.events {
+			if imported.StreamID == original.StreamID && imported.SequenceNumber == original.SequenceNumber {
+				found = true
+				require.Equal(t, original.EventType, imported.EventType)
+				require.JSONEq(t, string(original.Payload), string(imported.Payload))
+				require.Equal(t, original.Metadata, imported.Metadata)
+				require.True(t, original.OccurredAt.Equal(imported.OccurredAt))
+			}
+		}
+		require.True(t, found, "event %s not re-imported", original.EventID)
+	}
+}
+
+func sequentialIDs() *idCounter { return &idCounter{} }
+
+type idCounter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (g *idCounter) New(time.Time) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.n++
+	return fmt.Sprintf("imported-%d", g.n), nil
+}
+
+func TestWriteArchiveContainsEventsAndManifest(t *testing.T) {
+	store := seededStore(t, map[string]int{"orders": 3})
+	exporter := NewExporter(store, clock.RealClock{})
+
+	var buf bytes.Buffer
+	manifest, err := exporter.WriteArchive(context.Background(), &buf, []string{"orders"}, FormatParquet)
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, archive.File, 2)
+	require.Equal(t, "events.parquet", archive.File[0].Name)
+	require.Equal(t, ManifestFile, archive.File[1].Name)
+
+	content := readZipEntry(t, archive.File[0])
+	sum := sha256.Sum256(content)
+	require.Equal(t, hex.EncodeToString(sum[:]), manifest.ContentSHA256)
+
+	var stored Manifest
+	require.NoError(t, json.Unmarshal(readZipEntry(t, archive.File[1]), &stored))
+	require.Equal(t, manifest.ContentSHA256, stored.ContentSHA256)
+	require.Equal(t, int64(3), stored.EventCount)
+}
+
+func readZipEntry(t *testing.T, f *zip.File) []byte {
+	t.Helper()
+	rc, err := f.Open()
+	require.NoError(t, err)
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return content
+}
+
+func TestExportRejectsUnknownFormat(t *testing.T) {
+	_, err := NewExporter(&memoryStore{}, clock.RealClock{}).Export(context.Background(), io.Discard, []string{"orders"}, "csv")
+	require.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestParquetFileLayout(t *testing.T) {
+	store := seededStore(t, map[string]int{"orders": 4})
+	exporter := NewExporter(store, clock.RealClock{})
+
+	var buf bytes.Buffer
+	_, err := exporter.Export(context.Background(), &buf, []string{"orders"}, FormatParquet)
+	require.NoError(t, err)
+
+	file := buf.Bytes()
+	require.Equal(t, parquetMagic, string(file[:4]))
+	require.Equal(t, parquetMagic, string(file[len(file)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8 : len(file)-4]))
+	footer := decodeCompactStruct(t, &compactReader{buf: file[len(file)-8-footerLen : len(file)-8]})
+
+	require.Equal(t, int64(4), footer[3])
+	schema := footer[2].([]any)
+	require.Len(t, schema, len(parquetColumns)+1)
+	for i, col := range parquetColumns {
+		require.Equal(t, col.name, string(schema[i+1].(map[int16]any)[4].([]byte)))
+	}
+
+	rowGroups := footer[4].([]any)
+	require.Len(t, rowGroups, 1)
+	chunks := rowGroups[0].(map[int16]any)[1].([]any)
+	meta := chunks[2].(map[int16]any)[3].(map[int16]any)
+	offset := meta[9].(int64)
+	size := meta[7].(int64)
+	page := &compactReader{buf: file[offset : offset+size]}
+	header := decodeCompactStruct(t, page)
+	require.Equal(t, int64(4), header[5].(map[int16]any)[1])
+	values := page.buf[page.pos:]
+	for i := 0; i < 4; i++ {
+		require.Equal(t, uint64(i+1), binary.LittleEndian.Uint64(values[i*8:]))
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite the parquet golden files in testdata")
+
+// goldenEvents covers multi-byte text, empty strings, nil metadata and
+// sub-second timestamps. testdata/events.parquet is checked against pyarrow by
+// scripts/check_parquet_golden.py, so changing the fixture or the writer means
+// regenerating it with -update and re-running that script.
+func goldenEvents() []domain.Event {
+	occurred := time.Date(2026, 2, 14, 10, 0, 0, 123456000, time.UTC)
+	return []domain.Event{
+		{
+			EventID:        "evt-1",
+			StreamID:       "orders",
+			SequenceNumber: 1,
+			EventType:      "order.created",
+			Payload:        json.RawMessage(`{"amount":10}`),
+			Metadata:       map[string]string{"source": "api"},
+			IdempotencyKey: "idem-1",
+			OccurredAt:     occurred,
+			IngestedAt:     occurred.Add(time.Second),
+			SchemaVersion:  1,
+			Hash:           "a1",
+		},
+		{
+			EventID:        "evt-2",
+			StreamID:       "orders",
+			SequenceNumber: 2,
+			EventType:      "order.paid",
+			Payload:        json.RawMessage(`{"amount":10,"note":"zürich"}`),
+			OccurredAt:     occurred.Add(time.Minute),
+			IngestedAt:     occurred.Add(time.Minute + time.Second),
+			SchemaVersion:  2,
+			PrevHash:       "a1",
+			Hash:           "b2",
+		},
+		{
+			EventID:        "evt-3",
+			StreamID:       "invoices",
+			SequenceNumber: 1,
+			EventType:      "invoice.issued",
+			Payload:        json.RawMessage(`{}`),
+			Metadata:       map[string]string{"source": "batch", "region": "eu"},
+			IdempotencyKey: "idem-3",
+			OccurredAt:     occurred.Add(-time.Hour),
+			IngestedAt:     occurred,
+			SchemaVersion:  1,
+			Hash:           "c3",
+		},
+	}
+}
+
+func TestParquetMatchesGoldenFile(t *testing.T) {
+	events := goldenEvents()
+	var buf bytes.Buffer
+	writer := newParquetWriter(&buf)
+	rows := make([]map[string]any, 0, len(events))
+	for _, event := range events {
+		require.NoError(t, writer.Write(event))
+		row := map[string]any{}
+		for _, col := range parquetColumns {
+			v, err := col.value(event)
+			require.NoError(t, err)
+			row[col.name] = v
+		}
+		rows = append(rows, row)
+	}
+	require.NoError(t, writer.Close())
+	expected, err := json.MarshalIndent(rows, "", "  ")
+	require.NoError(t, err)
+	expected = append(expected, '\n')
+
+	if *updateGolden {
+		require.NoError(t, os.WriteFile("testdata/events.parquet", buf.Bytes(), 0o644))
+		require.NoError(t, os.WriteFile("testdata/events.json", expected, 0o644))
+	}
+	golden, err := os.ReadFile("testdata/events.parquet")
+	require.NoError(t, err)
+	require.Equal(t, golden, buf.Bytes(), "parquet output changed; regenerate with -update and run scripts/check_parquet_golden.py")
+	goldenRows, err := os.ReadFile("testdata/events.json")
+	require.NoError(t, err)
+	require.Equal(t, string(goldenRows), string(expected))
+}
+
+type compactReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *compactReader) byte() byte {
+	b := r.buf[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func decodeCompactStruct(t *testing.T, r *compactReader) map[int16]any {
+	t.Helper()
+	fields := map[int16]any{}
+	last := int16(0)
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		typ := header & 0x0F
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		last = id
+		fields[id] = decodeCompactValue(t, r, typ)
+	}
+}
+
+func decodeCompactValue(t *testing.T, r *compactReader, typ byte) any {
+	t.Helper()
+	switch typ {
+	case compactI32, compactI64:
+		return r.zigzag()
+	case compactBinary:
+		n := int(r.uvarint())
+		v := r.buf[r.pos : r.pos+n]
+		r.pos += n
+		return v
+	case compactList:
+		header := r.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		out := make([]any, 0, size)
+		for i := 0; i < size; i++ {
+			out = append(out, decodeCompactValue(t, r, header&0x0F))
+		}
+		return out
+	case compactStruct:
+		return decodeCompactStruct(t, r)
+	}
+	t.Fatalf("unexpected compact type %d", typ)
+	return nil
+}
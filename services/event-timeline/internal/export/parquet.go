@@ -0,0 +1,344 @@
+package export
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+const (
+	parquetMagic        = "PAR1"
+	parquetRowGroupRows = 5000
+	parquetCreatedBy    = "aevum event-timeline"
+)
+
+const (
+	parquetTypeInt32     = 1
+	parquetTypeInt64     = 2
+	parquetTypeByteArray = 6
+
+	parquetRequired = 0
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMicros = 10
+	parquetConvertedJSON            = 19
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecUncompressed = 0
+	parquetPageData          = 0
+)
+
+type parquetColumn struct {
+	name      string
+	physical  int32
+	converted int32
+	value     func(domain.Event) (any, error)
+}
+
+var parquetColumns = []parquetColumn{
+	{name: "event_id", physical: parquetTypeByteArray, converted: parquetConvertedUTF8, value: func(e domain.Event) (any, error) { return e.EventID, nil }},
+	{name: "stream_id", physical: parquetTypeByteArray, converted: parquetConvertedUTF8, value: func(e domain.Event) (any, error) { return e.StreamID, nil }},
+	{name: "sequence_number", physical: parquetTypeInt64, converted: -1, value: func(e domain.Event) (any, error) { return e.SequenceNumber, nil }},
+	{name: "event_type", physical: parquetTypeByteArray, converted: parquetConvertedUTF8, value: func(e domain.Event) (any, error) { return e.EventType, nil }},
+	{name: "payload", physical: parquetTypeByteArray, converted: parquetConvertedJSON, value: func(e domain.Event) (any, error) { return string(e.Payload), nil }},
+	{name: "metadata", physical: parquetTypeByteArray, converted: parquetConvertedJSON, value: func(e domain.Event) (any, error) {
+		if e.Metadata == nil {
+			return "{}", nil
+		}
+		raw, err := json.Marshal(e.Metadata)
+		return string(raw), err
+	}},
+	{name: "idempotency_key", physical: parquetTypeByteArray, converted: parquetConvertedUTF8, value: func(e domain.Event) (any, error) { return e.IdempotencyKey, nil }},
+	{name: "occurred_at", physical: parquetTypeInt64, converted: parquetConvertedTimestampMicros, value: func(e domain.Event) (any, error) { return e.OccurredAt.UnixMicro(), nil }},
+	{name: "ingested_at", physical: parquetTypeInt64, converted: parquetConvertedTimestampMicros, value: func(e domain.Event) (any, error) { return e.IngestedAt.UnixMicro(), nil }},
+	{name: "schema_version", physical: parquetTypeInt32, converted: -1, value: func(e domain.Event) (any, error) { return int32(e.SchemaVersion), nil }},
+	{name: "prev_hash", physical: parquetTypeByteArray, converted: parquetConvertedUTF8, value: func(e domain.Event) (any, error) { return e.PrevHash, nil }},
+	{name: "hash", physical: parquetTypeByteArray, converted: parquetConvertedUTF8, value: func(e domain.Event) (any, error) { return e.Hash, nil }},
+}
+
+type parquetColumnChunk struct {
+	offset     int64
+	size       int64
+	numValues  int64
+	columnType int32
+	path       string
+}
+
+type parquetRowGroup struct {
+	columns []parquetColumnChunk
+	numRows int64
+	size    int64
+}
+
+type parquetWriter struct {
+	w         io.Writer
+	offset    int64
+	pending   []domain.Event
+	rowGroups []parquetRowGroup
+	numRows   int64
+	started   bool
+}
+
+func newParquetWriter(w io.Writer) *parquetWriter {
+	return &parquetWriter{w: w, pending: make([]domain.Event, 0, parquetRowGroupRows)}
+}
+
+func (p *parquetWriter) Write(event domain.Event) error {
+	p.pending = append(p.pending, event)
+	if len(p.pending) >= parquetRowGroupRows {
+		return p.flush()
+	}
+	return nil
+}
+
+func (p *parquetWriter) Close() error {
+	if err := p.flush(); err != nil {
+		return err
+	}
+	if err := p.ensureHeader(); err != nil {
+		return err
+	}
+	footer := p.fileMetadata()
+	if err := p.write(footer); err != nil {
+		return err
+	}
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	if err := p.write(length[:]); err != nil {
+		return err
+	}
+	return p.write([]byte(parquetMagic))
+}
+
+func (p *parquetWriter) ensureHeader() error {
+	if p.started {
+		return nil
+	}
+	p.started = true
+	return p.write([]byte(parquetMagic))
+}
+
+func (p *parquetWriter) flush() error {
+	if len(p.pending) == 0 {
+		return nil
+	}
+	if err := p.ensureHeader(); err != nil {
+		return err
+	}
+	group := parquetRowGroup{numRows: int64(len(p.pending))}
+	for _, col := range parquetColumns {
+		data, err := encodePlainColumn(col, p.pending)
+		if err != nil {
+			return fmt.Errorf("encode parquet column %s: %w", col.name, err)
+		}
+		header := pageHeader(len(p.pending), len(data))
+		chunk := parquetColumnChunk{
+			offset:     p.offset,
+			size:       int64(len(header) + len(data)),
+			numValues:  int64(len(p.pending)),
+			columnType: col.physical,
+			path:       col.name,
+		}
+		if err := p.write(header); err != nil {
+			return err
+		}
+		if err := p.write(data); err != nil {
+			return err
+		}
+		group.columns = append(group.columns, chunk)
+		group.size += chunk.size
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.numRows += group.numRows
+	p.pending = p.pending[:0]
+	return nil
+}
+
+func (p *parquetWriter) write(b []byte) error {
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("write parquet: %w", err)
+	}
+	return nil
+}
+
+func encodePlainColumn(col parquetColumn, events []domain.Event) ([]byte, error) {
+	var buf []byte
+	for _, event := range events {
+		v, err := col.value(event)
+		if err != nil {
+			return nil, err
+		}
+		switch typed := v.(type) {
+		case string:
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(typed)))
+			buf = append(buf, typed...)
+		case int64:
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(typed))
+		case int32:
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(typed))
+		}
+	}
+	return buf, nil
+}
+
+func pageHeader(numValues, size int) []byte {
+	var c compactWriter
+	c.i32Field(1, parquetPageData)
+	c.i32Field(2, int32(size))
+	c.i32Field(3, int32(size))
+	c.structField(5)
+	c.i32Field(1, int32(numValues))
+	c.i32Field(2, parquetEncodingPlain)
+	c.i32Field(3, parquetEncodingRLE)
+	c.i32Field(4, parquetEncodingRLE)
+	c.endStruct()
+	c.endStruct()
+	return c.buf
+}
+
+func (p *parquetWriter) fileMetadata() []byte {
+	var c compactWriter
+	c.i32Field(1, 1)
+
+	c.listField(2, compactStruct, len(parquetColumns)+1)
+	c.beginStruct()
+	c.binaryField(4, "schema")
+	c.i32Field(5, int32(len(parquetColumns)))
+	c.endStruct()
+	for _, col := range parquetColumns {
+		c.beginStruct()
+		c.i32Field(1, col.physical)
+		c.i32Field(3, parquetRequired)
+		c.binaryField(4, col.name)
+		if col.converted >= 0 {
+			c.i32Field(6, col.converted)
+		}
+		c.endStruct()
+	}
+
+	c.i64Field(3, p.numRows)
+
+	c.listField(4, compactStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		c.beginStruct()
+		c.listField(1, compactStruct, len(group.columns))
+		for _, chunk := range group.columns {
+			c.beginStruct()
+			c.i64Field(2, chunk.offset)
+			c.structField(3)
+			c.i32Field(1, chunk.columnType)
+			c.listField(2, compactI32, 2)
+			c.varint(zigzag32(parquetEncodingPlain))
+			c.varint(zigzag32(parquetEncodingRLE))
+			c.listField(3, compactBinary, 1)
+			c.binary(chunk.path)
+			c.i32Field(4, parquetCodecUncompressed)
+			c.i64Field(5, chunk.numValues)
+			c.i64Field(6, chunk.size)
+			c.i64Field(7, chunk.size)
+			c.i64Field(9, chunk.offset)
+			c.endStruct()
+			c.endStruct()
+		}
+		c.i64Field(2, group.size)
+		c.i64Field(3, group.numRows)
+		c.endStruct()
+	}
+
+	c.binaryField(6, parquetCreatedBy)
+	c.endStruct()
+	return c.buf
+}
+
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+type compactWriter struct {
+	buf    []byte
+	last   int16
+	parent []int16
+}
+
+func (c *compactWriter) fieldHeader(id int16, typ byte) {
+	delta := id - c.last
+	if delta > 0 && delta <= 15 {
+		c.buf = append(c.buf, byte(delta)<<4|typ)
+	} else {
+		c.buf = append(c.buf, typ)
+		c.varint(zigzag32(int32(id)))
+	}
+	c.last = id
+}
+
+func (c *compactWriter) i32Field(id int16, v int32) {
+	c.fieldHeader(id, compactI32)
+	c.varint(zigzag32(v))
+}
+
+func (c *compactWriter) i64Field(id int16, v int64) {
+	c.fieldHeader(id, compactI64)
+	c.varint(zigzag64(v))
+}
+
+func (c *compactWriter) binaryField(id int16, v string) {
+	c.fieldHeader(id, compactBinary)
+	c.binary(v)
+}
+
+func (c *compactWriter) binary(v string) {
+	c.varint(uint64(len(v)))
+	c.buf = append(c.buf, v...)
+}
+
+func (c *compactWriter) listField(id int16, elemType byte, size int) {
+	c.fieldHeader(id, compactList)
+	if size < 15 {
+		c.buf = append(c.buf, byte(size)<<4|elemType)
+		return
+	}
+	c.buf = append(c.buf, 0xF0|elemType)
+	c.varint(uint64(size))
+}
+
+func (c *compactWriter) structField(id int16) {
+	c.fieldHeader(id, compactStruct)
+	c.beginStruct()
+}
+
+func (c *compactWriter) beginStruct() {
+	c.parent = append(c.parent, c.last)
+	c.last = 0
+}
+
+func (c *compactWriter) endStruct() {
+	c.buf = append(c.buf, 0)
+	if len(c.parent) == 0 {
+		return
+	}
+	c.last = c.parent[len(c.parent)-1]
+	c.parent = c.parent[:len(c.parent)-1]
+}
+
+func (c *compactWriter) varint(v uint64) {
+	c.buf = binary.AppendUvarint(c.buf, v)
+}
+
+func zigzag32(v int32) uint64 {
+	return uint64(uint32((v << 1) ^ (v >> 31)))
+}
+
+func zigzag64(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
@@ -0,0 +1,260 @@
+package ingest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultImportWorkers  = 8
+	DefaultMaxLineBytes   = 1 << 20
+	DefaultMaxLineErrors  = 1000
+	importQueueDepth      = 64
+	importProgressEvery   = 10000
+	importStatusCreated   = "created"
+	importStatusDuplicate = "duplicate"
+	exportedKeyPrefix     = "import:"
+)
+
+type ImportOptions struct {
+	Workers       int
+	MaxLineBytes  int
+	MaxLineErrors int
+}
+
+type ImportLineError struct {
+	Line           int64  `json:"line"`
+	StreamID       string `json:"stream_id,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	Error          string `json:"error"`
+}
+
+type ImportSummary struct {
+	LinesRead       int64             `json:"lines_read"`
+	Created         int64             `json:"created"`
+	Duplicates      int64             `json:"duplicates"`
+	Failed          int64             `json:"failed"`
+	DurationMS      int64             `json:"duration_ms"`
+	Errors          []ImportLineError `json:"errors"`
+	ErrorsTruncated bool              `json:"errors_truncated"`
+}
+
+type Importer struct {
+	service *Service
+	logger  *slog.Logger
+	opts    ImportOptions
+}
+
+type importJob struct {
+	line  int64
+	input EventInput
+}
+
+func NewImporter(service *Service, logger *slog.Logger, opts ImportOptions) *Importer {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultImportWorkers
+	}
+	if opts.MaxLineBytes <= 0 {
+		opts.MaxLineBytes = DefaultMaxLineBytes
+	}
+	if opts.MaxLineErrors <= 0 {
+		opts.MaxLineErrors = DefaultMaxLineErrors
+	}
+	return &Importer{service: service, logger: logger, opts: opts}
+}
+
+func (i *Importer) Import(ctx context.Context, r io.Reader) (ImportSummary, error) {
+	start := time.Now()
+	tracker := &importTracker{maxErrors: i.opts.MaxLineErrors}
+
+	queues := make([]chan importJob, i.opts.Workers)
+	var wg sync.WaitGroup
+	for w := range queues {
+		queues[w] = make(chan importJob, importQueueDepth)
+		wg.Add(1)
+		go func(jobs <-chan importJob) {
+			defer wg.Done()
+			for job := range jobs {
+				i.process(ctx, job, tracker)
+			}
+		}(queues[w])
+	}
+
+	readErr := i.dispatch(ctx, bufio.NewReaderSize(r, 64*1024), queues, tracker)
+	for _, q := range queues {
+		close(q)
+	}
+	wg.Wait()
+
+	summary := tracker.summary()
+	summary.DurationMS = time.Since(start).Milliseconds()
+	i.logger.Info("import finished",
+		slog.Int64("lines_read", summary.LinesRead),
+		slog.Int64("created", summary.Created),
+		slog.Int64("duplicates", summary.Duplicates),
+		slog.Int64("failed", summary.Failed),
+	)
+	return summary, readErr
+}
+
+func (i *Importer) dispatch(ctx context.Context, reader *bufio.Reader, queues []chan importJob, tracker *importTracker) error {
+	var line int64
+	for {
+		raw, tooLong, err := readLine(reader, i.opts.MaxLineBytes)
+		if err == nil || len(raw) > 0 || tooLong {
+			line++
+		}
+		if len(raw) > 0 || tooLong {
+			tracker.read()
+			if line%importProgressEvery == 0 {
+				i.logger.Info("import progress", slog.Int64("lines_read", line))
+			}
+			if tooLong {
+				tracker.fail(ImportLineError{Line: line, Error: fmt.Sprintf("line exceeds %d bytes", i.opts.MaxLineBytes)})
+			} else if job, lineErr := parseImportLine(line, raw); lineErr != nil {
+				tracker.fail(*lineErr)
+			} else {
+				select {
+				case queues[shardFor(job.input.StreamID, len(queues))] <- job:
+				case <-ctx.Done():
+					return fmt.Errorf("import aborted at line %d: %w", line, ctx.Err())
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read import body at line %d: %w", line+1, err)
+		}
+	}
+}
+
+func (i *Importer) process(ctx context.Context, job importJob, tracker *importTracker) {
+	if ctx.Err() != nil {
+		tracker.fail(ImportLineError{Line: job.line, StreamID: job.input.StreamID, IdempotencyKey: job.input.IdempotencyKey, Error: ctx.Err().Error()})
+		return
+	}
+	_, created, err := i.service.Ingest(ctx, job.input)
+	if err != nil {
+		tracker.fail(ImportLineError{Line: job.line, StreamID: job.input.StreamID, IdempotencyKey: job.input.IdempotencyKey, Error: err.Error()})
+		return
+	}
+	if created {
+		tracker.record(importStatusCreated)
+		return
+	}
+	tracker.record(importStatusDuplicate)
+}
+
+func parseImportLine(line int64, raw []byte) (importJob, *ImportLineError) {
+	var record struct {
+		EventInput
+		EventID string `json:"event_id"`
+	}
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return importJob{}, &ImportLineError{Line: line, Error: fmt.Sprintf("invalid json: %v", err)}
+	}
+	in := record.EventInput
+	if in.IdempotencyKey == "" && record.EventID != "" {
+		in.IdempotencyKey = exportedKeyPrefix + record.EventID
+	}
+	if err := ValidateEventInput(in); err != nil {
+		return importJob{}, &ImportLineError{Line: line, StreamID: in.StreamID, IdempotencyKey: in.IdempotencyKey, Error: err.Error()}
+	}
+	if in.IdempotencyKey == "" {
+		return importJob{}, &ImportLineError{Line: line, StreamID: in.StreamID, Error: "idempotency_key is required for imports"}
+	}
+	return importJob{line: line, input: in}, nil
+}
+
+func readLine(reader *bufio.Reader, maxBytes int) ([]byte, bool, error) {
+	var buf []byte
+	tooLong := false
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if !tooLong {
+			if len(buf)+len(chunk) > maxBytes+1 {
+				tooLong = true
+				buf = nil
+			} else {
+				buf = append(buf, chunk...)
+			}
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if tooLong {
+			return nil, true, err
+		}
+		return trimLine(buf), false, err
+	}
+}
+
+func trimLine(b []byte) []byte {
+	for len(b) > 0 && (b[len(b)-1] == '\n' || b[len(b)-1] == '\r' || b[len(b)-1] == ' ' || b[len(b)-1] == '\t') {
+		b = b[:len(b)-1]
+	}
+	for len(b) > 0 && (b[0] == ' ' || b[0] == '\t') {
+		b = b[1:]
+	}
+	return b
+}
+
+func shardFor(streamID string, shards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(streamID))
+	return int(h.Sum32() % uint32(shards))
+}
+
+type importTracker struct {
+	mu        sync.Mutex
+	maxErrors int
+	result    ImportSummary
+}
+
+func (t *importTracker) read() {
+	t.mu.Lock()
+	t.result.LinesRead++
+	t.mu.Unlock()
+}
+
+func (t *importTracker) record(status string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch status {
+	case importStatusCreated:
+		t.result.Created++
+	case importStatusDuplicate:
+		t.result.Duplicates++
+	}
+}
+
+func (t *importTracker) fail(lineErr ImportLineError) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.result.Failed++
+	if len(t.result.Errors) >= t.maxErrors {
+		t.result.ErrorsTruncated = true
+		return
+	}
+	t.result.Errors = append(t.result.Errors, lineErr)
+}
+
+func (t *importTracker) summary() ImportSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := t.result
+	out.Errors = append([]ImportLineError{}, t.result.Errors...)
+	sort.Slice(out.Errors, func(a, b int) bool { return out.Errors[a].Line < out.Errors[b].Line })
+	return out
+}
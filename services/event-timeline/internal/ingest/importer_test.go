@@ -0,0 +1,129 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+type lockedStore struct {
+	mu sync.Mutex
+	testStore
+}
+
+func (s *lockedStore) PutEvent(ctx context.Context, e domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.testStore.PutEvent(ctx, e)
+}
+
+func (s *lockedStore) FindByIdempotencyKey(ctx context.Context, streamID, key string) (domain.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.testStore.FindByIdempotencyKey(ctx, streamID, key)
+}
+
+func (s *lockedStore) GetLatestSequence(ctx context.Context, streamID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.testStore.GetLatestSequence(ctx, streamID)
+}
+
+type counterGenerator struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (g *counterGenerator) New(time.Time) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.n++
+	return fmt.Sprintf("evt-%d", g.n), nil
+}
+
+func newTestImporter(store *lockedStore, opts ImportOptions) *Importer {
+	service := NewService(store, &counterGenerator{}, clock.MockClock{Current: time.Now().UTC()}, observability.NewMetrics())
+	return NewImporter(service, slog.New(slog.NewTextHandler(io.Discard, nil)), opts)
+}
+
+func importLine(streamID, key string, minute int) string {
+	return fmt.Sprintf(`{"stream_id":%q,"event_type":"created","payload":{"n":%d},"idempotency_key":%q,"occurred_at":"2025-01-01T00:%02d:00Z"}`, streamID, minute, key, minute)
+}
+
+func TestImportPreservesStreamOrderAndIsRestartable(t *testing.T) {
+	store := &lockedStore{}
+	importer := newTestImporter(store, ImportOptions{Workers: 4})
+
+	var body strings.Builder
+	for i := 0; i < 30; i++ {
+		body.WriteString(importLine(fmt.Sprintf("stream-%d", i%3), fmt.Sprintf("k-%d", i), i))
+		body.WriteString("\n")
+	}
+
+	summary, err := importer.Import(context.Background(), strings.NewReader(body.String()))
+	require.NoError(t, err)
+	require.Equal(t, int64(30), summary.LinesRead)
+	require.Equal(t, int64(30), summary.Created)
+	require.Zero(t, summary.Failed)
+
+	for _, event := range store.events {
+		var n int
+		_, err := fmt.Sscanf(event.IdempotencyKey, "k-%d", &n)
+		require.NoError(t, err)
+		require.Equal(t, int64(n/3+1), event.SequenceNumber)
+		require.Equal(t, time.Date(2025, 1, 1, 0, n, 0, 0, time.UTC), event.OccurredAt)
+	}
+
+	rerun, err := importer.Import(context.Background(), strings.NewReader(body.String()))
+	require.NoError(t, err)
+	require.Equal(t, int64(30), rerun.Duplicates)
+	require.Zero(t, rerun.Created)
+	require.Len(t, store.events, 30)
+}
+
+func TestImportReportsLineErrors(t *testing.T) {
+	store := &lockedStore{}
+	importer := newTestImporter(store, ImportOptions{Workers: 2, MaxLineBytes: 512})
+
+	body := strings.Join([]string{
+		importLine("stream-1", "k-1", 1),
+		"",
+		`{not json`,
+		`{"stream_id":"stream-1","event_type":"created","payload":{},"occurred_at":"2025-01-01T00:00:00Z"}`,
+		`{"stream_id":"stream-1","event_type":"created"}`,
+		`{"stream_id":"stream-1","payload":"` + strings.Repeat("x", 600) + `"}`,
+		importLine("stream-1", "k-2", 2),
+	}, "\n")
+
+	summary, err := importer.Import(context.Background(), strings.NewReader(body))
+	require.NoError(t, err)
+	require.Equal(t, int64(6), summary.LinesRead)
+	require.Equal(t, int64(2), summary.Created)
+	require.Equal(t, int64(4), summary.Failed)
+	require.Len(t, summary.Errors, 4)
+	require.Equal(t, []int64{3, 4, 5, 6}, []int64{summary.Errors[0].Line, summary.Errors[1].Line, summary.Errors[2].Line, summary.Errors[3].Line})
+	require.Contains(t, summary.Errors[0].Error, "invalid json")
+	require.Contains(t, summary.Errors[1].Error, "idempotency_key is required")
+	require.Contains(t, summary.Errors[3].Error, "exceeds 512 bytes")
+}
+
+func TestImportTruncatesErrorReport(t *testing.T) {
+	importer := newTestImporter(&lockedStore{}, ImportOptions{MaxLineErrors: 2})
+
+	summary, err := importer.Import(context.Background(), strings.NewReader("x\ny\nz\n"))
+	require.NoError(t, err)
+	require.Equal(t, int64(3), summary.Failed)
+	require.Len(t, summary.Errors, 2)
+	require.True(t, summary.ErrorsTruncated)
+}
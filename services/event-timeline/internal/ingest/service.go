@@ -2,6 +2,7 @@ package ingest
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -9,16 +10,24 @@ import (
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/observability"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/identifier"
 )
 
+const MaxBatchSize = 25
+
+type PayloadEncryptor interface {
+	EncryptPayload(ctx context.Context, streamID, eventType string, payload json.RawMessage) (json.RawMessage, error)
+}
+
 type Service struct {
 	eventStore  storage.EventStore
 	idempotency *IdempotencyChecker
 	idGenerator identifier.Generator
 	clock       clock.Clock
 	metrics     *observability.Metrics
+	encryptor   PayloadEncryptor
 }
 
 func NewService(eventStore storage.EventStore, idGenerator identifier.Generator, c clock.Clock, metrics *observability.Metrics) *Service {
@@ -31,26 +40,46 @@ func NewService(eventStore storage.EventStore, idGenerator identifier.Generator,
 	}
 }
 
+func (s *Service) WithPayloadEncryptor(encryptor PayloadEncryptor) *Service {
+	s.encryptor = encryptor
+	return s
+}
+
 func (s *Service) Ingest(ctx context.Context, in EventInput) (domain.Event, bool, error) {
 	start := time.Now()
+	tenantID := tenant.FromContext(ctx)
 	if err := ValidateEventInput(in); err != nil {
-		s.metrics.RecordIngest(in.StreamID, in.EventType, "invalid")
+		s.metrics.RecordIngest(tenantID, in.StreamID, in.EventType, "invalid")
 		return domain.Event{}, false, err
 	}
 	if existing, ok, err := s.idempotency.FindExisting(ctx, in.StreamID, in.IdempotencyKey); err != nil {
 		return domain.Event{}, false, fmt.Errorf("idempotency check: %w", err)
 	} else if ok {
-		s.metrics.RecordIngest(in.StreamID, in.EventType, "duplicate")
+		s.metrics.RecordIngest(tenantID, in.StreamID, in.EventType, "duplicate")
 		s.metrics.ObserveIngestionDuration(time.Since(start).Seconds())
 		return existing, false, nil
 	}
 
+	payload := in.Payload
+	if s.encryptor != nil {
+		encrypted, err := s.encryptor.EncryptPayload(ctx, in.StreamID, in.EventType, in.Payload)
+		if err != nil {
+			s.metrics.RecordIngest(tenantID, in.StreamID, in.EventType, "rejected")
+			return domain.Event{}, false, fmt.Errorf("encrypt payload: %w", err)
+		}
+		payload = encrypted
+	}
+
 	latest, err := s.eventStore.GetLatestSequence(ctx, in.StreamID)
 	if err != nil {
 		return domain.Event{}, false, fmt.Errorf("get latest sequence: %w", err)
 	}
 
 	for retries := 0; retries < 3; retries++ {
+		prevHash, err := s.previousHash(ctx, in.StreamID, latest)
+		if err != nil {
+			return domain.Event{}, false, fmt.Errorf("resolve previous hash: %w", err)
+		}
 		eventID, err := s.idGenerator.New(s.clock.Now())
 		if err != nil {
 			return domain.Event{}, false, fmt.Errorf("generate event id: %w", err)
@@ -60,7 +89,7 @@ func (s *Service) Ingest(ctx context.Context, in EventInput) (domain.Event, bool
 			StreamID:       in.StreamID,
 			SequenceNumber: latest + 1,
 			EventType:      in.EventType,
-			Payload:        in.Payload,
+			Payload:        payload,
 			Metadata:       in.Metadata,
 			IdempotencyKey: in.IdempotencyKey,
 			OccurredAt:     in.OccurredAt,
@@ -70,9 +99,13 @@ func (s *Service) Ingest(ctx context.Context, in EventInput) (domain.Event, bool
 		if err != nil {
 			return domain.Event{}, false, fmt.Errorf("construct event: %w", err)
 		}
+		candidate, err = domain.ChainEvent(candidate, prevHash)
+		if err != nil {
+			return domain.Event{}, false, fmt.Errorf("chain event: %w", err)
+		}
 		err = s.eventStore.PutEvent(ctx, candidate)
 		if err == nil {
-			s.metrics.RecordIngest(in.StreamID, in.EventType, "created")
+			s.metrics.RecordIngest(tenantID, in.StreamID, in.EventType, "created")
 			s.metrics.ObserveIngestionDuration(time.Since(start).Seconds())
 			return candidate, true, nil
 		}
@@ -82,7 +115,7 @@ func (s *Service) Ingest(ctx context.Context, in EventInput) (domain.Event, bool
 				return domain.Event{}, false, fmt.Errorf("idempotency conflict lookup: %w", lookupErr)
 			}
 			if ok {
-				s.metrics.RecordIngest(in.StreamID, in.EventType, "duplicate")
+				s.metrics.RecordIngest(tenantID, in.StreamID, in.EventType, "duplicate")
 				s.metrics.ObserveIngestionDuration(time.Since(start).Seconds())
 				return existing, false, nil
 			}
@@ -97,6 +130,33 @@ func (s *Service) Ingest(ctx context.Context, in EventInput) (domain.Event, bool
 	return domain.Event{}, false, fmt.Errorf("max retries reached for sequence assignment")
 }
 
+func (s *Service) previousHash(ctx context.Context, streamID string, sequence int64) (string, error) {
+	if sequence <= 0 {
+		return domain.GenesisHash, nil
+	}
+	var hash string
+	if chain, ok := s.eventStore.(storage.ChainHashReader); ok {
+		h, err := chain.GetChainHash(ctx, streamID, sequence)
+		if err != nil {
+			return "", err
+		}
+		hash = h
+	} else {
+		events, _, _, err := s.eventStore.QueryByStream(ctx, streamID, sequence, domain.DirectionForward, 1)
+		if err != nil {
+			return "", err
+		}
+		if len(events) == 0 || events[0].SequenceNumber != sequence {
+			return "", fmt.Errorf("sequence %d of stream %s not found: %w", sequence, streamID, domain.ErrNotFound)
+		}
+		hash = events[0].Hash
+	}
+	if hash == "" {
+		return domain.GenesisHash, nil
+	}
+	return hash, nil
+}
+
 type BatchResult struct {
 	Event   domain.Event `json:"event"`
 	Status  string       `json:"status"`
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
@@ -29,6 +30,10 @@ type flakyStore struct {
 func (s *flakyStore) PutEvent(_ context.Context, e domain.Event) error {
 	if s.sequenceConflictsLeft > 0 {
 		s.sequenceConflictsLeft--
+		concurrent := e
+		concurrent.EventID = "evt-concurrent"
+		concurrent.IdempotencyKey = ""
+		s.events = append(s.events, concurrent)
 		return domain.ErrSequenceConflict
 	}
 	if s.returnIdempotencyOnPut {
@@ -51,10 +56,6 @@ func (s *testStore) PutEvent(_ context.Context, e domain.Event) error {
 	}
 	return nil
 }
-func (s *testStore) PutEventsBatch(_ context.Context, events []domain.Event) error {
-	s.events = append(s.events, events...)
-	return nil
-}
 func (s *testStore) GetByEventID(context.Context, string) (domain.Event, error) {
 	return domain.Event{}, domain.ErrNotFound
 }
@@ -106,6 +107,8 @@ func TestIngestAndBatchIngest(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, created)
 	require.Equal(t, int64(1), event.SequenceNumber)
+	require.Equal(t, domain.GenesisHash, event.PrevHash)
+	require.NotEmpty(t, event.Hash)
 
 	results := service.BatchIngest(context.Background(), []EventInput{
 		{StreamID: "stream-1", EventType: "updated", Payload: json.RawMessage(`{"x":1}`), OccurredAt: time.Now().UTC()},
@@ -138,6 +141,7 @@ func TestIngestRetriesOnSequenceConflict(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, created)
 	require.Equal(t, int64(2), event.SequenceNumber)
+	require.Equal(t, store.events[0].Hash, event.PrevHash)
 }
 
 func TestIngestHandlesIdempotencyConflict(t *testing.T) {
@@ -186,3 +190,31 @@ func TestIngestFailsWhenIdempotencyConflictHasNoExisting(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "idempotency conflict without existing event")
 }
+
+type prefixEncryptor struct{ err error }
+
+func (e prefixEncryptor) EncryptPayload(_ context.Context, _, _ string, payload json.RawMessage) (json.RawMessage, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return json.RawMessage(`{"sealed":` + strconv.Quote(string(payload)) + `}`), nil
+}
+
+func TestIngestEncryptsPayloadBeforeChaining(t *testing.T) {
+	store := &testStore{byKey: map[string]domain.Event{}}
+	service := NewService(store, testGenerator{}, clock.MockClock{Current: time.Now().UTC()}, observability.NewMetrics()).
+		WithPayloadEncryptor(prefixEncryptor{})
+	in := EventInput{StreamID: "stream-1", EventType: "created", Payload: json.RawMessage(`{"ssn":"123"}`), OccurredAt: time.Now().UTC()}
+
+	event, _, err := service.Ingest(context.Background(), in)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"sealed":"{\"ssn\":\"123\"}"}`, string(event.Payload))
+	hash, err := domain.ComputeEventHash(event)
+	require.NoError(t, err)
+	require.Equal(t, hash, event.Hash, "the hash covers the stored, encrypted payload")
+
+	service.WithPayloadEncryptor(prefixEncryptor{err: domain.ErrKeyShredded})
+	_, _, err = service.Ingest(context.Background(), in)
+	require.ErrorIs(t, err, domain.ErrKeyShredded)
+	require.Len(t, store.events, 1)
+}
@@ -0,0 +1,297 @@
+package integrity
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+const (
+	DefaultMaxCheckpointLeaves = 10000
+	checkpointPageSize         = int32(200)
+)
+
+var (
+	ErrSigningKeyMissing = errors.New("checkpoint signing key not configured")
+	ErrNotCheckpointed   = errors.New("event not covered by a checkpoint")
+	ErrCheckpointInvalid = errors.New("checkpoint does not match stored events")
+)
+
+type CheckpointerDependencies struct {
+	EventStore      storage.EventStore
+	StreamStore     storage.StreamStore
+	CheckpointStore storage.CheckpointStore
+	SigningKey      ed25519.PrivateKey
+	Clock           clock.Clock
+	Logger          *slog.Logger
+	Interval        time.Duration
+	MaxLeaves       int
+}
+
+type Checkpointer struct {
+	eventStore  storage.EventStore
+	streamStore storage.StreamStore
+	checkpoints storage.CheckpointStore
+	signingKey  ed25519.PrivateKey
+	clock       clock.Clock
+	logger      *slog.Logger
+	interval    time.Duration
+	maxLeaves   int
+}
+
+type Proof struct {
+	EventID        string            `json:"event_id"`
+	StreamID       string            `json:"stream_id"`
+	SequenceNumber int64             `json:"sequence_number"`
+	EventHash      string            `json:"event_hash"`
+	LeafIndex      int64             `json:"leaf_index"`
+	TreeSize       int64             `json:"tree_size"`
+	AuditPath      []string          `json:"audit_path"`
+	Checkpoint     domain.Checkpoint `json:"checkpoint"`
+}
+
+func NewCheckpointer(deps CheckpointerDependencies) *Checkpointer {
+	maxLeaves := deps.MaxLeaves
+	if maxLeaves <= 0 {
+		maxLeaves = DefaultMaxCheckpointLeaves
+	}
+	return &Checkpointer{
+		eventStore:  deps.EventStore,
+		streamStore: deps.StreamStore,
+		checkpoints: deps.CheckpointStore,
+		signingKey:  deps.SigningKey,
+		clock:       deps.Clock,
+		logger:      deps.Logger,
+		interval:    deps.Interval,
+		maxLeaves:   maxLeaves,
+	}
+}
+
+func ParseSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode checkpoint signing key: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("checkpoint signing key must be a %d-byte seed or %d-byte private key", ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+}
+
+func KeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+func VerifyCheckpointSignature(checkpoint domain.Checkpoint, publicKey ed25519.PublicKey) bool {
+	signature, err := base64.StdEncoding.DecodeString(checkpoint.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(publicKey, checkpoint.SigningPayload(), signature)
+}
+
+func (c *Checkpointer) Run(ctx context.Context) error {
+	if c.signingKey == nil || c.interval <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.checkpointAll(ctx)
+		}
+	}
+}
+
+func (c *Checkpointer) checkpointAll(ctx context.Context) {
+	streams, err := storage.ListAllStreams(tenant.WithCrossTenant(ctx), c.streamStore)
+	if err != nil {
+		c.logger.Error("list streams for checkpoint", slog.String("error", err.Error()))
+		return
+	}
+	for _, stream := range streams {
+		streamCtx := tenant.WithTenant(ctx, stream.TenantID)
+		for ctx.Err() == nil {
+			checkpoint, created, err := c.CheckpointStream(streamCtx, stream.StreamID)
+			if err != nil {
+				c.logger.Error("checkpoint stream", slog.String("tenant", stream.TenantID), slog.String("stream_id", stream.StreamID), slog.String("error", err.Error()))
+				break
+			}
+			if !created {
+				break
+			}
+			c.logger.Info("checkpoint created",
+				slog.String("tenant", stream.TenantID),
+				slog.String("stream_id", checkpoint.StreamID),
+				slog.Int64("from_sequence", checkpoint.FromSequence),
+				slog.Int64("to_sequence", checkpoint.ToSequence),
+				slog.String("root_hash", checkpoint.RootHash),
+			)
+		}
+	}
+}
+
+func (c *Checkpointer) CheckpointStream(ctx context.Context, streamID string) (domain.Checkpoint, bool, error) {
+	if c.signingKey == nil {
+		return domain.Checkpoint{}, false, ErrSigningKeyMissing
+	}
+	from := int64(1)
+	last, err := c.checkpoints.LatestCheckpoint(ctx, streamID)
+	switch {
+	case err == nil:
+		from = last.ToSequence + 1
+	case !errors.Is(err, domain.ErrNotFound):
+		return domain.Checkpoint{}, false, fmt.Errorf("load latest checkpoint: %w", err)
+	}
+	latest, err := c.eventStore.GetLatestSequence(ctx, streamID)
+	if err != nil {
+		return domain.Checkpoint{}, false, fmt.Errorf("get latest sequence: %w", err)
+	}
+	to := latest
+	if to-from+1 > int64(c.maxLeaves) {
+		to = from + int64(c.maxLeaves) - 1
+	}
+	if to < from {
+		return domain.Checkpoint{}, false, nil
+	}
+
+	events, err := c.rangeEvents(ctx, streamID, from, to)
+	if err != nil {
+		return domain.Checkpoint{}, false, err
+	}
+	leaves, err := eventLeaves(events)
+	if err != nil {
+		return domain.Checkpoint{}, false, err
+	}
+	publicKey := c.signingKey.Public().(ed25519.PublicKey)
+	checkpoint := domain.Checkpoint{
+		CheckpointID: fmt.Sprintf("ckpt-%s-%d-%d", streamID, from, to),
+		StreamID:     streamID,
+		FromSequence: from,
+		ToSequence:   to,
+		TreeSize:     int64(len(leaves)),
+		RootHash:     hex.EncodeToString(MerkleRoot(leaves)),
+		KeyID:        KeyID(publicKey),
+		PublicKey:    base64.StdEncoding.EncodeToString(publicKey),
+		CreatedAt:    c.clock.Now().UTC(),
+	}
+	checkpoint.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(c.signingKey, checkpoint.SigningPayload()))
+	if err := c.checkpoints.PutCheckpoint(ctx, checkpoint); err != nil {
+		return domain.Checkpoint{}, false, fmt.Errorf("store checkpoint: %w", err)
+	}
+	return checkpoint, true, nil
+}
+
+func (c *Checkpointer) Proof(ctx context.Context, eventID string) (Proof, error) {
+	event, err := c.eventStore.GetByEventID(ctx, eventID)
+	if err != nil {
+		return Proof{}, err
+	}
+	checkpoint, err := c.checkpoints.FindCheckpoint(ctx, event.StreamID, event.SequenceNumber)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return Proof{}, ErrNotCheckpointed
+		}
+		return Proof{}, fmt.Errorf("find checkpoint: %w", err)
+	}
+	events, err := c.rangeEvents(ctx, event.StreamID, checkpoint.FromSequence, checkpoint.ToSequence)
+	if err != nil {
+		return Proof{}, err
+	}
+	leaves, err := eventLeaves(events)
+	if err != nil {
+		return Proof{}, err
+	}
+	root, err := hex.DecodeString(checkpoint.RootHash)
+	if err != nil || !bytes.Equal(root, MerkleRoot(leaves)) {
+		return Proof{}, fmt.Errorf("checkpoint %s: %w", checkpoint.CheckpointID, ErrCheckpointInvalid)
+	}
+
+	index := int(event.SequenceNumber - checkpoint.FromSequence)
+	path := InclusionProof(leaves, index)
+	auditPath := make([]string, 0, len(path))
+	for _, node := range path {
+		auditPath = append(auditPath, hex.EncodeToString(node))
+	}
+	return Proof{
+		EventID:        event.EventID,
+		StreamID:       event.StreamID,
+		SequenceNumber: event.SequenceNumber,
+		EventHash:      hex.EncodeToString(leaves[index]),
+		LeafIndex:      int64(index),
+		TreeSize:       int64(len(leaves)),
+		AuditPath:      auditPath,
+		Checkpoint:     checkpoint,
+	}, nil
+}
+
+func (c *Checkpointer) rangeEvents(ctx context.Context, streamID string, from, to int64) ([]domain.Event, error) {
+	events := make([]domain.Event, 0, to-from+1)
+	sequence := from
+	for sequence <= to {
+		page, nextSeq, hasMore, err := c.eventStore.QueryByStream(ctx, streamID, sequence, domain.DirectionForward, checkpointPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("query stream %s: %w", streamID, err)
+		}
+		for _, event := range page {
+			if event.SequenceNumber > to {
+				break
+			}
+			if event.SequenceNumber != from+int64(len(events)) {
+				return nil, fmt.Errorf("stream %s has a gap at sequence %d: %w", streamID, from+int64(len(events)), ErrCheckpointInvalid)
+			}
+			events = append(events, event)
+		}
+		if !hasMore || len(page) == 0 {
+			break
+		}
+		sequence = nextSeq
+	}
+	if int64(len(events)) != to-from+1 {
+		return nil, fmt.Errorf("stream %s returned %d of %d events for range %d-%d: %w", streamID, len(events), to-from+1, from, to, ErrCheckpointInvalid)
+	}
+	return events, nil
+}
+
+func eventLeaves(events []domain.Event) ([][]byte, error) {
+	leaves := make([][]byte, 0, len(events))
+	for _, event := range events {
+		hash := event.Hash
+		if hash == "" {
+			computed, err := domain.ComputeEventHash(event)
+			if err != nil {
+				return nil, err
+			}
+			hash = computed
+		}
+		leaf, err := hex.DecodeString(hash)
+		if err != nil {
+			return nil, fmt.Errorf("decode hash of event %s: %w", event.EventID, err)
+		}
+		leaves = append(leaves, leaf)
+	}
+	return leaves, nil
+}
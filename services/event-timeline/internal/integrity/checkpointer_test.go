@@ -0,0 +1,158 @@
+package integrity
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+type memoryCheckpointStore struct {
+	checkpoints []domain.Checkpoint
+}
+
+func (s *memoryCheckpointStore) PutCheckpoint(_ context.Context, checkpoint domain.Checkpoint) error {
+	for _, existing := range s.checkpoints {
+		if existing.StreamID == checkpoint.StreamID && existing.FromSequence == checkpoint.FromSequence {
+			return domain.ErrSequenceConflict
+		}
+	}
+	s.checkpoints = append(s.checkpoints, checkpoint)
+	return nil
+}
+
+func (s *memoryCheckpointStore) LatestCheckpoint(_ context.Context, streamID string) (domain.Checkpoint, error) {
+	var latest *domain.Checkpoint
+	for i := range s.checkpoints {
+		if s.checkpoints[i].StreamID == streamID && (latest == nil || s.checkpoints[i].ToSequence > latest.ToSequence) {
+			latest = &s.checkpoints[i]
+		}
+	}
+	if latest == nil {
+		return domain.Checkpoint{}, domain.ErrNotFound
+	}
+	return *latest, nil
+}
+
+func (s *memoryCheckpointStore) FindCheckpoint(_ context.Context, streamID string, sequence int64) (domain.Checkpoint, error) {
+	for _, checkpoint := range s.checkpoints {
+		if checkpoint.StreamID == streamID && checkpoint.FromSequence <= sequence && sequence <= checkpoint.ToSequence {
+			return checkpoint, nil
+		}
+	}
+	return domain.Checkpoint{}, domain.ErrNotFound
+}
+
+type listStreams []domain.Stream
+
+func (l listStreams) ListStreams(context.Context, string, int32) (storage.StreamPage, error) {
+	return storage.StreamPage{Streams: l}, nil
+}
+
+func newTestCheckpointer(store *streamStore, checkpoints *memoryCheckpointStore, key ed25519.PrivateKey, maxLeaves int) *Checkpointer {
+	return NewCheckpointer(CheckpointerDependencies{
+		EventStore:      store,
+		StreamStore:     listStreams{{StreamID: "stream-1"}},
+		CheckpointStore: checkpoints,
+		SigningKey:      key,
+		Clock:           clock.MockClock{Current: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		MaxLeaves:       maxLeaves,
+	})
+}
+
+func testSigningKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	key, err := ParseSigningKey(base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize)))
+	require.NoError(t, err)
+	return key
+}
+
+func TestCheckpointStreamSignsContiguousRanges(t *testing.T) {
+	store := buildStream(t, 1, 6)
+	checkpoints := &memoryCheckpointStore{}
+	key := testSigningKey(t)
+	checkpointer := newTestCheckpointer(store, checkpoints, key, 4)
+
+	first, created, err := checkpointer.CheckpointStream(context.Background(), "stream-1")
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, int64(1), first.FromSequence)
+	require.Equal(t, int64(4), first.ToSequence)
+	require.True(t, VerifyCheckpointSignature(first, key.Public().(ed25519.PublicKey)))
+
+	second, created, err := checkpointer.CheckpointStream(context.Background(), "stream-1")
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, int64(5), second.FromSequence)
+	require.Equal(t, int64(7), second.ToSequence)
+
+	_, created, err = checkpointer.CheckpointStream(context.Background(), "stream-1")
+	require.NoError(t, err)
+	require.False(t, created)
+
+	tampered := second
+	tampered.RootHash = first.RootHash
+	require.False(t, VerifyCheckpointSignature(tampered, key.Public().(ed25519.PublicKey)))
+}
+
+func TestProofVerifiesAgainstSignedCheckpoint(t *testing.T) {
+	store := buildStream(t, 0, 5)
+	checkpoints := &memoryCheckpointStore{}
+	checkpointer := newTestCheckpointer(store, checkpoints, testSigningKey(t), 0)
+
+	_, err := checkpointer.Proof(context.Background(), "evt-3")
+	require.ErrorIs(t, err, ErrNotCheckpointed)
+
+	_, _, err = checkpointer.CheckpointStream(context.Background(), "stream-1")
+	require.NoError(t, err)
+
+	proof, err := checkpointer.Proof(context.Background(), "evt-3")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), proof.LeafIndex)
+	require.Equal(t, store.events[2].Hash, proof.EventHash)
+
+	publicKey, err := base64.StdEncoding.DecodeString(proof.Checkpoint.PublicKey)
+	require.NoError(t, err)
+	require.True(t, VerifyCheckpointSignature(proof.Checkpoint, publicKey))
+
+	leaf, _ := hex.DecodeString(proof.EventHash)
+	root, _ := hex.DecodeString(proof.Checkpoint.RootHash)
+	path := make([][]byte, 0, len(proof.AuditPath))
+	for _, node := range proof.AuditPath {
+		decoded, err := hex.DecodeString(node)
+		require.NoError(t, err)
+		path = append(path, decoded)
+	}
+	require.True(t, VerifyInclusion(leaf, proof.LeafIndex, proof.TreeSize, path, root))
+
+	store.events[1].Payload = []byte(`{"n":"tampered"}`)
+	store.events[1].Hash = ""
+	_, err = checkpointer.Proof(context.Background(), "evt-3")
+	require.ErrorIs(t, err, ErrCheckpointInvalid)
+
+	_, err = checkpointer.Proof(context.Background(), "missing")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestCheckpointRequiresSigningKey(t *testing.T) {
+	checkpointer := newTestCheckpointer(buildStream(t, 0, 1), &memoryCheckpointStore{}, nil, 0)
+	_, _, err := checkpointer.CheckpointStream(context.Background(), "stream-1")
+	require.ErrorIs(t, err, ErrSigningKeyMissing)
+	require.NoError(t, checkpointer.Run(context.Background()))
+
+	_, err = ParseSigningKey("not-base64!")
+	require.Error(t, err)
+	_, err = ParseSigningKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	require.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package integrity
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleLeafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleNodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+func MerkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	}
+	if len(leaves) == 1 {
+		return LeafHash(leaves[0])
+	}
+	k := splitPoint(len(leaves))
+	return nodeHash(MerkleRoot(leaves[:k]), MerkleRoot(leaves[k:]))
+}
+
+func InclusionProof(leaves [][]byte, index int) [][]byte {
+	if len(leaves) <= 1 {
+		return [][]byte{}
+	}
+	k := splitPoint(len(leaves))
+	if index < k {
+		return append(InclusionProof(leaves[:k], index), MerkleRoot(leaves[k:]))
+	}
+	return append(InclusionProof(leaves[k:], index-k), MerkleRoot(leaves[:k]))
+}
+
+func VerifyInclusion(leaf []byte, index, size int64, proof [][]byte, root []byte) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+	fn, sn := index, size-1
+	hash := LeafHash(leaf)
+	for _, sibling := range proof {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			hash = nodeHash(sibling, hash)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			hash = nodeHash(hash, sibling)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(hash, root)
+}
+
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
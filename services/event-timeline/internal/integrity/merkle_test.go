@@ -0,0 +1,44 @@
+package integrity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprintf("leaf-%d", i))
+	}
+	return leaves
+}
+
+func TestInclusionProofsVerifyForAllTreeShapes(t *testing.T) {
+	for size := 1; size <= 17; size++ {
+		leaves := testLeaves(size)
+		root := MerkleRoot(leaves)
+		for i := range leaves {
+			proof := InclusionProof(leaves, i)
+			require.True(t, VerifyInclusion(leaves[i], int64(i), int64(size), proof, root), "size %d index %d", size, i)
+		}
+	}
+}
+
+func TestVerifyInclusionRejectsTampering(t *testing.T) {
+	leaves := testLeaves(7)
+	root := MerkleRoot(leaves)
+	proof := InclusionProof(leaves, 3)
+
+	require.False(t, VerifyInclusion([]byte("forged"), 3, 7, proof, root))
+	require.False(t, VerifyInclusion(leaves[3], 4, 7, proof, root))
+	require.False(t, VerifyInclusion(leaves[3], 3, 4, proof, root))
+	require.False(t, VerifyInclusion(leaves[3], 3, 7, proof[:len(proof)-1], root))
+}
+
+func TestMerkleRootMatchesRFC6962Construction(t *testing.T) {
+	leaves := testLeaves(3)
+	expected := nodeHash(nodeHash(LeafHash(leaves[0]), LeafHash(leaves[1])), LeafHash(leaves[2]))
+	require.Equal(t, expected, MerkleRoot(leaves))
+}
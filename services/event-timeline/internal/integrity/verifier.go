@@ -0,0 +1,109 @@
+package integrity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+)
+
+const (
+	BreakHashMismatch     = "hash_mismatch"
+	BreakPrevHashMismatch = "prev_hash_mismatch"
+	BreakMissingHash      = "missing_hash"
+	BreakSequenceGap      = "sequence_gap"
+
+	verifyPageSize = int32(200)
+)
+
+type BrokenLink struct {
+	SequenceNumber int64  `json:"sequence_number"`
+	EventID        string `json:"event_id"`
+	Reason         string `json:"reason"`
+	Expected       string `json:"expected"`
+	Actual         string `json:"actual"`
+}
+
+type VerificationReport struct {
+	StreamID       string      `json:"stream_id"`
+	Valid          bool        `json:"valid"`
+	EventsChecked  int64       `json:"events_checked"`
+	UnchainedCount int64       `json:"unchained_events"`
+	FirstChained   int64       `json:"first_chained_sequence,omitempty"`
+	LastSequence   int64       `json:"last_sequence"`
+	HeadHash       string      `json:"head_hash,omitempty"`
+	BrokenLink     *BrokenLink `json:"broken_link,omitempty"`
+}
+
+type Verifier struct {
+	eventStore storage.EventStore
+}
+
+func NewVerifier(eventStore storage.EventStore) *Verifier {
+	return &Verifier{eventStore: eventStore}
+}
+
+func (v *Verifier) VerifyStream(ctx context.Context, streamID string) (VerificationReport, error) {
+	report := VerificationReport{StreamID: streamID, Valid: true}
+	sequence := int64(1)
+	prevHash := ""
+	for {
+		events, nextSeq, hasMore, err := v.eventStore.QueryByStream(ctx, streamID, sequence, domain.DirectionForward, verifyPageSize)
+		if err != nil {
+			return report, fmt.Errorf("query stream %s: %w", streamID, err)
+		}
+		for _, event := range events {
+			report.EventsChecked++
+			if broken := checkLink(event, report.LastSequence, prevHash); broken != nil {
+				report.Valid = false
+				report.BrokenLink = broken
+				return report, nil
+			}
+			report.LastSequence = event.SequenceNumber
+			if event.Hash == "" {
+				report.UnchainedCount++
+				continue
+			}
+			if prevHash == "" {
+				report.FirstChained = event.SequenceNumber
+			}
+			prevHash = event.Hash
+			report.HeadHash = event.Hash
+		}
+		if !hasMore || len(events) == 0 {
+			return report, nil
+		}
+		sequence = nextSeq
+	}
+}
+
+func checkLink(event domain.Event, lastSequence int64, prevHash string) *BrokenLink {
+	broken := func(reason, expected, actual string) *BrokenLink {
+		return &BrokenLink{SequenceNumber: event.SequenceNumber, EventID: event.EventID, Reason: reason, Expected: expected, Actual: actual}
+	}
+	if event.SequenceNumber != lastSequence+1 {
+		return broken(BreakSequenceGap, fmt.Sprintf("%d", lastSequence+1), fmt.Sprintf("%d", event.SequenceNumber))
+	}
+	if event.Hash == "" {
+		if prevHash != "" {
+			return broken(BreakMissingHash, "hash", "")
+		}
+		return nil
+	}
+	expectedPrev := prevHash
+	if expectedPrev == "" {
+		expectedPrev = domain.GenesisHash
+	}
+	if event.PrevHash != expectedPrev {
+		return broken(BreakPrevHashMismatch, expectedPrev, event.PrevHash)
+	}
+	computed, err := domain.ComputeEventHash(event)
+	if err != nil {
+		return broken(BreakHashMismatch, event.Hash, err.Error())
+	}
+	if computed != event.Hash {
+		return broken(BreakHashMismatch, event.Hash, computed)
+	}
+	return nil
+}
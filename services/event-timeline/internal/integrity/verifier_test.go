@@ -0,0 +1,119 @@
+package integrity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+type streamStore struct {
+	events []domain.Event
+}
+
+func (s *streamStore) PutEvent(context.Context, domain.Event) error { return nil }
+func (s *streamStore) GetByEventID(_ context.Context, eventID string) (domain.Event, error) {
+	for _, event := range s.events {
+		if event.EventID == eventID {
+			return event, nil
+		}
+	}
+	return domain.Event{}, domain.ErrNotFound
+}
+func (s *streamStore) FindByIdempotencyKey(context.Context, string, string) (domain.Event, error) {
+	return domain.Event{}, domain.ErrNotFound
+}
+func (s *streamStore) GetLatestSequence(context.Context, string) (int64, error) {
+	return int64(len(s.events)), nil
+}
+func (s *streamStore) QueryByStream(_ context.Context, streamID string, from int64, _ string, limit int32) ([]domain.Event, int64, bool, error) {
+	out := make([]domain.Event, 0)
+	for _, event := range s.events {
+		if event.StreamID == streamID && event.SequenceNumber >= from {
+			out = append(out, event)
+		}
+	}
+	hasMore := int32(len(out)) > limit
+	if hasMore {
+		out = out[:limit]
+	}
+	next := from
+	if len(out) > 0 {
+		next = out[len(out)-1].SequenceNumber + 1
+	}
+	return out, next, hasMore, nil
+}
+
+func buildStream(t *testing.T, legacy, chained int) *streamStore {
+	t.Helper()
+	store := &streamStore{}
+	prev := domain.GenesisHash
+	for seq := 1; seq <= legacy+chained; seq++ {
+		event, err := domain.NewEvent(domain.NewEventInput{
+			EventID:        fmt.Sprintf("evt-%d", seq),
+			StreamID:       "stream-1",
+			SequenceNumber: int64(seq),
+			EventType:      "created",
+			Payload:        json.RawMessage(fmt.Sprintf(`{"n":%d}`, seq)),
+			OccurredAt:     time.Date(2026, 1, 1, 0, 0, seq, 0, time.UTC),
+			IngestedAt:     time.Date(2026, 1, 1, 0, 1, seq, 0, time.UTC),
+		})
+		require.NoError(t, err)
+		if seq > legacy {
+			event, err = domain.ChainEvent(event, prev)
+			require.NoError(t, err)
+			prev = event.Hash
+		}
+		store.events = append(store.events, event)
+	}
+	return store
+}
+
+func TestVerifyStreamAcceptsIntactChainWithLegacyPrefix(t *testing.T) {
+	store := buildStream(t, 2, 3)
+	report, err := NewVerifier(store).VerifyStream(context.Background(), "stream-1")
+	require.NoError(t, err)
+	require.True(t, report.Valid)
+	require.Equal(t, int64(5), report.EventsChecked)
+	require.Equal(t, int64(2), report.UnchainedCount)
+	require.Equal(t, int64(3), report.FirstChained)
+	require.Equal(t, store.events[4].Hash, report.HeadHash)
+}
+
+func TestVerifyStreamReportsFirstBrokenLink(t *testing.T) {
+	store := buildStream(t, 0, 4)
+	store.events[1].Payload = json.RawMessage(`{"n":999}`)
+	store.events[2].Payload = json.RawMessage(`{"n":998}`)
+
+	report, err := NewVerifier(store).VerifyStream(context.Background(), "stream-1")
+	require.NoError(t, err)
+	require.False(t, report.Valid)
+	require.Equal(t, int64(2), report.BrokenLink.SequenceNumber)
+	require.Equal(t, BreakHashMismatch, report.BrokenLink.Reason)
+}
+
+func TestVerifyStreamDetectsRelinkAndDeletion(t *testing.T) {
+	relinked := buildStream(t, 0, 3)
+	relinked.events[2].PrevHash = domain.GenesisHash
+	report, err := NewVerifier(relinked).VerifyStream(context.Background(), "stream-1")
+	require.NoError(t, err)
+	require.Equal(t, BreakPrevHashMismatch, report.BrokenLink.Reason)
+
+	deleted := buildStream(t, 0, 3)
+	deleted.events = append(deleted.events[:1], deleted.events[2:]...)
+	report, err = NewVerifier(deleted).VerifyStream(context.Background(), "stream-1")
+	require.NoError(t, err)
+	require.Equal(t, BreakSequenceGap, report.BrokenLink.Reason)
+	require.Equal(t, int64(3), report.BrokenLink.SequenceNumber)
+
+	unchainedAfterChain := buildStream(t, 0, 3)
+	unchainedAfterChain.events[2].Hash = ""
+	report, err = NewVerifier(unchainedAfterChain).VerifyStream(context.Background(), "stream-1")
+	require.NoError(t, err)
+	require.Equal(t, BreakMissingHash, report.BrokenLink.Reason)
+}
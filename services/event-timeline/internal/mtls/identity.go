@@ -0,0 +1,100 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+)
+
+type Identity struct {
+	SAN     string   `json:"san"`
+	Subject string   `json:"subject"`
+	Scopes  []string `json:"scopes"`
+	Streams []string `json:"streams"`
+	Tenant  string   `json:"tenant"`
+}
+
+type Identities struct {
+	bySAN map[string]Identity
+}
+
+func LoadIdentities(path string) (*Identities, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read mtls identities: %w", err)
+	}
+	var doc struct {
+		Identities []Identity `json:"identities"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("decode mtls identities: %w", err)
+	}
+	return NewIdentities(doc.Identities)
+}
+
+func NewIdentities(list []Identity) (*Identities, error) {
+	ids := &Identities{bySAN: make(map[string]Identity, len(list))}
+	for _, identity := range list {
+		if identity.SAN == "" {
+			return nil, fmt.Errorf("mtls identity without san")
+		}
+		if _, ok := ids.bySAN[identity.SAN]; ok {
+			return nil, fmt.Errorf("duplicate mtls identity %q", identity.SAN)
+		}
+		if identity.Subject == "" {
+			identity.Subject = identity.SAN
+		}
+		if len(identity.Streams) == 0 {
+			return nil, fmt.Errorf("mtls identity %q grants no streams, \"*\" grants all", identity.SAN)
+		}
+		if identity.Tenant == "" {
+			identity.Tenant = tenant.Default
+		}
+		if err := tenant.Validate(identity.Tenant); err != nil {
+			return nil, fmt.Errorf("mtls identity %q: %w", identity.SAN, err)
+		}
+		ids.bySAN[identity.SAN] = identity
+	}
+	return ids, nil
+}
+
+func (ids *Identities) Lookup(cert *x509.Certificate) (Identity, bool) {
+	if ids == nil || cert == nil {
+		return Identity{}, false
+	}
+	sans := append([]string{}, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	for _, san := range sans {
+		if identity, ok := ids.bySAN[san]; ok {
+			return identity, true
+		}
+	}
+	return Identity{}, false
+}
+
+// Peer certificates are only trusted here because the server's
+// VerifyConnection rejects handshakes whose chain does not verify.
+func (ids *Identities) Authenticate(ctx context.Context, state *tls.ConnectionState) (context.Context, bool) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return ctx, false
+	}
+	identity, ok := ids.Lookup(state.PeerCertificates[0])
+	if !ok {
+		return ctx, false
+	}
+	ctx = authz.WithPrincipal(ctx, authz.Principal{
+		Subject:        identity.Subject,
+		Scopes:         identity.Scopes,
+		StreamPatterns: identity.Streams,
+	})
+	return tenant.WithTenant(ctx, identity.Tenant), true
+}
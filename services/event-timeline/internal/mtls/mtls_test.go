@@ -0,0 +1,201 @@
+package mtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (ca testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage, dnsNames []string, uris ...string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     dnsNames,
+	}
+	for _, raw := range uris {
+		uri, err := url.Parse(raw)
+		require.NoError(t, err)
+		tmpl.URIs = append(tmpl.URIs, uri)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestReloaderPicksUpRotatedCertificate(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	start := time.Now().Add(-time.Minute)
+	certPEM, keyPEM := ca.issue(t, 2, x509.ExtKeyUsageServerAuth, []string{"localhost"})
+	writeFile(t, certFile, certPEM, start)
+	writeFile(t, keyFile, keyPEM, start)
+
+	reloader, err := NewReloader(certFile, keyFile, "")
+	require.NoError(t, err)
+	first, err := reloader.Certificate()
+	require.NoError(t, err)
+
+	writeFile(t, certFile, []byte("not a certificate"), start.Add(time.Second))
+	kept, err := reloader.Certificate()
+	require.NoError(t, err)
+	require.Same(t, first, kept, "a broken rotation keeps serving the previous certificate")
+
+	certPEM, keyPEM = ca.issue(t, 3, x509.ExtKeyUsageServerAuth, []string{"localhost"})
+	writeFile(t, keyFile, keyPEM, start.Add(2*time.Second))
+	writeFile(t, certFile, certPEM, start.Add(2*time.Second))
+	rotated, err := reloader.Certificate()
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(rotated.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, int64(3), leaf.SerialNumber.Int64())
+
+	_, err = NewReloader(filepath.Join(dir, "missing.crt"), keyFile, "")
+	require.Error(t, err)
+}
+
+func TestServerConfigVerifiesClientCertificatesAndMapsIdentity(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	serverCert, serverKey := ca.issue(t, 2, x509.ExtKeyUsageServerAuth, []string{"localhost"})
+	require.NoError(t, os.WriteFile(certFile, serverCert, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, serverKey, 0o600))
+	require.NoError(t, os.WriteFile(caFile, ca.pem, 0o600))
+
+	reloader, err := NewReloader(certFile, keyFile, caFile)
+	require.NoError(t, err)
+	identities, err := NewIdentities([]Identity{{SAN: "spiffe://aevum/query-audit", Scopes: []string{authz.ScopeEventsRead}, Streams: []string{"*"}, Tenant: "acme"}})
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, ok := identities.Authenticate(r.Context(), r.TLS)
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		principal, _ := authz.FromContext(ctx)
+		_, _ = w.Write([]byte(principal.Subject + " " + tenant.FromContext(ctx)))
+	}))
+	srv.TLS = reloader.ServerConfig(ClientAuthRequest)
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+	client := func(certPEM, keyPEM []byte) *http.Client {
+		cfg := &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS12}
+		if certPEM != nil {
+			pair, err := tls.X509KeyPair(certPEM, keyPEM)
+			require.NoError(t, err)
+			cfg.Certificates = []tls.Certificate{pair}
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+	}
+
+	clientCert, clientKey := ca.issue(t, 4, x509.ExtKeyUsageClientAuth, nil, "spiffe://aevum/query-audit")
+	resp, err := client(clientCert, clientKey).Get(srv.URL)
+	require.NoError(t, err)
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "spiffe://aevum/query-audit acme", string(body[:n]))
+
+	resp, err = client(nil, nil).Get(srv.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode, "request mode accepts connections without a certificate")
+
+	unknownCert, unknownKey := ca.issue(t, 5, x509.ExtKeyUsageClientAuth, nil, "spiffe://aevum/other")
+	resp, err = client(unknownCert, unknownKey).Get(srv.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	rogue := newTestCA(t, "rogue")
+	rogueCert, rogueKey := rogue.issue(t, 6, x509.ExtKeyUsageClientAuth, nil, "spiffe://aevum/query-audit")
+	_, err = client(rogueCert, rogueKey).Get(srv.URL)
+	require.Error(t, err, "certificates from an untrusted CA fail the handshake")
+}
+
+func TestIdentitiesValidation(t *testing.T) {
+	_, err := NewIdentities([]Identity{{Subject: "no-san"}})
+	require.Error(t, err)
+	_, err = NewIdentities([]Identity{{SAN: "a", Streams: []string{"*"}}, {SAN: "a", Streams: []string{"*"}}})
+	require.ErrorContains(t, err, "duplicate")
+	_, err = NewIdentities([]Identity{{SAN: "a", Streams: []string{"*"}, Tenant: "Not Valid"}})
+	require.Error(t, err)
+	_, err = NewIdentities([]Identity{{SAN: "a", Scopes: []string{authz.ScopeEventsRead}}})
+	require.ErrorContains(t, err, "grants no streams", "identities name their streams, as a missing list grants none")
+
+	path := filepath.Join(t.TempDir(), "identities.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"identities":[{"san":"query-audit.aevum.svc","scopes":["events:read"],"streams":["*"]}]}`), 0o600))
+	identities, err := LoadIdentities(path)
+	require.NoError(t, err)
+	_, ok := identities.Authenticate(context.Background(), nil)
+	require.False(t, ok)
+	identity, ok := identities.Lookup(&x509.Certificate{DNSNames: []string{"query-audit.aevum.svc"}})
+	require.True(t, ok)
+	require.Equal(t, "query-audit.aevum.svc", identity.Subject)
+	require.Equal(t, tenant.Default, identity.Tenant)
+}
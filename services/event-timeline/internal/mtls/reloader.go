@@ -0,0 +1,147 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+type ClientAuth string
+
+const (
+	ClientAuthRequest ClientAuth = "request"
+	ClientAuthRequire ClientAuth = "require"
+)
+
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime map[string]time.Time
+}
+
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("tls certificate and key files are required")
+	}
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile, modTime: map[string]time.Time{}}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reloader) Certificate() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reloadIfChanged()
+	return r.cert, nil
+}
+
+func (r *Reloader) CertPool() *x509.CertPool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reloadIfChanged()
+	return r.pool
+}
+
+func (r *Reloader) ServerConfig(clientAuth ClientAuth) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.Certificate()
+		},
+	}
+	if r.caFile == "" {
+		return cfg
+	}
+	// Client certificates are verified in VerifyConnection instead of through
+	// ClientCAs so a rotated CA bundle applies to the next handshake.
+	cfg.ClientAuth = tls.RequestClientCert
+	if clientAuth == ClientAuthRequire {
+		cfg.ClientAuth = tls.RequireAnyClientCert
+	}
+	cfg.VerifyConnection = func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return nil
+		}
+		return r.verify(state.PeerCertificates, x509.ExtKeyUsageClientAuth)
+	}
+	return cfg
+}
+
+func (r *Reloader) verify(chain []*x509.Certificate, usage x509.ExtKeyUsage) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         r.CertPool(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+	if err != nil {
+		return fmt.Errorf("verify peer certificate: %w", err)
+	}
+	return nil
+}
+
+// A failed reload keeps the previous certificate; files are often replaced
+// one at a time during rotation and the next handshake retries.
+func (r *Reloader) reloadIfChanged() {
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err == nil && !info.ModTime().Equal(r.modTime[file]) {
+			_ = r.reloadLocked()
+			return
+		}
+	}
+}
+
+func (r *Reloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reloadLocked()
+}
+
+func (r *Reloader) reloadLocked() error {
+	modTime := map[string]time.Time{}
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", file, err)
+		}
+		modTime[file] = info.ModTime()
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load tls key pair: %w", err)
+	}
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		raw, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("read ca file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(raw) {
+			return fmt.Errorf("ca file %s contains no certificates", r.caFile)
+		}
+	}
+	r.cert, r.pool, r.modTime = &cert, pool, modTime
+	return nil
+}
@@ -11,6 +11,9 @@ type Metrics struct {
 	ActiveReplays            prometheus.Gauge
 	HTTPRequestTotal         *prometheus.CounterVec
 	HTTPRequestDuration      *prometheus.HistogramVec
+	RateLimitedTotal         *prometheus.CounterVec
+	RateLimitBackendErrors   prometheus.Counter
+	AccessLogDropped         prometheus.Counter
 }
 
 func NewMetrics() *Metrics {
@@ -20,7 +23,7 @@ func NewMetrics() *Metrics {
 		EventsIngestedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "aevum_events_ingested_total",
 			Help: "Total ingested events",
-		}, []string{"stream_id", "event_type", "status"}),
+		}, []string{"tenant", "stream_id", "event_type", "status"}),
 		IngestionDurationSeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Name: "aevum_ingestion_duration_seconds",
 			Help: "Ingestion duration seconds",
@@ -45,6 +48,18 @@ func NewMetrics() *Metrics {
 			Name: "aevum_http_request_duration_seconds",
 			Help: "HTTP request duration seconds",
 		}, []string{"method", "path"}),
+		RateLimitedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aevum_rate_limited_requests_total",
+			Help: "Requests rejected by a rate limit policy",
+		}, []string{"policy", "routes", "tenant"}),
+		RateLimitBackendErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "aevum_rate_limit_backend_errors_total",
+			Help: "Rate limit checks that fell back to local limits because the shared backend failed",
+		}),
+		AccessLogDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "aevum_access_log_dropped_total",
+			Help: "Access log entries dropped because the buffer was full or the write failed",
+		}),
 	}
 	registry.MustRegister(
 		m.EventsIngestedTotal,
@@ -54,12 +69,19 @@ func NewMetrics() *Metrics {
 		m.ActiveReplays,
 		m.HTTPRequestTotal,
 		m.HTTPRequestDuration,
+		m.RateLimitedTotal,
+		m.RateLimitBackendErrors,
+		m.AccessLogDropped,
 	)
 	return m
 }
 
-func (m *Metrics) RecordIngest(streamID, eventType, status string) {
-	m.EventsIngestedTotal.WithLabelValues(streamID, eventType, status).Inc()
+func (m *Metrics) RecordIngest(tenantID, streamID, eventType, status string) {
+	m.EventsIngestedTotal.WithLabelValues(tenantID, streamID, eventType, status).Inc()
+}
+
+func (m *Metrics) RecordRateLimited(policy, routes, tenantID string) {
+	m.RateLimitedTotal.WithLabelValues(policy, routes, tenantID).Inc()
 }
 
 func (m *Metrics) ObserveIngestionDuration(seconds float64) {
@@ -16,8 +16,8 @@ func TestNewMetricsRegistersCollectors(t *testing.T) {
 
 func TestRecordIngestIncrementsCounter(t *testing.T) {
 	metrics := NewMetrics()
-	metrics.RecordIngest("stream-1", "created", "success")
+	metrics.RecordIngest("acme", "stream-1", "created", "success")
 
-	value := testutil.ToFloat64(metrics.EventsIngestedTotal.WithLabelValues("stream-1", "created", "success"))
+	value := testutil.ToFloat64(metrics.EventsIngestedTotal.WithLabelValues("acme", "stream-1", "created", "success"))
 	require.Equal(t, float64(1), value)
 }
@@ -5,6 +5,8 @@ import (
 	"github.com/labstack/echo/v4"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc/stats"
 )
 
 func GinOTelMiddleware(service string) gin.HandlerFunc {
@@ -14,3 +16,7 @@ func GinOTelMiddleware(service string) gin.HandlerFunc {
 func EchoOTelMiddleware(service string) echo.MiddlewareFunc {
 	return otelecho.Middleware(service)
 }
+
+func GRPCOTelHandler() stats.Handler {
+	return otelgrpc.NewServerHandler()
+}
@@ -0,0 +1,245 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/pkg/clock"
+)
+
+const (
+	RoutesIngest = "ingest"
+	RoutesRead   = "read"
+	RoutesAll    = "all"
+
+	KeyIP      = "ip"
+	KeySubject = "subject"
+	KeyTenant  = "tenant"
+	KeyStream  = "stream"
+
+	bucketIdleTTL = 3 * time.Minute
+	sweepInterval = time.Minute
+)
+
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+type Policy struct {
+	Name      string           `json:"name"`
+	Routes    string           `json:"routes"`
+	Key       string           `json:"key"`
+	Rate      float64          `json:"rate"`
+	Burst     int              `json:"burst"`
+	Overrides map[string]Limit `json:"overrides,omitempty"`
+}
+
+type Decision struct {
+	Policy     string
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+func LoadPolicies(path string) ([]Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rate limit policies: %w", err)
+	}
+	var doc struct {
+		Policies []Policy `json:"policies"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("decode rate limit policies: %w", err)
+	}
+	names := map[string]bool{}
+	for i := range doc.Policies {
+		policy := &doc.Policies[i]
+		if policy.Routes == "" {
+			policy.Routes = RoutesAll
+		}
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("rate limit policy %d (%q): %w", i, policy.Name, err)
+		}
+		if names[policy.Name] {
+			return nil, fmt.Errorf("duplicate rate limit policy %q", policy.Name)
+		}
+		names[policy.Name] = true
+	}
+	return doc.Policies, nil
+}
+
+func (p Policy) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	switch p.Routes {
+	case RoutesIngest, RoutesRead, RoutesAll:
+	default:
+		return fmt.Errorf("unknown routes %q", p.Routes)
+	}
+	switch p.Key {
+	case KeyIP, KeySubject, KeyTenant, KeyStream:
+	default:
+		return fmt.Errorf("unknown key %q", p.Key)
+	}
+	if err := (Limit{Rate: p.Rate, Burst: p.Burst}).validate(); err != nil {
+		return err
+	}
+	for key, limit := range p.Overrides {
+		if err := limit.validate(); err != nil {
+			return fmt.Errorf("override %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (l Limit) validate() error {
+	if l.Rate <= 0 || l.Burst <= 0 {
+		return fmt.Errorf("rate and burst must be greater than zero")
+	}
+	return nil
+}
+
+func (p Policy) Applies(routes string) bool {
+	return p.Routes == RoutesAll || p.Routes == routes
+}
+
+func (p Policy) LimitFor(key string) Limit {
+	if limit, ok := p.Overrides[key]; ok {
+		return limit
+	}
+	return Limit{Rate: p.Rate, Burst: p.Burst}
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	limit    Limit
+	lastSeen time.Time
+}
+
+type Backend interface {
+	TakeAll(ctx context.Context, requests []Request) (Decision, error)
+}
+
+type MemoryBackend struct {
+	clock     clock.Clock
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryBackend(clk clock.Clock) *MemoryBackend {
+	if clk == nil {
+		clk = clock.RealClock{}
+	}
+	return &MemoryBackend{clock: clk, buckets: map[string]*bucket{}}
+}
+
+type Request struct {
+	Policy string
+	Key    string
+	Limit  Limit
+}
+
+func (l *MemoryBackend) TakeAll(_ context.Context, requests []Request) (Decision, error) {
+	now := l.clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	var (
+		worst        Decision
+		reservations []*rate.Reservation
+	)
+	for i, req := range requests {
+		b := l.bucket(req, now)
+		available := b.limiter.TokensAt(now)
+		reservation := b.limiter.ReserveN(now, 1)
+		reservations = append(reservations, reservation)
+		decision := Decision{Policy: req.Policy, Limit: req.Limit.Burst}
+		if reservation.OK() && reservation.DelayFrom(now) == 0 {
+			decision.Allowed = true
+			available--
+		} else {
+			decision.RetryAfter = secondsFor(1-available, req.Limit.Rate)
+		}
+		decision.Remaining = int(math.Max(0, math.Floor(available)))
+		decision.Reset = secondsFor(float64(req.Limit.Burst)-available, req.Limit.Rate)
+		if i == 0 {
+			worst = decision
+		} else {
+			worst = MoreRestrictive(worst, decision)
+		}
+	}
+	if !worst.Allowed {
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+	}
+	return worst, nil
+}
+
+func (l *MemoryBackend) bucket(req Request, now time.Time) *bucket {
+	id := req.Policy + "|" + req.Key
+	b, ok := l.buckets[id]
+	if !ok || b.limit != req.Limit {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(req.Limit.Rate), req.Limit.Burst), limit: req.Limit}
+		l.buckets[id] = b
+	}
+	b.lastSeen = now
+	return b
+}
+
+func (l *MemoryBackend) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for id, b := range l.buckets {
+		if now.Sub(b.lastSeen) > bucketIdleTTL {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+func secondsFor(tokens, perSecond float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(tokens/perSecond)) * time.Second
+}
+
+func MoreRestrictive(a, b Decision) Decision {
+	switch {
+	case a.Allowed != b.Allowed:
+		if !a.Allowed {
+			return a
+		}
+		return b
+	case !a.Allowed && a.RetryAfter != b.RetryAfter:
+		if a.RetryAfter > b.RetryAfter {
+			return a
+		}
+		return b
+	case a.Remaining != b.Remaining:
+		if a.Remaining < b.Remaining {
+			return a
+		}
+		return b
+	case a.Reset >= b.Reset:
+		return a
+	default:
+		return b
+	}
+}
@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func writePolicies(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policies.json")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	return path
+}
+
+func TestLoadPolicies(t *testing.T) {
+	policies, err := LoadPolicies(writePolicies(t, `{"policies":[
+		{"name":"ingest-tenant","routes":"ingest","key":"tenant","rate":10,"burst":20,"overrides":{"acme":{"rate":50,"burst":100}}},
+		{"name":"any-subject","key":"subject","rate":5,"burst":5}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	require.Equal(t, Limit{Rate: 50, Burst: 100}, policies[0].LimitFor("acme"))
+	require.Equal(t, Limit{Rate: 10, Burst: 20}, policies[0].LimitFor("globex"))
+	require.True(t, policies[0].Applies(RoutesIngest))
+	require.False(t, policies[0].Applies(RoutesRead))
+	require.True(t, policies[1].Applies(RoutesRead), "routes default to all")
+
+	for name, body := range map[string]string{
+		"unknown key":    `{"policies":[{"name":"p","key":"region","rate":1,"burst":1}]}`,
+		"unknown routes": `{"policies":[{"name":"p","routes":"admin","key":"ip","rate":1,"burst":1}]}`,
+		"zero burst":     `{"policies":[{"name":"p","key":"ip","rate":1}]}`,
+		"bad override":   `{"policies":[{"name":"p","key":"ip","rate":1,"burst":1,"overrides":{"x":{"rate":0,"burst":1}}}]}`,
+		"duplicate":      `{"policies":[{"name":"p","key":"ip","rate":1,"burst":1},{"name":"p","key":"tenant","rate":1,"burst":1}]}`,
+		"malformed":      `{"policies":`,
+	} {
+		_, err := LoadPolicies(writePolicies(t, body))
+		require.Error(t, err, name)
+	}
+}
+
+type backendFixture struct {
+	backend Backend
+	advance func(time.Duration)
+}
+
+func backends(t *testing.T) map[string]backendFixture {
+	t.Helper()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clk := &fakeClock{now: start}
+
+	server := miniredis.RunT(t)
+	server.SetTime(start)
+	redisNow := start
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return map[string]backendFixture{
+		"memory": {
+			backend: NewMemoryBackend(clk),
+			advance: func(d time.Duration) { clk.now = clk.now.Add(d) },
+		},
+		"redis": {
+			backend: NewRedisBackend(client, ""),
+			advance: func(d time.Duration) {
+				redisNow = redisNow.Add(d)
+				server.SetTime(redisNow)
+			},
+		},
+	}
+}
+
+func take(t *testing.T, backend Backend, requests ...Request) Decision {
+	t.Helper()
+	decision, err := backend.TakeAll(context.Background(), requests)
+	require.NoError(t, err)
+	return decision
+}
+
+func TestBackendsReportQuota(t *testing.T) {
+	for name, fx := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			req := Request{Policy: "p", Key: "k", Limit: Limit{Rate: 1, Burst: 3}}
+
+			for want := 2; want >= 0; want-- {
+				decision := take(t, fx.backend, req)
+				require.True(t, decision.Allowed)
+				require.Equal(t, 3, decision.Limit)
+				require.Equal(t, want, decision.Remaining)
+			}
+			denied := take(t, fx.backend, req)
+			require.False(t, denied.Allowed)
+			require.Equal(t, time.Second, denied.RetryAfter)
+			require.Equal(t, 3*time.Second, denied.Reset)
+
+			require.True(t, take(t, fx.backend, Request{Policy: "p", Key: "other", Limit: req.Limit}).Allowed, "keys have separate buckets")
+			require.True(t, take(t, fx.backend, Request{Policy: "q", Key: "k", Limit: req.Limit}).Allowed, "policies have separate buckets")
+
+			fx.advance(time.Second)
+			require.True(t, take(t, fx.backend, req).Allowed)
+			require.False(t, take(t, fx.backend, req).Allowed)
+		})
+	}
+}
+
+func TestBackendsRefundWhenAnyBucketDenies(t *testing.T) {
+	for name, fx := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			tenant := Request{Policy: "tenant", Key: "acme", Limit: Limit{Rate: 1, Burst: 2}}
+			stream := Request{Policy: "stream", Key: "acme#s1", Limit: Limit{Rate: 1, Burst: 1}}
+
+			require.True(t, take(t, fx.backend, tenant, stream).Allowed)
+			denied := take(t, fx.backend, tenant, stream)
+			require.False(t, denied.Allowed)
+			require.Equal(t, "stream", denied.Policy)
+
+			other := Request{Policy: "stream", Key: "acme#s2", Limit: Limit{Rate: 1, Burst: 1}}
+			decision := take(t, fx.backend, tenant, other)
+			require.True(t, decision.Allowed, "the denied request must not use up the tenant quota")
+			require.Equal(t, 0, decision.Remaining)
+		})
+	}
+}
+
+func TestRedisBackendSharesQuotaAcrossReplicas(t *testing.T) {
+	server := miniredis.RunT(t)
+	replicaA := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	replicaB := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = replicaA.Close(); _ = replicaB.Close() })
+	req := Request{Policy: "tenant", Key: "acme", Limit: Limit{Rate: 0.1, Burst: 2}}
+
+	require.True(t, take(t, NewRedisBackend(replicaA, ""), req).Allowed)
+	require.True(t, take(t, NewRedisBackend(replicaB, ""), req).Allowed)
+	require.False(t, take(t, NewRedisBackend(replicaA, ""), req).Allowed)
+	require.True(t, server.Exists("{aevum-rl}:tenant|acme"))
+
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	_, err := NewRedisBackend(replicaA, "").TakeAll(context.Background(), []Request{req})
+	require.Error(t, err)
+}
+
+func TestMoreRestrictive(t *testing.T) {
+	allowed := Decision{Policy: "a", Allowed: true, Remaining: 1}
+	lower := Decision{Policy: "b", Allowed: true, Remaining: 0}
+	denied := Decision{Policy: "c", Allowed: false, RetryAfter: time.Second}
+	deniedLonger := Decision{Policy: "d", Allowed: false, RetryAfter: 5 * time.Second}
+
+	require.Equal(t, "b", MoreRestrictive(allowed, lower).Policy)
+	require.Equal(t, "c", MoreRestrictive(lower, denied).Policy)
+	require.Equal(t, "d", MoreRestrictive(denied, deniedLonger).Policy)
+}
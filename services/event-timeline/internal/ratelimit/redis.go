@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const DefaultRedisKeyPrefix = "aevum-rl"
+
+// gcraScript checks every bucket against the server clock and only stores the
+// new arrival times when all buckets allow the request.
+var gcraScript = redis.NewScript(`
+local now_parts = redis.call('TIME')
+local now = tonumber(now_parts[1]) * 1000 + math.floor(tonumber(now_parts[2]) / 1000)
+local results = {}
+local new_tats = {}
+local allowed_all = true
+for i, key in ipairs(KEYS) do
+	local interval = tonumber(ARGV[(i - 1) * 2 + 1])
+	local burst = tonumber(ARGV[(i - 1) * 2 + 2])
+	local tat = tonumber(redis.call('GET', key) or now)
+	if tat < now then
+		tat = now
+	end
+	local new_tat = tat + interval
+	local allow_at = new_tat - interval * burst
+	if now < allow_at then
+		allowed_all = false
+		results[i] = {0, 0, math.ceil(tat - now), math.ceil(allow_at - now)}
+	else
+		results[i] = {1, math.floor((now - allow_at) / interval), math.ceil(new_tat - now), 0}
+	end
+	new_tats[i] = new_tat
+end
+if allowed_all then
+	for i, key in ipairs(KEYS) do
+		redis.call('SET', key, new_tats[i], 'PX', math.ceil(new_tats[i] - now) + 1000)
+	end
+end
+return results
+`)
+
+type RedisBackend struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewRedisBackend(client redis.Scripter, prefix string) *RedisBackend {
+	if prefix == "" {
+		prefix = DefaultRedisKeyPrefix
+	}
+	return &RedisBackend{client: client, prefix: prefix}
+}
+
+func (b *RedisBackend) TakeAll(ctx context.Context, requests []Request) (Decision, error) {
+	if len(requests) == 0 {
+		return Decision{Allowed: true}, nil
+	}
+	keys := make([]string, 0, len(requests))
+	args := make([]any, 0, len(requests)*2)
+	for _, req := range requests {
+		// The hash tag keeps every key of one script call in the same cluster slot.
+		keys = append(keys, "{"+b.prefix+"}:"+req.Policy+"|"+req.Key)
+		args = append(args, strconv.FormatFloat(1000/req.Limit.Rate, 'f', -1, 64), req.Limit.Burst)
+	}
+	raw, err := gcraScript.Run(ctx, b.client, keys, args...).Slice()
+	if err != nil {
+		return Decision{}, fmt.Errorf("run rate limit script: %w", err)
+	}
+	if len(raw) != len(requests) {
+		return Decision{}, fmt.Errorf("rate limit script returned %d results for %d buckets", len(raw), len(requests))
+	}
+
+	var worst Decision
+	for i, item := range raw {
+		values, ok := item.([]any)
+		if !ok || len(values) != 4 {
+			return Decision{}, fmt.Errorf("unexpected rate limit script result %v", item)
+		}
+		ints := make([]int64, len(values))
+		for j, v := range values {
+			if ints[j], ok = v.(int64); !ok {
+				return Decision{}, fmt.Errorf("unexpected rate limit script value %v", v)
+			}
+		}
+		decision := Decision{
+			Policy:     requests[i].Policy,
+			Allowed:    ints[0] == 1,
+			Limit:      requests[i].Limit.Burst,
+			Remaining:  int(ints[1]),
+			Reset:      roundUpSeconds(time.Duration(ints[2]) * time.Millisecond),
+			RetryAfter: roundUpSeconds(time.Duration(ints[3]) * time.Millisecond),
+		}
+		if i == 0 {
+			worst = decision
+		} else {
+			worst = MoreRestrictive(worst, decision)
+		}
+	}
+	return worst, nil
+}
+
+func roundUpSeconds(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return (d + time.Second - 1) / time.Second * time.Second
+}
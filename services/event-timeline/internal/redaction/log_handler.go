@@ -0,0 +1,67 @@
+package redaction
+
+import (
+	"context"
+	"log/slog"
+)
+
+type LogHandler struct {
+	next   slog.Handler
+	policy *Policy
+}
+
+func NewLogHandler(next slog.Handler, policy *Policy) slog.Handler {
+	if policy.Empty() {
+		return next
+	}
+	return &LogHandler{next: next, policy: policy}
+}
+
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr, ok := h.redactAttr(attr); ok {
+			redacted.AddAttrs(attr)
+		}
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		if attr, ok := h.redactAttr(attr); ok {
+			redacted = append(redacted, attr)
+		}
+	}
+	return &LogHandler{next: h.next.WithAttrs(redacted), policy: h.policy}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name), policy: h.policy}
+}
+
+func (h *LogHandler) redactAttr(attr slog.Attr) (slog.Attr, bool) {
+	if mode, ok := h.policy.MatchesKey(attr.Key); ok {
+		if mode == ModeDrop {
+			return slog.Attr{}, false
+		}
+		return slog.Any(attr.Key, h.policy.Value(mode, attr.Value.Resolve().Any())), true
+	}
+	value := attr.Value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		return attr, true
+	}
+	group := make([]slog.Attr, 0, len(value.Group()))
+	for _, child := range value.Group() {
+		if child, ok := h.redactAttr(child); ok {
+			group = append(group, child)
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: slog.GroupValue(group...)}, true
+}
@@ -0,0 +1,221 @@
+package redaction
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+type Mode string
+
+const (
+	ModeMask Mode = "mask"
+	ModeHash Mode = "hash"
+	ModeDrop Mode = "drop"
+
+	MaskedValue  = "***"
+	anyEventType = "*"
+)
+
+type Rule struct {
+	Paths       []string `json:"paths"`
+	KeyPatterns []string `json:"key_patterns"`
+	Mode        Mode     `json:"mode"`
+
+	keys []*regexp.Regexp
+}
+
+type Policy struct {
+	Rules    map[string][]Rule `json:"rules"`
+	HashSalt string            `json:"hash_salt"`
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read redaction policy: %w", err)
+	}
+	return ParsePolicy(raw)
+}
+
+func ParsePolicy(raw []byte) (*Policy, error) {
+	var policy Policy
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		return nil, fmt.Errorf("decode redaction policy: %w", err)
+	}
+	for eventType, rules := range policy.Rules {
+		for i := range rules {
+			rule := &rules[i]
+			switch rule.Mode {
+			case ModeMask, ModeHash, ModeDrop:
+			default:
+				return nil, fmt.Errorf("redaction policy %q: unknown mode %q", eventType, rule.Mode)
+			}
+			if len(rule.Paths) == 0 && len(rule.KeyPatterns) == 0 {
+				return nil, fmt.Errorf("redaction policy %q: rule needs paths or key_patterns", eventType)
+			}
+			for _, path := range rule.Paths {
+				if path == "" || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+					return nil, fmt.Errorf("redaction policy %q: invalid path %q", eventType, path)
+				}
+			}
+			for _, pattern := range rule.KeyPatterns {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("redaction policy %q: key pattern %q: %w", eventType, pattern, err)
+				}
+				rule.keys = append(rule.keys, re)
+			}
+		}
+	}
+	return &policy, nil
+}
+
+func (p *Policy) Empty() bool {
+	if p == nil {
+		return true
+	}
+	for _, rules := range p.Rules {
+		if len(rules) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Policy) rulesFor(eventType string) []Rule {
+	rules := append([]Rule{}, p.Rules[anyEventType]...)
+	if eventType != anyEventType {
+		rules = append(rules, p.Rules[eventType]...)
+	}
+	return rules
+}
+
+func (p *Policy) Redact(eventType string, payload json.RawMessage) (json.RawMessage, error) {
+	if p.Empty() || len(payload) == 0 {
+		return payload, nil
+	}
+	rules := p.rulesFor(eventType)
+	if len(rules) == 0 {
+		return payload, nil
+	}
+	var doc any
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+	for _, rule := range rules {
+		for _, path := range rule.Paths {
+			doc = p.redactPath(doc, strings.Split(path, "."), rule.Mode)
+		}
+	}
+	// Paths run first so a value dropped by path is not also matched by a key pattern.
+	for _, rule := range rules {
+		if len(rule.keys) > 0 {
+			doc = p.redactKeys(doc, rule)
+		}
+	}
+	redacted, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	return redacted, nil
+}
+
+// Log attributes carry no event type, so key patterns of every event type apply.
+func (p *Policy) MatchesKey(key string) (Mode, bool) {
+	if p == nil {
+		return "", false
+	}
+	for _, rules := range p.Rules {
+		for _, rule := range rules {
+			for _, re := range rule.keys {
+				if re.MatchString(key) {
+					return rule.Mode, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func (p *Policy) redactPath(value any, segments []string, mode Mode) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		child, ok := typed[segments[0]]
+		if !ok {
+			return typed
+		}
+		if len(segments) == 1 {
+			p.replace(typed, segments[0], child, mode)
+			return typed
+		}
+		typed[segments[0]] = p.redactPath(child, segments[1:], mode)
+		return typed
+	case []any:
+		for i, item := range typed {
+			typed[i] = p.redactPath(item, segments, mode)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+func (p *Policy) redactKeys(value any, rule Rule) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, child := range typed {
+			if matchesAny(rule.keys, key) {
+				p.replace(typed, key, child, rule.Mode)
+				continue
+			}
+			typed[key] = p.redactKeys(child, rule)
+		}
+		return typed
+	case []any:
+		for i, item := range typed {
+			typed[i] = p.redactKeys(item, rule)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+func (p *Policy) replace(parent map[string]any, key string, value any, mode Mode) {
+	if mode == ModeDrop {
+		delete(parent, key)
+		return
+	}
+	parent[key] = p.Value(mode, value)
+}
+
+// Hashes are salted so equal values still correlate across events, but
+// cannot be matched against a dictionary of known values without the salt.
+func (p *Policy) Value(mode Mode, value any) any {
+	if mode != ModeHash {
+		return MaskedValue
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded = []byte(fmt.Sprint(value))
+	}
+	sum := sha256.Sum256(append([]byte(p.HashSalt), encoded...))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func matchesAny(patterns []*regexp.Regexp, key string) bool {
+	for _, re := range patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
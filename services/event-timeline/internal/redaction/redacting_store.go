@@ -0,0 +1,64 @@
+package redaction
+
+import (
+	"context"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/storage"
+)
+
+type RedactingEventStore struct {
+	storage.EventStore
+	policy *Policy
+}
+
+func NewRedactingEventStore(store storage.EventStore, policy *Policy) storage.EventStore {
+	if policy.Empty() {
+		return store
+	}
+	return &RedactingEventStore{EventStore: store, policy: policy}
+}
+
+func (s *RedactingEventStore) GetByEventID(ctx context.Context, eventID string) (domain.Event, error) {
+	event, err := s.EventStore.GetByEventID(ctx, eventID)
+	if err != nil || canReadPII(ctx) {
+		return event, err
+	}
+	return s.redact(event)
+}
+
+func (s *RedactingEventStore) FindByIdempotencyKey(ctx context.Context, streamID, key string) (domain.Event, error) {
+	event, err := s.EventStore.FindByIdempotencyKey(ctx, streamID, key)
+	if err != nil || canReadPII(ctx) {
+		return event, err
+	}
+	return s.redact(event)
+}
+
+func (s *RedactingEventStore) QueryByStream(ctx context.Context, streamID string, fromSequence int64, direction string, limit int32) ([]domain.Event, int64, bool, error) {
+	events, next, hasMore, err := s.EventStore.QueryByStream(ctx, streamID, fromSequence, direction, limit)
+	if err != nil || canReadPII(ctx) {
+		return events, next, hasMore, err
+	}
+	for i := range events {
+		if events[i], err = s.redact(events[i]); err != nil {
+			return nil, 0, false, err
+		}
+	}
+	return events, next, hasMore, nil
+}
+
+func (s *RedactingEventStore) redact(event domain.Event) (domain.Event, error) {
+	payload, err := s.policy.Redact(event.EventType, event.Payload)
+	if err != nil {
+		return domain.Event{}, err
+	}
+	event.Payload = payload
+	return event, nil
+}
+
+func canReadPII(ctx context.Context) bool {
+	principal, ok := authz.FromContext(ctx)
+	return ok && principal.HasScope(authz.ScopePIIRead)
+}
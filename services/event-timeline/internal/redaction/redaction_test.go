@@ -0,0 +1,143 @@
+package redaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/authz"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+const testPolicy = `{
+	"hash_salt": "pepper",
+	"rules": {
+		"*": [{"key_patterns": ["(?i)^(ssn|phone)$"], "mode": "hash"}],
+		"signup": [
+			{"paths": ["customer.email"], "mode": "mask"},
+			{"paths": ["addresses.street", "notes"], "mode": "drop"}
+		]
+	}
+}`
+
+func mustPolicy(t *testing.T) *Policy {
+	t.Helper()
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+	return policy
+}
+
+func TestPolicyRedactsPathsAndKeyPatterns(t *testing.T) {
+	policy := mustPolicy(t)
+	payload := json.RawMessage(`{"customer":{"email":"a@b.test","name":"Ada","SSN":"123-45-6789"},"addresses":[{"street":"1 Main St","city":"Berlin"},{"street":"2 Side St"}],"notes":"call after 5","amount":10.50}`)
+
+	redacted, err := policy.Redact("signup", payload)
+	require.NoError(t, err)
+	for _, secret := range []string{"a@b.test", "123-45-6789", "Main St", "Side St", "call after 5"} {
+		require.NotContains(t, string(redacted), secret)
+	}
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(redacted, &doc))
+	customer := doc["customer"].(map[string]any)
+	require.Equal(t, MaskedValue, customer["email"])
+	require.Equal(t, "Ada", customer["name"])
+	require.Equal(t, policy.Value(ModeHash, "123-45-6789"), customer["SSN"])
+	require.Regexp(t, `^sha256:[0-9a-f]{64}$`, customer["SSN"])
+	require.Equal(t, []any{map[string]any{"city": "Berlin"}, map[string]any{}}, doc["addresses"])
+	require.NotContains(t, doc, "notes")
+	require.Contains(t, string(redacted), `"amount":10.50`, "numbers keep their precision")
+
+	other, err := policy.Redact("order_placed", json.RawMessage(`{"customer":{"email":"a@b.test","phone":"555"}}`))
+	require.NoError(t, err)
+	require.Contains(t, string(other), "a@b.test", "path rules only apply to their event type")
+	require.NotContains(t, string(other), "555")
+}
+
+func TestHashDependsOnSalt(t *testing.T) {
+	policy := mustPolicy(t)
+	unsalted := &Policy{}
+	require.Equal(t, policy.Value(ModeHash, "555"), policy.Value(ModeHash, "555"))
+	require.NotEqual(t, policy.Value(ModeHash, "555"), unsalted.Value(ModeHash, "555"))
+}
+
+func TestLoadPolicyRejectsInvalidRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redaction.json")
+	for _, raw := range []string{
+		`{"rules":{"*":[{"paths":["a"],"mode":"shuffle"}]}}`,
+		`{"rules":{"*":[{"mode":"mask"}]}}`,
+		`{"rules":{"*":[{"paths":["a..b"],"mode":"mask"}]}}`,
+		`{"rules":{"*":[{"key_patterns":["("],"mode":"mask"}]}}`,
+		`{"rules":`,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(raw), 0o600))
+		_, err := LoadPolicy(path)
+		require.Error(t, err, raw)
+	}
+	require.NoError(t, os.WriteFile(path, []byte(testPolicy), 0o600))
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+	require.False(t, policy.Empty())
+	require.True(t, (*Policy)(nil).Empty())
+}
+
+type staticEventStore struct {
+	domain.Event
+}
+
+func (s staticEventStore) PutEvent(context.Context, domain.Event) error { return nil }
+func (s staticEventStore) GetByEventID(context.Context, string) (domain.Event, error) {
+	return s.Event, nil
+}
+func (s staticEventStore) FindByIdempotencyKey(context.Context, string, string) (domain.Event, error) {
+	return s.Event, nil
+}
+func (s staticEventStore) GetLatestSequence(context.Context, string) (int64, error) { return 1, nil }
+func (s staticEventStore) QueryByStream(context.Context, string, int64, string, int32) ([]domain.Event, int64, bool, error) {
+	return []domain.Event{s.Event}, 2, false, nil
+}
+
+func TestRedactingEventStoreHonoursPIIScope(t *testing.T) {
+	inner := staticEventStore{domain.Event{StreamID: "s1", EventType: "signup", Payload: json.RawMessage(`{"customer":{"email":"a@b.test"}}`)}}
+	store := NewRedactingEventStore(inner, mustPolicy(t))
+
+	reader := authz.WithPrincipal(context.Background(), authz.Principal{Scopes: []string{authz.ScopeEventsRead}})
+	event, err := store.GetByEventID(reader, "evt-1")
+	require.NoError(t, err)
+	require.NotContains(t, string(event.Payload), "a@b.test")
+	events, _, _, err := store.QueryByStream(reader, "s1", 1, domain.DirectionForward, 10)
+	require.NoError(t, err)
+	require.NotContains(t, string(events[0].Payload), "a@b.test")
+	event, err = store.GetByEventID(context.Background(), "evt-1")
+	require.NoError(t, err)
+	require.NotContains(t, string(event.Payload), "a@b.test", "calls without a principal are redacted")
+
+	privileged := authz.WithPrincipal(context.Background(), authz.Principal{Scopes: []string{authz.ScopeEventsRead, authz.ScopePIIRead}})
+	event, err = store.FindByIdempotencyKey(privileged, "s1", "key")
+	require.NoError(t, err)
+	require.Contains(t, string(event.Payload), "a@b.test")
+
+	require.Equal(t, inner, NewRedactingEventStore(inner, nil), "without a policy reads are not wrapped")
+}
+
+func TestLogHandlerRedactsMatchingAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil), mustPolicy(t)))
+
+	logger.With(slog.String("phone", "555-0100")).Info("lookup",
+		slog.String("event_id", "evt-1"),
+		slog.Group("customer", slog.String("ssn", "123-45-6789"), slog.String("name", "Ada")),
+	)
+	out := buf.String()
+	require.NotContains(t, out, "555-0100")
+	require.NotContains(t, out, "123-45-6789")
+	require.Contains(t, out, `"event_id":"evt-1"`)
+	require.Contains(t, out, `"name":"Ada"`)
+	require.Contains(t, out, "sha256:")
+}
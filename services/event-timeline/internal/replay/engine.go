@@ -29,7 +29,7 @@ func (e *Engine) Replay(ctx context.Context, req domain.ReplayRequest) (<-chan d
 		defer close(eventsCh)
 		defer close(errCh)
 		defer e.metrics.ActiveReplays.Dec()
-		defer e.metrics.ObserveReplayDuration(time.Since(start).Seconds())
+		defer func() { e.metrics.ObserveReplayDuration(time.Since(start).Seconds()) }()
 
 		sequence := int64(1)
 		for {
@@ -16,8 +16,7 @@ type replayStore struct {
 	events []domain.Event
 }
 
-func (replayStore) PutEvent(context.Context, domain.Event) error         { return nil }
-func (replayStore) PutEventsBatch(context.Context, []domain.Event) error { return nil }
+func (replayStore) PutEvent(context.Context, domain.Event) error { return nil }
 func (replayStore) GetByEventID(context.Context, string) (domain.Event, error) {
 	return domain.Event{}, domain.ErrNotFound
 }
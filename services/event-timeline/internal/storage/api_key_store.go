@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+type APIKeyStore interface {
+	CreateAPIKey(ctx context.Context, key domain.APIKey) error
+	GetAPIKey(ctx context.Context, keyID string) (domain.APIKey, error)
+	ListAPIKeys(ctx context.Context, tenantID string) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID string, at time.Time) (domain.APIKey, error)
+	RotateAPIKey(ctx context.Context, rotated domain.APIKey) error
+	TouchAPIKey(ctx context.Context, keyID string, at time.Time) error
+}
@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+type CheckpointStore interface {
+	PutCheckpoint(ctx context.Context, checkpoint domain.Checkpoint) error
+	LatestCheckpoint(ctx context.Context, streamID string) (domain.Checkpoint, error)
+	FindCheckpoint(ctx context.Context, streamID string, sequence int64) (domain.Checkpoint, error)
+}
@@ -0,0 +1,14 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+type DataKeyStore interface {
+	GetDataKey(ctx context.Context, streamID string) (domain.DataKey, error)
+	CreateDataKey(ctx context.Context, key domain.DataKey) error
+	ShredDataKey(ctx context.Context, streamID string, at time.Time) (domain.DataKey, error)
+}
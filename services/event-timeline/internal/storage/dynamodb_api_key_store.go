@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+const (
+	apiKeyPKPrefix = "APIKEY#"
+	apiKeySK       = "APIKEY"
+)
+
+// Key IDs are ULIDs and unique across tenants, so items are keyed by ID
+// alone and a presented key can be looked up before its tenant is known.
+type DynamoDBAPIKeyStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBAPIKeyStore(client *dynamodb.Client, tableName string) *DynamoDBAPIKeyStore {
+	return &DynamoDBAPIKeyStore{client: client, tableName: tableName}
+}
+
+func apiKeyItemKey(keyID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: apiKeyPKPrefix + keyID},
+		"SK": &types.AttributeValueMemberS{Value: apiKeySK},
+	}
+}
+
+func (s *DynamoDBAPIKeyStore) CreateAPIKey(ctx context.Context, key domain.APIKey) error {
+	item, err := attributevalue.MarshalMap(key)
+	if err != nil {
+		return fmt.Errorf("marshal api key: %w", err)
+	}
+	for name, value := range apiKeyItemKey(key.KeyID) {
+		item[name] = value
+	}
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+	if err != nil {
+		return fmt.Errorf("put api key: %w", err)
+	}
+	return nil
+}
+
+func (s *DynamoDBAPIKeyStore) GetAPIKey(ctx context.Context, keyID string) (domain.APIKey, error) {
+	resp, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            apiKeyItemKey(keyID),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("get api key: %w", err)
+	}
+	if len(resp.Item) == 0 {
+		return domain.APIKey{}, fmt.Errorf("api key not found: %w", domain.ErrNotFound)
+	}
+	return unmarshalAPIKey(resp.Item)
+}
+
+func (s *DynamoDBAPIKeyStore) ListAPIKeys(ctx context.Context, tenantID string) ([]domain.APIKey, error) {
+	filter := "begins_with(PK, :prefix)"
+	values := map[string]types.AttributeValue{
+		":prefix": &types.AttributeValueMemberS{Value: apiKeyPKPrefix},
+	}
+	if tenantID != "" {
+		filter += " AND TenantID = :tenant"
+		values[":tenant"] = &types.AttributeValueMemberS{Value: tenantID}
+	}
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(s.tableName),
+		FilterExpression:          aws.String(filter),
+		ExpressionAttributeValues: values,
+	}
+	keys := make([]domain.APIKey, 0)
+	for {
+		resp, err := s.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("scan api keys: %w", err)
+		}
+		for _, item := range resp.Items {
+			key, err := unmarshalAPIKey(item)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+		if len(resp.LastEvaluatedKey) == 0 {
+			return keys, nil
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
+func (s *DynamoDBAPIKeyStore) RevokeAPIKey(ctx context.Context, keyID string, at time.Time) (domain.APIKey, error) {
+	revokedAt, err := attributevalue.Marshal(at.UTC())
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("marshal revoked_at: %w", err)
+	}
+	resp, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.tableName),
+		Key:                 apiKeyItemKey(keyID),
+		UpdateExpression:    aws.String("SET RevokedAt = :at REMOVE PreviousSecretHash, PreviousExpiresAt"),
+		ConditionExpression: aws.String("attribute_exists(PK) AND attribute_not_exists(RevokedAt)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":at": revokedAt,
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return s.GetAPIKey(ctx, keyID)
+		}
+		return domain.APIKey{}, fmt.Errorf("revoke api key: %w", err)
+	}
+	return unmarshalAPIKey(resp.Attributes)
+}
+
+func (s *DynamoDBAPIKeyStore) RotateAPIKey(ctx context.Context, rotated domain.APIKey) error {
+	values, err := attributevalue.MarshalMap(map[string]any{
+		":hash":         rotated.SecretHash,
+		":previous":     rotated.PreviousSecretHash,
+		":previous_exp": rotated.PreviousExpiresAt,
+		":rotated":      rotated.RotatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal api key rotation: %w", err)
+	}
+	_, err = s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(s.tableName),
+		Key:                       apiKeyItemKey(rotated.KeyID),
+		UpdateExpression:          aws.String("SET SecretHash = :hash, PreviousSecretHash = :previous, PreviousExpiresAt = :previous_exp, RotatedAt = :rotated"),
+		ConditionExpression:       aws.String("attribute_exists(PK) AND attribute_not_exists(RevokedAt)"),
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return fmt.Errorf("api key %s not found or revoked: %w", rotated.KeyID, domain.ErrNotFound)
+		}
+		return fmt.Errorf("rotate api key: %w", err)
+	}
+	return nil
+}
+
+func (s *DynamoDBAPIKeyStore) TouchAPIKey(ctx context.Context, keyID string, at time.Time) error {
+	lastUsed, err := attributevalue.Marshal(at.UTC())
+	if err != nil {
+		return fmt.Errorf("marshal last_used_at: %w", err)
+	}
+	_, err = s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.tableName),
+		Key:                 apiKeyItemKey(keyID),
+		UpdateExpression:    aws.String("SET LastUsedAt = :at"),
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":at": lastUsed,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("touch api key: %w", err)
+	}
+	return nil
+}
+
+func unmarshalAPIKey(item map[string]types.AttributeValue) (domain.APIKey, error) {
+	var key domain.APIKey
+	if err := attributevalue.UnmarshalMap(item, &key); err != nil {
+		return domain.APIKey{}, fmt.Errorf("unmarshal api key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+type DynamoDBCheckpointStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBCheckpointStore(client *dynamodb.Client, tableName string) *DynamoDBCheckpointStore {
+	return &DynamoDBCheckpointStore{client: client, tableName: tableName}
+}
+
+func checkpointPK(streamID string) string {
+	return "CKPT#" + streamID
+}
+
+func checkpointGuardPK(streamID string) string {
+	return "CKPTFROM#" + streamID
+}
+
+func checkpointSK(sequence int64) string {
+	return fmt.Sprintf("%020d", sequence)
+}
+
+func (s *DynamoDBCheckpointStore) PutCheckpoint(ctx context.Context, checkpoint domain.Checkpoint) error {
+	item, err := attributevalue.MarshalMap(checkpoint)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+	key, err := streamKey(ctx, checkpoint.StreamID)
+	if err != nil {
+		return err
+	}
+	item["PK"] = &types.AttributeValueMemberS{Value: checkpointPK(key)}
+	item["SK"] = &types.AttributeValueMemberS{Value: checkpointSK(checkpoint.ToSequence)}
+
+	_, err = s.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{
+			Put: &types.Put{
+				TableName:           aws.String(s.tableName),
+				Item:                item,
+				ConditionExpression: aws.String("attribute_not_exists(PK) AND attribute_not_exists(SK)"),
+			},
+		},
+		{
+			Put: &types.Put{
+				TableName: aws.String(s.tableName),
+				Item: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: checkpointGuardPK(key)},
+					"SK": &types.AttributeValueMemberS{Value: checkpointSK(checkpoint.FromSequence)},
+				},
+				ConditionExpression: aws.String("attribute_not_exists(PK) AND attribute_not_exists(SK)"),
+			},
+		},
+	}})
+	if err != nil {
+		var cancelled *types.TransactionCanceledException
+		if errors.As(err, &cancelled) {
+			return fmt.Errorf("checkpoint range already covered: %w", domain.ErrSequenceConflict)
+		}
+		return fmt.Errorf("put checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (s *DynamoDBCheckpointStore) LatestCheckpoint(ctx context.Context, streamID string) (domain.Checkpoint, error) {
+	key, err := streamKey(ctx, streamID)
+	if err != nil {
+		return domain.Checkpoint{}, err
+	}
+	return s.queryOne(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("PK = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: checkpointPK(key)},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(1),
+	})
+}
+
+func (s *DynamoDBCheckpointStore) FindCheckpoint(ctx context.Context, streamID string, sequence int64) (domain.Checkpoint, error) {
+	key, err := streamKey(ctx, streamID)
+	if err != nil {
+		return domain.Checkpoint{}, err
+	}
+	checkpoint, err := s.queryOne(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("PK = :pk AND SK >= :sk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: checkpointPK(key)},
+			":sk": &types.AttributeValueMemberS{Value: checkpointSK(sequence)},
+		},
+		ScanIndexForward: aws.Bool(true),
+		Limit:            aws.Int32(1),
+	})
+	if err != nil {
+		return domain.Checkpoint{}, err
+	}
+	if checkpoint.FromSequence > sequence {
+		return domain.Checkpoint{}, fmt.Errorf("no checkpoint covers sequence %d: %w", sequence, domain.ErrNotFound)
+	}
+	return checkpoint, nil
+}
+
+func (s *DynamoDBCheckpointStore) queryOne(ctx context.Context, input *dynamodb.QueryInput) (domain.Checkpoint, error) {
+	resp, err := s.client.Query(ctx, input)
+	if err != nil {
+		return domain.Checkpoint{}, fmt.Errorf("query checkpoints: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return domain.Checkpoint{}, fmt.Errorf("checkpoint not found: %w", domain.ErrNotFound)
+	}
+	var checkpoint domain.Checkpoint
+	if err := attributevalue.UnmarshalMap(resp.Items[0], &checkpoint); err != nil {
+		return domain.Checkpoint{}, fmt.Errorf("unmarshal checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
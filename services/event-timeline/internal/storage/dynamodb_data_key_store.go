@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+const dataKeySK = "DEK"
+
+type DynamoDBDataKeyStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+func NewDynamoDBDataKeyStore(client *dynamodb.Client, tableName string) *DynamoDBDataKeyStore {
+	return &DynamoDBDataKeyStore{client: client, tableName: tableName}
+}
+
+func dataKeyPK(streamKey string) string {
+	return "DEK#" + streamKey
+}
+
+func (s *DynamoDBDataKeyStore) itemKey(ctx context.Context, streamID string) (map[string]types.AttributeValue, error) {
+	key, err := streamKey(ctx, streamID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: dataKeyPK(key)},
+		"SK": &types.AttributeValueMemberS{Value: dataKeySK},
+	}, nil
+}
+
+func (s *DynamoDBDataKeyStore) GetDataKey(ctx context.Context, streamID string) (domain.DataKey, error) {
+	key, err := s.itemKey(ctx, streamID)
+	if err != nil {
+		return domain.DataKey{}, err
+	}
+	resp, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            key,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return domain.DataKey{}, fmt.Errorf("get data key: %w", err)
+	}
+	if len(resp.Item) == 0 {
+		return domain.DataKey{}, fmt.Errorf("data key not found: %w", domain.ErrNotFound)
+	}
+	return unmarshalDataKey(resp.Item, streamID)
+}
+
+func (s *DynamoDBDataKeyStore) CreateDataKey(ctx context.Context, dataKey domain.DataKey) error {
+	item, err := attributevalue.MarshalMap(dataKey)
+	if err != nil {
+		return fmt.Errorf("marshal data key: %w", err)
+	}
+	key, err := s.itemKey(ctx, dataKey.StreamID)
+	if err != nil {
+		return err
+	}
+	for name, value := range key {
+		item[name] = value
+	}
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return fmt.Errorf("stream %s: %w", dataKey.StreamID, domain.ErrKeyExists)
+		}
+		return fmt.Errorf("put data key: %w", err)
+	}
+	return nil
+}
+
+func (s *DynamoDBDataKeyStore) ShredDataKey(ctx context.Context, streamID string, at time.Time) (domain.DataKey, error) {
+	key, err := s.itemKey(ctx, streamID)
+	if err != nil {
+		return domain.DataKey{}, err
+	}
+	shreddedAt, err := attributevalue.Marshal(at.UTC())
+	if err != nil {
+		return domain.DataKey{}, fmt.Errorf("marshal shredded_at: %w", err)
+	}
+	resp, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.tableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("SET ShreddedAt = :at REMOVE WrappedKey"),
+		ConditionExpression: aws.String("attribute_exists(PK) AND attribute_not_exists(ShreddedAt)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":at": shreddedAt,
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return s.GetDataKey(ctx, streamID)
+		}
+		return domain.DataKey{}, fmt.Errorf("shred data key: %w", err)
+	}
+	return unmarshalDataKey(resp.Attributes, streamID)
+}
+
+func unmarshalDataKey(item map[string]types.AttributeValue, streamID string) (domain.DataKey, error) {
+	var dataKey domain.DataKey
+	if err := attributevalue.UnmarshalMap(item, &dataKey); err != nil {
+		return domain.DataKey{}, fmt.Errorf("unmarshal data key: %w", err)
+	}
+	dataKey.StreamID = streamID
+	return dataKey, nil
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -13,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
 )
 
 type DynamoDBEventStore struct {
@@ -24,11 +24,6 @@ func NewDynamoDBEventStore(client *dynamodb.Client, tableName string) *DynamoDBE
 	return &DynamoDBEventStore{client: client, tableName: tableName}
 }
 
-const (
-	batchWriteMaxItems = 25
-	batchWriteMaxRetry = 5
-)
-
 func idempotencyLookupKey(streamID, key string) string {
 	if key == "" {
 		return ""
@@ -44,7 +39,32 @@ func sequenceGuardSK(sequence int64) string {
 	return strconv.FormatInt(sequence, 10)
 }
 
+func streamKey(ctx context.Context, streamID string) (string, error) {
+	return tenant.StreamKey(tenant.FromContext(ctx), streamID)
+}
+
+func toStoredEvent(ctx context.Context, event domain.Event) (domain.Event, error) {
+	key, err := streamKey(ctx, event.StreamID)
+	if err != nil {
+		return domain.Event{}, err
+	}
+	event.StreamID = key
+	event.SK = fmt.Sprintf("EVENT#%s#%020d", key, event.SequenceNumber)
+	event.GSI2PK = idempotencyLookupKey(key, event.IdempotencyKey)
+	event.TenantID = ""
+	return event, nil
+}
+
+func fromStoredEvent(event domain.Event) domain.Event {
+	event.TenantID, event.StreamID = tenant.SplitStreamKey(event.StreamID)
+	return event
+}
+
 func (s *DynamoDBEventStore) PutEvent(ctx context.Context, event domain.Event) error {
+	event, err := toStoredEvent(ctx, event)
+	if err != nil {
+		return err
+	}
 	item, err := attributevalue.MarshalMap(event)
 	if err != nil {
 		return fmt.Errorf("marshal event: %w", err)
@@ -60,11 +80,8 @@ func (s *DynamoDBEventStore) PutEvent(ctx context.Context, event domain.Event) e
 		},
 		{
 			Put: &types.Put{
-				TableName: aws.String(s.tableName),
-				Item: map[string]types.AttributeValue{
-					"PK": &types.AttributeValueMemberS{Value: sequenceGuardPK(event.StreamID)},
-					"SK": &types.AttributeValueMemberS{Value: sequenceGuardSK(event.SequenceNumber)},
-				},
+				TableName:           aws.String(s.tableName),
+				Item:                sequenceGuardItem(event),
 				ConditionExpression: aws.String("attribute_not_exists(PK) AND attribute_not_exists(SK)"),
 			},
 		},
@@ -85,6 +102,13 @@ func (s *DynamoDBEventStore) PutEvent(ctx context.Context, event domain.Event) e
 		})
 	}
 
+	if check := s.chainLinkCheck(event); check != nil {
+		transactItems = append(transactItems, types.TransactWriteItem{ConditionCheck: check})
+	}
+	transactItems = append(transactItems, types.TransactWriteItem{
+		Update: streamHeadUpdate(s.tableName, event.StreamID, event.SequenceNumber),
+	})
+
 	_, err = s.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
 	if err != nil {
 		var cancelled *types.TransactionCanceledException
@@ -109,48 +133,65 @@ func (s *DynamoDBEventStore) PutEvent(ctx context.Context, event domain.Event) e
 	return nil
 }
 
-func (s *DynamoDBEventStore) PutEventsBatch(ctx context.Context, events []domain.Event) error {
-	if len(events) == 0 {
-		return nil
+func sequenceGuardItem(event domain.Event) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: sequenceGuardPK(event.StreamID)},
+		"SK": &types.AttributeValueMemberS{Value: sequenceGuardSK(event.SequenceNumber)},
 	}
+	if event.Hash != "" {
+		item["Hash"] = &types.AttributeValueMemberS{Value: event.Hash}
+	}
+	return item
+}
 
-	for start := 0; start < len(events); start += batchWriteMaxItems {
-		end := start + batchWriteMaxItems
-		if end > len(events) {
-			end = len(events)
-		}
-
-		chunk := events[start:end]
-		items := make([]types.WriteRequest, 0, len(chunk))
-		for _, event := range chunk {
-			item, err := attributevalue.MarshalMap(event)
-			if err != nil {
-				return fmt.Errorf("marshal event: %w", err)
-			}
-			items = append(items, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
-		}
+func (s *DynamoDBEventStore) chainLinkCheck(event domain.Event) *types.ConditionCheck {
+	if event.Hash == "" || event.SequenceNumber <= 1 {
+		return nil
+	}
+	check := &types.ConditionCheck{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: sequenceGuardPK(event.StreamID)},
+			"SK": &types.AttributeValueMemberS{Value: sequenceGuardSK(event.SequenceNumber - 1)},
+		},
+	}
+	if event.PrevHash == domain.GenesisHash {
+		check.ConditionExpression = aws.String("attribute_exists(PK) AND attribute_not_exists(#hash)")
+		check.ExpressionAttributeNames = map[string]string{"#hash": "Hash"}
+		return check
+	}
+	check.ConditionExpression = aws.String("#hash = :prev_hash")
+	check.ExpressionAttributeNames = map[string]string{"#hash": "Hash"}
+	check.ExpressionAttributeValues = map[string]types.AttributeValue{
+		":prev_hash": &types.AttributeValueMemberS{Value: event.PrevHash},
+	}
+	return check
+}
 
-		pending := map[string][]types.WriteRequest{s.tableName: items}
-		for attempt := 0; attempt < batchWriteMaxRetry && len(pending[s.tableName]) > 0; attempt++ {
-			resp, err := s.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
-			if err != nil {
-				return fmt.Errorf("batch write item: %w", err)
-			}
-			pending = resp.UnprocessedItems
-			if len(pending[s.tableName]) == 0 {
-				break
-			}
-			select {
-			case <-ctx.Done():
-				return fmt.Errorf("batch write cancelled: %w", ctx.Err())
-			case <-time.After(time.Duration(attempt+1) * 25 * time.Millisecond):
-			}
-		}
-		if len(pending[s.tableName]) > 0 {
-			return fmt.Errorf("batch write item: unprocessed items remain")
-		}
+func (s *DynamoDBEventStore) GetChainHash(ctx context.Context, streamID string, sequence int64) (string, error) {
+	key, err := streamKey(ctx, streamID)
+	if err != nil {
+		return "", err
 	}
-	return nil
+	resp, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: sequenceGuardPK(key)},
+			"SK": &types.AttributeValueMemberS{Value: sequenceGuardSK(sequence)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("get chain hash: %w", err)
+	}
+	if len(resp.Item) == 0 {
+		return "", fmt.Errorf("sequence %d of stream %s not found: %w", sequence, streamID, domain.ErrNotFound)
+	}
+	hash, ok := resp.Item["Hash"].(*types.AttributeValueMemberS)
+	if !ok {
+		return "", nil
+	}
+	return hash.Value, nil
 }
 
 func (s *DynamoDBEventStore) GetByEventID(ctx context.Context, eventID string) (domain.Event, error) {
@@ -172,16 +213,24 @@ func (s *DynamoDBEventStore) GetByEventID(ctx context.Context, eventID string) (
 	if err := attributevalue.UnmarshalMap(resp.Items[0], &event); err != nil {
 		return domain.Event{}, fmt.Errorf("unmarshal event: %w", err)
 	}
+	event = fromStoredEvent(event)
+	if event.TenantID != tenant.FromContext(ctx) && !tenant.IsCrossTenant(ctx) {
+		return domain.Event{}, fmt.Errorf("event not found: %w", domain.ErrNotFound)
+	}
 	return event, nil
 }
 
 func (s *DynamoDBEventStore) FindByIdempotencyKey(ctx context.Context, streamID, key string) (domain.Event, error) {
+	physical, err := streamKey(ctx, streamID)
+	if err != nil {
+		return domain.Event{}, err
+	}
 	resp, err := s.client.Query(ctx, &dynamodb.QueryInput{
 		TableName:              aws.String(s.tableName),
 		IndexName:              aws.String(GSI2Name),
 		KeyConditionExpression: aws.String("GSI2PK = :idempotency_key"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":idempotency_key": &types.AttributeValueMemberS{Value: idempotencyLookupKey(streamID, key)},
+			":idempotency_key": &types.AttributeValueMemberS{Value: idempotencyLookupKey(physical, key)},
 		},
 		Limit: aws.Int32(1),
 	})
@@ -195,16 +244,20 @@ func (s *DynamoDBEventStore) FindByIdempotencyKey(ctx context.Context, streamID,
 	if err := attributevalue.UnmarshalMap(resp.Items[0], &event); err != nil {
 		return domain.Event{}, fmt.Errorf("unmarshal event: %w", err)
 	}
-	return event, nil
+	return fromStoredEvent(event), nil
 }
 
 func (s *DynamoDBEventStore) GetLatestSequence(ctx context.Context, streamID string) (int64, error) {
+	key, err := streamKey(ctx, streamID)
+	if err != nil {
+		return 0, err
+	}
 	resp, err := s.client.Query(ctx, &dynamodb.QueryInput{
 		TableName:              aws.String(s.tableName),
 		IndexName:              aws.String(GSI1Name),
 		KeyConditionExpression: aws.String("GSI1PK = :stream_id"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":stream_id": &types.AttributeValueMemberS{Value: streamID},
+			":stream_id": &types.AttributeValueMemberS{Value: key},
 		},
 		ScanIndexForward: aws.Bool(false),
 		Limit:            aws.Int32(1),
@@ -226,6 +279,10 @@ func (s *DynamoDBEventStore) QueryByStream(ctx context.Context, streamID string,
 	if limit <= 0 {
 		limit = 50
 	}
+	key, err := streamKey(ctx, streamID)
+	if err != nil {
+		return nil, 0, false, err
+	}
 	scanForward := direction != domain.DirectionBackward
 	operator := ">="
 	if !scanForward {
@@ -236,7 +293,7 @@ func (s *DynamoDBEventStore) QueryByStream(ctx context.Context, streamID string,
 		IndexName:              aws.String(GSI1Name),
 		KeyConditionExpression: aws.String("GSI1PK = :stream_id AND GSI1SK " + operator + " :seq"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":stream_id": &types.AttributeValueMemberS{Value: streamID},
+			":stream_id": &types.AttributeValueMemberS{Value: key},
 			":seq":       &types.AttributeValueMemberN{Value: strconv.FormatInt(fromSequence, 10)},
 		},
 		ScanIndexForward: aws.Bool(scanForward),
@@ -251,7 +308,7 @@ func (s *DynamoDBEventStore) QueryByStream(ctx context.Context, streamID string,
 		if err := attributevalue.UnmarshalMap(item, &event); err != nil {
 			return nil, 0, false, fmt.Errorf("unmarshal stream event: %w", err)
 		}
-		events = append(events, event)
+		events = append(events, fromStoredEvent(event))
 	}
 	nextSeq := fromSequence
 	if len(events) > 0 {
@@ -3,8 +3,12 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
 )
 
 func testDynamoClient(t *testing.T, handler http.Handler) (*dynamodb.Client, func()) {
@@ -122,41 +127,7 @@ func TestDynamoDBEventStoreQueries(t *testing.T) {
 	require.True(t, hasMore)
 }
 
-func TestDynamoDBEventStoreBatchAndEdgeCases(t *testing.T) {
-	t.Run("batch empty", func(t *testing.T) {
-		client, cleanup := testDynamoClient(t, dynamoHandler(http.StatusOK, `{}`))
-		defer cleanup()
-
-		store := NewDynamoDBEventStore(client, "events")
-		err := store.PutEventsBatch(context.Background(), nil)
-		require.NoError(t, err)
-	})
-
-	t.Run("batch success and failure", func(t *testing.T) {
-		calls := 0
-		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
-			calls++
-			if calls == 1 {
-				w.WriteHeader(http.StatusOK)
-				_, _ = w.Write([]byte(`{}`))
-				return
-			}
-			w.WriteHeader(http.StatusInternalServerError)
-			_, _ = w.Write([]byte(`{"__type":"InternalServerError","message":"boom"}`))
-		})
-
-		client, cleanup := testDynamoClient(t, handler)
-		defer cleanup()
-		store := NewDynamoDBEventStore(client, "events")
-
-		err := store.PutEventsBatch(context.Background(), []domain.Event{sampleEvent(t)})
-		require.NoError(t, err)
-
-		err = store.PutEventsBatch(context.Background(), []domain.Event{sampleEvent(t)})
-		require.Error(t, err)
-	})
-
+func TestDynamoDBEventStoreEdgeCases(t *testing.T) {
 	t.Run("query not found and backward paging", func(t *testing.T) {
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
@@ -182,17 +153,6 @@ func TestDynamoDBEventStoreBatchAndEdgeCases(t *testing.T) {
 	})
 }
 
-func TestDynamoDBStreamStoreListStreams(t *testing.T) {
-	resp := `{"Items":[{"GSI1PK":{"S":"stream-1"},"GSI1SK":{"N":"1"}},{"GSI1PK":{"S":"stream-1"},"GSI1SK":{"N":"3"}},{"GSI1PK":{"S":"stream-2"},"GSI1SK":{"N":"2"}}]}`
-	client, cleanup := testDynamoClient(t, dynamoHandler(http.StatusOK, resp))
-	defer cleanup()
-
-	store := NewDynamoDBStreamStore(client, "events")
-	streams, err := store.ListStreams(context.Background(), 0)
-	require.NoError(t, err)
-	require.Len(t, streams, 2)
-}
-
 func TestDynamoDBEventStoreNotFoundAndErrors(t *testing.T) {
 	notFoundHandler := dynamoHandler(http.StatusOK, `{"Items":[]}`)
 	client, cleanup := testDynamoClient(t, notFoundHandler)
@@ -221,11 +181,353 @@ func TestDynamoDBEventStoreNotFoundAndErrors(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestDynamoDBEventStoreHashChain(t *testing.T) {
+	var transact map[string]any
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusOK)
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.TransactWriteItems":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&transact))
+			_, _ = w.Write([]byte(`{}`))
+		case "DynamoDB_20120810.GetItem":
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, true, req["ConsistentRead"])
+			sk := req["Key"].(map[string]any)["SK"].(map[string]any)["S"]
+			switch sk {
+			case "1":
+				_, _ = w.Write([]byte(`{"Item":{"PK":{"S":"SEQ#stream-1"},"SK":{"S":"1"},"Hash":{"S":"abc"}}}`))
+			case "2":
+				_, _ = w.Write([]byte(`{"Item":{"PK":{"S":"SEQ#stream-1"},"SK":{"S":"2"}}}`))
+			default:
+				_, _ = w.Write([]byte(`{}`))
+			}
+		}
+	})
+	client, cleanup := testDynamoClient(t, handler)
+	defer cleanup()
+	store := NewDynamoDBEventStore(client, "events")
+
+	hash, err := store.GetChainHash(context.Background(), "stream-1", 1)
+	require.NoError(t, err)
+	require.Equal(t, "abc", hash)
+	hash, err = store.GetChainHash(context.Background(), "stream-1", 2)
+	require.NoError(t, err)
+	require.Empty(t, hash)
+	_, err = store.GetChainHash(context.Background(), "stream-1", 3)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	event := sampleEvent(t)
+	event.SequenceNumber = 2
+	event, err = domain.ChainEvent(event, "abc")
+	require.NoError(t, err)
+	require.NoError(t, store.PutEvent(context.Background(), event))
+
+	items := transact["TransactItems"].([]any)
+	require.Len(t, items, 4)
+	guard := items[1].(map[string]any)["Put"].(map[string]any)["Item"].(map[string]any)
+	require.Equal(t, event.Hash, guard["Hash"].(map[string]any)["S"])
+	check := items[2].(map[string]any)["ConditionCheck"].(map[string]any)
+	require.Equal(t, "#hash = :prev_hash", check["ConditionExpression"])
+	require.Equal(t, "1", check["Key"].(map[string]any)["SK"].(map[string]any)["S"])
+	head := items[3].(map[string]any)["Update"].(map[string]any)
+	require.Equal(t, "default#stream-1", head["Key"].(map[string]any)["SK"].(map[string]any)["S"])
+	require.Equal(t, "2", head["ExpressionAttributeValues"].(map[string]any)[":seq"].(map[string]any)["N"])
+}
+
+func TestDynamoDBEventStoreTenantIsolation(t *testing.T) {
+	var transact map[string]any
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusOK)
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.TransactWriteItems":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&transact))
+			_, _ = w.Write([]byte(`{}`))
+		case "DynamoDB_20120810.Query":
+			_, _ = w.Write([]byte(`{"Items":[{"PK":{"S":"evt-1"},"GSI1PK":{"S":"T#acme#stream-1"},"GSI1SK":{"N":"1"},"EventType":{"S":"created"}}]}`))
+		}
+	})
+	client, cleanup := testDynamoClient(t, handler)
+	defer cleanup()
+	store := NewDynamoDBEventStore(client, "events")
+	acme := tenant.WithTenant(context.Background(), "acme")
+
+	require.NoError(t, store.PutEvent(acme, sampleEvent(t)))
+	item := transact["TransactItems"].([]any)[0].(map[string]any)["Put"].(map[string]any)["Item"].(map[string]any)
+	require.Equal(t, "T#acme#stream-1", item["GSI1PK"].(map[string]any)["S"])
+
+	event, err := store.GetByEventID(acme, "evt-1")
+	require.NoError(t, err)
+	require.Equal(t, "acme", event.TenantID)
+	require.Equal(t, "stream-1", event.StreamID)
+
+	_, err = store.GetByEventID(context.Background(), "evt-1")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = store.GetByEventID(tenant.WithTenant(context.Background(), "globex"), "evt-1")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = store.GetLatestSequence(acme, "T#default#stream-1")
+	require.ErrorIs(t, err, domain.ErrValidation)
+}
+
+// headTable answers Query requests for stream heads the way DynamoDB does:
+// by partition, in key order, after the exclusive start key, up to the limit.
+func headTable(t *testing.T, heads map[string]int64, queries *[]map[string]any) http.Handler {
+	shards := map[string][]string{}
+	for sk := range heads {
+		shards[streamHeadPK(sk)] = append(shards[streamHeadPK(sk)], sk)
+	}
+	for pk := range shards {
+		sort.Strings(shards[pk])
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Limit                     int
+			ExclusiveStartKey         map[string]map[string]string
+			ExpressionAttributeValues map[string]map[string]string
+		}
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(raw, &req))
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal(raw, &decoded))
+		*queries = append(*queries, decoded)
+
+		pk := req.ExpressionAttributeValues[":pk"]["S"]
+		prefix := req.ExpressionAttributeValues[":prefix"]["S"]
+		var items []map[string]any
+		var last string
+		for _, sk := range shards[pk] {
+			if sk <= req.ExclusiveStartKey["SK"]["S"] || !strings.HasPrefix(sk, prefix) {
+				continue
+			}
+			items = append(items, map[string]any{
+				"PK":             map[string]string{"S": pk},
+				"SK":             map[string]string{"S": sk},
+				"LatestSequence": map[string]string{"N": strconv.FormatInt(heads[sk], 10)},
+			})
+			if len(items) == req.Limit {
+				last = sk
+				break
+			}
+		}
+		resp := map[string]any{"Items": items}
+		if last != "" {
+			resp["LastEvaluatedKey"] = map[string]any{"PK": map[string]string{"S": pk}, "SK": map[string]string{"S": last}}
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	})
+}
+
+func TestDynamoDBStreamStoreListsStreamHeads(t *testing.T) {
+	heads := map[string]int64{
+		"default#stream-1": 3,
+		"default#stream-2": 2,
+		"acme#stream-1":    4,
+		"acme#stream-2":    2,
+		"acme#stream-3":    7,
+	}
+	var queries []map[string]any
+	client, cleanup := testDynamoClient(t, headTable(t, heads, &queries))
+	defer cleanup()
+	store := NewDynamoDBStreamStore(client, "events")
+
+	page, err := store.ListStreams(context.Background(), "", 0)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []domain.Stream{
+		{TenantID: tenant.Default, StreamID: "stream-1", LatestSequence: 3},
+		{TenantID: tenant.Default, StreamID: "stream-2", LatestSequence: 2},
+	}, page.Streams)
+	require.Empty(t, page.NextCursor)
+	require.Len(t, queries, streamHeadShards)
+	require.Equal(t, float64(DefaultStreamPageSize), queries[0]["Limit"])
+	require.Equal(t, "PK = :pk AND begins_with(SK, :prefix)", queries[0]["KeyConditionExpression"])
+
+	acme := tenant.WithTenant(context.Background(), "acme")
+	var paged []domain.Stream
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 10)
+		page, err = store.ListStreams(acme, cursor, 1)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Streams), 1)
+		paged = append(paged, page.Streams...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	require.ElementsMatch(t, []domain.Stream{
+		{TenantID: "acme", StreamID: "stream-1", LatestSequence: 4},
+		{TenantID: "acme", StreamID: "stream-2", LatestSequence: 2},
+		{TenantID: "acme", StreamID: "stream-3", LatestSequence: 7},
+	}, paged)
+
+	queries = nil
+	all, err := ListAllStreams(tenant.WithCrossTenant(context.Background()), store)
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	require.Equal(t, "PK = :pk", queries[0]["KeyConditionExpression"])
+}
+
+func TestDynamoDBStreamStoreRejectsForeignCursors(t *testing.T) {
+	var queries []map[string]any
+	client, cleanup := testDynamoClient(t, headTable(t, map[string]int64{"acme#stream-1": 1}, &queries))
+	defer cleanup()
+	store := NewDynamoDBStreamStore(client, "events")
+
+	for _, cursor := range []string{"not base64!", encodeStreamCursor(streamHeadShards, ""), encodeStreamCursor(0, "acme#stream-1")} {
+		_, err := store.ListStreams(context.Background(), cursor, 10)
+		require.ErrorIs(t, err, domain.ErrValidation, cursor)
+	}
+	require.Empty(t, queries)
+
+	_, err := store.ListStreams(tenant.WithTenant(context.Background(), "acme"), encodeStreamCursor(0, "acme#stream-1"), 10)
+	require.NoError(t, err)
+}
+
 func TestDynamoDBStreamStoreError(t *testing.T) {
 	client, cleanup := testDynamoClient(t, dynamoHandler(http.StatusInternalServerError, `{"__type":"InternalServerError","message":"boom"}`))
 	defer cleanup()
 
 	store := NewDynamoDBStreamStore(client, "events")
-	_, err := store.ListStreams(context.Background(), 10)
+	_, err := store.ListStreams(context.Background(), "", 10)
 	require.Error(t, err)
 }
+
+func TestDynamoDBStreamStoreBackfillsHeads(t *testing.T) {
+	var updates []map[string]any
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.Scan":
+			if _, ok := req["ExclusiveStartKey"]; ok {
+				_, _ = w.Write([]byte(`{"Items":[{"GSI1PK":{"S":"T#acme#stream-1"},"GSI1SK":{"N":"4"}}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"Items":[{"GSI1PK":{"S":"stream-1"},"GSI1SK":{"N":"1"}},{"GSI1PK":{"S":"stream-1"},"GSI1SK":{"N":"3"}}],"LastEvaluatedKey":{"PK":{"S":"e1"},"SK":{"S":"s"}}}`))
+		case "DynamoDB_20120810.UpdateItem":
+			updates = append(updates, req)
+			if len(updates) == 1 {
+				// A head already past the scanned sequence is left alone
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	client, cleanup := testDynamoClient(t, handler)
+	defer cleanup()
+	store := NewDynamoDBStreamStore(client, "events")
+
+	count, err := store.BackfillStreamHeads(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	written := map[string]string{}
+	for _, update := range updates {
+		sk := update["Key"].(map[string]any)["SK"].(map[string]any)["S"].(string)
+		written[sk] = update["ExpressionAttributeValues"].(map[string]any)[":seq"].(map[string]any)["N"].(string)
+		require.Equal(t, streamHeadPK(sk), update["Key"].(map[string]any)["PK"].(map[string]any)["S"])
+	}
+	require.Equal(t, map[string]string{"default#stream-1": "3", "acme#stream-1": "4"}, written)
+}
+
+func TestDynamoDBDataKeyStore(t *testing.T) {
+	var requests []map[string]any
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.PutItem":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"exists"}`))
+		case "DynamoDB_20120810.UpdateItem":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"Attributes":{"PK":{"S":"DEK#T#acme#s1"},"KeyID":{"S":"k1"},"ShreddedAt":{"S":"2026-03-01T12:00:00Z"}}}`))
+		case "DynamoDB_20120810.GetItem":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	client, cleanup := testDynamoClient(t, handler)
+	defer cleanup()
+	store := NewDynamoDBDataKeyStore(client, "events")
+	acme := tenant.WithTenant(context.Background(), "acme")
+
+	err := store.CreateDataKey(acme, domain.DataKey{StreamID: "s1", KeyID: "k1", WrappedKey: []byte("wrapped")})
+	require.ErrorIs(t, err, domain.ErrKeyExists)
+	item := requests[0]["Item"].(map[string]any)
+	require.Equal(t, "DEK#T#acme#s1", item["PK"].(map[string]any)["S"])
+
+	key, err := store.ShredDataKey(acme, "s1", time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, key.Shredded())
+	require.Equal(t, "s1", key.StreamID)
+	require.Contains(t, requests[1]["UpdateExpression"], "REMOVE WrappedKey")
+
+	_, err = store.GetDataKey(acme, "s1")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestDynamoDBAPIKeyStore(t *testing.T) {
+	var requests []map[string]any
+	var targets []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		target := r.Header.Get("X-Amz-Target")
+		targets = append(targets, target)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch target {
+		case "DynamoDB_20120810.PutItem":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+		case "DynamoDB_20120810.GetItem":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"Item":{"PK":{"S":"APIKEY#k1"},"SK":{"S":"APIKEY"},"KeyID":{"S":"k1"},"TenantID":{"S":"acme"},"SecretHash":{"S":"hash"},"RevokedAt":{"S":"2026-03-01T12:00:00Z"}}}`))
+		case "DynamoDB_20120810.Scan":
+			w.WriteHeader(http.StatusOK)
+			if _, ok := req["ExclusiveStartKey"]; ok {
+				_, _ = w.Write([]byte(`{"Items":[{"KeyID":{"S":"k2"},"TenantID":{"S":"acme"}}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"Items":[{"KeyID":{"S":"k1"},"TenantID":{"S":"acme"}}],"LastEvaluatedKey":{"PK":{"S":"APIKEY#k1"},"SK":{"S":"APIKEY"}}}`))
+		case "DynamoDB_20120810.UpdateItem":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"revoked"}`))
+		}
+	})
+	client, cleanup := testDynamoClient(t, handler)
+	defer cleanup()
+	store := NewDynamoDBAPIKeyStore(client, "events")
+	ctx := context.Background()
+
+	require.NoError(t, store.CreateAPIKey(ctx, domain.APIKey{KeyID: "k1", TenantID: "acme", SecretHash: "hash"}))
+	item := requests[0]["Item"].(map[string]any)
+	require.Equal(t, "APIKEY#k1", item["PK"].(map[string]any)["S"])
+	require.Equal(t, "hash", item["SecretHash"].(map[string]any)["S"])
+	require.Equal(t, "attribute_not_exists(PK)", requests[0]["ConditionExpression"])
+
+	keys, err := store.ListAPIKeys(ctx, "acme")
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Contains(t, requests[1]["FilterExpression"], "TenantID = :tenant")
+
+	// Revoking an already revoked key returns the stored key unchanged.
+	key, err := store.RevokeAPIKey(ctx, "k1", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, key.Revoked())
+	require.Equal(t, "DynamoDB_20120810.GetItem", targets[len(targets)-1])
+
+	err = store.RotateAPIKey(ctx, domain.APIKey{KeyID: "k1", SecretHash: "new"})
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -2,15 +2,82 @@ package storage
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/tenant"
 )
 
+// Every stream has a head item holding its latest sequence, written in the
+// same transaction as its events. Heads are spread over a fixed number of
+// partitions so appends to different streams do not all update one key.
+const (
+	streamHeadPrefix = "STREAMS#"
+	streamHeadShards = 8
+)
+
+// streamHeadSK always carries the tenant, including the default one, so a
+// tenant's heads can be listed with a key prefix.
+func streamHeadSK(storedKey string) string {
+	tenantID, streamID := tenant.SplitStreamKey(storedKey)
+	return tenantID + "#" + streamID
+}
+
+func streamHeadPK(sk string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(sk))
+	return streamHeadPrefix + strconv.FormatUint(uint64(h.Sum32()%streamHeadShards), 10)
+}
+
+func streamHeadKey(storedKey string) map[string]types.AttributeValue {
+	sk := streamHeadSK(storedKey)
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: streamHeadPK(sk)},
+		"SK": &types.AttributeValueMemberS{Value: sk},
+	}
+}
+
+// streamHeadUpdate moves the head of a stream forward and fails when it is
+// already at or past the sequence.
+func streamHeadUpdate(tableName, storedKey string, sequence int64) *types.Update {
+	return &types.Update{
+		TableName:           aws.String(tableName),
+		Key:                 streamHeadKey(storedKey),
+		UpdateExpression:    aws.String("SET LatestSequence = :seq"),
+		ConditionExpression: aws.String("attribute_not_exists(LatestSequence) OR LatestSequence < :seq"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":seq": &types.AttributeValueMemberN{Value: strconv.FormatInt(sequence, 10)},
+		},
+	}
+}
+
+// advanceStreamHead applies streamHeadUpdate outside a transaction. A head
+// that is already further along is left as it is.
+func advanceStreamHead(ctx context.Context, client *dynamodb.Client, tableName, storedKey string, sequence int64) error {
+	update := streamHeadUpdate(tableName, storedKey, sequence)
+	_, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 update.TableName,
+		Key:                       update.Key,
+		UpdateExpression:          update.UpdateExpression,
+		ConditionExpression:       update.ConditionExpression,
+		ExpressionAttributeValues: update.ExpressionAttributeValues,
+	})
+	var ccf *types.ConditionalCheckFailedException
+	if err != nil && !errors.As(err, &ccf) {
+		return fmt.Errorf("update stream head: %w", err)
+	}
+	return nil
+}
+
 type DynamoDBStreamStore struct {
 	client    *dynamodb.Client
 	tableName string
@@ -20,37 +87,156 @@ func NewDynamoDBStreamStore(client *dynamodb.Client, tableName string) *DynamoDB
 	return &DynamoDBStreamStore{client: client, tableName: tableName}
 }
 
-func (s *DynamoDBStreamStore) ListStreams(ctx context.Context, limit int32) ([]domain.Stream, error) {
+// ListStreams queries the head partitions one after another. The cursor
+// holds the partition and the last head returned from it.
+func (s *DynamoDBStreamStore) ListStreams(ctx context.Context, cursor string, limit int32) (StreamPage, error) {
 	if limit <= 0 {
-		limit = 200
+		limit = DefaultStreamPageSize
+	}
+	if limit > MaxStreamPageSize {
+		limit = MaxStreamPageSize
+	}
+	prefix := ""
+	if !tenant.IsCrossTenant(ctx) {
+		prefix = tenant.FromContext(ctx) + "#"
+	}
+	shard, lastSK, err := decodeStreamCursor(cursor, prefix)
+	if err != nil {
+		return StreamPage{}, err
+	}
+
+	page := StreamPage{Streams: []domain.Stream{}}
+	for ; shard < streamHeadShards; shard, lastSK = shard+1, "" {
+		pk := streamHeadPrefix + strconv.Itoa(shard)
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(s.tableName),
+			KeyConditionExpression: aws.String("PK = :pk"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk": &types.AttributeValueMemberS{Value: pk},
+			},
+		}
+		if prefix != "" {
+			input.KeyConditionExpression = aws.String("PK = :pk AND begins_with(SK, :prefix)")
+			input.ExpressionAttributeValues[":prefix"] = &types.AttributeValueMemberS{Value: prefix}
+		}
+		if lastSK != "" {
+			input.ExclusiveStartKey = map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: pk},
+				"SK": &types.AttributeValueMemberS{Value: lastSK},
+			}
+		}
+		for {
+			input.Limit = aws.Int32(limit - int32(len(page.Streams)))
+			resp, err := s.client.Query(ctx, input)
+			if err != nil {
+				return StreamPage{}, fmt.Errorf("query stream heads: %w", err)
+			}
+			for _, item := range resp.Items {
+				if stream, ok := streamFromHead(item); ok {
+					page.Streams = append(page.Streams, stream)
+				}
+			}
+			if int32(len(page.Streams)) >= limit {
+				if sk, ok := resp.LastEvaluatedKey["SK"].(*types.AttributeValueMemberS); ok {
+					page.NextCursor = encodeStreamCursor(shard, sk.Value)
+				} else if shard+1 < streamHeadShards {
+					page.NextCursor = encodeStreamCursor(shard+1, "")
+				}
+				return page, nil
+			}
+			if len(resp.LastEvaluatedKey) == 0 {
+				break
+			}
+			input.ExclusiveStartKey = resp.LastEvaluatedKey
+		}
 	}
-	resp, err := s.client.Scan(ctx, &dynamodb.ScanInput{
+	return page, nil
+}
+
+// BackfillStreamHeads scans the event items once and writes the head of
+// every stream found, for tables that hold events written before heads.
+func (s *DynamoDBStreamStore) BackfillStreamHeads(ctx context.Context) (int, error) {
+	input := &dynamodb.ScanInput{
 		TableName:            aws.String(s.tableName),
-		Limit:                aws.Int32(limit),
 		ProjectionExpression: aws.String("GSI1PK, GSI1SK"),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("scan streams: %w", err)
+		FilterExpression:     aws.String("attribute_exists(GSI1PK)"),
 	}
-	seen := map[string]int64{}
-	for _, item := range resp.Items {
-		streamAttr, ok := item["GSI1PK"].(*types.AttributeValueMemberS)
-		if !ok {
-			continue
+	latest := map[string]int64{}
+	for {
+		resp, err := s.client.Scan(ctx, input)
+		if err != nil {
+			return 0, fmt.Errorf("scan events: %w", err)
+		}
+		for _, item := range resp.Items {
+			streamAttr, ok := item["GSI1PK"].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			seqAttr, ok := item["GSI1SK"].(*types.AttributeValueMemberN)
+			if !ok {
+				continue
+			}
+			seq, err := strconv.ParseInt(seqAttr.Value, 10, 64)
+			if err != nil {
+				continue
+			}
+			if seq > latest[streamAttr.Value] {
+				latest[streamAttr.Value] = seq
+			}
 		}
-		seqAttr, ok := item["GSI1SK"].(*types.AttributeValueMemberN)
-		if !ok {
-			continue
+		if len(resp.LastEvaluatedKey) == 0 {
+			break
 		}
-		var seq int64
-		_, _ = fmt.Sscan(seqAttr.Value, &seq)
-		if seq > seen[streamAttr.Value] {
-			seen[streamAttr.Value] = seq
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+	for key, seq := range latest {
+		if err := advanceStreamHead(ctx, s.client, s.tableName, key, seq); err != nil {
+			return 0, err
 		}
 	}
-	streams := make([]domain.Stream, 0, len(seen))
-	for id, latest := range seen {
-		streams = append(streams, domain.Stream{StreamID: id, LatestSequence: latest})
+	return len(latest), nil
+}
+
+func streamFromHead(item map[string]types.AttributeValue) (domain.Stream, bool) {
+	sk, ok := item["SK"].(*types.AttributeValueMemberS)
+	if !ok {
+		return domain.Stream{}, false
+	}
+	tenantID, streamID, ok := strings.Cut(sk.Value, "#")
+	if !ok {
+		return domain.Stream{}, false
+	}
+	stream := domain.Stream{TenantID: tenantID, StreamID: streamID}
+	if seq, ok := item["LatestSequence"].(*types.AttributeValueMemberN); ok {
+		stream.LatestSequence, _ = strconv.ParseInt(seq.Value, 10, 64)
+	}
+	return stream, true
+}
+
+func encodeStreamCursor(shard int, lastSK string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(shard) + ":" + lastSK))
+}
+
+// decodeStreamCursor rejects cursors whose last head lies outside the
+// tenant prefix, so a cursor cannot page through another tenant's streams.
+func decodeStreamCursor(cursor, prefix string) (int, string, error) {
+	if cursor == "" {
+		return 0, "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", fmt.Errorf("decode stream cursor: %w", domain.ErrValidation)
+	}
+	shardPart, lastSK, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return 0, "", fmt.Errorf("invalid stream cursor format: %w", domain.ErrValidation)
+	}
+	shard, err := strconv.Atoi(shardPart)
+	if err != nil || shard < 0 || shard >= streamHeadShards {
+		return 0, "", fmt.Errorf("invalid stream cursor partition: %w", domain.ErrValidation)
+	}
+	if lastSK != "" && !strings.HasPrefix(lastSK, prefix) {
+		return 0, "", fmt.Errorf("stream cursor belongs to another tenant: %w", domain.ErrValidation)
 	}
-	return streams, nil
+	return shard, lastSK, nil
 }
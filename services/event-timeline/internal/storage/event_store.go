@@ -8,9 +8,12 @@ import (
 
 type EventStore interface {
 	PutEvent(ctx context.Context, event domain.Event) error
-	PutEventsBatch(ctx context.Context, events []domain.Event) error
 	GetByEventID(ctx context.Context, eventID string) (domain.Event, error)
 	FindByIdempotencyKey(ctx context.Context, streamID, key string) (domain.Event, error)
 	GetLatestSequence(ctx context.Context, streamID string) (int64, error)
 	QueryByStream(ctx context.Context, streamID string, fromSequence int64, direction string, limit int32) ([]domain.Event, int64, bool, error)
 }
+
+type ChainHashReader interface {
+	GetChainHash(ctx context.Context, streamID string, sequence int64) (string, error)
+}
@@ -6,6 +6,41 @@ import (
 	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
 )
 
+const (
+	DefaultStreamPageSize = 100
+	MaxStreamPageSize     = 1000
+)
+
+type StreamPage struct {
+	Streams    []domain.Stream
+	NextCursor string
+}
+
+// StreamStore lists streams page by page. An empty cursor starts at the
+// first page and an empty NextCursor marks the last one.
 type StreamStore interface {
-	ListStreams(ctx context.Context, limit int32) ([]domain.Stream, error)
+	ListStreams(ctx context.Context, cursor string, limit int32) (StreamPage, error)
+}
+
+// StreamHeadBackfiller writes the head items of streams whose events were
+// stored before heads existed.
+type StreamHeadBackfiller interface {
+	BackfillStreamHeads(ctx context.Context) (int, error)
+}
+
+// ListAllStreams follows the cursor of the store until the last page.
+func ListAllStreams(ctx context.Context, store StreamStore) ([]domain.Stream, error) {
+	var streams []domain.Stream
+	cursor := ""
+	for {
+		page, err := store.ListStreams(ctx, cursor, MaxStreamPageSize)
+		if err != nil {
+			return nil, err
+		}
+		streams = append(streams, page.Streams...)
+		if page.NextCursor == "" {
+			return streams, nil
+		}
+		cursor = page.NextCursor
+	}
 }
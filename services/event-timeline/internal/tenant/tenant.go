@@ -0,0 +1,73 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+const (
+	Default      = "default"
+	streamPrefix = "T#"
+	// System holds platform-owned streams. It fails Validate, so no token can
+	// act as this tenant.
+	System = "_system"
+)
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type tenantContextKey struct{}
+
+type crossTenantContextKey struct{}
+
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(tenantContextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+func WithCrossTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, crossTenantContextKey{}, true)
+}
+
+func IsCrossTenant(ctx context.Context) bool {
+	cross, _ := ctx.Value(crossTenantContextKey{}).(bool)
+	return cross
+}
+
+func Validate(id string) error {
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("tenant id %q must match %s: %w", id, idPattern.String(), domain.ErrValidation)
+	}
+	return nil
+}
+
+func StreamKey(tenantID, streamID string) (string, error) {
+	if strings.HasPrefix(streamID, streamPrefix) {
+		return "", fmt.Errorf("stream id must not start with %q: %w", streamPrefix, domain.ErrValidation)
+	}
+	if tenantID == "" || tenantID == Default {
+		return streamID, nil
+	}
+	return streamPrefix + tenantID + "#" + streamID, nil
+}
+
+func SplitStreamKey(key string) (string, string) {
+	if !strings.HasPrefix(key, streamPrefix) {
+		return Default, key
+	}
+	rest := key[len(streamPrefix):]
+	idx := strings.IndexByte(rest, '#')
+	if idx < 0 {
+		return Default, key
+	}
+	return rest[:idx], rest[idx+1:]
+}
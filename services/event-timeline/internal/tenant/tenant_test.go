@@ -0,0 +1,43 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/event-timeline/internal/domain"
+)
+
+func TestStreamKeyRoundTrip(t *testing.T) {
+	key, err := StreamKey(Default, "orders-1")
+	require.NoError(t, err)
+	require.Equal(t, "orders-1", key, "default tenant keeps existing keys")
+
+	key, err = StreamKey("acme", "orders-1")
+	require.NoError(t, err)
+	require.Equal(t, "T#acme#orders-1", key)
+
+	tenantID, streamID := SplitStreamKey(key)
+	require.Equal(t, "acme", tenantID)
+	require.Equal(t, "orders-1", streamID)
+
+	tenantID, streamID = SplitStreamKey("orders-1")
+	require.Equal(t, Default, tenantID)
+	require.Equal(t, "orders-1", streamID)
+
+	_, err = StreamKey(Default, "T#acme#orders-1")
+	require.ErrorIs(t, err, domain.ErrValidation, "reserved prefix must not reach another tenant's streams")
+}
+
+func TestContextAndValidation(t *testing.T) {
+	require.Equal(t, Default, FromContext(context.Background()))
+	require.Equal(t, "acme", FromContext(WithTenant(context.Background(), "acme")))
+	require.False(t, IsCrossTenant(context.Background()))
+	require.True(t, IsCrossTenant(WithCrossTenant(context.Background())))
+
+	require.NoError(t, Validate("acme-eu_1"))
+	require.Error(t, Validate("Acme"))
+	require.Error(t, Validate("a#b"))
+	require.Error(t, Validate(""))
+}
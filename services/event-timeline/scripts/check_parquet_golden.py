@@ -0,0 +1,73 @@
+#!/usr/bin/env python3
+"""Reads the export golden file with pyarrow and compares it to the expected rows.
+
+The export package writes Parquet without a library, so this checks its output
+with an independent reader. Run from services/event-timeline:
+
+    pip install pyarrow
+    python3 scripts/check_parquet_golden.py
+"""
+
+import json
+import pathlib
+import sys
+
+import pyarrow as pa
+import pyarrow.parquet as pq
+
+TESTDATA = pathlib.Path(__file__).resolve().parent.parent / "internal" / "export" / "testdata"
+
+EXPECTED_SCHEMA = pa.schema(
+    [
+        pa.field("event_id", pa.string(), nullable=False),
+        pa.field("stream_id", pa.string(), nullable=False),
+        pa.field("sequence_number", pa.int64(), nullable=False),
+        pa.field("event_type", pa.string(), nullable=False),
+        pa.field("payload", pa.string(), nullable=False),
+        pa.field("metadata", pa.string(), nullable=False),
+        pa.field("idempotency_key", pa.string(), nullable=False),
+        pa.field("occurred_at", pa.timestamp("us", tz="UTC"), nullable=False),
+        pa.field("ingested_at", pa.timestamp("us", tz="UTC"), nullable=False),
+        pa.field("schema_version", pa.int32(), nullable=False),
+        pa.field("prev_hash", pa.string(), nullable=False),
+        pa.field("hash", pa.string(), nullable=False),
+    ]
+)
+
+TIMESTAMP_COLUMNS = ("occurred_at", "ingested_at")
+JSON_COLUMNS = ("payload", "metadata")
+
+
+def main() -> int:
+    parquet_file = pq.ParquetFile(TESTDATA / "events.parquet")
+    for i in range(parquet_file.metadata.num_columns):
+        logical = parquet_file.schema.column(i).logical_type.type
+        name = parquet_file.schema.column(i).name
+        if name in JSON_COLUMNS and logical != "JSON":
+            print(f"{name}: logical type {logical}, want JSON")
+            return 1
+
+    table = parquet_file.read()
+    if not table.schema.equals(EXPECTED_SCHEMA):
+        print(f"schema mismatch:\n{table.schema}\nwant:\n{EXPECTED_SCHEMA}")
+        return 1
+
+    for name in TIMESTAMP_COLUMNS:
+        index = table.schema.get_field_index(name)
+        table = table.set_column(index, name, table.column(name).cast(pa.int64()))
+
+    rows = table.to_pylist()
+    expected = json.loads((TESTDATA / "events.json").read_text(encoding="utf-8"))
+    if rows != expected:
+        for i, (got, want) in enumerate(zip(rows, expected)):
+            if got != want:
+                print(f"row {i}:\n got  {got}\n want {want}")
+        print(f"read {len(rows)} rows, want {len(expected)}")
+        return 1
+
+    print(f"{TESTDATA / 'events.parquet'}: {len(rows)} rows match")
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
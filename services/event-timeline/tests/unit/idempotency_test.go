@@ -54,8 +54,6 @@ func (m *mockEventStore) PutEvent(_ context.Context, e domain.Event) error {
 	return nil
 }
 
-func (m *mockEventStore) PutEventsBatch(context.Context, []domain.Event) error { return nil }
-
 func (m *mockEventStore) GetByEventID(context.Context, string) (domain.Event, error) {
 	return domain.Event{}, fmt.Errorf("not implemented")
 }
@@ -10,14 +10,19 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/accesslog"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/api"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/clients"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/config"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/deadletter"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/indexer"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/mtls"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/redaction"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/search"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/storage"
 	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
 )
 
 func main() {
@@ -28,6 +33,17 @@ func main() {
 	logger := observability.NewLogger(cfg.Environment)
 	logger.Info("initializing service")
 
+	// Load redaction policy
+	var redactionPolicy *redaction.Policy
+	if cfg.Redaction.PolicyFile != "" {
+		policy, err := redaction.LoadPolicy(cfg.Redaction.PolicyFile)
+		if err != nil {
+			logger.Error("failed to load redaction policy", slog.Any("error", err))
+			os.Exit(1)
+		}
+		redactionPolicy = policy
+	}
+
 	// Create Elasticsearch client
 	esClient, err := storage.NewElasticsearchClient(cfg.Elasticsearch.URLs)
 	if err != nil {
@@ -52,14 +68,65 @@ func main() {
 		cancel()
 		os.Exit(1)
 	}
+	for _, vi := range storage.VersionedIndexes {
+		outdated, err := indexManager.MappingOutdated(ctx, vi.Alias)
+		if err != nil {
+			logger.Warn("failed to check index mapping version", slog.String("index", vi.Alias), slog.Any("error", err))
+		} else if outdated {
+			logger.Warn("index mapping is outdated; rebuild its source with POST /admin/rebuild", slog.String("index", vi.Alias))
+		}
+	}
 	cancel()
 
-	// Initialize clients
-	eventTimelineClient := clients.NewEventTimelineClient(cfg.EventTimeline.BaseURL)
-	decisionEngineClient := clients.NewDecisionEngineClient(cfg.DecisionEngine.BaseURL)
+	// Export spans of calls to other services when a collector is configured
+	if cfg.Tracing.OTELEndpoint != "" {
+		tp, err := observability.InitTracerProvider(context.Background(), cfg.Tracing.OTELEndpoint)
+		if err != nil {
+			logger.Error("failed to initialize tracing", slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = tp.Shutdown(ctx)
+		}()
+	}
 
-	// Create bulk indexer
-	bulkIndexer := indexer.NewBulkIndexer(esClient.GetClient(), cfg.Sync.BatchSize, logger)
+	// Initialize clients; calls are retried within a budget, go through a circuit breaker per host,
+	// and are counted and traced by the observer
+	metrics := observability.NewMetrics()
+	clientObserver := observability.NewClientObserver(metrics)
+	breakerPolicy := aevumclient.DefaultBreakerPolicy()
+	breakerPolicy.FailureThreshold = cfg.Breaker.FailureThreshold
+	breakerPolicy.OpenTimeout = cfg.Breaker.OpenTimeout
+	eventTimelineClient := clients.NewEventTimelineClient(cfg.EventTimeline.BaseURL).WithBreakerPolicy(breakerPolicy).WithObserver(clientObserver)
+	decisionEngineClient := clients.NewDecisionEngineClient(cfg.DecisionEngine.BaseURL).WithBreakerPolicy(breakerPolicy).WithObserver(clientObserver)
+	for _, status := range []aevumclient.BreakerStatus{eventTimelineClient.BreakerStatus(), decisionEngineClient.BreakerStatus()} {
+		clientObserver.BreakerChanged(status.Service, status.Host, status.State)
+	}
+	if cfg.ClientTLS.Enabled() {
+		clientCerts, err := mtls.NewReloader(cfg.ClientTLS.CertFile, cfg.ClientTLS.KeyFile, cfg.ClientTLS.CAFile)
+		if err != nil {
+			logger.Error("failed to load client tls certificates", slog.Any("error", err))
+			os.Exit(1)
+		}
+		eventTimelineClient.WithTLSConfig(clientCerts.ClientConfig())
+		decisionEngineClient.WithTLSConfig(clientCerts.ClientConfig())
+	}
+
+	// Create bulk indexer; documents that cannot be indexed go to the dead-letter index
+	deadLetters := deadletter.NewStore(esClient.GetClient()).WithMetrics(metrics)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	if _, err := deadLetters.Count(ctx); err != nil {
+		logger.Warn("failed to count dead letters", slog.Any("error", err))
+	}
+	cancel()
+	bulkIndexer := indexer.NewBulkIndexer(esClient.GetClient(), cfg.Sync.BatchSize, logger).
+		WithLimits(cfg.Sync.BatchBytes, cfg.Sync.FlushInterval).
+		WithRetries(cfg.Sync.BulkMaxRetries, 200*time.Millisecond).
+		WithDeadLetters(deadLetters).
+		WithMetrics(metrics)
+	replayer := indexer.NewReplayer(deadLetters, bulkIndexer, logger)
 
 	// Create search engines
 	searchEngine := search.NewEngine(esClient.GetClient(), logger)
@@ -69,25 +136,58 @@ func main() {
 	auditBuilder := search.NewAuditBuilder(esClient.GetClient(), eventTimelineClient, decisionEngineClient, logger)
 
 	// Create sync workers
-	eventIndexer := indexer.NewEventIndexer(eventTimelineClient, bulkIndexer, logger)
+	syncStates := syncpkg.NewElasticsearchStateStore(esClient.GetClient())
+	eventIndexer := indexer.NewEventIndexer(eventTimelineClient, bulkIndexer, redactionPolicy, logger).
+		WithStreamStates(syncStates, cfg.Sync.StreamConcurrency).
+		WithTenants(cfg.EventTimeline.Tenants)
 	decisionIndexer := indexer.NewDecisionIndexer(decisionEngineClient, bulkIndexer, logger)
 
-	eventWorker := syncpkg.NewWorker("event-timeline", eventIndexer.Sync, cfg.Sync.Interval, cfg.Sync.MaxBackoff, logger)
-	decisionWorker := syncpkg.NewWorker("decision-engine", func(ctx context.Context, cursor string) (string, error) {
-		now := time.Now()
-		if err := decisionIndexer.Sync(ctx, now.Add(-1*time.Hour), now); err != nil {
-			return cursor, err
-		}
-		return cursor, nil
-	}, cfg.Sync.Interval, cfg.Sync.MaxBackoff, logger)
+	eventWorker := syncpkg.NewWorker("event-timeline", eventIndexer.Sync, cfg.Sync.Interval, cfg.Sync.MaxBackoff, logger).WithStateStore(syncStates)
+	decisionWorker := syncpkg.NewWorker("decision-engine", decisionIndexer.Sync, cfg.Sync.Interval, cfg.Sync.MaxBackoff, logger).WithStateStore(syncStates)
+
+	syncOperations := syncpkg.NewOperations(indexManager, logger,
+		syncpkg.Source{Worker: eventWorker, Indexer: eventIndexer, Index: storage.EventsAlias},
+		syncpkg.Source{Worker: decisionWorker, Indexer: decisionIndexer, Index: storage.DecisionsAlias},
+	)
+	reconciler := indexer.NewReconciler(eventIndexer, esClient.GetClient(), logger).
+		WithSampling(cfg.Consistency.SampleSize).
+		WithAutoResync(cfg.Consistency.AutoResync).
+		WithMetrics(metrics)
 
 	// Start sync workers
 	workersCtx, workersCancel := context.WithCancel(context.Background())
 	eventWorker.Start(workersCtx, "")
 	decisionWorker.Start(workersCtx, "")
+	bulkDone := make(chan struct{})
+	go func() {
+		defer close(bulkDone)
+		bulkIndexer.Run(workersCtx)
+	}()
+	reconcilerDone := make(chan struct{})
+	go func() {
+		defer close(reconcilerDone)
+		if cfg.Consistency.Interval > 0 {
+			reconciler.Run(workersCtx, cfg.Consistency.Interval)
+		}
+	}()
+
+	// Start access log recorder; it stops after the server so requests served during shutdown are kept
+	accessStore := accesslog.NewStore(esClient.GetClient())
+	var accessRecorder *accesslog.Recorder
+	accessLogCtx, accessLogCancel := context.WithCancel(context.Background())
+	accessLogDone := make(chan struct{})
+	if cfg.AccessLog.Enabled {
+		accessRecorder = accesslog.NewRecorder(accessStore, cfg.AccessLog.BufferSize, cfg.AccessLog.FlushInterval, logger)
+		go func() {
+			defer close(accessLogDone)
+			accessRecorder.Run(accessLogCtx)
+		}()
+	} else {
+		close(accessLogDone)
+	}
 
 	// Setup router
-	router := api.SetupRouter(searchEngine, temporalQuery, correlationQuery, diffEngine, auditBuilder)
+	router := api.SetupRouter(searchEngine, temporalQuery, correlationQuery, diffEngine, auditBuilder, accessRecorder, accessStore, syncStates, syncOperations, replayer, reconciler, metrics, []api.Dependency{eventTimelineClient, decisionEngineClient})
 
 	// Create HTTP server
 	addr := cfg.Server.Host + ":" + strconv.Itoa(cfg.Server.Port)
@@ -98,10 +198,19 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 	}
 
+	if cfg.TLS.CertFile != "" || cfg.TLS.KeyFile != "" {
+		serverCerts, err := mtls.NewReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ClientCAFile)
+		if err != nil {
+			logger.Error("failed to load server tls certificates", slog.Any("error", err))
+			os.Exit(1)
+		}
+		server.TLSConfig = serverCerts.ServerConfig(cfg.TLS.RequireClientCert)
+	}
+
 	// Start server in goroutine
 	go func() {
-		logger.Info("starting server", slog.String("addr", addr))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Info("starting server", slog.String("addr", addr), slog.Bool("tls", server.TLSConfig != nil))
+		if err := listenAndServe(server); err != nil && err != http.ErrServerClosed {
 			logger.Error("server error", slog.Any("error", err))
 		}
 	}()
@@ -112,10 +221,14 @@ func main() {
 	<-sigChan
 
 	logger.Info("shutting down service")
+	syncOperations.Close()
+	reconciler.Close()
 	workersCancel()
+	<-reconcilerDone
 
 	eventWorker.Stop()
 	decisionWorker.Stop()
+	<-bulkDone
 
 	// Graceful shutdown
 	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
@@ -125,5 +238,19 @@ func main() {
 		logger.Error("shutdown error", slog.Any("error", err))
 	}
 
+	accessLogCancel()
+	<-accessLogDone
+	if accessRecorder != nil && accessRecorder.Dropped() > 0 {
+		logger.Warn("access log entries dropped", slog.Int64("count", accessRecorder.Dropped()))
+	}
+
 	logger.Info("service stopped")
 }
+
+// listenAndServe serves TLS when the server has a TLS config
+func listenAndServe(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,150 @@
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+type memorySink struct {
+	mu      sync.Mutex
+	batches [][]Entry
+	err     error
+}
+
+func (s *memorySink) WriteAccessLog(_ context.Context, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, append([]Entry{}, entries...))
+	return nil
+}
+
+func quietLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestRecorderDropsWhenBufferIsFull(t *testing.T) {
+	recorder := NewRecorder(&memorySink{}, 2, time.Hour, quietLogger())
+	for i := 0; i < 5; i++ {
+		recorder.Record(Entry{Route: "/api/v1/search"})
+	}
+	if recorder.Dropped() != 3 {
+		t.Fatalf("expected 3 dropped entries, got %d", recorder.Dropped())
+	}
+}
+
+func TestRecorderFlushesBufferOnShutdown(t *testing.T) {
+	sink := &memorySink{}
+	recorder := NewRecorder(sink, 0, time.Hour, quietLogger())
+	recorder.Record(Entry{Route: "/api/v1/search"})
+	recorder.Record(Entry{Route: "/api/v1/audit/:decisionId"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+
+	if len(sink.batches) != 1 || len(sink.batches[0]) != 2 {
+		t.Fatalf("expected one batch of two entries, got %+v", sink.batches)
+	}
+	if recorder.Dropped() != 0 {
+		t.Fatalf("expected no dropped entries, got %d", recorder.Dropped())
+	}
+}
+
+func TestRecorderCountsFailedWrites(t *testing.T) {
+	recorder := NewRecorder(&memorySink{err: errors.New("unavailable")}, 0, time.Hour, quietLogger())
+	recorder.Record(Entry{Route: "/api/v1/search"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+
+	if recorder.Dropped() != 1 {
+		t.Fatalf("expected failed write to count as dropped, got %d", recorder.Dropped())
+	}
+}
+
+func TestNilRecorderIgnoresEntries(t *testing.T) {
+	var recorder *Recorder
+	recorder.Record(Entry{})
+}
+
+func TestStoreWritesCreateActionsAndQueries(t *testing.T) {
+	var bulkBody, searchBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_bulk"):
+			bulkBody = string(body)
+			_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+		case r.URL.Path == "/"+IndexName+"/_search":
+			searchBody = string(body)
+			_, _ = w.Write([]byte(`{"hits":{"hits":[{"_source":{"subject":"spiffe://aevum/auditor","route":"/api/v1/audit/:decisionId","decision_id":"d1","status":200}}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	store := NewStore(es)
+
+	if err := store.WriteAccessLog(context.Background(), []Entry{{Route: "/api/v1/search"}, {Route: "/api/v1/diff"}}); err != nil {
+		t.Fatalf("expected write success, got %v", err)
+	}
+	if strings.Count(bulkBody, `{"create":{"_index":"aevum-access-log"}}`) != 2 || strings.Contains(bulkBody, `"_id"`) {
+		t.Fatalf("expected create actions without ids, got %s", bulkBody)
+	}
+
+	entries, err := store.Query(context.Background(), Filter{DecisionID: "d1", Since: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Size: 5000})
+	if err != nil {
+		t.Fatalf("expected query success, got %v", err)
+	}
+	if len(entries) != 1 || entries[0].DecisionID != "d1" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	var query map[string]interface{}
+	if err := json.Unmarshal([]byte(searchBody), &query); err != nil {
+		t.Fatalf("expected json query, got %v", err)
+	}
+	if query["size"] != float64(maxQuerySize) {
+		t.Fatalf("expected size capped at %d, got %v", maxQuerySize, query["size"])
+	}
+	if !strings.Contains(searchBody, `"decision_id":"d1"`) || !strings.Contains(searchBody, `"gte":"2026-01-01T00:00:00Z"`) {
+		t.Fatalf("expected decision and time filters, got %s", searchBody)
+	}
+}
+
+func TestStoreReportsItemErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{"errors":true,"items":[]}`))
+	}))
+	defer server.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := NewStore(es).WriteAccessLog(context.Background(), []Entry{{Route: "/api/v1/search"}}); err == nil {
+		t.Fatal("expected item errors to fail the write")
+	}
+}
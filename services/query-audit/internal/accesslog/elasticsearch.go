@@ -0,0 +1,149 @@
+package accesslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+const (
+	// IndexName is the Elasticsearch index holding access log entries
+	IndexName = "aevum-access-log"
+
+	defaultQuerySize = 100
+	maxQuerySize     = 1000
+)
+
+// Store writes entries to and reads them from Elasticsearch
+type Store struct {
+	client *elasticsearch.Client
+}
+
+// NewStore creates a new access log store
+func NewStore(client *elasticsearch.Client) *Store {
+	return &Store{client: client}
+}
+
+// WriteAccessLog appends entries with create actions and server generated IDs,
+// so an existing entry is never overwritten
+func (s *Store) WriteAccessLog(ctx context.Context, entries []Entry) error {
+	var body bytes.Buffer
+	action := []byte(`{"create":{"_index":"` + IndexName + `"}}` + "\n")
+	for _, entry := range entries {
+		doc, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal access log entry: %w", err)
+		}
+		body.Write(action)
+		body.Write(doc)
+		body.WriteString("\n")
+	}
+
+	res, err := esapi.BulkRequest{Body: &body}.Do(ctx, s.client)
+	if err != nil {
+		return fmt.Errorf("access log bulk request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		respBody, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("access log bulk request failed: %s", string(respBody))
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode access log bulk response: %w", err)
+	}
+	if result.Errors {
+		return fmt.Errorf("access log bulk request had item errors")
+	}
+	return nil
+}
+
+// Filter selects access log entries; empty fields match everything
+type Filter struct {
+	Subject    string
+	StreamID   string
+	DecisionID string
+	Since      time.Time
+	Until      time.Time
+	Size       int
+}
+
+// Query returns matching entries, newest first
+func (s *Store) Query(ctx context.Context, f Filter) ([]Entry, error) {
+	body, err := json.Marshal(buildQuery(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal access log query: %w", err)
+	}
+
+	res, err := s.client.Search(s.client.Search.WithContext(ctx), s.client.Search.WithIndex(IndexName), s.client.Search.WithBody(bytes.NewReader(body)))
+	if err != nil {
+		return nil, fmt.Errorf("access log search failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("access log search failed: status %d", res.StatusCode)
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source Entry `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode access log search response: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		entries = append(entries, hit.Source)
+	}
+	return entries, nil
+}
+
+// buildQuery builds the ES query for a filter
+func buildQuery(f Filter) map[string]interface{} {
+	size := f.Size
+	if size <= 0 {
+		size = defaultQuerySize
+	}
+	if size > maxQuerySize {
+		size = maxQuerySize
+	}
+
+	filters := []map[string]interface{}{}
+	terms := map[string]string{"subject": f.Subject, "stream_id": f.StreamID, "decision_id": f.DecisionID}
+	for _, field := range []string{"subject", "stream_id", "decision_id"} {
+		if terms[field] != "" {
+			filters = append(filters, map[string]interface{}{"term": map[string]interface{}{field: terms[field]}})
+		}
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		timeRange := map[string]interface{}{}
+		if !f.Since.IsZero() {
+			timeRange["gte"] = f.Since.Format(time.RFC3339Nano)
+		}
+		if !f.Until.IsZero() {
+			timeRange["lt"] = f.Until.Format(time.RFC3339Nano)
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"time": timeRange}})
+	}
+
+	return map[string]interface{}{
+		"size":  size,
+		"sort":  []map[string]interface{}{{"time": map[string]interface{}{"order": "desc"}}},
+		"query": map[string]interface{}{"bool": map[string]interface{}{"filter": filters}},
+	}
+}
@@ -0,0 +1,128 @@
+package accesslog
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultBufferSize    = 1024
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	drainTimeout         = 5 * time.Second
+)
+
+// Entry is one recorded API call
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Subject    string    `json:"subject,omitempty"`
+	Method     string    `json:"method"`
+	Route      string    `json:"route"`
+	StreamID   string    `json:"stream_id,omitempty"`
+	EventID    string    `json:"event_id,omitempty"`
+	DecisionID string    `json:"decision_id,omitempty"`
+	Status     int       `json:"status"`
+	RequestID  string    `json:"request_id,omitempty"`
+}
+
+// Sink stores batches of entries
+type Sink interface {
+	WriteAccessLog(ctx context.Context, entries []Entry) error
+}
+
+// Recorder buffers entries in memory and writes them to a sink in batches
+type Recorder struct {
+	sink          Sink
+	entries       chan Entry
+	batchSize     int
+	flushInterval time.Duration
+	dropped       atomic.Int64
+	logger        *slog.Logger
+}
+
+// NewRecorder creates a recorder; non-positive sizes use the defaults
+func NewRecorder(sink Sink, bufferSize int, flushInterval time.Duration, logger *slog.Logger) *Recorder {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+	return &Recorder{
+		sink:          sink,
+		entries:       make(chan Entry, bufferSize),
+		batchSize:     defaultBatchSize,
+		flushInterval: flushInterval,
+		logger:        logger,
+	}
+}
+
+// Record queues an entry without blocking; it is dropped when the buffer is full
+func (r *Recorder) Record(entry Entry) {
+	if r == nil {
+		return
+	}
+	select {
+	case r.entries <- entry:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many entries were lost to a full buffer or failed writes
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Run writes batches until ctx is cancelled, then flushes what is left
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+	batch := make([]Entry, 0, r.batchSize)
+	for {
+		select {
+		case entry := <-r.entries:
+			batch = append(batch, entry)
+			if len(batch) >= r.batchSize {
+				batch = r.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(ctx, batch)
+		case <-ctx.Done():
+			r.drain(batch)
+			return
+		}
+	}
+}
+
+// drain flushes the batch and the buffer with a fresh deadline
+func (r *Recorder) drain(batch []Entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	for {
+		select {
+		case entry := <-r.entries:
+			batch = append(batch, entry)
+			if len(batch) >= r.batchSize {
+				batch = r.flush(ctx, batch)
+			}
+		default:
+			r.flush(ctx, batch)
+			return
+		}
+	}
+}
+
+// flush writes the batch and returns it emptied for reuse
+func (r *Recorder) flush(ctx context.Context, batch []Entry) []Entry {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := r.sink.WriteAccessLog(ctx, batch); err != nil {
+		dropped := r.dropped.Add(int64(len(batch)))
+		r.logger.Warn("access log write failed", slog.Int("entries", len(batch)), slog.Int64("dropped_total", dropped), slog.Any("error", err))
+	}
+	return batch[:0]
+}
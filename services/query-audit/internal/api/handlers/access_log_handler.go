@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/accesslog"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+)
+
+// AccessLogHandler handles access log queries
+type AccessLogHandler struct {
+	store *accesslog.Store
+}
+
+// NewAccessLogHandler creates a new access log handler
+func NewAccessLogHandler(store *accesslog.Store) *AccessLogHandler {
+	return &AccessLogHandler{store: store}
+}
+
+// Handle returns access log entries, newest first
+func (ah *AccessLogHandler) Handle(c *gin.Context) {
+	filter := accesslog.Filter{
+		Subject:    c.Query("subject"),
+		StreamID:   c.Query("stream_id"),
+		DecisionID: c.Query("decision_id"),
+	}
+	for param, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be RFC3339", "code": string(domain.ErrInvalidQuery)})
+			return
+		}
+		*target = parsed
+	}
+	if raw := c.Query("size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "size must be a positive integer", "code": string(domain.ErrInvalidQuery)})
+			return
+		}
+		filter.Size = size
+	}
+
+	entries, err := ah.store.Query(c, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "access log query failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/indexer"
+)
+
+// ConsistencyHandler starts consistency checks between Event Timeline and the events index and reports them
+type ConsistencyHandler struct {
+	reconciler *indexer.Reconciler
+}
+
+// NewConsistencyHandler creates a new consistency handler
+func NewConsistencyHandler(reconciler *indexer.Reconciler) *ConsistencyHandler {
+	return &ConsistencyHandler{reconciler: reconciler}
+}
+
+type checkRequest struct {
+	Resync bool `json:"resync"`
+}
+
+// Report returns the report of the last check and whether a check is running
+func (ch *ConsistencyHandler) Report(c *gin.Context) {
+	report, running := ch.reconciler.Last()
+	c.JSON(http.StatusOK, gin.H{"report": report, "running": running})
+}
+
+// Check starts a check in the background, re-syncing what it finds when asked to
+func (ch *ConsistencyHandler) Check(c *gin.Context) {
+	var req checkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "code": string(domain.ErrInvalidQuery)})
+			return
+		}
+	}
+
+	err := ch.reconciler.Start(req.Resync)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusAccepted, gin.H{"status": "started", "resync": req.Resync})
+	case errors.Is(err, indexer.ErrCheckRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "consistency check failed to start"})
+	}
+}
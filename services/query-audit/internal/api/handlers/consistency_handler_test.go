@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/clients"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/indexer"
+)
+
+func TestConsistencyHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	timeline := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer timeline.Close()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	events := indexer.NewEventIndexer(clients.NewEventTimelineClient(timeline.URL), nil, nil, logger)
+	reconciler := indexer.NewReconciler(events, nil, logger)
+	defer reconciler.Close()
+
+	handler := NewConsistencyHandler(reconciler)
+	r := gin.New()
+	r.GET("/admin/consistency", handler.Report)
+	r.POST("/admin/consistency/check", handler.Check)
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	if w := call(http.MethodGet, "/admin/consistency", ""); w.Code != http.StatusOK || w.Body.String() != `{"report":null,"running":false}` {
+		t.Fatalf("unexpected report before the first check %d %s", w.Code, w.Body.String())
+	}
+	if w := call(http.MethodPost, "/admin/consistency/check", `{"resync":`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid body, got %d", w.Code)
+	}
+	if w := call(http.MethodPost, "/admin/consistency/check", `{"resync":true}`); w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d %s", w.Code, w.Body.String())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		w := call(http.MethodGet, "/admin/consistency", "")
+		if strings.Contains(w.Body.String(), `"running":false`) && strings.Contains(w.Body.String(), "failed to list streams") {
+			if !strings.Contains(w.Body.String(), `"resync":true`) || !strings.Contains(w.Body.String(), `"consistent":false`) {
+				t.Fatalf("unexpected failed report %s", w.Body.String())
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the failed check to be reported, got %s", w.Body.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
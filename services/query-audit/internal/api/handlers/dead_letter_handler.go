@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/deadletter"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/indexer"
+)
+
+// DeadLetterHandler lists the documents that could not be indexed and retries them
+type DeadLetterHandler struct {
+	replayer *indexer.Replayer
+}
+
+// NewDeadLetterHandler creates a new dead-letter handler
+func NewDeadLetterHandler(replayer *indexer.Replayer) *DeadLetterHandler {
+	return &DeadLetterHandler{replayer: replayer}
+}
+
+type retryRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// List returns dead letters, most recently failed first, optionally of one index
+func (dh *DeadLetterHandler) List(c *gin.Context) {
+	filter := deadletter.Filter{Index: c.Query("index")}
+	if raw := c.Query("size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 || size > deadletter.MaxQuerySize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "size must be between 1 and " + strconv.Itoa(deadletter.MaxQuerySize), "code": string(domain.ErrInvalidQuery)})
+			return
+		}
+		filter.Size = size
+	}
+
+	letters, total, err := dh.replayer.List(c, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "dead letter query failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"dead_letters": letters, "total": total})
+}
+
+// Retry indexes the requested dead letters again, or all of them when no ids are given
+func (dh *DeadLetterHandler) Retry(c *gin.Context) {
+	var req retryRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "code": string(domain.ErrInvalidQuery)})
+			return
+		}
+	}
+
+	result, err := dh.replayer.Replay(c, req.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "dead letter retry failed", "result": result})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": result})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/deadletter"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/indexer"
+)
+
+type fakeDeadLetters struct {
+	letters []deadletter.Letter
+	filter  deadletter.Filter
+	deleted []string
+}
+
+func (f *fakeDeadLetters) Record(context.Context, []deadletter.Letter) error { return nil }
+
+func (f *fakeDeadLetters) Query(_ context.Context, filter deadletter.Filter) ([]deadletter.Letter, int64, error) {
+	f.filter = filter
+	return f.letters, int64(len(f.letters)), nil
+}
+
+func (f *fakeDeadLetters) Delete(_ context.Context, ids []string) error {
+	f.deleted = append(f.deleted, ids...)
+	return nil
+}
+
+func (f *fakeDeadLetters) Count(context.Context) (int64, error) {
+	return int64(len(f.letters) - len(f.deleted)), nil
+}
+
+func TestDeadLetterHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{"errors":false,"items":[{"index":{"_id":"dec-1","status":201}}]}`))
+	}))
+	defer es.Close()
+	client, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{es.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := &fakeDeadLetters{letters: []deadletter.Letter{{
+		ID:         "aevum-decisions-write/dec-1",
+		Index:      "aevum-decisions-write",
+		DocumentID: "dec-1",
+		Document:   json.RawMessage(`{"id":"dec-1"}`),
+		Stage:      deadletter.StageIndex,
+		Attempts:   1,
+	}}}
+	handler := NewDeadLetterHandler(indexer.NewReplayer(store, indexer.NewBulkIndexer(client, 10, logger), logger))
+
+	r := gin.New()
+	r.GET("/admin/dead-letters", handler.List)
+	r.POST("/admin/dead-letters/retry", handler.Retry)
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := call(http.MethodGet, "/admin/dead-letters?index=aevum-decisions-write&size=10", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"total":1`) || !strings.Contains(w.Body.String(), `"document_id":"dec-1"`) {
+		t.Fatalf("unexpected list response %d %s", w.Code, w.Body.String())
+	}
+	if store.filter.Index != "aevum-decisions-write" || store.filter.Size != 10 {
+		t.Fatalf("unexpected filter %+v", store.filter)
+	}
+	if w := call(http.MethodGet, "/admin/dead-letters?size=0", ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid size, got %d", w.Code)
+	}
+
+	w = call(http.MethodPost, "/admin/dead-letters/retry", `{"ids":["aevum-decisions-write/dec-1"]}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"indexed":1`) {
+		t.Fatalf("unexpected retry response %d %s", w.Code, w.Body.String())
+	}
+	if len(store.filter.IDs) != 1 || len(store.deleted) != 1 {
+		t.Fatalf("expected the requested letter to be retried and removed, got %+v %v", store.filter, store.deleted)
+	}
+	if w := call(http.MethodPost, "/admin/dead-letters/retry", `{"ids":`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid body, got %d", w.Code)
+	}
+}
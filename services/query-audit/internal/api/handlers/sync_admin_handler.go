@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+)
+
+// SyncAdminHandler starts sync, reset and rebuild operations and reports their progress
+type SyncAdminHandler struct {
+	operations *syncpkg.Operations
+}
+
+// NewSyncAdminHandler creates a new sync admin handler
+func NewSyncAdminHandler(operations *syncpkg.Operations) *SyncAdminHandler {
+	return &SyncAdminHandler{operations: operations}
+}
+
+type sourceRequest struct {
+	Source string `json:"source"`
+}
+
+type resetRequest struct {
+	Source string `json:"source"`
+	syncpkg.Position
+}
+
+// Sync starts a sync of the requested source, or of every source when none is given
+func (sh *SyncAdminHandler) Sync(c *gin.Context) {
+	var req sourceRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "code": string(domain.ErrInvalidQuery)})
+			return
+		}
+	}
+
+	if req.Source != "" {
+		sh.start(c, syncpkg.OperationSync, req.Source)
+		return
+	}
+	operations := []syncpkg.Operation{}
+	for _, source := range sh.operations.Sources() {
+		// A source that is already busy reports its running operation
+		op, err := sh.operations.Start(syncpkg.OperationSync, source)
+		if err != nil && !errors.Is(err, syncpkg.ErrOperationRunning) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "sync failed to start"})
+			return
+		}
+		operations = append(operations, op)
+	}
+	c.JSON(http.StatusAccepted, gin.H{"operations": operations})
+}
+
+// Rebuild indexes the requested source from the start into a new version of its index
+func (sh *SyncAdminHandler) Rebuild(c *gin.Context) {
+	var req sourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Source == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source is required", "code": string(domain.ErrInvalidQuery)})
+		return
+	}
+	sh.start(c, syncpkg.OperationRebuild, req.Source)
+}
+
+// Reset moves the requested source to a stream sequence or a timestamp; the next sync continues from there
+func (sh *SyncAdminHandler) Reset(c *gin.Context) {
+	var req resetRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Source == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source is required and timestamp must be RFC3339", "code": string(domain.ErrInvalidQuery)})
+		return
+	}
+
+	state, err := sh.operations.Reset(c, req.Source, req.Position)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"state": state})
+	case errors.Is(err, syncpkg.ErrUnknownSource):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": string(domain.ErrNotFound)})
+	case errors.Is(err, syncpkg.ErrInvalidPosition):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": string(domain.ErrInvalidQuery)})
+	case errors.Is(err, syncpkg.ErrOperationRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, syncpkg.ErrNotReady):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "sync reset failed"})
+	}
+}
+
+// Operations returns the recent operations, newest first
+func (sh *SyncAdminHandler) Operations(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"operations": sh.operations.List()})
+}
+
+// Operation returns one operation
+func (sh *SyncAdminHandler) Operation(c *gin.Context) {
+	op, ok := sh.operations.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "operation not found", "code": string(domain.ErrNotFound)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"operation": op})
+}
+
+// start begins an operation on one source
+func (sh *SyncAdminHandler) start(c *gin.Context, kind, source string) {
+	op, err := sh.operations.Start(kind, source)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusAccepted, gin.H{"operation": op})
+	case errors.Is(err, syncpkg.ErrUnknownSource):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": string(domain.ErrNotFound)})
+	case errors.Is(err, syncpkg.ErrOperationRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "operation": op})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "operation failed to start"})
+	}
+}
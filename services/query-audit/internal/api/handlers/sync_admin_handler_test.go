@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+)
+
+type fakeSourceIndexer struct{}
+
+func (fakeSourceIndexer) Remaining(context.Context, string) (int64, error) { return 0, nil }
+
+func (fakeSourceIndexer) Reset(_ context.Context, position syncpkg.Position) (string, error) {
+	if position.StreamID != "" {
+		return "", syncpkg.ErrInvalidPosition
+	}
+	return position.Timestamp.Format(time.RFC3339), nil
+}
+
+type fakeIndices struct{}
+
+func (fakeIndices) CreateVersion(context.Context, string) (string, error) {
+	return "aevum-decisions-v2", nil
+}
+
+func (fakeIndices) PromoteVersion(context.Context, string, string) error { return nil }
+
+func TestSyncAdminHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	worker := syncpkg.NewWorker("decision-engine", func(_ context.Context, cursor string) (string, error) {
+		return cursor, nil
+	}, time.Hour, time.Hour, logger)
+	worker.Start(context.Background(), "")
+	defer worker.Stop()
+	for {
+		if _, err := worker.Do(context.Background(), func(*syncpkg.SyncState) error { return nil }); !errors.Is(err, syncpkg.ErrNotReady) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	operations := syncpkg.NewOperations(fakeIndices{}, logger, syncpkg.Source{Worker: worker, Indexer: fakeSourceIndexer{}})
+	defer operations.Close()
+
+	handler := NewSyncAdminHandler(operations)
+	r := gin.New()
+	r.POST("/admin/sync", handler.Sync)
+	r.POST("/admin/sync/reset", handler.Reset)
+	r.POST("/admin/rebuild", handler.Rebuild)
+	r.GET("/admin/operations", handler.Operations)
+	r.GET("/admin/operations/:id", handler.Operation)
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := call(http.MethodPost, "/admin/sync", "")
+	if w.Code != http.StatusAccepted || !strings.Contains(w.Body.String(), `"source":"decision-engine"`) {
+		t.Fatalf("unexpected sync response %d %s", w.Code, w.Body.String())
+	}
+	if w := call(http.MethodPost, "/admin/sync", `{"source":"unknown"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown source, got %d", w.Code)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		w = call(http.MethodGet, "/admin/operations/1", "")
+		var body struct {
+			Operation syncpkg.Operation `json:"operation"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if w.Code == http.StatusOK && body.Operation.Status == "succeeded" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected sync operation to succeed, got %d %s", w.Code, w.Body.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if w := call(http.MethodGet, "/admin/operations/99", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown operation, got %d", w.Code)
+	}
+
+	w = call(http.MethodPost, "/admin/sync/reset", `{"source":"decision-engine","timestamp":"2026-03-01T12:00:00Z"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"last_synced_cursor":"2026-03-01T12:00:00Z"`) {
+		t.Fatalf("unexpected reset response %d %s", w.Code, w.Body.String())
+	}
+	if w := call(http.MethodPost, "/admin/sync/reset", `{"source":"decision-engine","stream_id":"orders"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid position, got %d", w.Code)
+	}
+	if w := call(http.MethodPost, "/admin/sync/reset", `{"source":"decision-engine","timestamp":"yesterday"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid timestamp, got %d", w.Code)
+	}
+
+	if w := call(http.MethodPost, "/admin/rebuild", `{}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without source, got %d", w.Code)
+	}
+	w = call(http.MethodPost, "/admin/rebuild", `{"source":"decision-engine"}`)
+	if w.Code != http.StatusAccepted || !strings.Contains(w.Body.String(), `"kind":"rebuild"`) {
+		t.Fatalf("unexpected rebuild response %d %s", w.Code, w.Body.String())
+	}
+
+	w = call(http.MethodGet, "/admin/operations", "")
+	if w.Code != http.StatusOK || strings.Count(w.Body.String(), `"id"`) != 2 {
+		t.Fatalf("unexpected operations response %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+)
+
+// SyncStatusHandler reports the persisted state of the sync workers
+type SyncStatusHandler struct {
+	store syncpkg.StateStore
+}
+
+// NewSyncStatusHandler creates a new sync status handler
+func NewSyncStatusHandler(store syncpkg.StateStore) *SyncStatusHandler {
+	return &SyncStatusHandler{store: store}
+}
+
+// Handle returns the sync state of every source
+func (sh *SyncStatusHandler) Handle(c *gin.Context) {
+	states, err := sh.store.List(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "sync status query failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"states": states})
+}
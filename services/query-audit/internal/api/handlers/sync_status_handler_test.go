@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+)
+
+type fakeStateStore struct {
+	states []syncpkg.SyncState
+	err    error
+}
+
+func (s *fakeStateStore) Load(_ context.Context, serviceName string) (*syncpkg.SyncState, error) {
+	return syncpkg.NewSyncState(serviceName), nil
+}
+
+func (s *fakeStateStore) Save(context.Context, syncpkg.SyncState) error { return nil }
+
+func (s *fakeStateStore) List(context.Context) ([]syncpkg.SyncState, error) {
+	return s.states, s.err
+}
+
+func TestSyncStatusHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &fakeStateStore{states: []syncpkg.SyncState{{ServiceName: "event-timeline", LastSyncedCursor: "42", SyncStatus: "synced"}}}
+	r := gin.New()
+	r.GET("/admin/sync/status", NewSyncStatusHandler(store).Handle)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/sync/status", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"last_synced_cursor":"42"`) {
+		t.Fatalf("unexpected response %d %s", w.Code, w.Body.String())
+	}
+
+	store.err = errors.New("unavailable")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/sync/status", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+}
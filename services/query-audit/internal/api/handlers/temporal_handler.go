@@ -219,7 +219,7 @@ func (ah *AuditHandler) Handle(c *gin.Context) {
 		return
 	}
 
-	trail, err := ah.builder.Build(c, decisionID)
+	trail, err := ah.builder.Build(c.Request.Context(), decisionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "audit build failed"})
 		return
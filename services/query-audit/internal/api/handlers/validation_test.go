@@ -93,3 +93,24 @@ func TestAuditHandlerValidation(t *testing.T) {
 		t.Fatalf("expected 400 for missing decision id, got %d", w.Code)
 	}
 }
+
+func TestAccessLogHandlerValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewAccessLogHandler(nil)
+	r := gin.New()
+	r.GET("/admin/access-log", h.Handle)
+
+	cases := []string{
+		"/admin/access-log?since=yesterday",
+		"/admin/access-log?until=2026-01-01",
+		"/admin/access-log?size=0",
+		"/admin/access-log?size=many",
+	}
+	for _, path := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", path, w.Code)
+		}
+	}
+}
@@ -4,21 +4,59 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/accesslog"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/api/handlers"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/indexer"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/middleware"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/observability"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/search"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Dependency is a service client whose circuit breaker decides readiness
+type Dependency interface {
+	BreakerStatus() aevumclient.BreakerStatus
+}
+
 // SetupRouter sets up the HTTP router
-func SetupRouter(searchEngine *search.Engine, temporalQuery *search.TemporalQuery, correlationQuery *search.CorrelationQuery, diffEngine *search.DiffEngine, auditBuilder *search.AuditBuilder) *gin.Engine {
+func SetupRouter(searchEngine *search.Engine, temporalQuery *search.TemporalQuery, correlationQuery *search.CorrelationQuery, diffEngine *search.DiffEngine, auditBuilder *search.AuditBuilder, accessRecorder *accesslog.Recorder, accessStore *accesslog.Store, syncStates syncpkg.StateStore, syncOperations *syncpkg.Operations, deadLetters *indexer.Replayer, consistency *indexer.Reconciler, metrics *observability.Metrics, dependencies []Dependency) *gin.Engine {
 	router := gin.Default()
 
 	// Apply middleware
 	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.AccessLogMiddleware(accessRecorder))
 
-	// Health check
+	// Health check; dead letters degrade the status without failing the check, as the service keeps serving
 	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		health := gin.H{"status": "ok"}
+		if deadLetters != nil {
+			if count, err := deadLetters.Count(c); err == nil {
+				health["dead_letters"] = count
+				if count > 0 {
+					health["status"] = "degraded"
+				}
+			}
+		}
+		c.JSON(http.StatusOK, health)
+	})
+
+	// Readiness fails while the breaker of a service query-audit calls is open; a half-open breaker
+	// is probing and counts as ready
+	router.GET("/ready", func(c *gin.Context) {
+		statuses := make([]aevumclient.BreakerStatus, 0, len(dependencies))
+		ready := true
+		for _, dependency := range dependencies {
+			status := dependency.BreakerStatus()
+			statuses = append(statuses, status)
+			ready = ready && status.State != aevumclient.BreakerOpen
+		}
+		if !ready {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not_ready", "dependencies": statuses})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ready", "dependencies": statuses})
 	})
 
 	// API v1 group
@@ -51,15 +89,40 @@ func SetupRouter(searchEngine *search.Engine, temporalQuery *search.TemporalQuer
 
 	// Admin endpoints
 	admin := router.Group("/admin")
-	admin.POST("/sync", func(c *gin.Context) {
-		c.JSON(http.StatusOK, map[string]string{"status": "synced"})
-	})
-	admin.GET("/metrics", func(c *gin.Context) {
-		c.JSON(http.StatusOK, map[string]interface{}{
-			"total_documents": 0,
-			"indexes":         []string{},
+	if syncOperations != nil {
+		syncAdminHandler := handlers.NewSyncAdminHandler(syncOperations)
+		admin.POST("/sync", syncAdminHandler.Sync)
+		admin.POST("/sync/reset", syncAdminHandler.Reset)
+		admin.POST("/rebuild", syncAdminHandler.Rebuild)
+		admin.GET("/operations", syncAdminHandler.Operations)
+		admin.GET("/operations/:id", syncAdminHandler.Operation)
+	}
+	if deadLetters != nil {
+		deadLetterHandler := handlers.NewDeadLetterHandler(deadLetters)
+		admin.GET("/dead-letters", deadLetterHandler.List)
+		admin.POST("/dead-letters/retry", deadLetterHandler.Retry)
+	}
+	if consistency != nil {
+		consistencyHandler := handlers.NewConsistencyHandler(consistency)
+		admin.GET("/consistency", consistencyHandler.Report)
+		admin.POST("/consistency/check", consistencyHandler.Check)
+	}
+	if syncStates != nil {
+		admin.GET("/sync/status", handlers.NewSyncStatusHandler(syncStates).Handle)
+	}
+	if accessStore != nil {
+		admin.GET("/access-log", handlers.NewAccessLogHandler(accessStore).Handle)
+	}
+	if metrics != nil {
+		admin.GET("/metrics", gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+	} else {
+		admin.GET("/metrics", func(c *gin.Context) {
+			c.JSON(http.StatusOK, map[string]interface{}{
+				"total_documents": 0,
+				"indexes":         []string{},
+			})
 		})
-	})
+	}
 
 	return router
 }
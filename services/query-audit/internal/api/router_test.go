@@ -1,20 +1,27 @@
 package api
 
 import (
+	"context"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/clients"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/deadletter"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/indexer"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/observability"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/search"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
 )
 
 func TestSetupRouter_BasicEndpointsAndMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := SetupRouter(nil, nil, nil, nil, nil)
+	router := SetupRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
@@ -45,7 +52,7 @@ func TestSetupRouter_RegistersSearchRoutesWhenDependenciesProvided(t *testing.T)
 	diff := search.NewDiffEngine(nil, logger)
 	audit := search.NewAuditBuilder(nil, clients.NewEventTimelineClient("http://example"), clients.NewDecisionEngineClient("http://example"), logger)
 
-	router := SetupRouter(searchEngine, temporal, correlation, diff, audit)
+	router := SetupRouter(searchEngine, temporal, correlation, diff, audit, nil, nil, syncpkg.NewElasticsearchStateStore(nil), syncpkg.NewOperations(nil, logger), indexer.NewReplayer(countedDeadLetters{}, indexer.NewBulkIndexer(nil, 10, logger), logger), indexer.NewReconciler(nil, nil, logger), observability.NewMetrics(), nil)
 
 	routeSet := map[string]bool{}
 	for _, route := range router.Routes() {
@@ -62,6 +69,16 @@ func TestSetupRouter_RegistersSearchRoutesWhenDependenciesProvided(t *testing.T)
 		http.MethodGet + " /api/v1/diff",
 		http.MethodPost + " /api/v1/diff",
 		http.MethodGet + " /api/v1/audit/:decisionId",
+		http.MethodGet + " /admin/sync/status",
+		http.MethodPost + " /admin/sync",
+		http.MethodPost + " /admin/sync/reset",
+		http.MethodPost + " /admin/rebuild",
+		http.MethodGet + " /admin/operations",
+		http.MethodGet + " /admin/operations/:id",
+		http.MethodGet + " /admin/dead-letters",
+		http.MethodPost + " /admin/dead-letters/retry",
+		http.MethodGet + " /admin/consistency",
+		http.MethodPost + " /admin/consistency/check",
 	}
 
 	for _, key := range required {
@@ -69,4 +86,67 @@ func TestSetupRouter_RegistersSearchRoutesWhenDependenciesProvided(t *testing.T)
 			t.Fatalf("expected route %s to be registered", key)
 		}
 	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/metrics", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "aevum_bulk_request_duration_seconds") {
+		t.Fatalf("expected prometheus metrics, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+type countedDeadLetters struct{ count int64 }
+
+func (d countedDeadLetters) Record(context.Context, []deadletter.Letter) error { return nil }
+
+func (d countedDeadLetters) Query(context.Context, deadletter.Filter) ([]deadletter.Letter, int64, error) {
+	return nil, d.count, nil
+}
+
+func (d countedDeadLetters) Delete(context.Context, []string) error { return nil }
+
+func (d countedDeadLetters) Count(context.Context) (int64, error) { return d.count, nil }
+
+func TestSetupRouter_HealthReportsDeadLetters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for count, want := range map[int64]string{0: `{"dead_letters":0,"status":"ok"}`, 2: `{"dead_letters":2,"status":"degraded"}`} {
+		replayer := indexer.NewReplayer(countedDeadLetters{count: count}, indexer.NewBulkIndexer(nil, 10, logger), logger)
+		router := SetupRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, replayer, nil, nil, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+		if w.Code != http.StatusOK || w.Body.String() != want {
+			t.Fatalf("expected 200 %s, got %d %s", want, w.Code, w.Body.String())
+		}
+	}
+}
+
+type breakerDependency aevumclient.BreakerStatus
+
+func (d breakerDependency) BreakerStatus() aevumclient.BreakerStatus {
+	return aevumclient.BreakerStatus(d)
+}
+
+func TestSetupRouter_ReadinessFollowsBreakers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for state, want := range map[aevumclient.BreakerState]string{
+		aevumclient.BreakerHalfOpen: `{"dependencies":[{"service":"event-timeline","host":"et:8080","state":"closed"},{"service":"decision-engine","host":"de:8081","state":"half-open"}],"status":"ready"}`,
+		aevumclient.BreakerOpen:     `{"dependencies":[{"service":"event-timeline","host":"et:8080","state":"closed"},{"service":"decision-engine","host":"de:8081","state":"open"}],"status":"not_ready"}`,
+	} {
+		dependencies := []Dependency{
+			breakerDependency{Service: "event-timeline", Host: "et:8080", State: aevumclient.BreakerClosed},
+			breakerDependency{Service: "decision-engine", Host: "de:8081", State: state},
+		}
+		router := SetupRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, dependencies)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		wantCode := http.StatusOK
+		if state == aevumclient.BreakerOpen {
+			wantCode = http.StatusServiceUnavailable
+		}
+		if w.Code != wantCode || w.Body.String() != want {
+			t.Fatalf("expected %d %s, got %d %s", wantCode, want, w.Code, w.Body.String())
+		}
+	}
 }
@@ -2,88 +2,114 @@ package clients
 
 import (
 	"context"
-	"fmt"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
-	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
 )
 
-func TestEventTimelineClientGetEventsAndGetEvent(t *testing.T) {
+func TestEventTimelineClientAuthenticatesFromEnvironment(t *testing.T) {
+	var authorization string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/api/v1/streams/all/events":
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"data":[{"event_id":"e1"}],"meta":{"next_cursor":"c2"}}`))
-		case "/api/v1/events/e1":
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"data":{"event_id":"e1","stream_id":"s1"}}`))
-		default:
-			w.WriteHeader(http.StatusNotFound)
-		}
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"streams":[]}`))
 	}))
 	defer server.Close()
 
-	client := NewEventTimelineClient(server.URL)
+	t.Setenv("EVENT_TIMELINE_JWT_SECRET", "secret")
+	t.Setenv("EVENT_TIMELINE_JWT_AUDIENCE", "event-timeline")
+	if _, err := NewEventTimelineClient(server.URL).ListStreams(context.Background()); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if parts := strings.Split(strings.TrimPrefix(authorization, "Bearer "), "."); len(parts) != 3 {
+		t.Fatalf("expected a minted JWT, got %q", authorization)
+	}
 
-	events, cursor, err := client.GetEvents(context.Background(), "", 50)
-	if err != nil {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("issued-token\n"), 0o600); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	t.Setenv("EVENT_TIMELINE_TOKEN_FILE", tokenFile)
+	if _, err := NewEventTimelineClient(server.URL).ListStreams(context.Background()); err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
-	if len(events) != 1 || cursor != "c2" {
-		t.Fatalf("unexpected events response: len=%d cursor=%s", len(events), cursor)
+	if authorization != "Bearer issued-token" {
+		t.Fatalf("expected the token file to win over the secret, got %q", authorization)
 	}
 
-	event, err := client.GetEvent(context.Background(), "e1")
-	if err != nil {
+	t.Setenv("EVENT_TIMELINE_TOKEN_FILE", "")
+	t.Setenv("EVENT_TIMELINE_JWT_SECRET", "")
+	if _, err := NewEventTimelineClient(server.URL).ListStreams(context.Background()); err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
-	if (*event)["event_id"] != "e1" {
-		t.Fatal("unexpected event payload")
+	if authorization != "" {
+		t.Fatalf("expected no token without settings, got %q", authorization)
 	}
 }
 
-func TestDecisionEngineClientEndpoints(t *testing.T) {
+func TestEventTimelineClientTokensCarryTheTenant(t *testing.T) {
+	var authorization string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch {
-		case r.URL.Path == "/api/v1/decisions":
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"data":[{"decision_id":"d1"}]}`))
-		case r.URL.Path == "/api/v1/decisions/d1":
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"data":{"decision_id":"d1"}}`))
-		case r.URL.Path == "/api/v1/rules":
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"data":{"items":[]}}`))
-		case r.URL.Path == "/api/v1/rules/r1":
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"data":{"id":"r1"}}`))
-		default:
-			w.WriteHeader(http.StatusNotFound)
-		}
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"streams":[]}`))
 	}))
 	defer server.Close()
-
-	client := NewDecisionEngineClient(server.URL)
-
-	decisions, err := client.GetDecisions(context.Background(), time.Now().Add(-time.Hour), time.Now())
-	if err != nil || len(decisions) != 1 {
-		t.Fatalf("unexpected decisions response: err=%v len=%d", err, len(decisions))
+	claims := func() map[string]interface{} {
+		parts := strings.Split(strings.TrimPrefix(authorization, "Bearer "), ".")
+		if len(parts) != 3 {
+			t.Fatalf("expected a minted JWT, got %q", authorization)
+		}
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var claims map[string]interface{}
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			t.Fatalf("expected JSON claims, got %v", err)
+		}
+		return claims
 	}
 
-	decision, err := client.GetDecision(context.Background(), "d1")
-	if err != nil || (*decision)["decision_id"] != "d1" {
-		t.Fatalf("unexpected decision response: err=%v value=%v", err, decision)
+	t.Setenv("EVENT_TIMELINE_JWT_SECRET", "secret")
+	t.Setenv("EVENT_TIMELINE_TENANT_CLAIM", "org")
+	client := NewEventTimelineClient(server.URL)
+	if _, err := client.ListStreams(aevumclient.WithTenant(context.Background(), "acme")); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if tenant := claims()["org"]; tenant != "acme" {
+		t.Fatalf("expected the tenant in the configured claim, got %v", tenant)
+	}
+	if _, err := client.ListStreams(context.Background()); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if _, ok := claims()["org"]; ok {
+		t.Fatal("expected no tenant claim for the default tenant")
 	}
 
-	rules, err := client.GetRules(context.Background())
-	if err != nil || rules == nil {
-		t.Fatalf("unexpected rules response: err=%v", err)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "acme.token"), []byte("acme-token"), 0o600); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	t.Setenv("EVENT_TIMELINE_TOKEN_FILE", filepath.Join(dir, "{tenant}.token"))
+	if _, err := NewEventTimelineClient(server.URL).ListStreams(aevumclient.WithTenant(context.Background(), "acme")); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if authorization != "Bearer acme-token" {
+		t.Fatalf("expected the token file of the tenant, got %q", authorization)
 	}
 
-	rule, err := client.GetRule(context.Background(), "r1")
-	if err != nil || rule["id"] != "r1" {
-		t.Fatalf("unexpected rule response: err=%v value=%v", err, rule)
+	// A token file without the placeholder holds the default tenant's token, so other tenants mint theirs
+	t.Setenv("EVENT_TIMELINE_TOKEN_FILE", filepath.Join(dir, "acme.token"))
+	if _, err := NewEventTimelineClient(server.URL).ListStreams(aevumclient.WithTenant(context.Background(), "globex")); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if tenant := claims()["org"]; tenant != "globex" {
+		t.Fatalf("expected a minted token of globex, got %v", tenant)
 	}
 }
 
@@ -95,18 +121,44 @@ func TestClientHandlesNon200(t *testing.T) {
 	defer server.Close()
 
 	eventClient := NewEventTimelineClient(server.URL)
-	_, _, err := eventClient.GetEvents(context.Background(), "", 10)
+	_, err := eventClient.GetStreamEvents(context.Background(), "default", "", 10)
 	if err == nil {
 		t.Fatal("expected error on non-200 get events")
 	}
 
 	decisionClient := NewDecisionEngineClient(server.URL)
-	_, err = decisionClient.GetRules(context.Background())
+	_, err = decisionClient.ListRules(context.Background())
 	if err == nil {
 		t.Fatal("expected error on non-200 get rules")
 	}
 
-	if fmt.Sprint(err) == "" {
-		t.Fatal("expected non-empty error")
+	if !strings.Contains(err.Error(), "status 500") || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected status and message in the error, got %v", err)
+	}
+}
+
+func TestClientsWithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if strings.HasPrefix(r.URL.Path, "/api/v1/events/") {
+			_, _ = w.Write([]byte(`{"event":{"event_id":"e1"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"d1"}`))
+	}))
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	cfg := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+
+	if _, err := NewEventTimelineClient(server.URL).GetEvent(context.Background(), "e1"); err == nil {
+		t.Fatal("expected untrusted server certificate to fail without tls config")
+	}
+	if _, err := NewEventTimelineClient(server.URL).WithTLSConfig(cfg).GetEvent(context.Background(), "e1"); err != nil {
+		t.Fatalf("expected nil error with tls config, got %v", err)
+	}
+	if _, err := NewDecisionEngineClient(server.URL).WithTLSConfig(cfg).GetDecision(context.Background(), "d1"); err != nil {
+		t.Fatalf("expected nil error with tls config, got %v", err)
 	}
 }
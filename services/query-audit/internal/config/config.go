@@ -14,6 +14,13 @@ type Config struct {
 	EventTimeline  EventTimelineConfig
 	DecisionEngine DecisionEngineConfig
 	Sync           SyncConfig
+	Redaction      RedactionConfig
+	TLS            TLSConfig
+	ClientTLS      ClientTLSConfig
+	AccessLog      AccessLogConfig
+	Consistency    ConsistencyConfig
+	Breaker        BreakerConfig
+	Tracing        TracingConfig
 	Environment    string
 }
 
@@ -31,6 +38,8 @@ type ElasticsearchConfig struct {
 // EventTimelineConfig represents Event Timeline Service settings
 type EventTimelineConfig struct {
 	BaseURL string
+	// Tenants are the tenants whose streams are synced, each with a token of its own
+	Tenants []string
 }
 
 // DecisionEngineConfig represents Decision Engine settings
@@ -40,9 +49,63 @@ type DecisionEngineConfig struct {
 
 // SyncConfig represents sync settings
 type SyncConfig struct {
+	Interval          time.Duration
+	MaxBackoff        time.Duration
+	BatchSize         int
+	BatchBytes        int
+	FlushInterval     time.Duration
+	BulkMaxRetries    int
+	StreamConcurrency int
+}
+
+// RedactionConfig represents payload redaction settings
+type RedactionConfig struct {
+	PolicyFile string
+}
+
+// TLSConfig represents HTTP server TLS settings
+type TLSConfig struct {
+	CertFile          string
+	KeyFile           string
+	ClientCAFile      string
+	RequireClientCert bool
+}
+
+// ClientTLSConfig represents TLS settings for calls to other services
+type ClientTLSConfig struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// Enabled reports whether outgoing calls need a custom TLS config
+func (c ClientTLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.CAFile != ""
+}
+
+// AccessLogConfig represents API access log settings
+type AccessLogConfig struct {
+	Enabled       bool
+	BufferSize    int
+	FlushInterval time.Duration
+}
+
+// ConsistencyConfig represents the consistency check between Event Timeline and the events index
+type ConsistencyConfig struct {
 	Interval   time.Duration
-	MaxBackoff time.Duration
-	BatchSize  int
+	SampleSize int
+	AutoResync bool
+}
+
+// BreakerConfig represents the circuit breakers of calls to other services
+type BreakerConfig struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// TracingConfig represents span export settings
+type TracingConfig struct {
+	OTELEndpoint string
 }
 
 // Load loads configuration from environment
@@ -57,14 +120,50 @@ func Load() *Config {
 		},
 		EventTimeline: EventTimelineConfig{
 			BaseURL: getEnv("EVENT_TIMELINE_URL", "http://localhost:8081"),
+			Tenants: getEnvSlice("EVENT_TIMELINE_TENANTS", []string{"default"}),
 		},
 		DecisionEngine: DecisionEngineConfig{
 			BaseURL: getEnv("DECISION_ENGINE_URL", "http://localhost:8082"),
 		},
 		Sync: SyncConfig{
-			Interval:   time.Duration(getEnvInt("SYNC_INTERVAL", 30)) * time.Second,
-			MaxBackoff: time.Duration(getEnvInt("SYNC_MAX_BACKOFF", 300)) * time.Second,
-			BatchSize:  getEnvInt("SYNC_BATCH_SIZE", 500),
+			Interval:          time.Duration(getEnvInt("SYNC_INTERVAL", 30)) * time.Second,
+			MaxBackoff:        time.Duration(getEnvInt("SYNC_MAX_BACKOFF", 300)) * time.Second,
+			BatchSize:         getEnvInt("SYNC_BATCH_SIZE", 500),
+			BatchBytes:        getEnvInt("SYNC_BATCH_BYTES", 5<<20),
+			FlushInterval:     time.Duration(getEnvInt("SYNC_FLUSH_INTERVAL_MS", 5000)) * time.Millisecond,
+			BulkMaxRetries:    getEnvInt("SYNC_BULK_MAX_RETRIES", 3),
+			StreamConcurrency: getEnvInt("SYNC_STREAM_CONCURRENCY", 4),
+		},
+		Redaction: RedactionConfig{
+			PolicyFile: getEnv("REDACTION_POLICY_FILE", ""),
+		},
+		TLS: TLSConfig{
+			CertFile:          getEnv("TLS_CERT_FILE", ""),
+			KeyFile:           getEnv("TLS_KEY_FILE", ""),
+			ClientCAFile:      getEnv("TLS_CLIENT_CA_FILE", ""),
+			RequireClientCert: getEnv("TLS_CLIENT_AUTH", "request") == "require",
+		},
+		ClientTLS: ClientTLSConfig{
+			CertFile: getEnv("CLIENT_TLS_CERT_FILE", ""),
+			KeyFile:  getEnv("CLIENT_TLS_KEY_FILE", ""),
+			CAFile:   getEnv("CLIENT_TLS_CA_FILE", ""),
+		},
+		AccessLog: AccessLogConfig{
+			Enabled:       getEnv("ACCESS_LOG_ENABLED", "true") != "false",
+			BufferSize:    getEnvInt("ACCESS_LOG_BUFFER_SIZE", 1024),
+			FlushInterval: time.Duration(getEnvInt("ACCESS_LOG_FLUSH_INTERVAL_MS", 1000)) * time.Millisecond,
+		},
+		Consistency: ConsistencyConfig{
+			Interval:   time.Duration(getEnvInt("CONSISTENCY_CHECK_INTERVAL", 3600)) * time.Second,
+			SampleSize: getEnvInt("CONSISTENCY_SAMPLE_SIZE", 20),
+			AutoResync: getEnv("CONSISTENCY_AUTO_RESYNC", "false") == "true",
+		},
+		Breaker: BreakerConfig{
+			FailureThreshold: getEnvInt("CLIENT_BREAKER_FAILURE_THRESHOLD", 5),
+			OpenTimeout:      time.Duration(getEnvInt("CLIENT_BREAKER_OPEN_TIMEOUT", 30)) * time.Second,
+		},
+		Tracing: TracingConfig{
+			OTELEndpoint: getEnv("AEVUM_OTEL_ENDPOINT", ""),
 		},
 		Environment: getEnv("ENVIRONMENT", "development"),
 	}
@@ -15,7 +15,19 @@ func TestLoadDefaults(t *testing.T) {
 	_ = os.Unsetenv("SYNC_INTERVAL")
 	_ = os.Unsetenv("SYNC_MAX_BACKOFF")
 	_ = os.Unsetenv("SYNC_BATCH_SIZE")
+	_ = os.Unsetenv("SYNC_BATCH_BYTES")
+	_ = os.Unsetenv("SYNC_FLUSH_INTERVAL_MS")
+	_ = os.Unsetenv("SYNC_BULK_MAX_RETRIES")
+	_ = os.Unsetenv("SYNC_STREAM_CONCURRENCY")
 	_ = os.Unsetenv("ENVIRONMENT")
+	_ = os.Unsetenv("REDACTION_POLICY_FILE")
+	_ = os.Unsetenv("TLS_CERT_FILE")
+	_ = os.Unsetenv("TLS_CLIENT_AUTH")
+	_ = os.Unsetenv("CLIENT_TLS_CERT_FILE")
+	_ = os.Unsetenv("CLIENT_TLS_CA_FILE")
+	_ = os.Unsetenv("ACCESS_LOG_ENABLED")
+	_ = os.Unsetenv("ACCESS_LOG_BUFFER_SIZE")
+	_ = os.Unsetenv("ACCESS_LOG_FLUSH_INTERVAL_MS")
 
 	cfg := Load()
 	if cfg.Server.Port != 8080 {
@@ -36,9 +48,30 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.Sync.BatchSize != 500 {
 		t.Fatalf("expected default batch size 500, got %d", cfg.Sync.BatchSize)
 	}
+	if cfg.Sync.BatchBytes != 5<<20 || cfg.Sync.FlushInterval != 5*time.Second || cfg.Sync.BulkMaxRetries != 3 {
+		t.Fatalf("unexpected default bulk config: %+v", cfg.Sync)
+	}
+	if cfg.Sync.StreamConcurrency != 4 {
+		t.Fatalf("expected default stream concurrency 4, got %d", cfg.Sync.StreamConcurrency)
+	}
 	if cfg.Environment != "development" {
 		t.Fatalf("expected default environment development, got %s", cfg.Environment)
 	}
+	if cfg.Redaction.PolicyFile != "" {
+		t.Fatalf("expected no default redaction policy, got %s", cfg.Redaction.PolicyFile)
+	}
+	if cfg.TLS.CertFile != "" || cfg.TLS.RequireClientCert || cfg.ClientTLS.Enabled() {
+		t.Fatalf("expected tls to be disabled by default, got %+v %+v", cfg.TLS, cfg.ClientTLS)
+	}
+	if !cfg.AccessLog.Enabled || cfg.AccessLog.BufferSize != 1024 || cfg.AccessLog.FlushInterval != time.Second {
+		t.Fatalf("unexpected default access log config: %+v", cfg.AccessLog)
+	}
+	if cfg.Consistency.Interval != time.Hour || cfg.Consistency.SampleSize != 20 || cfg.Consistency.AutoResync {
+		t.Fatalf("unexpected default consistency config: %+v", cfg.Consistency)
+	}
+	if cfg.Breaker.FailureThreshold != 5 || cfg.Breaker.OpenTimeout != 30*time.Second || cfg.Tracing.OTELEndpoint != "" {
+		t.Fatalf("unexpected default breaker or tracing config: %+v %+v", cfg.Breaker, cfg.Tracing)
+	}
 }
 
 func TestLoadFromEnvironment(t *testing.T) {
@@ -50,7 +83,24 @@ func TestLoadFromEnvironment(t *testing.T) {
 	t.Setenv("SYNC_INTERVAL", "5")
 	t.Setenv("SYNC_MAX_BACKOFF", "60")
 	t.Setenv("SYNC_BATCH_SIZE", "100")
+	t.Setenv("SYNC_BATCH_BYTES", "1048576")
+	t.Setenv("SYNC_FLUSH_INTERVAL_MS", "500")
+	t.Setenv("SYNC_BULK_MAX_RETRIES", "5")
+	t.Setenv("SYNC_STREAM_CONCURRENCY", "8")
 	t.Setenv("ENVIRONMENT", "sit")
+	t.Setenv("REDACTION_POLICY_FILE", "/etc/aevum/redaction.json")
+	t.Setenv("TLS_CERT_FILE", "/etc/aevum/tls.crt")
+	t.Setenv("TLS_CLIENT_AUTH", "require")
+	t.Setenv("CLIENT_TLS_CA_FILE", "/etc/aevum/ca.crt")
+	t.Setenv("ACCESS_LOG_ENABLED", "false")
+	t.Setenv("ACCESS_LOG_BUFFER_SIZE", "64")
+	t.Setenv("ACCESS_LOG_FLUSH_INTERVAL_MS", "250")
+	t.Setenv("CONSISTENCY_CHECK_INTERVAL", "600")
+	t.Setenv("CONSISTENCY_SAMPLE_SIZE", "0")
+	t.Setenv("CONSISTENCY_AUTO_RESYNC", "true")
+	t.Setenv("CLIENT_BREAKER_FAILURE_THRESHOLD", "0")
+	t.Setenv("CLIENT_BREAKER_OPEN_TIMEOUT", "5")
+	t.Setenv("AEVUM_OTEL_ENDPOINT", "otel-collector:4317")
 
 	cfg := Load()
 	if cfg.Server.Port != 9099 {
@@ -71,9 +121,33 @@ func TestLoadFromEnvironment(t *testing.T) {
 	if cfg.Sync.BatchSize != 100 {
 		t.Fatalf("expected batch size 100, got %d", cfg.Sync.BatchSize)
 	}
+	if cfg.Sync.BatchBytes != 1048576 || cfg.Sync.FlushInterval != 500*time.Millisecond || cfg.Sync.BulkMaxRetries != 5 {
+		t.Fatalf("unexpected bulk config: %+v", cfg.Sync)
+	}
+	if cfg.Sync.StreamConcurrency != 8 {
+		t.Fatalf("expected stream concurrency 8, got %d", cfg.Sync.StreamConcurrency)
+	}
 	if cfg.Environment != "sit" {
 		t.Fatalf("expected environment sit, got %s", cfg.Environment)
 	}
+	if cfg.Redaction.PolicyFile != "/etc/aevum/redaction.json" {
+		t.Fatalf("expected redaction policy file, got %s", cfg.Redaction.PolicyFile)
+	}
+	if cfg.TLS.CertFile != "/etc/aevum/tls.crt" || !cfg.TLS.RequireClientCert {
+		t.Fatalf("unexpected tls config: %+v", cfg.TLS)
+	}
+	if !cfg.ClientTLS.Enabled() || cfg.ClientTLS.CAFile != "/etc/aevum/ca.crt" {
+		t.Fatalf("unexpected client tls config: %+v", cfg.ClientTLS)
+	}
+	if cfg.AccessLog.Enabled || cfg.AccessLog.BufferSize != 64 || cfg.AccessLog.FlushInterval != 250*time.Millisecond {
+		t.Fatalf("unexpected access log config: %+v", cfg.AccessLog)
+	}
+	if cfg.Consistency.Interval != 10*time.Minute || cfg.Consistency.SampleSize != 0 || !cfg.Consistency.AutoResync {
+		t.Fatalf("unexpected consistency config: %+v", cfg.Consistency)
+	}
+	if cfg.Breaker.FailureThreshold != 0 || cfg.Breaker.OpenTimeout != 5*time.Second || cfg.Tracing.OTELEndpoint != "otel-collector:4317" {
+		t.Fatalf("unexpected breaker or tracing config: %+v %+v", cfg.Breaker, cfg.Tracing)
+	}
 }
 
 func TestLoadFallsBackOnInvalidNumbers(t *testing.T) {
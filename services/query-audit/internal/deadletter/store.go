@@ -0,0 +1,266 @@
+package deadletter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/observability"
+)
+
+const (
+	// IndexName is the Elasticsearch index holding dead letters
+	IndexName = "aevum-dlq"
+
+	// StageConvert marks a source document that could not be converted into an index document
+	StageConvert = "convert"
+	// StageIndex marks a document Elasticsearch rejected
+	StageIndex = "index"
+
+	defaultQuerySize = 100
+	// MaxQuerySize bounds the dead letters read by one query
+	MaxQuerySize = 1000
+)
+
+// Letter is a source document that could not be indexed
+type Letter struct {
+	ID            string          `json:"id"`
+	Index         string          `json:"index"`
+	DocumentID    string          `json:"document_id,omitempty"`
+	Document      json.RawMessage `json:"document"`
+	Stage         string          `json:"stage"`
+	Status        int             `json:"status,omitempty"`
+	Reason        string          `json:"reason"`
+	Attempts      int             `json:"attempts"`
+	FirstFailedAt time.Time       `json:"first_failed_at"`
+	LastFailedAt  time.Time       `json:"last_failed_at"`
+}
+
+// LetterID returns the ID of the dead letter of a document, so a document that fails again updates
+// its letter instead of adding one; documents without an ID are identified by their content
+func LetterID(index, documentID string, document []byte) string {
+	if documentID != "" {
+		return index + "/" + documentID
+	}
+	sum := sha256.Sum256(document)
+	return index + "/sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Store writes dead letters to and reads them from Elasticsearch
+type Store struct {
+	client  *elasticsearch.Client
+	metrics *observability.Metrics
+	now     func() time.Time
+}
+
+// NewStore creates a new dead-letter store
+func NewStore(client *elasticsearch.Client) *Store {
+	return &Store{client: client, now: time.Now}
+}
+
+// WithMetrics keeps the dead-letter gauge up to date after each write
+func (s *Store) WithMetrics(metrics *observability.Metrics) *Store {
+	s.metrics = metrics
+	return s
+}
+
+// Record adds letters, or adds their attempts to the letters already recorded for the same documents
+func (s *Store) Record(ctx context.Context, letters []Letter) error {
+	if len(letters) == 0 {
+		return nil
+	}
+	now := s.now().UTC()
+	var body bytes.Buffer
+	for _, letter := range letters {
+		if letter.ID == "" {
+			letter.ID = LetterID(letter.Index, letter.DocumentID, letter.Document)
+		}
+		if letter.Attempts < 1 {
+			letter.Attempts = 1
+		}
+		letter.FirstFailedAt, letter.LastFailedAt = now, now
+
+		action, err := json.Marshal(map[string]interface{}{"update": map[string]interface{}{"_index": IndexName, "_id": letter.ID}})
+		if err != nil {
+			return fmt.Errorf("failed to marshal dead letter action: %w", err)
+		}
+		update, err := json.Marshal(map[string]interface{}{
+			"script": map[string]interface{}{
+				"source": "ctx._source.attempts += params.attempts; ctx._source.last_failed_at = params.last_failed_at; " +
+					"ctx._source.document = params.document; ctx._source.stage = params.stage; " +
+					"ctx._source.status = params.status; ctx._source.reason = params.reason",
+				"params": map[string]interface{}{
+					"attempts":       letter.Attempts,
+					"last_failed_at": letter.LastFailedAt,
+					"document":       letter.Document,
+					"stage":          letter.Stage,
+					"status":         letter.Status,
+					"reason":         letter.Reason,
+				},
+			},
+			"upsert": letter,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal dead letter %s: %w", letter.ID, err)
+		}
+		body.Write(action)
+		body.WriteString("\n")
+		body.Write(update)
+		body.WriteString("\n")
+	}
+	if err := s.bulk(ctx, &body); err != nil {
+		return err
+	}
+	s.refreshGauge(ctx)
+	return nil
+}
+
+// Delete removes the letters with ids
+func (s *Store) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	for _, id := range ids {
+		action, err := json.Marshal(map[string]interface{}{"delete": map[string]interface{}{"_index": IndexName, "_id": id}})
+		if err != nil {
+			return fmt.Errorf("failed to marshal dead letter action: %w", err)
+		}
+		body.Write(action)
+		body.WriteString("\n")
+	}
+	if err := s.bulk(ctx, &body); err != nil {
+		return err
+	}
+	s.refreshGauge(ctx)
+	return nil
+}
+
+// Filter selects dead letters; empty fields match everything
+type Filter struct {
+	IDs   []string
+	Index string
+	Size  int
+}
+
+// Query returns matching letters, most recently failed first, and the number of matching letters
+func (s *Store) Query(ctx context.Context, f Filter) ([]Letter, int64, error) {
+	size := f.Size
+	if size <= 0 {
+		size = defaultQuerySize
+	}
+	if size > MaxQuerySize {
+		size = MaxQuerySize
+	}
+	filters := []map[string]interface{}{}
+	if len(f.IDs) > 0 {
+		filters = append(filters, map[string]interface{}{"ids": map[string]interface{}{"values": f.IDs}})
+	}
+	if f.Index != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"index": f.Index}})
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"size":             size,
+		"track_total_hits": true,
+		"sort":             []map[string]interface{}{{"last_failed_at": map[string]interface{}{"order": "desc"}}},
+		"query":            map[string]interface{}{"bool": map[string]interface{}{"filter": filters}},
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal dead letter query: %w", err)
+	}
+
+	res, err := s.client.Search(s.client.Search.WithContext(ctx), s.client.Search.WithIndex(IndexName), s.client.Search.WithBody(bytes.NewReader(body)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("dead letter search failed: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, 0, fmt.Errorf("dead letter search failed: status %d", res.StatusCode)
+	}
+
+	var result struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source Letter `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode dead letter search response: %w", err)
+	}
+	letters := make([]Letter, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		letters = append(letters, hit.Source)
+	}
+	return letters, result.Hits.Total.Value, nil
+}
+
+// Count returns the number of dead letters
+func (s *Store) Count(ctx context.Context) (int64, error) {
+	res, err := s.client.Count(s.client.Count.WithContext(ctx), s.client.Count.WithIndex(IndexName))
+	if err != nil {
+		return 0, fmt.Errorf("dead letter count failed: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return 0, fmt.Errorf("dead letter count failed: status %d", res.StatusCode)
+	}
+	var result struct {
+		Count int64 `json:"count"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode dead letter count: %w", err)
+	}
+	if s.metrics != nil {
+		s.metrics.DeadLetters.Set(float64(result.Count))
+	}
+	return result.Count, nil
+}
+
+// refreshGauge updates the dead-letter gauge; a failed count leaves the gauge for the next write
+func (s *Store) refreshGauge(ctx context.Context) {
+	if s.metrics != nil {
+		_, _ = s.Count(ctx)
+	}
+}
+
+// bulk sends a bulk request whose changes are visible to searches once it returns
+func (s *Store) bulk(ctx context.Context, body *bytes.Buffer) error {
+	res, err := esapi.BulkRequest{Body: body, Refresh: "wait_for"}.Do(ctx, s.client)
+	if err != nil {
+		return fmt.Errorf("dead letter bulk request failed: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		respBody, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("dead letter bulk request failed: %s", string(respBody))
+	}
+
+	var result struct {
+		Errors bool                              `json:"errors"`
+		Items  []map[string]struct{ Status int } `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode dead letter bulk response: %w", err)
+	}
+	for _, item := range result.Items {
+		for _, outcome := range item {
+			// Deleting a letter that is already gone is not an error
+			if outcome.Status >= 300 && outcome.Status != http.StatusNotFound {
+				return fmt.Errorf("dead letter bulk request had item errors")
+			}
+		}
+	}
+	return nil
+}
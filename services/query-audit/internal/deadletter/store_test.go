@@ -0,0 +1,165 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/observability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeDLQ records the requests of the store and answers them with canned responses
+type fakeDLQ struct {
+	bulks       []string
+	searches    []string
+	bulkStatus  int
+	count       int64
+	searchReply string
+}
+
+func (f *fakeDLQ) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	body, _ := io.ReadAll(r.Body)
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/_bulk"):
+		f.bulks = append(f.bulks, string(body))
+		items := []map[string]interface{}{}
+		for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+			var action map[string]json.RawMessage
+			_ = json.Unmarshal([]byte(line), &action)
+			for op := range action {
+				if op == "update" || op == "delete" {
+					items = append(items, map[string]interface{}{op: map[string]interface{}{"status": f.bulkStatus}})
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": f.bulkStatus >= 300, "items": items})
+	case strings.HasSuffix(r.URL.Path, "/_search"):
+		f.searches = append(f.searches, string(body))
+		_, _ = w.Write([]byte(f.searchReply))
+	case strings.HasSuffix(r.URL.Path, "/_count"):
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"count": f.count})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeStore(t *testing.T, fake *fakeDLQ) *Store {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	store := NewStore(es)
+	store.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return store
+}
+
+func TestLetterID(t *testing.T) {
+	if id := LetterID("aevum-events-write", "evt-1", []byte(`{}`)); id != "aevum-events-write/evt-1" {
+		t.Fatalf("unexpected letter id %s", id)
+	}
+	a := LetterID("aevum-events-write", "", []byte(`{"a":1}`))
+	b := LetterID("aevum-events-write", "", []byte(`{"a":2}`))
+	if !strings.HasPrefix(a, "aevum-events-write/sha256:") || a == b {
+		t.Fatalf("expected content ids, got %s and %s", a, b)
+	}
+}
+
+func TestStoreRecordUpsertsLettersAndUpdatesGauge(t *testing.T) {
+	fake := &fakeDLQ{bulkStatus: http.StatusCreated, count: 3}
+	metrics := observability.NewMetrics()
+	store := newFakeStore(t, fake).WithMetrics(metrics)
+
+	err := store.Record(context.Background(), []Letter{{
+		Index:      "aevum-decisions-write",
+		DocumentID: "dec-1",
+		Document:   json.RawMessage(`{"id":"dec-1"}`),
+		Stage:      StageIndex,
+		Status:     http.StatusBadRequest,
+		Reason:     "mapper_parsing_exception: bad field",
+	}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(fake.bulks) != 1 {
+		t.Fatalf("expected one bulk request, got %d", len(fake.bulks))
+	}
+	lines := strings.Split(strings.TrimSpace(fake.bulks[0]), "\n")
+	if lines[0] != `{"update":{"_id":"aevum-decisions-write/dec-1","_index":"aevum-dlq"}}` {
+		t.Fatalf("unexpected bulk action %s", lines[0])
+	}
+	var update struct {
+		Script struct {
+			Params map[string]interface{} `json:"params"`
+		} `json:"script"`
+		Upsert Letter `json:"upsert"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &update); err != nil {
+		t.Fatalf("expected valid update, got %v", err)
+	}
+	if update.Script.Params["attempts"] != float64(1) || update.Upsert.Attempts != 1 || update.Upsert.ID != "aevum-decisions-write/dec-1" {
+		t.Fatalf("unexpected update %s", lines[1])
+	}
+	if !update.Upsert.FirstFailedAt.Equal(store.now()) || string(update.Upsert.Document) != `{"id":"dec-1"}` {
+		t.Fatalf("unexpected upserted letter %+v", update.Upsert)
+	}
+	if got := testutil.ToFloat64(metrics.DeadLetters); got != 3 {
+		t.Fatalf("expected the gauge to follow the count, got %v", got)
+	}
+
+	fake.bulkStatus = http.StatusBadRequest
+	if err := store.Record(context.Background(), []Letter{{Index: "aevum-events-write", Document: json.RawMessage(`{}`)}}); err == nil {
+		t.Fatal("expected an error when a letter is not written")
+	}
+}
+
+func TestStoreDeleteIgnoresMissingLetters(t *testing.T) {
+	fake := &fakeDLQ{bulkStatus: http.StatusNotFound}
+	store := newFakeStore(t, fake)
+	if err := store.Delete(context.Background(), []string{"aevum-events-write/evt-1"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(fake.bulks[0], `{"delete":{"_id":"aevum-events-write/evt-1","_index":"aevum-dlq"}}`) {
+		t.Fatalf("unexpected bulk request %s", fake.bulks[0])
+	}
+	if err := store.Delete(context.Background(), nil); err != nil || len(fake.bulks) != 1 {
+		t.Fatalf("expected no request without ids, got %v", err)
+	}
+}
+
+func TestStoreQueryFiltersAndClampsSize(t *testing.T) {
+	fake := &fakeDLQ{searchReply: `{"hits":{"total":{"value":7},"hits":[{"_source":{"id":"aevum-events-write/evt-1","index":"aevum-events-write","stage":"index","attempts":2}}]}}`}
+	store := newFakeStore(t, fake)
+
+	letters, total, err := store.Query(context.Background(), Filter{IDs: []string{"aevum-events-write/evt-1"}, Index: "aevum-events-write", Size: 5000})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if total != 7 || len(letters) != 1 || letters[0].Attempts != 2 {
+		t.Fatalf("unexpected result %d %+v", total, letters)
+	}
+	var query struct {
+		Size  int `json:"size"`
+		Query struct {
+			Bool struct {
+				Filter []map[string]interface{} `json:"filter"`
+			} `json:"bool"`
+		} `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(fake.searches[0]), &query); err != nil {
+		t.Fatalf("expected valid query, got %v", err)
+	}
+	if query.Size != MaxQuerySize || len(query.Query.Bool.Filter) != 2 {
+		t.Fatalf("unexpected query %s", fake.searches[0])
+	}
+}
@@ -5,6 +5,7 @@ import "time"
 // IndexedEvent represents an event in Elasticsearch
 type IndexedEvent struct {
 	EventID       string                 `json:"event_id"`
+	TenantID      string                 `json:"tenant_id,omitempty"`
 	StreamID      string                 `json:"stream_id"`
 	SequenceNum   int64                  `json:"sequence_number"`
 	EventType     string                 `json:"event_type"`
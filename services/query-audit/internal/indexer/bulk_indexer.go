@@ -5,84 +5,405 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	gosync "sync"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/deadletter"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/observability"
 )
 
-// BulkIndexer manages bulk document indexing
+const (
+	defaultBatchBytes    = 5 << 20
+	defaultFlushInterval = 5 * time.Second
+	defaultMaxRetries    = 3
+	defaultRetryBackoff  = 200 * time.Millisecond
+	// maxRejectionBody caps the response body a dead letter keeps for a rejected bulk request
+	maxRejectionBody = 4 << 10
+)
+
+// DeadLetterSink keeps documents that cannot be indexed, so they can be inspected and indexed again
+type DeadLetterSink interface {
+	Record(ctx context.Context, letters []deadletter.Letter) error
+}
+
+// BulkIndexer batches documents into bulk requests. Documents Elasticsearch fails with 429 or 5xx are
+// retried with backoff, documents it rejects are dead-lettered. A request rejected as too large is sent
+// again in halves, and the documents of a request rejected otherwise are all dead-lettered. It is safe
+// for concurrent use
 type BulkIndexer struct {
-	client    *elasticsearch.Client
-	batchSize int
-	buffer    []interface{}
-	logger    *slog.Logger
+	client        *elasticsearch.Client
+	batchSize     int
+	batchBytes    int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+	deadLetters   DeadLetterSink
+	metrics       *observability.Metrics
+	logger        *slog.Logger
+
+	// flushMu keeps flushes in order, so an older version of a document never overwrites a newer one
+	flushMu gosync.Mutex
+	mu      gosync.Mutex
+	buffer  []bulkItem
+	bytes   int
+	// err is the failure of a background flush, reported by the next Flush
+	err error
+}
+
+// bulkItem is a buffered document with its encoded bulk lines and the source document it was converted from
+type bulkItem struct {
+	index  string
+	id     string
+	action []byte
+	doc    []byte
+	source []byte
+}
+
+// bulkResponse is the part of a bulk response that reports each document
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
 }
 
-// NewBulkIndexer creates a new bulk indexer
+// NewBulkIndexer creates a new bulk indexer that flushes every batchSize documents
 func NewBulkIndexer(client *elasticsearch.Client, batchSize int, logger *slog.Logger) *BulkIndexer {
 	return &BulkIndexer{
-		client:    client,
-		batchSize: batchSize,
-		buffer:    []interface{}{},
-		logger:    logger,
+		client:        client,
+		batchSize:     batchSize,
+		batchBytes:    defaultBatchBytes,
+		flushInterval: defaultFlushInterval,
+		maxRetries:    defaultMaxRetries,
+		retryBackoff:  defaultRetryBackoff,
+		logger:        logger,
 	}
 }
 
-// IndexDocument adds a document to the bulk buffer
-func (bi *BulkIndexer) IndexDocument(ctx context.Context, indexName, docID string, doc interface{}) error {
-	if err := bi.addToBatch(indexName, docID, doc); err != nil {
-		return err
-	}
+// WithLimits also flushes once the buffered documents reach batchBytes, and every flushInterval while Run runs
+func (bi *BulkIndexer) WithLimits(batchBytes int, flushInterval time.Duration) *BulkIndexer {
+	bi.batchBytes = batchBytes
+	bi.flushInterval = flushInterval
+	return bi
+}
 
-	if len(bi.buffer) >= bi.batchSize*2 {
-		return bi.Flush(ctx)
-	}
-	return nil
+// WithRetries retries documents failed with 429 or 5xx up to maxRetries times, doubling backoff each time
+func (bi *BulkIndexer) WithRetries(maxRetries int, backoff time.Duration) *BulkIndexer {
+	bi.maxRetries = maxRetries
+	bi.retryBackoff = backoff
+	return bi
+}
+
+// WithDeadLetters records documents Elasticsearch rejects in sink instead of only logging them
+func (bi *BulkIndexer) WithDeadLetters(sink DeadLetterSink) *BulkIndexer {
+	bi.deadLetters = sink
+	return bi
+}
+
+// WithMetrics counts indexed, retried and failed documents
+func (bi *BulkIndexer) WithMetrics(metrics *observability.Metrics) *BulkIndexer {
+	bi.metrics = metrics
+	return bi
+}
+
+// fork returns an indexer with the same settings and its own buffer
+func (bi *BulkIndexer) fork() *BulkIndexer {
+	return NewBulkIndexer(bi.client, bi.batchSize, bi.logger).
+		WithLimits(bi.batchBytes, bi.flushInterval).
+		WithRetries(bi.maxRetries, bi.retryBackoff).
+		WithDeadLetters(bi.deadLetters).
+		WithMetrics(bi.metrics)
+}
+
+// IndexDocument adds a document to the bulk buffer and flushes when the buffer is full
+func (bi *BulkIndexer) IndexDocument(ctx context.Context, indexName, docID string, doc interface{}) error {
+	return bi.IndexSourceDocument(ctx, indexName, docID, doc, nil)
 }
 
-// addToBatch adds a document to the batch
-func (bi *BulkIndexer) addToBatch(indexName, docID string, doc interface{}) error {
-	metadata := map[string]interface{}{
+// IndexSourceDocument adds a document converted from source to the bulk buffer; source is what a
+// dead letter keeps if Elasticsearch rejects the document
+func (bi *BulkIndexer) IndexSourceDocument(ctx context.Context, indexName, docID string, doc, source interface{}) error {
+	action, err := json.Marshal(map[string]interface{}{
 		"index": map[string]interface{}{
 			"_index": indexName,
 			"_id":    docID,
 		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal item: %w", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document %s: %w", docID, err)
+	}
+	sourceData := data
+	if source != nil {
+		if sourceData, err = json.Marshal(source); err != nil {
+			return fmt.Errorf("failed to marshal source of document %s: %w", docID, err)
+		}
+	}
+
+	bi.mu.Lock()
+	bi.buffer = append(bi.buffer, bulkItem{index: indexName, id: docID, action: action, doc: data, source: sourceData})
+	bi.bytes += len(action) + len(data) + 2
+	full := len(bi.buffer) >= bi.batchSize || bi.bytes >= bi.batchBytes
+	bi.mu.Unlock()
+
+	if full {
+		return bi.Flush(ctx)
 	}
-	bi.buffer = append(bi.buffer, metadata, doc)
 	return nil
 }
 
-// Flush sends all buffered documents to ES
+// Flush sends all buffered documents to ES. It fails when documents are still failing after the
+// retries, or when a background flush failed since the last Flush
 func (bi *BulkIndexer) Flush(ctx context.Context) error {
-	if len(bi.buffer) == 0 {
-		return nil
+	bi.flushMu.Lock()
+	defer bi.flushMu.Unlock()
+
+	bi.mu.Lock()
+	items := bi.buffer
+	bi.buffer = nil
+	bi.bytes = 0
+	pending := bi.err
+	bi.err = nil
+	bi.mu.Unlock()
+
+	if len(items) == 0 {
+		return pending
+	}
+	if err := bi.send(ctx, items); err != nil {
+		return err
 	}
+	return pending
+}
 
-	var body bytes.Buffer
-	for _, item := range bi.buffer {
-		data, err := json.Marshal(item)
-		if err != nil {
-			return fmt.Errorf("failed to marshal item: %w", err)
+// Run flushes the buffer every flush interval until ctx is done, then flushes it a last time
+func (bi *BulkIndexer) Run(ctx context.Context) {
+	ticker := time.NewTicker(bi.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := bi.Flush(flushCtx); err != nil {
+				bi.logger.Error("final bulk flush failed", slog.Any("error", err))
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := bi.Flush(ctx); err != nil {
+				bi.logger.Error("background bulk flush failed", slog.Any("error", err))
+				bi.mu.Lock()
+				bi.err = err
+				bi.mu.Unlock()
+			}
 		}
-		body.Write(data)
-		body.WriteString("\n")
 	}
+}
+
+// send indexes items, retrying the ones that fail with 429 or 5xx and dead-lettering the rejected ones
+func (bi *BulkIndexer) send(ctx context.Context, items []bulkItem) error {
+	var rejected []deadletter.Letter
+	for attempt := 1; ; attempt++ {
+		retry, failed, err := bi.do(ctx, items)
+		if err != nil && len(retry) == 0 {
+			return err
+		}
+		for i := range failed {
+			failed[i].Attempts = attempt
+		}
+		rejected = append(rejected, failed...)
+		bi.count(items, retry, failed)
 
-	req := esapi.BulkRequest{
-		Body: &body,
+		if len(retry) == 0 {
+			break
+		}
+		if attempt > bi.maxRetries || ctx.Err() != nil {
+			for _, item := range retry {
+				bi.observe(func(m *observability.Metrics) {
+					m.FailedDocumentsTotal.WithLabelValues(item.index, "retries_exhausted").Inc()
+				})
+			}
+			if dlErr := bi.recordDeadLetters(ctx, rejected); dlErr != nil {
+				return dlErr
+			}
+			return fmt.Errorf("%d documents not indexed after %d attempts: %w", len(retry), attempt, err)
+		}
+
+		bi.logger.Warn("retrying bulk documents", slog.Int("documents", len(retry)), slog.Int("attempt", attempt), slog.Any("error", err))
+		for _, item := range retry {
+			bi.observe(func(m *observability.Metrics) { m.BulkRetriesTotal.WithLabelValues(item.index).Inc() })
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(bi.retryBackoff << (attempt - 1)):
+		}
+		items = retry
+	}
+	return bi.recordDeadLetters(ctx, rejected)
+}
+
+// do sends one bulk request and returns the items to retry with the reason and the rejected items
+func (bi *BulkIndexer) do(ctx context.Context, items []bulkItem) ([]bulkItem, []deadletter.Letter, error) {
+	var body bytes.Buffer
+	for _, item := range items {
+		body.Write(item.action)
+		body.WriteString("\n")
+		body.Write(item.doc)
+		body.WriteString("\n")
 	}
-	res, err := req.Do(ctx, bi.client)
+
+	start := time.Now()
+	res, err := esapi.BulkRequest{Body: &body}.Do(ctx, bi.client)
+	bi.observe(func(m *observability.Metrics) { m.BulkRequestDuration.Observe(time.Since(start).Seconds()) })
 	if err != nil {
-		return fmt.Errorf("bulk request failed: %w", err)
+		return items, nil, fmt.Errorf("bulk request failed: %w", err)
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return fmt.Errorf("bulk indexing failed: status %d", res.StatusCode)
+		if retriable(res.StatusCode) {
+			return items, nil, fmt.Errorf("bulk indexing failed: status %d", res.StatusCode)
+		}
+		if res.StatusCode == http.StatusRequestEntityTooLarge && len(items) > 1 {
+			return bi.split(ctx, items)
+		}
+		body, _ := io.ReadAll(io.LimitReader(res.Body, maxRejectionBody))
+		bi.logger.Warn("bulk request rejected", slog.Int("documents", len(items)), slog.Int("status", res.StatusCode))
+		return nil, rejectAll(items, res.StatusCode, body), nil
 	}
 
-	bi.buffer = []interface{}{}
-	bi.logger.Info("bulk indexing completed", slog.Int("documents", len(bi.buffer)/2))
+	var result bulkResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return items, nil, fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil, nil, nil
+	}
+	if len(result.Items) != len(items) {
+		return items, nil, fmt.Errorf("bulk response has %d items for %d documents", len(result.Items), len(items))
+	}
+
+	var retry []bulkItem
+	var rejected []deadletter.Letter
+	var reason error
+	for i, entry := range result.Items {
+		for _, outcome := range entry {
+			switch {
+			case outcome.Status < 300:
+			case retriable(outcome.Status):
+				retry = append(retry, items[i])
+				reason = fmt.Errorf("document %s failed with status %d: %s", items[i].id, outcome.Status, outcome.Error.Reason)
+			default:
+				rejected = append(rejected, deadletter.Letter{
+					Index:      items[i].index,
+					DocumentID: items[i].id,
+					Document:   items[i].source,
+					Stage:      deadletter.StageIndex,
+					Status:     outcome.Status,
+					Reason:     outcome.Error.Type + ": " + outcome.Error.Reason,
+				})
+			}
+		}
+	}
+	return retry, rejected, reason
+}
+
+// split sends the two halves of a batch Elasticsearch rejected as too large as separate requests,
+// halving again until the requests fit
+func (bi *BulkIndexer) split(ctx context.Context, items []bulkItem) ([]bulkItem, []deadletter.Letter, error) {
+	half := len(items) / 2
+	retry, rejected, err := bi.do(ctx, items[:half])
+	moreRetry, moreRejected, moreErr := bi.do(ctx, items[half:])
+	if err == nil {
+		err = moreErr
+	}
+	return append(retry, moreRetry...), append(rejected, moreRejected...), err
+}
+
+// rejectAll dead-letters every item of a bulk request Elasticsearch rejected as a whole
+func rejectAll(items []bulkItem, status int, body []byte) []deadletter.Letter {
+	reason := fmt.Sprintf("bulk request rejected with status %d: %s", status, bytes.TrimSpace(body))
+	letters := make([]deadletter.Letter, 0, len(items))
+	for _, item := range items {
+		letters = append(letters, deadletter.Letter{
+			Index:      item.index,
+			DocumentID: item.id,
+			Document:   item.source,
+			Stage:      deadletter.StageIndex,
+			Status:     status,
+			Reason:     reason,
+		})
+	}
+	return letters
+}
+
+// count records the outcome of one bulk request in the metrics
+func (bi *BulkIndexer) count(items, retry []bulkItem, rejected []deadletter.Letter) {
+	bi.observe(func(m *observability.Metrics) {
+		indexed := map[string]int{}
+		for _, item := range items {
+			indexed[item.index]++
+		}
+		for _, item := range retry {
+			indexed[item.index]--
+		}
+		for _, letter := range rejected {
+			indexed[letter.Index]--
+			m.FailedDocumentsTotal.WithLabelValues(letter.Index, "rejected").Inc()
+		}
+		for index, n := range indexed {
+			m.IndexedDocumentsTotal.WithLabelValues(index).Add(float64(n))
+		}
+	})
+}
+
+// RejectSource dead-letters a source document that cannot be converted into a document of indexName
+func (bi *BulkIndexer) RejectSource(ctx context.Context, indexName string, source interface{}, reason string) error {
+	data, err := json.Marshal(source)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rejected source document: %w", err)
+	}
+	bi.observe(func(m *observability.Metrics) {
+		m.FailedDocumentsTotal.WithLabelValues(indexName, "unconvertible").Inc()
+	})
+	return bi.recordDeadLetters(ctx, []deadletter.Letter{{Index: indexName, Document: data, Stage: deadletter.StageConvert, Reason: reason}})
+}
+
+// recordDeadLetters hands rejected documents to the dead-letter sink, or logs them without one
+func (bi *BulkIndexer) recordDeadLetters(ctx context.Context, letters []deadletter.Letter) error {
+	if len(letters) == 0 {
+		return nil
+	}
+	if bi.deadLetters == nil {
+		for _, letter := range letters {
+			bi.logger.Error("document not indexed", slog.String("index", letter.Index), slog.String("id", letter.DocumentID), slog.String("stage", letter.Stage), slog.Int("status", letter.Status), slog.String("reason", letter.Reason))
+		}
+		return nil
+	}
+	if err := bi.deadLetters.Record(ctx, letters); err != nil {
+		return fmt.Errorf("failed to record %d dead letters: %w", len(letters), err)
+	}
 	return nil
 }
+
+func (bi *BulkIndexer) observe(fn func(m *observability.Metrics)) {
+	if bi.metrics != nil {
+		fn(bi.metrics)
+	}
+}
+
+// retriable reports whether a bulk status is worth retrying
+func retriable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
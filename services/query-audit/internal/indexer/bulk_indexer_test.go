@@ -0,0 +1,295 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/deadletter"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/observability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// statusBulk answers each document of a bulk request with the next status scripted for its ID, 201 when none is left.
+// It rejects a whole request with requestStatus when set, and with 413 when the request holds more than maxDocs
+// documents or holds one of the tooLarge documents next to others
+type statusBulk struct {
+	mu            gosync.Mutex
+	statuses      map[string][]int
+	requestStatus int
+	maxDocs       int
+	tooLarge      map[string]bool
+	requests      [][]string
+}
+
+func (f *statusBulk) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	ids := []string{}
+	items := []map[string]interface{}{}
+	for i := 0; i < len(lines); i += 2 {
+		var action struct {
+			Index struct {
+				ID string `json:"_id"`
+			} `json:"index"`
+		}
+		_ = json.Unmarshal([]byte(lines[i]), &action)
+		id := action.Index.ID
+		ids = append(ids, id)
+		status := http.StatusCreated
+		if scripted := f.statuses[id]; len(scripted) > 0 {
+			status, f.statuses[id] = scripted[0], scripted[1:]
+		}
+		outcome := map[string]interface{}{"_id": id, "status": status}
+		if status >= 300 {
+			outcome["error"] = map[string]interface{}{"type": "scripted_exception", "reason": fmt.Sprintf("status %d", status)}
+		}
+		items = append(items, map[string]interface{}{"index": outcome})
+	}
+	f.requests = append(f.requests, ids)
+
+	status := f.requestStatus
+	if f.maxDocs > 0 && len(ids) > f.maxDocs {
+		status = http.StatusRequestEntityTooLarge
+	}
+	for _, id := range ids {
+		if f.tooLarge[id] {
+			status = http.StatusRequestEntityTooLarge
+		}
+	}
+	if status != 0 {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error":{"type":"scripted_request_exception","reason":"request rejected"},"status":` + fmt.Sprint(status) + `}`))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": true, "items": items})
+}
+
+func (f *statusBulk) sent() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.requests...)
+}
+
+type recordingDeadLetters struct {
+	mu      gosync.Mutex
+	letters []deadletter.Letter
+}
+
+func (r *recordingDeadLetters) Record(_ context.Context, letters []deadletter.Letter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.letters = append(r.letters, letters...)
+	return nil
+}
+
+func newStatusBulkIndexer(t *testing.T, bulk *statusBulk, batchSize int) *BulkIndexer {
+	t.Helper()
+	server := httptest.NewServer(bulk)
+	t.Cleanup(server.Close)
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	return NewBulkIndexer(es, batchSize, slog.New(slog.NewTextHandler(io.Discard, nil))).WithRetries(2, time.Millisecond)
+}
+
+func TestBulkIndexerRetriesAndDeadLettersPerDocument(t *testing.T) {
+	bulk := &statusBulk{statuses: map[string][]int{
+		"busy":     {http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		"rejected": {http.StatusBadRequest},
+	}}
+	deadLetters := &recordingDeadLetters{}
+	metrics := observability.NewMetrics()
+	bi := newStatusBulkIndexer(t, bulk, 10).WithDeadLetters(deadLetters).WithMetrics(metrics)
+
+	for _, id := range []string{"ok", "busy", "rejected"} {
+		if err := bi.IndexDocument(context.Background(), "aevum-events-write", id, map[string]string{"id": id}); err != nil {
+			t.Fatalf("expected buffered document, got %v", err)
+		}
+	}
+	if err := bi.Flush(context.Background()); err != nil {
+		t.Fatalf("expected flush to succeed after retries, got %v", err)
+	}
+
+	requests := bulk.sent()
+	if len(requests) != 3 || len(requests[1]) != 1 || requests[1][0] != "busy" || requests[2][0] != "busy" {
+		t.Fatalf("expected only the busy document to be retried, got %v", requests)
+	}
+	if len(deadLetters.letters) != 1 {
+		t.Fatalf("expected one dead letter, got %+v", deadLetters.letters)
+	}
+	letter := deadLetters.letters[0]
+	if letter.DocumentID != "rejected" || letter.Status != http.StatusBadRequest || letter.Attempts != 1 || string(letter.Document) != `{"id":"rejected"}` {
+		t.Fatalf("unexpected dead letter %+v", letter)
+	}
+	if got := testutil.ToFloat64(metrics.IndexedDocumentsTotal.WithLabelValues("aevum-events-write")); got != 2 {
+		t.Fatalf("expected 2 indexed documents, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.FailedDocumentsTotal.WithLabelValues("aevum-events-write", "rejected")); got != 1 {
+		t.Fatalf("expected 1 rejected document, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.BulkRetriesTotal.WithLabelValues("aevum-events-write")); got != 2 {
+		t.Fatalf("expected 2 retries, got %v", got)
+	}
+}
+
+func TestBulkIndexerFailsWhenRetriesRunOut(t *testing.T) {
+	bulk := &statusBulk{statuses: map[string][]int{"busy": {http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}}}
+	metrics := observability.NewMetrics()
+	bi := newStatusBulkIndexer(t, bulk, 10).WithMetrics(metrics)
+
+	_ = bi.IndexDocument(context.Background(), "aevum-decisions-write", "busy", map[string]string{})
+	err := bi.Flush(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 documents not indexed after 3 attempts") {
+		t.Fatalf("expected retries to run out, got %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.FailedDocumentsTotal.WithLabelValues("aevum-decisions-write", "retries_exhausted")); got != 1 {
+		t.Fatalf("expected 1 document out of retries, got %v", got)
+	}
+}
+
+func TestBulkIndexerSplitsBatchesTooLargeForElasticsearch(t *testing.T) {
+	bulk := &statusBulk{maxDocs: 2, tooLarge: map[string]bool{"huge": true}}
+	deadLetters := &recordingDeadLetters{}
+	metrics := observability.NewMetrics()
+	bi := newStatusBulkIndexer(t, bulk, 10).WithDeadLetters(deadLetters).WithMetrics(metrics)
+
+	for _, id := range []string{"a", "b", "c", "huge"} {
+		_ = bi.IndexDocument(context.Background(), "aevum-events-write", id, map[string]string{"id": id})
+	}
+	if err := bi.Flush(context.Background()); err != nil {
+		t.Fatalf("expected the split batch to be indexed, got %v", err)
+	}
+
+	want := [][]string{{"a", "b", "c", "huge"}, {"a", "b"}, {"c", "huge"}, {"c"}, {"huge"}}
+	if got := bulk.sent(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected requests %v, got %v", want, got)
+	}
+	if len(deadLetters.letters) != 1 {
+		t.Fatalf("expected the document too large on its own to be dead-lettered, got %+v", deadLetters.letters)
+	}
+	if letter := deadLetters.letters[0]; letter.DocumentID != "huge" || letter.Status != http.StatusRequestEntityTooLarge || string(letter.Document) != `{"id":"huge"}` {
+		t.Fatalf("unexpected dead letter %+v", letter)
+	}
+	if got := testutil.ToFloat64(metrics.IndexedDocumentsTotal.WithLabelValues("aevum-events-write")); got != 3 {
+		t.Fatalf("expected 3 indexed documents, got %v", got)
+	}
+}
+
+func TestBulkIndexerDeadLettersEveryDocumentOfARejectedRequest(t *testing.T) {
+	bulk := &statusBulk{requestStatus: http.StatusBadRequest}
+	deadLetters := &recordingDeadLetters{}
+	metrics := observability.NewMetrics()
+	bi := newStatusBulkIndexer(t, bulk, 10).WithDeadLetters(deadLetters).WithMetrics(metrics)
+
+	for _, id := range []string{"a", "b"} {
+		_ = bi.IndexDocument(context.Background(), "aevum-decisions-write", id, map[string]string{"id": id})
+	}
+	if err := bi.Flush(context.Background()); err != nil {
+		t.Fatalf("expected the rejected documents to be dead-lettered, got %v", err)
+	}
+
+	if requests := bulk.sent(); len(requests) != 1 {
+		t.Fatalf("expected a rejected request not to be retried, got %v", requests)
+	}
+	if len(deadLetters.letters) != 2 {
+		t.Fatalf("expected every document to be dead-lettered, got %+v", deadLetters.letters)
+	}
+	for i, letter := range deadLetters.letters {
+		if letter.DocumentID != []string{"a", "b"}[i] || letter.Status != http.StatusBadRequest || letter.Stage != deadletter.StageIndex || letter.Attempts != 1 ||
+			!strings.Contains(letter.Reason, "status 400") || !strings.Contains(letter.Reason, "scripted_request_exception") {
+			t.Fatalf("unexpected dead letter %+v", letter)
+		}
+	}
+	if got := testutil.ToFloat64(metrics.FailedDocumentsTotal.WithLabelValues("aevum-decisions-write", "rejected")); got != 2 {
+		t.Fatalf("expected 2 rejected documents, got %v", got)
+	}
+}
+
+func TestBulkIndexerFlushesOnCountBytesAndInterval(t *testing.T) {
+	bulk := &statusBulk{}
+	bi := newStatusBulkIndexer(t, bulk, 2)
+	for _, id := range []string{"a", "b", "c"} {
+		_ = bi.IndexDocument(context.Background(), "aevum-events-write", id, map[string]string{})
+	}
+	if requests := bulk.sent(); len(requests) != 1 || len(requests[0]) != 2 {
+		t.Fatalf("expected a flush after two documents, got %v", requests)
+	}
+
+	bulk = &statusBulk{}
+	bi = newStatusBulkIndexer(t, bulk, 100).WithLimits(128, 5*time.Millisecond)
+	_ = bi.IndexDocument(context.Background(), "aevum-events-write", "small", map[string]string{})
+	if requests := bulk.sent(); len(requests) != 0 {
+		t.Fatalf("expected no flush below the limits, got %v", requests)
+	}
+	_ = bi.IndexDocument(context.Background(), "aevum-events-write", "large", map[string]string{"payload": strings.Repeat("x", 128)})
+	if requests := bulk.sent(); len(requests) != 1 || len(requests[0]) != 2 {
+		t.Fatalf("expected a flush once the buffer exceeds the byte limit, got %v", requests)
+	}
+
+	_ = bi.IndexDocument(context.Background(), "aevum-events-write", "late", map[string]string{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bi.Run(ctx)
+	}()
+	deadline := time.Now().Add(time.Second)
+	for len(bulk.sent()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a flush on the interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestBulkIndexerIsSafeForConcurrentUse(t *testing.T) {
+	bulk := &statusBulk{}
+	bi := newStatusBulkIndexer(t, bulk, 7)
+
+	var wg gosync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				if err := bi.IndexDocument(context.Background(), "aevum-events-write", fmt.Sprintf("%d-%d", worker, i), map[string]int{"i": i}); err != nil {
+					t.Errorf("expected nil error, got %v", err)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+	if err := bi.Flush(context.Background()); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, request := range bulk.sent() {
+		for _, id := range request {
+			if seen[id] {
+				t.Fatalf("document %s sent twice", id)
+			}
+			seen[id] = true
+		}
+	}
+	if len(seen) != 100 {
+		t.Fatalf("expected 100 documents sent, got %d", len(seen))
+	}
+}
@@ -0,0 +1,259 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/clients"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
+)
+
+// fakeDecisionEngine serves the decision listing like Decision Engine: camelCase fields, oldest first,
+// from and to inclusive, page and pageSize paging
+type fakeDecisionEngine struct {
+	mu        gosync.Mutex
+	decisions []map[string]interface{}
+	failing   bool
+}
+
+func (f *fakeDecisionEngine) add(id string, evaluatedAt time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	at := sort.Search(len(f.decisions), func(i int) bool {
+		existing, _ := time.Parse(time.RFC3339Nano, f.decisions[i]["evaluatedAt"].(string))
+		return existing.After(evaluatedAt)
+	})
+	f.decisions = append(f.decisions, nil)
+	copy(f.decisions[at+1:], f.decisions[at:])
+	f.decisions[at] = map[string]interface{}{
+		"id":          id,
+		"ruleId":      "rule-1",
+		"ruleVersion": 2,
+		"status":      "Approved",
+		"evaluatedAt": evaluatedAt.Format(aevumclient.DecisionTimeLayout),
+	}
+}
+
+func (f *fakeDecisionEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path != "/api/v1/decisions" || f.failing {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+	from, _ := time.Parse(time.RFC3339Nano, query.Get("from"))
+	to, _ := time.Parse(time.RFC3339Nano, query.Get("to"))
+
+	matching := []map[string]interface{}{}
+	for _, decision := range f.decisions {
+		evaluatedAt, _ := time.Parse(time.RFC3339Nano, decision["evaluatedAt"].(string))
+		if evaluatedAt.Before(from) || (!to.IsZero() && evaluatedAt.After(to)) {
+			continue
+		}
+		matching = append(matching, decision)
+	}
+	start := (page - 1) * pageSize
+	end := start + pageSize
+	if start > len(matching) {
+		start = len(matching)
+	}
+	if end > len(matching) {
+		end = len(matching)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": matching[start:end], "page": page, "pageSize": pageSize, "total": len(matching)})
+}
+
+func TestDecisionIndexerSyncsFromHighWaterMark(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	engine := &fakeDecisionEngine{}
+	for i := 0; i < 250; i++ {
+		engine.add(fmt.Sprintf("d%03d", i), now.Add(-time.Hour).Add(time.Duration(i)*time.Second))
+	}
+	engineServer := httptest.NewServer(engine)
+	defer engineServer.Close()
+	bulk := &fakeBulk{}
+	esServer := httptest.NewServer(bulk)
+	defer esServer.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{esServer.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	di := NewDecisionIndexer(clients.NewDecisionEngineClient(engineServer.URL), NewBulkIndexer(es, 50, logger), logger)
+	di.now = func() time.Time { return now }
+
+	cursor, err := di.Sync(context.Background(), "")
+	if err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	if ids := bulk.take(); len(ids) != 250 {
+		t.Fatalf("expected all 250 decisions across two pages, got %d", len(ids))
+	}
+	if want := now.Add(-time.Hour).Add(249 * time.Second).Format(time.RFC3339Nano); cursor != want {
+		t.Fatalf("expected cursor %s, got %s", want, cursor)
+	}
+
+	// Decisions evaluated while the worker was down are caught up from the cursor;
+	// one evaluated within the settle delay waits for the next sync
+	engine.add("d250", now.Add(-30*time.Minute))
+	engine.add("d251", now.Add(-time.Minute))
+	engine.add("d252", now.Add(-time.Second))
+	cursor, err = di.Sync(context.Background(), cursor)
+	if err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	ids := bulk.take()
+	sort.Strings(ids)
+	if strings.Join(ids, ",") != "d249,d250,d251" {
+		t.Fatalf("expected decisions from the high-water mark, got %v", ids)
+	}
+	if want := now.Add(-time.Minute).Format(time.RFC3339Nano); cursor != want {
+		t.Fatalf("expected cursor %s, got %s", want, cursor)
+	}
+
+	di.now = func() time.Time { return now.Add(time.Minute) }
+	if cursor, err = di.Sync(context.Background(), cursor); err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	if ids := bulk.take(); strings.Join(ids, ",") != "d251,d252" {
+		t.Fatalf("expected the settled decision, got %v", ids)
+	}
+
+	engine.mu.Lock()
+	engine.failing = true
+	engine.mu.Unlock()
+	failedCursor, err := di.Sync(context.Background(), cursor)
+	if err == nil || failedCursor != cursor {
+		t.Fatalf("expected failure to keep cursor %s, got %s (%v)", cursor, failedCursor, err)
+	}
+
+	if _, err := di.Sync(context.Background(), "not-a-time"); err == nil {
+		t.Fatal("expected invalid cursor to fail")
+	}
+}
+
+func TestDecisionIndexerPagesPastTheCapWithinOneTimestamp(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	burst := now.Add(-time.Hour)
+	engine := &fakeDecisionEngine{}
+	for i := 0; i < maxDecisionPages*decisionPageSize+100; i++ {
+		engine.add(fmt.Sprintf("burst%04d", i), burst)
+	}
+	for i := 0; i < decisionPageSize; i++ {
+		engine.add(fmt.Sprintf("later%03d", i), burst.Add(time.Duration(i+1)*time.Second))
+	}
+	engineServer := httptest.NewServer(engine)
+	defer engineServer.Close()
+	bulk := &fakeBulk{}
+	esServer := httptest.NewServer(bulk)
+	defer esServer.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{esServer.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	di := NewDecisionIndexer(clients.NewDecisionEngineClient(engineServer.URL), NewBulkIndexer(es, 50, logger), logger)
+	di.now = func() time.Time { return now }
+
+	cursor, err := di.Sync(context.Background(), "")
+	if err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	if ids := bulk.take(); len(ids) != maxDecisionPages*decisionPageSize {
+		t.Fatalf("expected the first sync to stop at the cap, got %d decisions", len(ids))
+	}
+	if want := burst.Format(time.RFC3339Nano); cursor != want {
+		t.Fatalf("expected cursor %s, got %s", want, cursor)
+	}
+
+	// Every decision of the first pages shares the cursor's timestamp, so the next sync reads past
+	// the cap until the timestamp moves instead of reading the same pages again
+	cursor, err = di.Sync(context.Background(), cursor)
+	if err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	if ids := bulk.take(); len(ids) != (maxDecisionPages+1)*decisionPageSize {
+		t.Fatalf("expected the burst and the page after it, got %d decisions", len(ids))
+	}
+	if want := burst.Add(100 * time.Second).Format(time.RFC3339Nano); cursor != want {
+		t.Fatalf("expected cursor %s, got %s", want, cursor)
+	}
+
+	if cursor, err = di.Sync(context.Background(), cursor); err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	if ids := bulk.take(); len(ids) != 101 {
+		t.Fatalf("expected the decision at the cursor and the remaining ones, got %d", len(ids))
+	}
+	if want := burst.Add(decisionPageSize * time.Second).Format(time.RFC3339Nano); cursor != want {
+		t.Fatalf("expected cursor %s, got %s", want, cursor)
+	}
+}
+
+func TestConvertDecisionToIndexedReadsDecisionEngineFields(t *testing.T) {
+	var decision aevumclient.Decision
+	err := json.Unmarshal([]byte(`{"id":"d1","ruleId":"rule-1","ruleVersion":3,"status":1,"deterministicHash":"abc","outputData":{"result":"pass"},"evaluatedAt":"2026-03-01T12:00:00.1234567+00:00"}`), &decision)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	indexed := convertDecisionToIndexed(decision)
+	if indexed.DecisionID != "d1" || indexed.RuleID != "rule-1" || indexed.RuleVersion != "3" || indexed.Status != "Rejected" || indexed.DeterministicHash != "abc" {
+		t.Fatalf("unexpected decision %+v", indexed)
+	}
+	if indexed.Output["result"] != "pass" || indexed.EvaluatedAt.Nanosecond() != 123456700 {
+		t.Fatalf("unexpected output or evaluation time %+v", indexed)
+	}
+}
+
+func TestDecisionIndexerRemainingAndReset(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	engine := &fakeDecisionEngine{}
+	engine.add("d1", now.Add(-3*time.Minute))
+	engine.add("d2", now.Add(-2*time.Minute))
+	engine.add("d3", now.Add(-time.Second))
+	engineServer := httptest.NewServer(engine)
+	defer engineServer.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	di := NewDecisionIndexer(clients.NewDecisionEngineClient(engineServer.URL), nil, logger)
+	di.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if remaining, err := di.Remaining(ctx, ""); err != nil || remaining != 2 {
+		t.Fatalf("expected 2 settled decisions remaining, got %d, %v", remaining, err)
+	}
+	if remaining, _ := di.Remaining(ctx, now.Add(-3*time.Minute).Format(time.RFC3339Nano)); remaining != 1 {
+		t.Fatalf("expected decisions at the cursor not to count, got %d", remaining)
+	}
+
+	cursor, err := di.Reset(ctx, syncpkg.Position{Timestamp: now.Add(-time.Hour)})
+	if err != nil || cursor != now.Add(-time.Hour).Format(time.RFC3339Nano) {
+		t.Fatalf("unexpected reset cursor %q, %v", cursor, err)
+	}
+	if cursor, err := di.Reset(ctx, syncpkg.Position{}); err != nil || cursor != "" {
+		t.Fatalf("expected reset to the first decision, got %q, %v", cursor, err)
+	}
+	if _, err := di.Reset(ctx, syncpkg.Position{StreamID: "orders", Sequence: 3}); !errors.Is(err, syncpkg.ErrInvalidPosition) {
+		t.Fatalf("expected invalid position, got %v", err)
+	}
+}
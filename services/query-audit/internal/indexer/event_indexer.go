@@ -2,189 +2,516 @@ package indexer
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
+	gosync "sync"
 	"time"
 
-	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/clients"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/redaction"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/storage"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
+)
+
+const (
+	eventPageSize = 100
+	// maxPagesPerStream bounds one sync of a stream, so a long backlog does not hold up the other streams
+	maxPagesPerStream = 10
+	// StreamStatePrefix prefixes the sync state name of each stream
+	StreamStatePrefix = "event-timeline/"
+
+	decisionPageSize = 200
+	// maxDecisionPages bounds one decision sync once its high-water mark moved, so a long catch-up
+	// persists the mark in steps
+	maxDecisionPages = 10
+	// decisionSettleDelay keeps decisions this recent for the next sync, as earlier ones may still be written
+	decisionSettleDelay = 10 * time.Second
 )
 
 // EventIndexer synchronizes and indexes events
 type EventIndexer struct {
-	client      *clients.EventTimelineClient
+	client      *aevumclient.EventTimelineClient
 	bulkIndexer *BulkIndexer
+	redaction   *redaction.Policy
 	logger      *slog.Logger
+	states      syncpkg.StateStore
+	concurrency int
+	tenants     []string
+
+	mu           gosync.Mutex
+	streamStates map[string]*syncpkg.SyncState
 }
 
-// NewEventIndexer creates a new event indexer
-func NewEventIndexer(client *clients.EventTimelineClient, bulkIndexer *BulkIndexer, logger *slog.Logger) *EventIndexer {
+// tenantStream is a stream of one tenant of Event Timeline
+type tenantStream struct {
+	tenantID string
+	aevumclient.StreamInfo
+}
+
+// NewEventIndexer creates a new event indexer; a nil policy indexes payloads unredacted
+func NewEventIndexer(client *aevumclient.EventTimelineClient, bulkIndexer *BulkIndexer, policy *redaction.Policy, logger *slog.Logger) *EventIndexer {
 	return &EventIndexer{
-		client:      client,
-		bulkIndexer: bulkIndexer,
-		logger:      logger,
+		client:       client,
+		bulkIndexer:  bulkIndexer,
+		redaction:    policy,
+		logger:       logger,
+		concurrency:  1,
+		tenants:      []string{aevumclient.DefaultTenant},
+		streamStates: map[string]*syncpkg.SyncState{},
+	}
+}
+
+// WithTenants syncs the streams of each tenant, listing and reading them with a token of that tenant;
+// only the default tenant is synced otherwise
+func (ei *EventIndexer) WithTenants(tenants []string) *EventIndexer {
+	ei.tenants = ei.tenants[:0]
+	for _, tenantID := range tenants {
+		if tenantID = strings.TrimSpace(tenantID); tenantID != "" {
+			ei.tenants = append(ei.tenants, tenantID)
+		}
+	}
+	if len(ei.tenants) == 0 {
+		ei.tenants = []string{aevumclient.DefaultTenant}
 	}
+	return ei
 }
 
-// Sync fetches events and indexes them
+// WithStreamStates persists the cursor of each stream in store and syncs up to concurrency streams at once
+func (ei *EventIndexer) WithStreamStates(store syncpkg.StateStore, concurrency int) *EventIndexer {
+	ei.states = store
+	if concurrency > 0 {
+		ei.concurrency = concurrency
+	}
+	return ei
+}
+
+// Sync lists the streams of every tenant and syncs each from its own cursor, so new streams
+// are picked up on the next run; the worker cursor is passed through unchanged
 func (ei *EventIndexer) Sync(ctx context.Context, cursor string) (string, error) {
-	events, newCursor, err := ei.client.GetEvents(ctx, cursor, 100)
+	streams, err := ei.listStreams(ctx)
 	if err != nil {
-		ei.logger.Error("failed to fetch events", slog.Any("error", err))
+		ei.logger.Error("failed to list streams", slog.Any("error", err))
 		return cursor, err
 	}
 
-	for _, event := range events {
-		indexed := convertEventToIndexed(event)
-		if err := ei.bulkIndexer.IndexDocument(ctx, "aevum-events", indexed.EventID, indexed); err != nil {
-			ei.logger.Error("failed to index event", slog.String("event_id", indexed.EventID), slog.Any("error", err))
+	var (
+		wg       gosync.WaitGroup
+		mu       gosync.Mutex
+		failed   int
+		firstErr error
+	)
+	slots := make(chan struct{}, ei.concurrency)
+	for _, stream := range streams {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(stream tenantStream) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := ei.syncStream(aevumclient.WithTenant(ctx, stream.tenantID), stream); err != nil {
+				ei.logger.Error("failed to sync stream", slog.String("tenant_id", stream.tenantID), slog.String("stream_id", stream.StreamID), slog.Any("error", err))
+				mu.Lock()
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(stream)
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		return cursor, fmt.Errorf("%d of %d streams failed to sync: %w", failed, len(streams), firstErr)
+	}
+	return cursor, nil
+}
+
+// listStreams lists the streams of every tenant
+func (ei *EventIndexer) listStreams(ctx context.Context) ([]tenantStream, error) {
+	var streams []tenantStream
+	for _, tenantID := range ei.tenants {
+		listed, err := ei.client.ListStreams(aevumclient.WithTenant(ctx, tenantID))
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+		for _, stream := range listed {
+			streams = append(streams, tenantStream{tenantID: tenantID, StreamInfo: stream})
 		}
 	}
+	return streams, nil
+}
 
-	if err := ei.bulkIndexer.Flush(ctx); err != nil {
-		ei.logger.Error("failed to flush bulk indexer", slog.Any("error", err))
-		return cursor, err
+// syncStream indexes the events of one stream that follow its last indexed sequence; ctx carries
+// the stream's tenant
+func (ei *EventIndexer) syncStream(ctx context.Context, stream tenantStream) error {
+	state, err := ei.streamState(ctx, stream.tenantID, stream.StreamID)
+	if err != nil {
+		return err
 	}
+	if state.LastSequence >= stream.LatestSequence {
+		return nil
+	}
+
+	bulk := ei.bulkIndexer.fork()
+	cursor := state.LastSyncedCursor
+	for page := 0; page < maxPagesPerStream; page++ {
+		result, err := ei.client.GetStreamEvents(ctx, stream.StreamID, cursor, eventPageSize)
+		if err != nil {
+			return ei.streamFailed(ctx, state, fmt.Errorf("failed to fetch events: %w", err))
+		}
+
+		lastSequence := state.LastSequence
+		for _, event := range result.Events {
+			if event.TenantID == "" {
+				event.TenantID = stream.tenantID
+			}
+			indexed := convertEventToIndexed(event, ei.redaction)
+			if indexed.SequenceNum <= state.LastSequence {
+				continue
+			}
+			// The payload of event is redacted in place by the conversion, so its dead letter holds no more than the index would
+			if indexed.EventID == "" {
+				if err := bulk.RejectSource(ctx, storage.EventsWriteAlias, event, "event has no event_id"); err != nil {
+					return ei.streamFailed(ctx, state, fmt.Errorf("failed to dead-letter event: %w", err))
+				}
+			} else if err := bulk.IndexSourceDocument(ctx, storage.EventsWriteAlias, indexed.EventID, indexed, event); err != nil {
+				return ei.streamFailed(ctx, state, fmt.Errorf("failed to index event %s: %w", indexed.EventID, err))
+			}
+			if indexed.SequenceNum > lastSequence {
+				lastSequence = indexed.SequenceNum
+			}
+		}
+		if err := bulk.Flush(ctx); err != nil {
+			return ei.streamFailed(ctx, state, fmt.Errorf("failed to flush bulk indexer: %w", err))
+		}
 
-	return newCursor, nil
+		// Event Timeline returns no cursor on the last page, so the cursor of that page is kept;
+		// reading it again later returns the events appended since, after the ones already indexed
+		if result.NextCursor != "" {
+			cursor = result.NextCursor
+		}
+		state.UpdateCursor(cursor)
+		state.LastSequence = lastSequence
+		ei.saveStreamState(ctx, state)
+
+		if result.NextCursor == "" || lastSequence >= stream.LatestSequence {
+			return nil
+		}
+	}
+	return nil
 }
 
-// convertEventToIndexed transforms a raw event to IndexedEvent
-func convertEventToIndexed(event map[string]interface{}) *domain.IndexedEvent {
-	payload := convertToStringMap(event["payload"])
-	metadata := convertToStringMap(event["metadata"])
+// Remaining returns the number of events in all streams that follow their last indexed sequence
+func (ei *EventIndexer) Remaining(ctx context.Context, cursor string) (int64, error) {
+	streams, err := ei.listStreams(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var remaining int64
+	for _, stream := range streams {
+		state, err := ei.streamState(ctx, stream.tenantID, stream.StreamID)
+		if err != nil {
+			return 0, err
+		}
+		if stream.LatestSequence > state.LastSequence {
+			remaining += stream.LatestSequence - state.LastSequence
+		}
+	}
+	return remaining, nil
+}
 
-	occurredAt := parseTime(event["occurred_at"])
-	ingestedAt := parseTime(event["ingested_at"])
+// Reset moves a stream of the position's tenant back to the given sequence, or every stream of every
+// tenant to the start when no stream is given; the next sync indexes the events that follow it
+func (ei *EventIndexer) Reset(ctx context.Context, position syncpkg.Position) (string, error) {
+	if !position.Timestamp.IsZero() {
+		return "", fmt.Errorf("%w: event streams are reset to a sequence, not a timestamp", syncpkg.ErrInvalidPosition)
+	}
+	if position.Sequence < 0 || (position.StreamID == "" && position.Sequence != 0) {
+		return "", fmt.Errorf("%w: sequence must not be negative and requires stream_id", syncpkg.ErrInvalidPosition)
+	}
+	if position.StreamID == "" && position.TenantID != "" {
+		return "", fmt.Errorf("%w: tenant_id requires stream_id", syncpkg.ErrInvalidPosition)
+	}
 
-	return &domain.IndexedEvent{
-		EventID:       toString(event["event_id"]),
-		StreamID:      toString(event["stream_id"]),
-		SequenceNum:   toInt64(event["sequence_number"]),
-		EventType:     toString(event["event_type"]),
-		Payload:       payload,
-		Metadata:      metadata,
-		OccurredAt:    occurredAt,
-		IngestedAt:    ingestedAt,
-		SchemaVersion: toString(event["schema_version"]),
+	tenantID := position.TenantID
+	if tenantID == "" {
+		tenantID = aevumclient.DefaultTenant
+	}
+	streams := []tenantStream{{tenantID: tenantID, StreamInfo: aevumclient.StreamInfo{StreamID: position.StreamID}}}
+	if position.StreamID == "" {
+		listed, err := ei.listStreams(ctx)
+		if err != nil {
+			return "", err
+		}
+		streams = listed
+		ei.mu.Lock()
+		ei.streamStates = map[string]*syncpkg.SyncState{}
+		ei.mu.Unlock()
 	}
+
+	for _, stream := range streams {
+		name := StreamStateName(stream.tenantID, stream.StreamID)
+		state := syncpkg.NewSyncState(name)
+		state.LastSequence = position.Sequence
+		if ei.states != nil {
+			if err := ei.states.Save(ctx, *state); err != nil {
+				return "", err
+			}
+		}
+		ei.mu.Lock()
+		ei.streamStates[name] = state
+		ei.mu.Unlock()
+	}
+	return "", nil
 }
 
-// convertToStringMap safely converts to map[string]interface{}
-func convertToStringMap(v interface{}) map[string]interface{} {
-	if m, ok := v.(map[string]interface{}); ok {
-		return m
+// StreamStateName returns the sync state name of a stream of a tenant; the streams of the default
+// tenant keep the names they had before tenants were synced
+func StreamStateName(tenantID, streamID string) string {
+	return StreamStatePrefix + streamKey(tenantID, streamID)
+}
+
+// streamKey qualifies a stream ID with its tenant the way Event Timeline keys its streams
+func streamKey(tenantID, streamID string) string {
+	if tenantID == "" || tenantID == aevumclient.DefaultTenant {
+		return streamID
 	}
-	return make(map[string]interface{})
+	return "T#" + tenantID + "#" + streamID
 }
 
-// parseTime parses ISO8601 timestamp
-func parseTime(v interface{}) time.Time {
-	if s, ok := v.(string); ok {
-		t, _ := time.Parse(time.RFC3339, s)
-		return t
+// streamState returns the cached state of a stream, loading it on first use
+func (ei *EventIndexer) streamState(ctx context.Context, tenantID, streamID string) (*syncpkg.SyncState, error) {
+	name := StreamStateName(tenantID, streamID)
+	ei.mu.Lock()
+	defer ei.mu.Unlock()
+	if state, ok := ei.streamStates[name]; ok {
+		return state, nil
+	}
+
+	state := syncpkg.NewSyncState(name)
+	if ei.states != nil {
+		loaded, err := ei.states.Load(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		state = loaded
 	}
-	return time.Now()
+	ei.streamStates[name] = state
+	return state, nil
+}
+
+// streamFailed records a failed stream sync and returns err
+func (ei *EventIndexer) streamFailed(ctx context.Context, state *syncpkg.SyncState, err error) error {
+	state.MarkFailed(err)
+	ei.saveStreamState(ctx, state)
+	return err
 }
 
-// toString converts to string
-func toString(v interface{}) string {
-	if s, ok := v.(string); ok {
-		return s
+// saveStreamState persists a stream state; a failed save is retried with the stream's next sync
+func (ei *EventIndexer) saveStreamState(ctx context.Context, state *syncpkg.SyncState) {
+	if ei.states == nil {
+		return
+	}
+	if err := ei.states.Save(ctx, *state); err != nil {
+		ei.logger.Warn("failed to save stream sync state", slog.String("service", state.ServiceName), slog.Any("error", err))
 	}
-	return ""
 }
 
-// toInt64 converts to int64
-func toInt64(v interface{}) int64 {
-	switch val := v.(type) {
-	case int64:
-		return val
-	case float64:
-		return int64(val)
-	default:
-		return 0
+// convertEventToIndexed transforms an event to IndexedEvent, redacting the payload in place; events of
+// the default tenant are indexed without a tenant, as they were before tenants were synced
+func convertEventToIndexed(event aevumclient.Event, policy *redaction.Policy) *domain.IndexedEvent {
+	tenantID := event.TenantID
+	if tenantID == aevumclient.DefaultTenant {
+		tenantID = ""
+	}
+	payload := event.Payload
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	metadata := make(map[string]interface{}, len(event.Metadata))
+	for k, v := range event.Metadata {
+		metadata[k] = v
+	}
+	schemaVersion := ""
+	if event.SchemaVersion > 0 {
+		schemaVersion = strconv.Itoa(event.SchemaVersion)
+	}
+
+	return &domain.IndexedEvent{
+		EventID:       event.EventID,
+		TenantID:      tenantID,
+		StreamID:      event.StreamID,
+		SequenceNum:   event.SequenceNumber,
+		EventType:     event.EventType,
+		Payload:       policy.Redact(event.EventType, payload),
+		Metadata:      metadata,
+		OccurredAt:    event.OccurredAt,
+		IngestedAt:    event.IngestedAt,
+		SchemaVersion: schemaVersion,
 	}
 }
 
 // DecisionIndexer synchronizes and indexes decisions
 type DecisionIndexer struct {
-	client      *clients.DecisionEngineClient
+	client      *aevumclient.DecisionEngineClient
 	bulkIndexer *BulkIndexer
 	logger      *slog.Logger
+	now         func() time.Time
 }
 
 // NewDecisionIndexer creates a new decision indexer
-func NewDecisionIndexer(client *clients.DecisionEngineClient, bulkIndexer *BulkIndexer, logger *slog.Logger) *DecisionIndexer {
+func NewDecisionIndexer(client *aevumclient.DecisionEngineClient, bulkIndexer *BulkIndexer, logger *slog.Logger) *DecisionIndexer {
 	return &DecisionIndexer{
 		client:      client,
 		bulkIndexer: bulkIndexer,
 		logger:      logger,
+		now:         time.Now,
 	}
 }
 
-// Sync fetches decisions and indexes them
-func (di *DecisionIndexer) Sync(ctx context.Context, from, to time.Time) error {
-	decisions, err := di.client.GetDecisions(ctx, from, to)
-	if err != nil {
-		di.logger.Error("failed to fetch decisions", slog.Any("error", err))
-		return err
+// Sync indexes the decisions evaluated at or after the high-water mark in cursor, oldest first, and
+// returns the evaluation time of the newest indexed decision as the new cursor; an empty cursor starts
+// from the first decision, so a sync after downtime catches up without gaps
+func (di *DecisionIndexer) Sync(ctx context.Context, cursor string) (string, error) {
+	var from time.Time
+	if cursor != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, cursor)
+		if err != nil {
+			return cursor, fmt.Errorf("invalid decision cursor %q: %w", cursor, err)
+		}
+		from = parsed
 	}
 
-	for _, decision := range decisions {
-		indexed := convertDecisionToIndexed(decision)
-		if err := di.bulkIndexer.IndexDocument(ctx, "aevum-decisions", indexed.DecisionID, indexed); err != nil {
-			di.logger.Error("failed to index decision", slog.String("decision_id", indexed.DecisionID), slog.Any("error", err))
-		}
+	// The upper bound is fixed for the whole sync, so pages do not shift while decisions are evaluated,
+	// and trails the clock so decisions still being written are read by the next sync instead of skipped
+	to := di.now().Add(-decisionSettleDelay)
+	if to.Before(from) {
+		return cursor, nil
 	}
 
-	if err := di.bulkIndexer.Flush(ctx); err != nil {
-		di.logger.Error("failed to flush bulk indexer", slog.Any("error", err))
-		return err
+	highWater := from
+	for page := 1; ; page++ {
+		result, err := di.client.ListDecisions(ctx, from, to, page, decisionPageSize)
+		if err != nil {
+			di.logger.Error("failed to fetch decisions", slog.Any("error", err))
+			return cursor, err
+		}
+
+		for _, decision := range result.Items {
+			indexed := convertDecisionToIndexed(decision)
+			if indexed.DecisionID == "" {
+				if err := di.bulkIndexer.RejectSource(ctx, storage.DecisionsWriteAlias, decision, "decision has no id"); err != nil {
+					return cursor, fmt.Errorf("failed to dead-letter decision: %w", err)
+				}
+			} else if err := di.bulkIndexer.IndexSourceDocument(ctx, storage.DecisionsWriteAlias, indexed.DecisionID, indexed, decision); err != nil {
+				return cursor, fmt.Errorf("failed to index decision %s: %w", indexed.DecisionID, err)
+			}
+			if decision.EvaluatedAt.After(highWater) {
+				highWater = decision.EvaluatedAt
+			}
+		}
+		if err := di.bulkIndexer.Flush(ctx); err != nil {
+			di.logger.Error("failed to flush bulk indexer", slog.Any("error", err))
+			return cursor, err
+		}
+
+		if len(result.Items) < decisionPageSize || page*decisionPageSize >= result.Total {
+			break
+		}
+		// The cursor is a timestamp, so the cap only applies once it moved: stopping while every decision
+		// read shares the cursor's timestamp would read the same pages again on every sync
+		if page >= maxDecisionPages && highWater.After(from) {
+			break
+		}
 	}
 
-	return nil
+	// The listing's from is inclusive, so decisions sharing the newest evaluation time are read again
+	// by the next sync and indexed under the same IDs rather than missed
+	if highWater.IsZero() {
+		return cursor, nil
+	}
+	return highWater.UTC().Format(time.RFC3339Nano), nil
 }
 
-// convertDecisionToIndexed transforms a raw decision to IndexedDecision
-func convertDecisionToIndexed(decision map[string]interface{}) *domain.IndexedDecision {
-	input := convertToStringMap(decision["input"])
-	output := convertToStringMap(decision["output"])
-
-	trace := []domain.TraceEntry{}
-	if traceList, ok := decision["trace"].([]interface{}); ok {
-		for _, t := range traceList {
-			if traceMap, ok := t.(map[string]interface{}); ok {
-				trace = append(trace, domain.TraceEntry{
-					Step:      int(toInt64(traceMap["step"])),
-					Condition: toString(traceMap["condition"]),
-					Result:    toBool(traceMap["result"]),
-					Message:   toString(traceMap["message"]),
-					Timestamp: parseTime(traceMap["timestamp"]),
-				})
-			}
+// Remaining returns the number of decisions evaluated after cursor that a sync would read now
+func (di *DecisionIndexer) Remaining(ctx context.Context, cursor string) (int64, error) {
+	var from time.Time
+	if cursor != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, cursor)
+		if err != nil {
+			return 0, fmt.Errorf("invalid decision cursor %q: %w", cursor, err)
 		}
+		// Decision Engine stores times to 100ns, so this skips the decisions at the cursor
+		from = parsed.Add(100 * time.Nanosecond)
+	}
+	to := di.now().Add(-decisionSettleDelay)
+	if to.Before(from) {
+		return 0, nil
+	}
+
+	result, err := di.client.ListDecisions(ctx, from, to, 1, 1)
+	if err != nil {
+		return 0, err
+	}
+	return int64(result.Total), nil
+}
+
+// Reset returns the cursor that continues from the decisions evaluated at the position's timestamp,
+// or from the first decision for the zero position
+func (di *DecisionIndexer) Reset(_ context.Context, position syncpkg.Position) (string, error) {
+	if position.TenantID != "" || position.StreamID != "" || position.Sequence != 0 {
+		return "", fmt.Errorf("%w: decisions are reset to a timestamp, not a stream sequence", syncpkg.ErrInvalidPosition)
+	}
+	if position.Timestamp.IsZero() {
+		return "", nil
+	}
+	return position.Timestamp.UTC().Format(time.RFC3339Nano), nil
+}
+
+// convertDecisionToIndexed transforms a decision to IndexedDecision
+func convertDecisionToIndexed(decision aevumclient.Decision) *domain.IndexedDecision {
+	input := decision.InputContext
+	if input == nil {
+		input = map[string]interface{}{}
+	}
+	// Decision Engine returns the output as outputData
+	output := decision.Output
+	if output == nil {
+		output = decision.OutputData
+	}
+	if output == nil {
+		output = map[string]interface{}{}
+	}
+
+	trace := make([]domain.TraceEntry, 0, len(decision.Trace))
+	for _, step := range decision.Trace {
+		trace = append(trace, domain.TraceEntry{
+			Step:      step.Step,
+			Condition: strings.TrimSpace(step.Field + " " + step.Operator),
+			Result:    step.Matched,
+			Message:   step.Message,
+		})
+	}
+
+	ruleVersion := ""
+	if decision.RuleVersion > 0 {
+		ruleVersion = strconv.Itoa(decision.RuleVersion)
 	}
 
 	return &domain.IndexedDecision{
-		DecisionID:        toString(decision["decision_id"]),
-		EventID:           toString(decision["event_id"]),
-		StreamID:          toString(decision["stream_id"]),
-		RuleID:            toString(decision["rule_id"]),
-		RuleVersion:       toString(decision["rule_version"]),
-		Status:            toString(decision["status"]),
-		DeterministicHash: toString(decision["deterministic_hash"]),
+		DecisionID:        decision.ID,
+		EventID:           decision.EventID,
+		StreamID:          decision.StreamID,
+		RuleID:            decision.RuleID,
+		RuleVersion:       ruleVersion,
+		Status:            string(decision.Status),
+		DeterministicHash: decision.DeterministicHash,
 		Input:             input,
 		Output:            output,
 		Trace:             trace,
-		EvaluatedAt:       parseTime(decision["evaluated_at"]),
-		EventOccurredAt:   parseTime(decision["event_occurred_at"]),
-	}
-}
-
-// toBool converts to bool
-func toBool(v interface{}) bool {
-	if b, ok := v.(bool); ok {
-		return b
+		EvaluatedAt:       decision.EvaluatedAt,
 	}
-	return false
 }
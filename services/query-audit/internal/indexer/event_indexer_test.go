@@ -2,10 +2,15 @@ package indexer
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/redaction"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
 )
 
 func TestBulkIndexerBufferingWithoutFlush(t *testing.T) {
@@ -14,8 +19,8 @@ func TestBulkIndexerBufferingWithoutFlush(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
-	if len(bi.buffer) != 2 {
-		t.Fatalf("expected 2 buffered items, got %d", len(bi.buffer))
+	if len(bi.buffer) != 1 {
+		t.Fatalf("expected 1 buffered item, got %d", len(bi.buffer))
 	}
 
 	bi.buffer = nil
@@ -26,73 +31,103 @@ func TestBulkIndexerBufferingWithoutFlush(t *testing.T) {
 }
 
 func TestConvertEventToIndexed(t *testing.T) {
-	input := map[string]interface{}{
-		"event_id":        "evt-1",
-		"stream_id":       "s-1",
-		"sequence_number": float64(7),
-		"event_type":      "created",
-		"payload":         map[string]interface{}{"amount": 10},
-		"metadata":        map[string]interface{}{"source": "test"},
-		"occurred_at":     "2026-01-01T00:00:00Z",
-		"ingested_at":     "2026-01-01T00:00:01Z",
-		"schema_version":  "1",
+	input := aevumclient.Event{
+		EventID:        "evt-1",
+		StreamID:       "s-1",
+		SequenceNumber: 7,
+		EventType:      "created",
+		Payload:        map[string]interface{}{"amount": 10},
+		Metadata:       map[string]string{"source": "test"},
+		OccurredAt:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		IngestedAt:     time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC),
+		SchemaVersion:  1,
 	}
 
-	indexed := convertEventToIndexed(input)
+	indexed := convertEventToIndexed(input, nil)
 	if indexed.EventID != "evt-1" || indexed.StreamID != "s-1" {
 		t.Fatal("unexpected event conversion")
 	}
 	if indexed.SequenceNum != 7 {
 		t.Fatalf("expected sequence 7, got %d", indexed.SequenceNum)
 	}
-	if indexed.OccurredAt.IsZero() || indexed.IngestedAt.IsZero() {
-		t.Fatal("expected parsed timestamps")
+	if !indexed.OccurredAt.Equal(input.OccurredAt) || !indexed.IngestedAt.Equal(input.IngestedAt) {
+		t.Fatal("expected the event timestamps")
+	}
+	if indexed.SchemaVersion != "1" || indexed.Metadata["source"] != "test" {
+		t.Fatalf("unexpected schema version or metadata %+v", indexed)
+	}
+
+	// A missing timestamp stays zero instead of becoming the time of the sync
+	if indexed := convertEventToIndexed(aevumclient.Event{EventID: "evt-2"}, nil); !indexed.IngestedAt.IsZero() || indexed.SchemaVersion != "" || indexed.Payload == nil {
+		t.Fatalf("unexpected conversion of an event without optional fields %+v", indexed)
 	}
 }
 
-func TestConvertDecisionToIndexedAndHelpers(t *testing.T) {
-	input := map[string]interface{}{
-		"decision_id":        "dec-1",
-		"event_id":           "evt-1",
-		"stream_id":          "s-1",
-		"rule_id":            "rule-1",
-		"rule_version":       "2",
-		"status":             "approved",
-		"deterministic_hash": "hash-1",
-		"input":              map[string]interface{}{"x": 1},
-		"output":             map[string]interface{}{"approved": true},
-		"trace": []interface{}{
-			map[string]interface{}{
-				"step":      float64(1),
-				"condition": "amount > 10",
-				"result":    true,
-				"message":   "ok",
-				"timestamp": "2026-01-01T00:00:01Z",
-			},
+func TestConvertEventToIndexedRedactsPayload(t *testing.T) {
+	policy, err := redaction.ParsePolicy([]byte(`{
+		"rules": {
+			"*": [{"key_patterns": ["(?i)phone"], "mode": "hash"}],
+			"signup": [
+				{"paths": ["customer.email"], "mode": "mask"},
+				{"paths": ["customer.ssn", "notes"], "mode": "drop"}
+			]
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("expected valid policy, got %v", err)
+	}
+	input := aevumclient.Event{
+		EventID:   "evt-1",
+		EventType: "signup",
+		Payload: map[string]interface{}{
+			"customer": map[string]interface{}{"email": "ada@example.test", "ssn": "123-45-6789", "name": "Ada"},
+			"contacts": []interface{}{map[string]interface{}{"mobilePhone": "+49 170 0000000"}},
+			"notes":    "prefers calls after 5pm",
 		},
+		OccurredAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 
-	indexed := convertDecisionToIndexed(input)
-	if indexed.DecisionID != "dec-1" {
-		t.Fatal("unexpected decision id")
+	indexed := convertEventToIndexed(input, policy)
+	doc, err := json.Marshal(indexed)
+	if err != nil {
+		t.Fatalf("expected indexed event to marshal, got %v", err)
+	}
+	for _, secret := range []string{"ada@example.test", "123-45-6789", "+49 170 0000000", "prefers calls after 5pm"} {
+		if strings.Contains(string(doc), secret) {
+			t.Fatalf("redacted value %q found in indexed document %s", secret, doc)
+		}
 	}
-	if len(indexed.Trace) != 1 || !indexed.Trace[0].Result {
-		t.Fatal("unexpected trace conversion")
+	if !strings.Contains(string(doc), `"name":"Ada"`) {
+		t.Fatalf("expected unredacted fields to be indexed, got %s", doc)
 	}
+}
 
-	if toInt64(int64(4)) != 4 || toInt64(float64(5)) != 5 || toInt64("x") != 0 {
-		t.Fatal("unexpected toInt64 behavior")
+func TestConvertDecisionToIndexed(t *testing.T) {
+	input := aevumclient.Decision{
+		ID:                "dec-1",
+		EventID:           "evt-1",
+		StreamID:          "s-1",
+		RuleID:            "rule-1",
+		RuleVersion:       2,
+		Status:            "Approved",
+		DeterministicHash: "hash-1",
+		InputContext:      map[string]interface{}{"x": 1},
+		OutputData:        map[string]interface{}{"approved": true},
+		Trace:             []aevumclient.TraceStep{{Step: 1, Field: "amount", Operator: "GreaterThan", Matched: true, Message: "ok"}},
+		EvaluatedAt:       time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC),
 	}
-	if !toBool(true) || toBool("true") {
-		t.Fatal("unexpected toBool behavior")
+
+	indexed := convertDecisionToIndexed(input)
+	if indexed.DecisionID != "dec-1" || indexed.RuleVersion != "2" || indexed.Status != "Approved" {
+		t.Fatalf("unexpected decision conversion %+v", indexed)
 	}
-	if toString("abc") != "abc" || toString(1) != "" {
-		t.Fatal("unexpected toString behavior")
+	if indexed.Output["approved"] != true || indexed.Input["x"] != 1 {
+		t.Fatalf("expected input and output data, got %+v", indexed)
 	}
-	if !parseTime("2026-01-01T00:00:00Z").Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
-		t.Fatal("unexpected parseTime behavior")
+	if len(indexed.Trace) != 1 || !indexed.Trace[0].Result || indexed.Trace[0].Condition != "amount GreaterThan" {
+		t.Fatalf("unexpected trace conversion %+v", indexed.Trace)
 	}
-	if parseTime(10).IsZero() {
-		t.Fatal("expected non-zero parseTime fallback")
+	if !indexed.EvaluatedAt.Equal(input.EvaluatedAt) || !indexed.EventOccurredAt.IsZero() {
+		t.Fatalf("unexpected decision times %+v", indexed)
 	}
 }
@@ -0,0 +1,616 @@
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	gosync "sync"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/observability"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
+)
+
+const (
+	// gapScanSize is the largest sequence range whose indexed sequences are read one by one;
+	// larger ranges are narrowed down with a histogram first
+	gapScanSize = 1000
+	// maxReportedRanges bounds the missing ranges reported and re-synced per stream
+	maxReportedRanges = 100
+)
+
+// ErrCheckRunning is returned when a consistency check is started while another one runs
+var ErrCheckRunning = errors.New("consistency check already running")
+
+// SequenceRange is an inclusive range of stream sequences
+type SequenceRange struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// Mismatch is a sampled event whose indexed document differs from Event Timeline
+type Mismatch struct {
+	Sequence int64  `json:"sequence"`
+	EventID  string `json:"event_id"`
+	Reason   string `json:"reason"`
+}
+
+// StreamConsistency compares one stream of Event Timeline with the events index. Sequences up to
+// IndexedThrough should all be indexed; the ones after it are waiting for the next sync
+type StreamConsistency struct {
+	TenantID       string          `json:"tenant_id"`
+	StreamID       string          `json:"stream_id"`
+	SourceHead     int64           `json:"source_head"`
+	IndexedThrough int64           `json:"indexed_through"`
+	IndexHead      int64           `json:"index_head"`
+	IndexedCount   int64           `json:"indexed_count"`
+	Lag            int64           `json:"lag"`
+	MissingCount   int64           `json:"missing_count"`
+	Missing        []SequenceRange `json:"missing"`
+	Sampled        int             `json:"sampled"`
+	Mismatches     []Mismatch      `json:"mismatches"`
+	Resynced       int             `json:"resynced"`
+	Consistent     bool            `json:"consistent"`
+	Error          string          `json:"error,omitempty"`
+}
+
+// ConsistencyReport is the result of one consistency check
+type ConsistencyReport struct {
+	StartedAt  time.Time           `json:"started_at"`
+	FinishedAt time.Time           `json:"finished_at"`
+	Resync     bool                `json:"resync"`
+	Consistent bool                `json:"consistent"`
+	Streams    []StreamConsistency `json:"streams"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// Reconciler checks that the events index holds every event Event Timeline has, per stream, and
+// optionally indexes the missing and differing events again
+type Reconciler struct {
+	events     *EventIndexer
+	client     *elasticsearch.Client
+	logger     *slog.Logger
+	metrics    *observability.Metrics
+	sampleSize int
+	autoResync bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     gosync.WaitGroup
+
+	mu      gosync.Mutex
+	running bool
+	last    *ConsistencyReport
+}
+
+// NewReconciler creates a new reconciler of the streams synced by events
+func NewReconciler(events *EventIndexer, client *elasticsearch.Client, logger *slog.Logger) *Reconciler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Reconciler{events: events, client: client, logger: logger, ctx: ctx, cancel: cancel}
+}
+
+// WithSampling compares the content of up to size random indexed events per stream with Event Timeline
+func (r *Reconciler) WithSampling(size int) *Reconciler {
+	r.sampleSize = size
+	return r
+}
+
+// WithAutoResync indexes missing and differing events again in every check
+func (r *Reconciler) WithAutoResync(enabled bool) *Reconciler {
+	r.autoResync = enabled
+	return r
+}
+
+// WithMetrics exports the result of each check as gauges
+func (r *Reconciler) WithMetrics(metrics *observability.Metrics) *Reconciler {
+	r.metrics = metrics
+	return r
+}
+
+// Run checks every interval until ctx is done
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Check(ctx, false); err != nil && !errors.Is(err, ErrCheckRunning) {
+				r.logger.Error("consistency check failed", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// Start runs a check in the background; resync re-syncs what it finds even without auto resync
+func (r *Reconciler) Start(resync bool) error {
+	if !r.begin() {
+		return ErrCheckRunning
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if _, err := r.run(r.ctx, resync); err != nil {
+			r.logger.Error("consistency check failed", slog.Any("error", err))
+		}
+	}()
+	return nil
+}
+
+// Last returns the report of the last finished check, nil before the first, and whether a check runs
+func (r *Reconciler) Last() (*ConsistencyReport, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last, r.running
+}
+
+// Close cancels a running check and waits for it to stop
+func (r *Reconciler) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Check compares every stream of Event Timeline with the events index
+func (r *Reconciler) Check(ctx context.Context, resync bool) (ConsistencyReport, error) {
+	if !r.begin() {
+		return ConsistencyReport{}, ErrCheckRunning
+	}
+	return r.run(ctx, resync)
+}
+
+// begin marks a check as running, unless one already is
+func (r *Reconciler) begin() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		return false
+	}
+	r.running = true
+	return true
+}
+
+// run checks the streams and keeps the report as the last one
+func (r *Reconciler) run(ctx context.Context, resync bool) (ConsistencyReport, error) {
+	report := ConsistencyReport{StartedAt: time.Now().UTC(), Resync: resync || r.autoResync, Consistent: true, Streams: []StreamConsistency{}}
+	err := r.check(ctx, &report)
+	if err != nil {
+		report.Consistent = false
+		report.Error = err.Error()
+	}
+	report.FinishedAt = time.Now().UTC()
+	r.export(report)
+
+	r.mu.Lock()
+	r.running = false
+	r.last = &report
+	r.mu.Unlock()
+	return report, err
+}
+
+func (r *Reconciler) check(ctx context.Context, report *ConsistencyReport) error {
+	streams, err := r.events.listStreams(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list streams: %w", err)
+	}
+	// Documents flushed by the last sync become searchable, so they are not reported missing
+	res, err := r.client.Indices.Refresh(r.client.Indices.Refresh.WithIndex(storage.EventsAlias), r.client.Indices.Refresh.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to refresh %s: %w", storage.EventsAlias, err)
+	}
+	res.Body.Close()
+
+	for _, stream := range streams {
+		sc := StreamConsistency{TenantID: stream.tenantID, StreamID: stream.StreamID, SourceHead: stream.LatestSequence, Missing: []SequenceRange{}, Mismatches: []Mismatch{}}
+		if err := r.checkStream(aevumclient.WithTenant(ctx, stream.tenantID), &sc, report.Resync); err != nil {
+			sc.Error = err.Error()
+			r.logger.Error("failed to check stream consistency", slog.String("tenant_id", stream.tenantID), slog.String("stream_id", stream.StreamID), slog.Any("error", err))
+		}
+		sc.Consistent = sc.Error == "" && sc.MissingCount == 0 && len(sc.Mismatches) == 0
+		report.Consistent = report.Consistent && sc.Consistent
+		report.Streams = append(report.Streams, sc)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// checkStream finds the sequences up to the stream's sync position that are not indexed, samples the
+// indexed events for changed content, and indexes both again when resync is set; ctx carries the
+// stream's tenant
+func (r *Reconciler) checkStream(ctx context.Context, sc *StreamConsistency, resync bool) error {
+	head, indexed, err := r.stats(ctx, sc.TenantID, sc.StreamID, sc.SourceHead)
+	if err != nil {
+		return err
+	}
+	sc.IndexHead = head
+
+	through, err := r.events.indexedThrough(ctx, sc.TenantID, sc.StreamID)
+	if err != nil {
+		return err
+	}
+	if through < 0 {
+		// Without persisted stream states, what is indexed shows how far the stream was synced
+		through = head
+	}
+	if through > sc.SourceHead {
+		through = sc.SourceHead
+	}
+	if through != sc.SourceHead {
+		if _, indexed, err = r.stats(ctx, sc.TenantID, sc.StreamID, through); err != nil {
+			return err
+		}
+	}
+	sc.IndexedThrough, sc.IndexedCount = through, indexed
+	sc.Lag = sc.SourceHead - through
+	sc.MissingCount = through - indexed
+	if sc.MissingCount < 0 {
+		// Duplicate sequences under different event IDs; the sampling below reports their content
+		sc.MissingCount = 0
+	}
+
+	if sc.MissingCount > 0 {
+		if err := r.findGaps(ctx, sc.TenantID, sc.StreamID, 1, through, &sc.Missing); err != nil {
+			return err
+		}
+	}
+	var replacements []*domain.IndexedEvent
+	if r.sampleSize > 0 && through > 0 {
+		if sc.Sampled, replacements, err = r.sample(ctx, sc); err != nil {
+			return err
+		}
+	}
+	if !resync || (len(sc.Missing) == 0 && len(replacements) == 0) {
+		return nil
+	}
+
+	resynced, err := r.resync(ctx, sc.TenantID, sc.StreamID, sc.Missing, replacements)
+	sc.Resynced = resynced
+	if err == nil && resynced > 0 {
+		r.logger.Info("re-synced stream events", slog.String("tenant_id", sc.TenantID), slog.String("stream_id", sc.StreamID), slog.Int("events", resynced))
+	}
+	return err
+}
+
+// stats returns the highest indexed sequence of a stream and the number of indexed sequences up to through
+func (r *Reconciler) stats(ctx context.Context, tenantID, streamID string, through int64) (int64, int64, error) {
+	var result struct {
+		Aggregations struct {
+			Head struct {
+				Value *float64 `json:"value"`
+			} `json:"head"`
+			Indexed struct {
+				DocCount int64 `json:"doc_count"`
+			} `json:"indexed"`
+		} `json:"aggregations"`
+	}
+	err := r.search(ctx, map[string]interface{}{
+		"size":  0,
+		"query": streamQuery(tenantID, streamID, 0, 0),
+		"aggs": map[string]interface{}{
+			"head":    map[string]interface{}{"max": map[string]interface{}{"field": "sequence_number"}},
+			"indexed": map[string]interface{}{"filter": sequenceRange(1, through)},
+		},
+	}, &result)
+	if err != nil {
+		return 0, 0, err
+	}
+	var head int64
+	if result.Aggregations.Head.Value != nil {
+		head = int64(*result.Aggregations.Head.Value)
+	}
+	return head, result.Aggregations.Indexed.DocCount, nil
+}
+
+// findGaps appends the sequences between from and to that are not indexed to gaps, narrowing large
+// ranges down to the parts with fewer documents than sequences
+func (r *Reconciler) findGaps(ctx context.Context, tenantID, streamID string, from, to int64, gaps *[]SequenceRange) error {
+	if len(*gaps) >= maxReportedRanges {
+		return nil
+	}
+	if to-from+1 <= gapScanSize {
+		return r.scanGaps(ctx, tenantID, streamID, from, to, gaps)
+	}
+
+	interval := (to - from + gapScanSize) / gapScanSize
+	if interval < gapScanSize {
+		interval = gapScanSize
+	}
+	var result struct {
+		Aggregations struct {
+			Buckets struct {
+				Buckets []struct {
+					Key      float64 `json:"key"`
+					DocCount int64   `json:"doc_count"`
+				} `json:"buckets"`
+			} `json:"buckets"`
+		} `json:"aggregations"`
+	}
+	err := r.search(ctx, map[string]interface{}{
+		"size":  0,
+		"query": streamQuery(tenantID, streamID, from, to),
+		"aggs": map[string]interface{}{
+			"buckets": map[string]interface{}{"histogram": map[string]interface{}{
+				"field":           "sequence_number",
+				"interval":        interval,
+				"offset":          from % interval,
+				"min_doc_count":   0,
+				"extended_bounds": map[string]interface{}{"min": from, "max": to},
+			}},
+		},
+	}, &result)
+	if err != nil {
+		return err
+	}
+	for _, bucket := range result.Aggregations.Buckets.Buckets {
+		start := int64(bucket.Key)
+		end := start + interval - 1
+		if start < from {
+			start = from
+		}
+		if end > to {
+			end = to
+		}
+		if bucket.DocCount >= end-start+1 {
+			continue
+		}
+		if err := r.findGaps(ctx, tenantID, streamID, start, end, gaps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanGaps reads the indexed sequences between from and to and appends the ones missing to gaps
+func (r *Reconciler) scanGaps(ctx context.Context, tenantID, streamID string, from, to int64, gaps *[]SequenceRange) error {
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source struct {
+					SequenceNum int64 `json:"sequence_number"`
+				} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	err := r.search(ctx, map[string]interface{}{
+		"size":    to - from + 1,
+		"_source": []string{"sequence_number"},
+		"query":   streamQuery(tenantID, streamID, from, to),
+		"sort":    []map[string]interface{}{{"sequence_number": map[string]interface{}{"order": "asc"}}},
+	}, &result)
+	if err != nil {
+		return err
+	}
+
+	next := from
+	for _, hit := range result.Hits.Hits {
+		seq := hit.Source.SequenceNum
+		if seq > next {
+			addGap(gaps, next, seq-1)
+		}
+		if seq >= next {
+			next = seq + 1
+		}
+	}
+	if next <= to {
+		addGap(gaps, next, to)
+	}
+	return nil
+}
+
+// sample compares random indexed events of a stream with Event Timeline and returns the number
+// compared and the current documents of the events that differ
+func (r *Reconciler) sample(ctx context.Context, sc *StreamConsistency) (int, []*domain.IndexedEvent, error) {
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source domain.IndexedEvent `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	err := r.search(ctx, map[string]interface{}{
+		"size": r.sampleSize,
+		"query": map[string]interface{}{"function_score": map[string]interface{}{
+			"query":        streamQuery(sc.TenantID, sc.StreamID, 1, sc.IndexedThrough),
+			"random_score": map[string]interface{}{"seed": time.Now().UnixNano(), "field": "_seq_no"},
+		}},
+	}, &result)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var replacements []*domain.IndexedEvent
+	for _, hit := range result.Hits.Hits {
+		indexed := hit.Source
+		event, err := r.events.client.GetEvent(ctx, indexed.EventID)
+		if err != nil {
+			sc.Mismatches = append(sc.Mismatches, Mismatch{Sequence: indexed.SequenceNum, EventID: indexed.EventID, Reason: err.Error()})
+			continue
+		}
+		if event.TenantID == "" {
+			event.TenantID = sc.TenantID
+		}
+		current := convertEventToIndexed(*event, r.events.redaction)
+		if contentHash(current) != contentHash(&indexed) {
+			sc.Mismatches = append(sc.Mismatches, Mismatch{Sequence: indexed.SequenceNum, EventID: indexed.EventID, Reason: "content differs"})
+			replacements = append(replacements, current)
+		}
+	}
+	return len(result.Hits.Hits), replacements, nil
+}
+
+// resync indexes the events of the missing ranges, reading each range from its first sequence until it
+// is covered, and the replacements of differing events; it returns the events indexed
+func (r *Reconciler) resync(ctx context.Context, tenantID, streamID string, missing []SequenceRange, replacements []*domain.IndexedEvent) (int, error) {
+	bulk := r.events.bulkIndexer.fork()
+	count := 0
+	for _, event := range replacements {
+		if err := bulk.IndexDocument(ctx, storage.EventsWriteAlias, event.EventID, event); err != nil {
+			return count, fmt.Errorf("failed to index event %s: %w", event.EventID, err)
+		}
+		count++
+	}
+
+	for _, gap := range missing {
+		indexed, err := r.resyncRange(ctx, bulk, tenantID, streamID, gap)
+		count += indexed
+		if err != nil {
+			return count, err
+		}
+	}
+	if err := bulk.Flush(ctx); err != nil {
+		return count, fmt.Errorf("failed to flush bulk indexer: %w", err)
+	}
+	return count, nil
+}
+
+// resyncRange buffers the events of one missing range in bulk and returns how many it buffered
+func (r *Reconciler) resyncRange(ctx context.Context, bulk *BulkIndexer, tenantID, streamID string, gap SequenceRange) (int, error) {
+	limit := eventPageSize
+	if size := gap.To - gap.From + 1; size < int64(limit) {
+		limit = int(size)
+	}
+	page, err := r.events.client.GetStreamEventsFrom(ctx, streamID, gap.From, limit)
+	count := 0
+	for {
+		if err != nil {
+			return count, fmt.Errorf("failed to fetch events: %w", err)
+		}
+		for _, event := range page.Events {
+			if event.TenantID == "" {
+				event.TenantID = tenantID
+			}
+			indexed := convertEventToIndexed(event, r.events.redaction)
+			if indexed.SequenceNum > gap.To {
+				return count, nil
+			}
+			if indexed.SequenceNum < gap.From {
+				continue
+			}
+			if indexed.EventID == "" {
+				if err := bulk.RejectSource(ctx, storage.EventsWriteAlias, event, "event has no event_id"); err != nil {
+					return count, fmt.Errorf("failed to dead-letter event: %w", err)
+				}
+			} else {
+				if err := bulk.IndexSourceDocument(ctx, storage.EventsWriteAlias, indexed.EventID, indexed, event); err != nil {
+					return count, fmt.Errorf("failed to index event %s: %w", indexed.EventID, err)
+				}
+				count++
+			}
+			if indexed.SequenceNum == gap.To {
+				return count, nil
+			}
+		}
+		if page.NextCursor == "" {
+			return count, nil
+		}
+		page, err = r.events.client.GetStreamEvents(ctx, streamID, page.NextCursor, eventPageSize)
+	}
+}
+
+// export sets the consistency gauges from report; streams that are gone are dropped
+func (r *Reconciler) export(report ConsistencyReport) {
+	if r.metrics == nil {
+		return
+	}
+	r.metrics.ConsistencyMissing.Reset()
+	r.metrics.ConsistencyMismatched.Reset()
+	r.metrics.ConsistencyLag.Reset()
+	inconsistent := 0
+	for _, sc := range report.Streams {
+		stream := streamKey(sc.TenantID, sc.StreamID)
+		r.metrics.ConsistencyMissing.WithLabelValues(stream).Set(float64(sc.MissingCount))
+		r.metrics.ConsistencyMismatched.WithLabelValues(stream).Set(float64(len(sc.Mismatches)))
+		r.metrics.ConsistencyLag.WithLabelValues(stream).Set(float64(sc.Lag))
+		if !sc.Consistent {
+			inconsistent++
+		}
+	}
+	r.metrics.ConsistencyInconsistentStreams.Set(float64(inconsistent))
+	r.metrics.ConsistencyLastCheck.Set(float64(report.FinishedAt.Unix()))
+}
+
+// search runs a search on the events alias and decodes the response into result
+func (r *Reconciler) search(ctx context.Context, query map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal consistency query: %w", err)
+	}
+	res, err := r.client.Search(r.client.Search.WithContext(ctx), r.client.Search.WithIndex(storage.EventsAlias), r.client.Search.WithBody(bytes.NewReader(body)))
+	if err != nil {
+		return fmt.Errorf("consistency query failed: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("consistency query failed: status %d", res.StatusCode)
+	}
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode consistency query response: %w", err)
+	}
+	return nil
+}
+
+// indexedThrough returns the last sequence of a stream the persisted sync state says is indexed, or -1
+// without persisted states
+func (ei *EventIndexer) indexedThrough(ctx context.Context, tenantID, streamID string) (int64, error) {
+	if ei.states == nil {
+		return -1, nil
+	}
+	state, err := ei.states.Load(ctx, StreamStateName(tenantID, streamID))
+	if err != nil {
+		return 0, err
+	}
+	return state.LastSequence, nil
+}
+
+// streamQuery matches the events of a stream of a tenant, within a sequence range unless from is 0;
+// events of the default tenant are indexed without a tenant
+func streamQuery(tenantID, streamID string, from, to int64) map[string]interface{} {
+	filters := []map[string]interface{}{{"term": map[string]interface{}{"stream_id": streamID}}}
+	if from > 0 {
+		filters = append(filters, sequenceRange(from, to))
+	}
+	query := map[string]interface{}{}
+	if tenantID == "" || tenantID == aevumclient.DefaultTenant {
+		query["must_not"] = []map[string]interface{}{{"exists": map[string]interface{}{"field": "tenant_id"}}}
+	} else {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"tenant_id": tenantID}})
+	}
+	query["filter"] = filters
+	return map[string]interface{}{"bool": query}
+}
+
+func sequenceRange(from, to int64) map[string]interface{} {
+	return map[string]interface{}{"range": map[string]interface{}{"sequence_number": map[string]interface{}{"gte": from, "lte": to}}}
+}
+
+// contentHash hashes the fields of an indexed event that come from Event Timeline
+func contentHash(event *domain.IndexedEvent) string {
+	data, _ := json.Marshal(struct {
+		EventID       string                 `json:"event_id"`
+		StreamID      string                 `json:"stream_id"`
+		SequenceNum   int64                  `json:"sequence_number"`
+		EventType     string                 `json:"event_type"`
+		Payload       map[string]interface{} `json:"payload"`
+		Metadata      map[string]interface{} `json:"metadata"`
+		OccurredAt    time.Time              `json:"occurred_at"`
+		SchemaVersion string                 `json:"schema_version"`
+	}{event.EventID, event.StreamID, event.SequenceNum, event.EventType, event.Payload, event.Metadata, event.OccurredAt.UTC(), event.SchemaVersion})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func addGap(gaps *[]SequenceRange, from, to int64) {
+	if len(*gaps) < maxReportedRanges {
+		*gaps = append(*gaps, SequenceRange{From: from, To: to})
+	}
+}
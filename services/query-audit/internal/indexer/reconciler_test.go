@@ -0,0 +1,240 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	gosync "sync"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/clients"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/observability"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeEventsIndex holds indexed events and answers the searches of the reconciler and bulk writes
+type fakeEventsIndex struct {
+	mu     gosync.Mutex
+	events map[string]domain.IndexedEvent
+}
+
+func (f *fakeEventsIndex) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/_refresh"):
+		_, _ = w.Write([]byte(`{}`))
+	case strings.HasSuffix(r.URL.Path, "/_bulk"):
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		for i := 0; i+1 < len(lines); i += 2 {
+			var event domain.IndexedEvent
+			_ = json.Unmarshal([]byte(lines[i+1]), &event)
+			f.events[event.EventID] = event
+		}
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	case strings.HasSuffix(r.URL.Path, "/_search"):
+		var query struct {
+			Size  int64                  `json:"size"`
+			Query map[string]interface{} `json:"query"`
+			Aggs  map[string]struct {
+				Filter struct {
+					Range map[string]map[string]int64 `json:"range"`
+				} `json:"filter"`
+				Histogram struct {
+					Interval int64 `json:"interval"`
+					Offset   int64 `json:"offset"`
+				} `json:"histogram"`
+			} `json:"aggs"`
+		}
+		_ = json.Unmarshal(body, &query)
+		matched := f.match(query.Query)
+
+		if aggs, ok := query.Aggs["buckets"]; ok {
+			counts := map[int64]int64{}
+			from, to := bounds(query.Query)
+			for key := from - (from-aggs.Histogram.Offset)%aggs.Histogram.Interval; key <= to; key += aggs.Histogram.Interval {
+				counts[key] = 0
+			}
+			for _, event := range matched {
+				counts[event.SequenceNum-(event.SequenceNum-aggs.Histogram.Offset)%aggs.Histogram.Interval]++
+			}
+			buckets := []map[string]int64{}
+			for key, count := range counts {
+				buckets = append(buckets, map[string]int64{"key": key, "doc_count": count})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"aggregations": map[string]interface{}{"buckets": map[string]interface{}{"buckets": buckets}}})
+			return
+		}
+		if aggs, ok := query.Aggs["indexed"]; ok {
+			var head, indexed int64
+			window := aggs.Filter.Range["sequence_number"]
+			for _, event := range matched {
+				if event.SequenceNum > head {
+					head = event.SequenceNum
+				}
+				if event.SequenceNum >= window["gte"] && event.SequenceNum <= window["lte"] {
+					indexed++
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"aggregations": map[string]interface{}{
+				"head":    map[string]interface{}{"value": head},
+				"indexed": map[string]interface{}{"doc_count": indexed},
+			}})
+			return
+		}
+		hits := []map[string]interface{}{}
+		for _, event := range matched {
+			if int64(len(hits)) < query.Size {
+				hits = append(hits, map[string]interface{}{"_source": event})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"hits": map[string]interface{}{"hits": hits}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// match returns the events matching the stream and sequence filters of query, in sequence order
+func (f *fakeEventsIndex) match(query map[string]interface{}) []domain.IndexedEvent {
+	if score, ok := query["function_score"].(map[string]interface{}); ok {
+		query = score["query"].(map[string]interface{})
+	}
+	streamID := ""
+	for _, filter := range query["bool"].(map[string]interface{})["filter"].([]interface{}) {
+		if term, ok := filter.(map[string]interface{})["term"].(map[string]interface{}); ok {
+			streamID = term["stream_id"].(string)
+		}
+	}
+	from, to := bounds(query)
+	matched := []domain.IndexedEvent{}
+	for _, event := range f.events {
+		if event.StreamID == streamID && event.SequenceNum >= from && event.SequenceNum <= to {
+			matched = append(matched, event)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].SequenceNum < matched[j].SequenceNum })
+	return matched
+}
+
+// bounds returns the sequence range filter of query, every sequence when it has none
+func bounds(query map[string]interface{}) (int64, int64) {
+	if score, ok := query["function_score"].(map[string]interface{}); ok {
+		query = score["query"].(map[string]interface{})
+	}
+	for _, filter := range query["bool"].(map[string]interface{})["filter"].([]interface{}) {
+		if window, ok := filter.(map[string]interface{})["range"].(map[string]interface{}); ok {
+			seq := window["sequence_number"].(map[string]interface{})
+			return int64(seq["gte"].(float64)), int64(seq["lte"].(float64))
+		}
+	}
+	return 1, 1 << 20
+}
+
+func TestReconcilerReportsAndResyncsGapsAndMismatches(t *testing.T) {
+	timeline := &fakeTimeline{streams: map[string]int64{"orders": 1500, "payments": 3}, broken: map[string]bool{}}
+	timelineServer := httptest.NewServer(timeline)
+	defer timelineServer.Close()
+
+	index := &fakeEventsIndex{events: map[string]domain.IndexedEvent{}}
+	// Orders after 1450 wait for the next sync
+	for stream, head := range map[string]int64{"orders": 1450, "payments": 3} {
+		for seq := int64(1); seq <= head; seq++ {
+			if stream == "orders" && ((seq >= 1200 && seq <= 1204) || seq == 1400) {
+				continue
+			}
+			event := convertEventToIndexed(timelineEvent(stream, seq), nil)
+			index.events[event.EventID] = *event
+		}
+	}
+	changed := index.events["payments-2"]
+	changed.Payload = map[string]interface{}{"amount": float64(10)}
+	index.events["payments-2"] = changed
+	esServer := httptest.NewServer(index)
+	defer esServer.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{esServer.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := &memoryStateStore{states: map[string]syncpkg.SyncState{
+		StreamStatePrefix + "orders":   {ServiceName: StreamStatePrefix + "orders", LastSequence: 1450},
+		StreamStatePrefix + "payments": {ServiceName: StreamStatePrefix + "payments", LastSequence: 3},
+	}}
+	ei := NewEventIndexer(clients.NewEventTimelineClient(timelineServer.URL), NewBulkIndexer(es, 50, logger), nil, logger).WithStreamStates(store, 1)
+	metrics := observability.NewMetrics()
+	reconciler := NewReconciler(ei, es, logger).WithSampling(5).WithMetrics(metrics)
+	defer reconciler.Close()
+
+	report, err := reconciler.Check(context.Background(), false)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if report.Consistent || len(report.Streams) != 2 {
+		t.Fatalf("expected an inconsistent report of two streams, got %+v", report)
+	}
+	streams := map[string]StreamConsistency{}
+	for _, sc := range report.Streams {
+		streams[sc.StreamID] = sc
+	}
+	orders := streams["orders"]
+	if orders.SourceHead != 1500 || orders.IndexedThrough != 1450 || orders.IndexHead != 1450 || orders.Lag != 50 || orders.MissingCount != 6 {
+		t.Fatalf("unexpected orders report %+v", orders)
+	}
+	if len(orders.Missing) != 2 || orders.Missing[0] != (SequenceRange{From: 1200, To: 1204}) || orders.Missing[1] != (SequenceRange{From: 1400, To: 1400}) {
+		t.Fatalf("unexpected missing ranges %+v", orders.Missing)
+	}
+	if orders.Sampled != 5 || len(orders.Mismatches) != 0 || orders.Resynced != 0 {
+		t.Fatalf("expected sampled orders to match, got %+v", orders)
+	}
+	payments := streams["payments"]
+	if payments.Consistent || payments.MissingCount != 0 || len(payments.Mismatches) != 1 || payments.Mismatches[0].EventID != "payments-2" {
+		t.Fatalf("expected the changed payment to be reported, got %+v", payments)
+	}
+	if got := testutil.ToFloat64(metrics.ConsistencyMissing.WithLabelValues("orders")); got != 6 {
+		t.Fatalf("expected 6 missing orders, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.ConsistencyInconsistentStreams); got != 2 {
+		t.Fatalf("expected 2 inconsistent streams, got %v", got)
+	}
+	if last, running := reconciler.Last(); last == nil || running || last.StartedAt != report.StartedAt {
+		t.Fatalf("expected the report to be kept, got %+v %v", last, running)
+	}
+
+	timeline.reads = 0
+	report, err = reconciler.Check(context.Background(), true)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	// Pages of two events: three for 1200-1204 and one for 1400, instead of paging orders from the start
+	if timeline.reads != 4 {
+		t.Fatalf("expected the resync to read only the missing ranges, got %d pages", timeline.reads)
+	}
+	for _, sc := range report.Streams {
+		if want := map[string]int{"orders": 6, "payments": 1}[sc.StreamID]; sc.Resynced != want {
+			t.Fatalf("expected %d re-synced events of %s, got %+v", want, sc.StreamID, sc)
+		}
+	}
+	if _, ok := index.events["orders-1451"]; ok {
+		t.Fatal("expected events waiting for the sync not to be re-synced")
+	}
+
+	report, err = reconciler.Check(context.Background(), false)
+	if err != nil || !report.Consistent {
+		t.Fatalf("expected a consistent report after the resync, got %+v %v", report, err)
+	}
+	if got := testutil.ToFloat64(metrics.ConsistencyLag.WithLabelValues("orders")); got != 50 {
+		t.Fatalf("expected a lag of 50 orders, got %v", got)
+	}
+}
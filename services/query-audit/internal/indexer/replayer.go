@@ -0,0 +1,157 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	gosync "sync"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/deadletter"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
+)
+
+// DeadLetterStore keeps dead letters and reads them back for a retry
+type DeadLetterStore interface {
+	DeadLetterSink
+	Query(ctx context.Context, f deadletter.Filter) ([]deadletter.Letter, int64, error)
+	Delete(ctx context.Context, ids []string) error
+	Count(ctx context.Context) (int64, error)
+}
+
+// ReplayResult reports a retry of dead letters
+type ReplayResult struct {
+	Retried int      `json:"retried"`
+	Indexed int      `json:"indexed"`
+	Failed  []string `json:"failed"`
+}
+
+// Replayer converts dead letters again and indexes them, removing the letters that succeed
+type Replayer struct {
+	store  DeadLetterStore
+	bulk   *BulkIndexer
+	logger *slog.Logger
+}
+
+// NewReplayer creates a new replayer indexing with the settings of bulk
+func NewReplayer(store DeadLetterStore, bulk *BulkIndexer, logger *slog.Logger) *Replayer {
+	return &Replayer{store: store, bulk: bulk, logger: logger}
+}
+
+// List returns the dead letters matching f and the number of matching letters
+func (r *Replayer) List(ctx context.Context, f deadletter.Filter) ([]deadletter.Letter, int64, error) {
+	return r.store.Query(ctx, f)
+}
+
+// Count returns the number of dead letters
+func (r *Replayer) Count(ctx context.Context) (int64, error) {
+	return r.store.Count(ctx)
+}
+
+// Replay retries the dead letters with ids, or the deadletter.MaxQuerySize most recent letters when no
+// ids are given; letters that fail again stay in the store with one more attempt
+func (r *Replayer) Replay(ctx context.Context, ids []string) (ReplayResult, error) {
+	letters, _, err := r.store.Query(ctx, deadletter.Filter{IDs: ids, Size: deadletter.MaxQuerySize})
+	if err != nil {
+		return ReplayResult{}, err
+	}
+	result := ReplayResult{Retried: len(letters), Failed: []string{}}
+	if len(letters) == 0 {
+		return result, nil
+	}
+
+	rejected := &capturedLetters{}
+	bulk := r.bulk.fork().WithDeadLetters(rejected)
+	pending := map[string]deadletter.Letter{}
+	var failed []deadletter.Letter
+	for _, letter := range letters {
+		docID, doc, err := convertLetter(letter)
+		if err != nil {
+			letter.Stage, letter.Status, letter.Reason = deadletter.StageConvert, 0, err.Error()
+			failed = append(failed, letter)
+			continue
+		}
+		pending[letter.Index+"/"+docID] = letter
+		if err := bulk.IndexSourceDocument(ctx, letter.Index, docID, doc, letter.Document); err != nil {
+			return result, fmt.Errorf("failed to index dead letter %s: %w", letter.ID, err)
+		}
+	}
+	if err := bulk.Flush(ctx); err != nil {
+		return result, fmt.Errorf("failed to flush dead letters: %w", err)
+	}
+
+	for _, rejection := range rejected.letters {
+		letter, ok := pending[rejection.Index+"/"+rejection.DocumentID]
+		if !ok {
+			continue
+		}
+		delete(pending, rejection.Index+"/"+rejection.DocumentID)
+		letter.Stage, letter.Status, letter.Reason = rejection.Stage, rejection.Status, rejection.Reason
+		failed = append(failed, letter)
+	}
+
+	indexed := make([]string, 0, len(pending))
+	for _, letter := range pending {
+		indexed = append(indexed, letter.ID)
+	}
+	if err := r.store.Delete(ctx, indexed); err != nil {
+		return result, fmt.Errorf("failed to remove retried dead letters: %w", err)
+	}
+	result.Indexed = len(indexed)
+
+	for i := range failed {
+		// The letter keeps its ID, so the store adds this attempt to it
+		failed[i].Attempts = 1
+		result.Failed = append(result.Failed, failed[i].ID)
+	}
+	if err := r.store.Record(ctx, failed); err != nil {
+		return result, fmt.Errorf("failed to record dead letters again: %w", err)
+	}
+	if len(failed) > 0 {
+		r.logger.Warn("dead letters failed again", slog.Int("failed", len(failed)), slog.Int("indexed", result.Indexed))
+	}
+	return result, nil
+}
+
+// convertLetter converts the source document of a letter into the document of its index. Events are
+// converted without a redaction policy, as their payload was redacted before it was dead-lettered
+func convertLetter(letter deadletter.Letter) (string, interface{}, error) {
+	switch letter.Index {
+	case storage.EventsWriteAlias:
+		var event aevumclient.Event
+		if err := json.Unmarshal(letter.Document, &event); err != nil {
+			return "", nil, fmt.Errorf("invalid source document: %w", err)
+		}
+		indexed := convertEventToIndexed(event, nil)
+		if indexed.EventID == "" {
+			return "", nil, fmt.Errorf("event has no event_id")
+		}
+		return indexed.EventID, indexed, nil
+	case storage.DecisionsWriteAlias:
+		var decision aevumclient.Decision
+		if err := json.Unmarshal(letter.Document, &decision); err != nil {
+			return "", nil, fmt.Errorf("invalid source document: %w", err)
+		}
+		indexed := convertDecisionToIndexed(decision)
+		if indexed.DecisionID == "" {
+			return "", nil, fmt.Errorf("decision has no id")
+		}
+		return indexed.DecisionID, indexed, nil
+	default:
+		return "", nil, fmt.Errorf("unknown index %s", letter.Index)
+	}
+}
+
+// capturedLetters collects the documents Elasticsearch rejects during a replay
+type capturedLetters struct {
+	mu      gosync.Mutex
+	letters []deadletter.Letter
+}
+
+func (c *capturedLetters) Record(_ context.Context, letters []deadletter.Letter) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.letters = append(c.letters, letters...)
+	return nil
+}
@@ -0,0 +1,135 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/deadletter"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/storage"
+)
+
+// memoryDeadLetters keeps dead letters by ID, adding attempts like the Elasticsearch store
+type memoryDeadLetters struct {
+	letters map[string]deadletter.Letter
+}
+
+func (m *memoryDeadLetters) Record(_ context.Context, letters []deadletter.Letter) error {
+	for _, letter := range letters {
+		if letter.ID == "" {
+			letter.ID = deadletter.LetterID(letter.Index, letter.DocumentID, letter.Document)
+		}
+		if existing, ok := m.letters[letter.ID]; ok {
+			letter.Attempts += existing.Attempts
+		}
+		m.letters[letter.ID] = letter
+	}
+	return nil
+}
+
+func (m *memoryDeadLetters) Query(_ context.Context, f deadletter.Filter) ([]deadletter.Letter, int64, error) {
+	letters := []deadletter.Letter{}
+	for id, letter := range m.letters {
+		if len(f.IDs) > 0 && !contains(f.IDs, id) {
+			continue
+		}
+		letters = append(letters, letter)
+	}
+	return letters, int64(len(letters)), nil
+}
+
+func (m *memoryDeadLetters) Delete(_ context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(m.letters, id)
+	}
+	return nil
+}
+
+func (m *memoryDeadLetters) Count(context.Context) (int64, error) {
+	return int64(len(m.letters)), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRejectSourceAndIndexSourceDocumentKeepTheSource(t *testing.T) {
+	bulk := &statusBulk{statuses: map[string][]int{"evt-1": {http.StatusBadRequest}}}
+	store := &memoryDeadLetters{letters: map[string]deadletter.Letter{}}
+	bi := newStatusBulkIndexer(t, bulk, 10).WithDeadLetters(store)
+
+	source := map[string]interface{}{"event_id": "evt-1", "payload": map[string]interface{}{"email": "***"}}
+	if err := bi.IndexSourceDocument(context.Background(), storage.EventsWriteAlias, "evt-1", map[string]string{"converted": "yes"}, source); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := bi.Flush(context.Background()); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := bi.RejectSource(context.Background(), storage.DecisionsWriteAlias, map[string]interface{}{"status": "approved"}, "decision has no id"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	indexed := store.letters[storage.EventsWriteAlias+"/evt-1"]
+	if indexed.Stage != deadletter.StageIndex || string(indexed.Document) != `{"event_id":"evt-1","payload":{"email":"***"}}` {
+		t.Fatalf("expected the source document in the dead letter, got %+v", indexed)
+	}
+	if len(store.letters) != 2 {
+		t.Fatalf("expected two dead letters, got %+v", store.letters)
+	}
+	for _, letter := range store.letters {
+		if letter.Index == storage.DecisionsWriteAlias && (letter.Stage != deadletter.StageConvert || letter.Reason != "decision has no id") {
+			t.Fatalf("unexpected convert dead letter %+v", letter)
+		}
+	}
+}
+
+func TestReplayerIndexesLettersAndKeepsFailures(t *testing.T) {
+	bulk := &statusBulk{statuses: map[string][]int{"dec-bad": {http.StatusBadRequest}}}
+	store := &memoryDeadLetters{letters: map[string]deadletter.Letter{}}
+	for _, letter := range []deadletter.Letter{
+		{Index: storage.EventsWriteAlias, DocumentID: "evt-1", Document: json.RawMessage(`{"event_id":"evt-1","stream_id":"s-1","sequence_number":3}`), Stage: deadletter.StageIndex},
+		{Index: storage.DecisionsWriteAlias, DocumentID: "dec-bad", Document: json.RawMessage(`{"id":"dec-bad"}`), Stage: deadletter.StageIndex},
+		{Index: storage.EventsWriteAlias, Document: json.RawMessage(`{"stream_id":"s-1"}`), Stage: deadletter.StageConvert},
+	} {
+		letter.Attempts = 1
+		if err := store.Record(context.Background(), []deadletter.Letter{letter}); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+	replayer := NewReplayer(store, newStatusBulkIndexer(t, bulk, 10), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	result, err := replayer.Replay(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	sort.Strings(result.Failed)
+	if result.Retried != 3 || result.Indexed != 1 || len(result.Failed) != 2 || result.Failed[0] != storage.DecisionsWriteAlias+"/dec-bad" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if _, ok := store.letters[storage.EventsWriteAlias+"/evt-1"]; ok {
+		t.Fatal("expected the indexed letter to be removed")
+	}
+	failed := store.letters[storage.DecisionsWriteAlias+"/dec-bad"]
+	if failed.Attempts != 2 || failed.Status != http.StatusBadRequest || failed.Stage != deadletter.StageIndex {
+		t.Fatalf("expected the failed letter to count another attempt, got %+v", failed)
+	}
+	for id, letter := range store.letters {
+		if letter.Index == storage.EventsWriteAlias && (letter.Attempts != 2 || letter.Reason != "event has no event_id") {
+			t.Fatalf("unexpected unconvertible letter %s %+v", id, letter)
+		}
+	}
+
+	result, err = replayer.Replay(context.Background(), []string{storage.DecisionsWriteAlias + "/dec-bad"})
+	if err != nil || result.Retried != 1 || result.Indexed != 1 || len(store.letters) != 1 {
+		t.Fatalf("expected only the requested letter to be retried, got %+v %v", result, err)
+	}
+}
@@ -0,0 +1,359 @@
+package indexer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/clients"
+	syncpkg "github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/sync"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
+)
+
+type memoryStateStore struct {
+	mu     gosync.Mutex
+	states map[string]syncpkg.SyncState
+}
+
+func (s *memoryStateStore) Load(_ context.Context, serviceName string) (*syncpkg.SyncState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, ok := s.states[serviceName]; ok {
+		return &state, nil
+	}
+	return syncpkg.NewSyncState(serviceName), nil
+}
+
+func (s *memoryStateStore) Save(_ context.Context, state syncpkg.SyncState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.ServiceName] = state
+	return nil
+}
+
+func (s *memoryStateStore) List(context.Context) ([]syncpkg.SyncState, error) {
+	return nil, nil
+}
+
+// fakeTimeline serves stream listings and pages of two events, mimicking Event Timeline's cursor handling
+type fakeTimeline struct {
+	mu      gosync.Mutex
+	streams map[string]int64
+	broken  map[string]bool
+	reads   int
+}
+
+func (f *fakeTimeline) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path == "/api/v1/streams" {
+		streams := []aevumclient.StreamInfo{}
+		for id, latest := range f.streams {
+			streams = append(streams, aevumclient.StreamInfo{StreamID: id, LatestSequence: latest})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"streams": streams})
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v1/events/") {
+		var streamID string
+		var seq int64
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/events/")
+		dash := strings.LastIndex(id, "-")
+		streamID = id[:dash]
+		_, _ = fmt.Sscanf(id[dash+1:], "%d", &seq)
+		if seq < 1 || seq > f.streams[streamID] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"event": timelineEvent(streamID, seq)})
+		return
+	}
+
+	streamID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/streams/"), "/events")
+	f.reads++
+	if f.broken[streamID] {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	from := int64(1)
+	if raw := r.URL.Query().Get("from_sequence"); raw != "" {
+		_, _ = fmt.Sscanf(raw, "%d", &from)
+	}
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		_, _ = fmt.Sscanf(strings.TrimPrefix(cursor, streamID+"@"), "%d", &from)
+	}
+	events := []aevumclient.Event{}
+	for seq := from; seq <= f.streams[streamID] && seq < from+2; seq++ {
+		events = append(events, timelineEvent(streamID, seq))
+	}
+	next := ""
+	if from+2 <= f.streams[streamID] {
+		next = fmt.Sprintf("%s@%d", streamID, from+2)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"events": events, "next_cursor": next, "has_more": next != ""})
+}
+
+// timelineEvent is the event the fake Event Timeline holds at seq of a stream
+func timelineEvent(streamID string, seq int64) aevumclient.Event {
+	return aevumclient.Event{EventID: fmt.Sprintf("%s-%d", streamID, seq), StreamID: streamID, SequenceNumber: seq, OccurredAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// tenantTimelines serves a fake Event Timeline per tenant, picked by the tenant claim of the request's token
+type tenantTimelines map[string]*fakeTimeline
+
+func (f tenantTimelines) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID := aevumclient.DefaultTenant
+	if parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), "."); len(parts) == 3 {
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var claims struct {
+			TenantID string `json:"tenant_id"`
+		}
+		if json.Unmarshal(payload, &claims) == nil && claims.TenantID != "" {
+			tenantID = claims.TenantID
+		}
+	}
+	timeline, ok := f[tenantID]
+	if !ok {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	timeline.ServeHTTP(w, r)
+}
+
+type fakeBulk struct {
+	mu      gosync.Mutex
+	ids     []string
+	tenants []string
+}
+
+func (f *fakeBulk) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+		var action struct {
+			Index struct {
+				ID string `json:"_id"`
+			} `json:"index"`
+		}
+		if err := json.Unmarshal([]byte(line), &action); err == nil && action.Index.ID != "" {
+			f.ids = append(f.ids, action.Index.ID)
+			continue
+		}
+		var doc struct {
+			TenantID string `json:"tenant_id"`
+		}
+		if err := json.Unmarshal([]byte(line), &doc); err == nil {
+			f.tenants = append(f.tenants, doc.TenantID)
+		}
+	}
+	f.mu.Unlock()
+	_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+}
+
+func (f *fakeBulk) take() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := f.ids
+	f.ids, f.tenants = nil, nil
+	return ids
+}
+
+func TestEventIndexerSyncsStreamsIndependently(t *testing.T) {
+	timeline := &fakeTimeline{streams: map[string]int64{"orders": 3, "payments": 1}, broken: map[string]bool{}}
+	timelineServer := httptest.NewServer(timeline)
+	defer timelineServer.Close()
+	bulk := &fakeBulk{}
+	esServer := httptest.NewServer(bulk)
+	defer esServer.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{esServer.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := &memoryStateStore{states: map[string]syncpkg.SyncState{}}
+	ei := NewEventIndexer(clients.NewEventTimelineClient(timelineServer.URL), NewBulkIndexer(es, 50, logger), nil, logger).WithStreamStates(store, 2)
+
+	if _, err := ei.Sync(context.Background(), ""); err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	if ids := bulk.take(); len(ids) != 4 {
+		t.Fatalf("expected 4 indexed events, got %v", ids)
+	}
+	orders := store.states[StreamStatePrefix+"orders"]
+	if orders.LastSyncedCursor != "orders@3" || orders.LastSequence != 3 {
+		t.Fatalf("unexpected orders state %+v", orders)
+	}
+	if payments := store.states[StreamStatePrefix+"payments"]; payments.LastSequence != 1 || payments.SyncStatus != "synced" {
+		t.Fatalf("unexpected payments state %+v", payments)
+	}
+
+	reads := timeline.reads
+	if _, err := ei.Sync(context.Background(), ""); err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	if timeline.reads != reads {
+		t.Fatal("expected caught-up streams not to be read again")
+	}
+
+	timeline.mu.Lock()
+	timeline.streams["orders"] = 4
+	timeline.streams["refunds"] = 1
+	timeline.streams["broken"] = 1
+	timeline.broken["broken"] = true
+	timeline.mu.Unlock()
+
+	// A fresh indexer resumes from the persisted states, as after a restart
+	ei = NewEventIndexer(clients.NewEventTimelineClient(timelineServer.URL), NewBulkIndexer(es, 50, logger), nil, logger).WithStreamStates(store, 2)
+	_, err = ei.Sync(context.Background(), "")
+	if err == nil || !strings.Contains(err.Error(), "1 of 4 streams failed") {
+		t.Fatalf("expected the broken stream to fail the sync, got %v", err)
+	}
+	ids := bulk.take()
+	sort.Strings(ids)
+	if strings.Join(ids, ",") != "orders-4,refunds-1" {
+		t.Fatalf("expected only new events to be indexed, got %v", ids)
+	}
+	if broken := store.states[StreamStatePrefix+"broken"]; broken.SyncStatus != "failed" || broken.LastError == "" {
+		t.Fatalf("expected failed state for broken stream, got %+v", broken)
+	}
+}
+
+func TestEventIndexerRemainingAndReset(t *testing.T) {
+	timeline := &fakeTimeline{streams: map[string]int64{"orders": 3, "payments": 1}, broken: map[string]bool{}}
+	timelineServer := httptest.NewServer(timeline)
+	defer timelineServer.Close()
+	bulk := &fakeBulk{}
+	esServer := httptest.NewServer(bulk)
+	defer esServer.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{esServer.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := &memoryStateStore{states: map[string]syncpkg.SyncState{}}
+	ei := NewEventIndexer(clients.NewEventTimelineClient(timelineServer.URL), NewBulkIndexer(es, 50, logger), nil, logger).WithStreamStates(store, 2)
+	ctx := context.Background()
+
+	if remaining, err := ei.Remaining(ctx, ""); err != nil || remaining != 4 {
+		t.Fatalf("expected 4 remaining events, got %d, %v", remaining, err)
+	}
+	if _, err := ei.Sync(ctx, ""); err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	bulk.take()
+	if remaining, _ := ei.Remaining(ctx, ""); remaining != 0 {
+		t.Fatalf("expected nothing remaining, got %d", remaining)
+	}
+
+	if _, err := ei.Reset(ctx, syncpkg.Position{StreamID: "orders", Sequence: 1}); err != nil {
+		t.Fatalf("expected reset success, got %v", err)
+	}
+	if orders := store.states[StreamStatePrefix+"orders"]; orders.LastSequence != 1 || orders.LastSyncedCursor != "" {
+		t.Fatalf("unexpected reset state %+v", orders)
+	}
+	if remaining, _ := ei.Remaining(ctx, ""); remaining != 2 {
+		t.Fatalf("expected 2 remaining events, got %d", remaining)
+	}
+	if _, err := ei.Sync(ctx, ""); err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	ids := bulk.take()
+	sort.Strings(ids)
+	if strings.Join(ids, ",") != "orders-2,orders-3" {
+		t.Fatalf("expected events after the reset sequence, got %v", ids)
+	}
+
+	if _, err := ei.Reset(ctx, syncpkg.Position{}); err != nil {
+		t.Fatalf("expected reset of all streams, got %v", err)
+	}
+	if remaining, _ := ei.Remaining(ctx, ""); remaining != 4 {
+		t.Fatalf("expected all events remaining, got %d", remaining)
+	}
+
+	for _, position := range []syncpkg.Position{{Timestamp: time.Now()}, {Sequence: 2}, {StreamID: "orders", Sequence: -1}} {
+		if _, err := ei.Reset(ctx, position); !errors.Is(err, syncpkg.ErrInvalidPosition) {
+			t.Fatalf("expected invalid position for %+v, got %v", position, err)
+		}
+	}
+}
+
+func TestEventIndexerSyncsEveryTenant(t *testing.T) {
+	t.Setenv("EVENT_TIMELINE_JWT_SECRET", "secret")
+	timelines := tenantTimelines{
+		aevumclient.DefaultTenant: {streams: map[string]int64{"orders": 3}, broken: map[string]bool{}},
+		"acme":                    {streams: map[string]int64{"orders": 2, "invoices": 1}, broken: map[string]bool{}},
+	}
+	timelineServer := httptest.NewServer(timelines)
+	defer timelineServer.Close()
+	bulk := &fakeBulk{}
+	esServer := httptest.NewServer(bulk)
+	defer esServer.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{esServer.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := &memoryStateStore{states: map[string]syncpkg.SyncState{}}
+	client := clients.NewEventTimelineClient(timelineServer.URL)
+	ei := NewEventIndexer(client, NewBulkIndexer(es, 50, logger), nil, logger).WithStreamStates(store, 2).WithTenants([]string{"default", " acme "})
+	ctx := context.Background()
+
+	if remaining, err := ei.Remaining(ctx, ""); err != nil || remaining != 6 {
+		t.Fatalf("expected 6 remaining events over both tenants, got %d, %v", remaining, err)
+	}
+	if _, err := ei.Sync(ctx, ""); err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	bulk.mu.Lock()
+	tenants := append([]string(nil), bulk.tenants...)
+	bulk.mu.Unlock()
+	if ids := bulk.take(); len(ids) != 6 {
+		t.Fatalf("expected 6 indexed events, got %v", ids)
+	}
+	sort.Strings(tenants)
+	if strings.Join(tenants, ",") != ",,,acme,acme,acme" {
+		t.Fatalf("expected acme's events indexed with their tenant and the default tenant's without, got %v", tenants)
+	}
+	if orders := store.states[StreamStatePrefix+"orders"]; orders.LastSequence != 3 {
+		t.Fatalf("expected the default tenant to keep its state names, got %+v", orders)
+	}
+	if orders := store.states[StreamStateName("acme", "orders")]; orders.ServiceName != StreamStatePrefix+"T#acme#orders" || orders.LastSequence != 2 {
+		t.Fatalf("unexpected acme orders state %+v", orders)
+	}
+
+	if _, err := ei.Reset(ctx, syncpkg.Position{TenantID: "acme", StreamID: "orders", Sequence: 1}); err != nil {
+		t.Fatalf("expected reset success, got %v", err)
+	}
+	if remaining, _ := ei.Remaining(ctx, ""); remaining != 1 {
+		t.Fatalf("expected only acme's orders to be reset, got %d remaining", remaining)
+	}
+	if _, err := ei.Reset(ctx, syncpkg.Position{TenantID: "acme"}); !errors.Is(err, syncpkg.ErrInvalidPosition) {
+		t.Fatalf("expected tenant without stream to be invalid, got %v", err)
+	}
+
+	// Without tenants only the default tenant is synced, as before
+	ei = NewEventIndexer(client, NewBulkIndexer(es, 50, logger), nil, logger)
+	if _, err := ei.Sync(ctx, ""); err != nil {
+		t.Fatalf("expected sync success, got %v", err)
+	}
+	if ids := bulk.take(); len(ids) != 3 {
+		t.Fatalf("expected the default tenant's 3 events, got %v", ids)
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"crypto/x509"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/accesslog"
+)
+
+// AccessLogMiddleware records every request but health and readiness checks after the handler has run
+func AccessLogMiddleware(recorder *accesslog.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if recorder == nil || c.Request.URL.Path == "/health" || c.Request.URL.Path == "/ready" {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		decisionID := c.Param("decisionId")
+		if decisionID == "" {
+			decisionID = c.Query("decision_id")
+		}
+		entry := accesslog.Entry{
+			Time:       start.UTC(),
+			Method:     c.Request.Method,
+			Route:      route,
+			StreamID:   c.Query("stream_id"),
+			EventID:    c.Query("event_id"),
+			DecisionID: decisionID,
+			Status:     c.Writer.Status(),
+			RequestID:  c.GetString("request-id"),
+		}
+		if c.Request.TLS != nil && len(c.Request.TLS.PeerCertificates) > 0 {
+			entry.Subject = certificateSubject(c.Request.TLS.PeerCertificates[0])
+		}
+		recorder.Record(entry)
+	}
+}
+
+// certificateSubject names a client by its first URI, DNS or email SAN, falling back to the common name
+func certificateSubject(cert *x509.Certificate) string {
+	switch {
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0]
+	default:
+		return cert.Subject.CommonName
+	}
+}
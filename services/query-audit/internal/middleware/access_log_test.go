@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/accesslog"
+)
+
+type accessSink struct {
+	entries []accesslog.Entry
+}
+
+func (s *accessSink) WriteAccessLog(_ context.Context, entries []accesslog.Entry) error {
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func TestAccessLogMiddlewareRecordsRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sink := &accessSink{}
+	recorder := accesslog.NewRecorder(sink, 0, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.Use(AccessLogMiddleware(recorder))
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/v1/audit/:decisionId", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/api/v1/diff", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/d1", nil)
+	spiffe, _ := url.Parse("spiffe://aevum/auditor")
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{URIs: []*url.URL{spiffe}}}}
+	req.Header.Set("X-Request-ID", "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/diff?stream_id=orders", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "reporting"}}}}
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder.Run(ctx)
+
+	if len(sink.entries) != 2 {
+		t.Fatalf("expected two entries without the health check, got %+v", sink.entries)
+	}
+	audit := sink.entries[0]
+	if audit.Subject != "spiffe://aevum/auditor" || audit.Route != "/api/v1/audit/:decisionId" || audit.DecisionID != "d1" {
+		t.Fatalf("unexpected audit entry: %+v", audit)
+	}
+	if audit.Status != http.StatusNotFound || audit.RequestID != "req-1" {
+		t.Fatalf("unexpected audit status or request id: %+v", audit)
+	}
+	diff := sink.entries[1]
+	if diff.Subject != "reporting" || diff.StreamID != "orders" {
+		t.Fatalf("unexpected diff entry: %+v", diff)
+	}
+}
@@ -3,9 +3,11 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
 )
 
-// RequestIDMiddleware injects X-Request-ID header
+// RequestIDMiddleware injects X-Request-ID header and passes the ID on to the calls of the request
+// context to Event Timeline and Decision Engine
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
@@ -13,6 +15,7 @@ func RequestIDMiddleware() gin.HandlerFunc {
 			requestID = uuid.New().String()
 		}
 		c.Set("request-id", requestID)
+		c.Request = c.Request.WithContext(aevumclient.WithRequestID(c.Request.Context(), requestID))
 		c.Header("X-Request-ID", requestID)
 		c.Next()
 	}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
 )
 
 func TestRequestIDMiddlewareGeneratesIDWhenMissing(t *testing.T) {
@@ -32,7 +33,9 @@ func TestRequestIDMiddlewarePreservesHeader(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
 	r.Use(RequestIDMiddleware())
+	var propagated string
 	r.GET("/health", func(c *gin.Context) {
+		propagated = aevumclient.RequestID(c.Request.Context())
 		c.Status(http.StatusOK)
 	})
 
@@ -44,4 +47,7 @@ func TestRequestIDMiddlewarePreservesHeader(t *testing.T) {
 	if got := w.Header().Get("X-Request-ID"); got != "fixed-request-id" {
 		t.Fatalf("expected X-Request-ID to be preserved, got %s", got)
 	}
+	if propagated != "fixed-request-id" {
+		t.Fatalf("expected the request ID on the request context, got %q", propagated)
+	}
 }
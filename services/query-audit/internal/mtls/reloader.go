@@ -0,0 +1,173 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader serves a certificate, key and optional CA bundle from disk,
+// reloading them when a file's modification time changes
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime map[string]time.Time
+}
+
+// NewReloader loads the files once and fails if they are unusable
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("tls certificate and key files must be set together")
+	}
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile, modTime: map[string]time.Time{}}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.reloadLocked(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Certificate returns the current key pair, or nil when none is configured
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reloadIfChanged()
+	return r.cert
+}
+
+// CertPool returns the current CA bundle, or nil when none is configured
+func (r *Reloader) CertPool() *x509.CertPool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reloadIfChanged()
+	return r.pool
+}
+
+// ServerConfig returns a server TLS config; with a CA bundle, client
+// certificates are requested (or required) and verified against it
+func (r *Reloader) ServerConfig(requireClientCert bool) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+	}
+	if r.caFile == "" {
+		return cfg
+	}
+	cfg.ClientAuth = tls.RequestClientCert
+	if requireClientCert {
+		cfg.ClientAuth = tls.RequireAnyClientCert
+	}
+	cfg.VerifyConnection = func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return nil
+		}
+		return r.verify(state.PeerCertificates, "", x509.ExtKeyUsageClientAuth)
+	}
+	return cfg
+}
+
+// ClientConfig returns a client TLS config presenting the current key pair;
+// with a CA bundle, the server certificate is verified against it instead of
+// the system roots
+func (r *Reloader) ClientConfig() *tls.Config {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if r.certFile != "" {
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		}
+	}
+	if r.caFile == "" {
+		return cfg
+	}
+	// Standard verification is replaced, not skipped: VerifyConnection checks
+	// the chain and host name against the bundle as it is at handshake time
+	cfg.InsecureSkipVerify = true
+	cfg.VerifyConnection = func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return errors.New("server presented no certificate")
+		}
+		return r.verify(state.PeerCertificates, state.ServerName, x509.ExtKeyUsageServerAuth)
+	}
+	return cfg
+}
+
+// verify checks chain against the current CA bundle
+func (r *Reloader) verify(chain []*x509.Certificate, dnsName string, usage x509.ExtKeyUsage) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		DNSName:       dnsName,
+		Roots:         r.CertPool(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify peer certificate: %w", err)
+	}
+	return nil
+}
+
+// reloadIfChanged reloads when a file changed; a failed reload keeps the
+// previous material since rotated files are often replaced one at a time
+func (r *Reloader) reloadIfChanged() {
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err == nil && !info.ModTime().Equal(r.modTime[file]) {
+			_ = r.reloadLocked()
+			return
+		}
+	}
+}
+
+// reloadLocked reads all files; callers hold mu
+func (r *Reloader) reloadLocked() error {
+	modTime := map[string]time.Time{}
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		modTime[file] = info.ModTime()
+	}
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load tls key pair: %w", err)
+		}
+		cert = &pair
+	}
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		raw, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read ca file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(raw) {
+			return fmt.Errorf("ca file %s contains no certificates", r.caFile)
+		}
+	}
+	r.cert, r.pool, r.modTime = cert, pool, modTime
+	return nil
+}
@@ -0,0 +1,176 @@
+package mtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// writeLeaf issues a certificate for dnsName and writes it with its key to dir
+func (ca testCA) writeLeaf(t *testing.T, dir, name string, serial int64, usage x509.ExtKeyUsage, dnsName string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     []string{dnsName},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	writeFile(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certFile, keyFile
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutualTLSHandshakeAndReload(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	writeFile(t, caFile, ca.pem)
+	serverCert, serverKey := ca.writeLeaf(t, dir, "server", 2, x509.ExtKeyUsageServerAuth, "localhost")
+	clientCert, clientKey := ca.writeLeaf(t, dir, "client", 3, x509.ExtKeyUsageClientAuth, "query-audit.aevum.svc")
+
+	serverCerts, err := NewReloader(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatalf("expected server certificates to load, got %v", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].DNSNames[0]))
+	}))
+	server.TLS = serverCerts.ServerConfig(true)
+	server.StartTLS()
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	url := "https://localhost:" + port
+
+	clientCerts, err := NewReloader(clientCert, clientKey, caFile)
+	if err != nil {
+		t.Fatalf("expected client certificates to load, got %v", err)
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientCerts.ClientConfig()}}
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("expected mutual tls request to succeed, got %v", err)
+	}
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	_ = resp.Body.Close()
+	if string(body[:n]) != "query-audit.aevum.svc" {
+		t.Fatalf("expected server to see client identity, got %q", body[:n])
+	}
+
+	anonymous, err := NewReloader("", "", caFile)
+	if err != nil {
+		t.Fatalf("expected ca-only reloader, got %v", err)
+	}
+	if _, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: anonymous.ClientConfig()}}).Get(url); err == nil {
+		t.Fatal("expected server to require a client certificate")
+	}
+
+	// Rotate the client to a certificate from another CA; the server rejects it
+	// on the next connection without restarting either side
+	rogue := newTestCA(t)
+	rogueCert, rogueKey := rogue.writeLeaf(t, t.TempDir(), "client", 4, x509.ExtKeyUsageClientAuth, "query-audit.aevum.svc")
+	later := time.Now().Add(time.Minute)
+	for _, pair := range [][2]string{{rogueCert, clientCert}, {rogueKey, clientKey}} {
+		raw, _ := os.ReadFile(pair[0])
+		writeFile(t, pair[1], raw)
+		_ = os.Chtimes(pair[1], later, later)
+	}
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: clientCerts.ClientConfig()}}
+	if _, err := client.Get(url); err == nil {
+		t.Fatal("expected rotated certificate from an untrusted ca to be rejected")
+	}
+}
+
+func TestClientConfigVerifiesServerName(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	writeFile(t, caFile, ca.pem)
+	serverCert, serverKey := ca.writeLeaf(t, dir, "server", 2, x509.ExtKeyUsageServerAuth, "event-timeline.aevum.svc")
+
+	serverCerts, err := NewReloader(serverCert, serverKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = serverCerts.ServerConfig(false)
+	server.StartTLS()
+	defer server.Close()
+
+	roots, _ := NewReloader("", "", caFile)
+	if _, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: roots.ClientConfig()}}).Get(server.URL); err == nil {
+		t.Fatal("expected host name mismatch to fail verification")
+	}
+}
+
+func TestNewReloaderRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewReloader("cert.pem", "", ""); err == nil {
+		t.Fatal("expected error for certificate without key")
+	}
+	empty := filepath.Join(dir, "ca.crt")
+	writeFile(t, empty, []byte("no certificates here"))
+	if _, err := NewReloader("", "", empty); err == nil {
+		t.Fatal("expected error for empty ca bundle")
+	}
+}
@@ -0,0 +1,88 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ClientObserver records a span and metrics for every call of the service clients
+type ClientObserver struct {
+	metrics *Metrics
+	tracer  trace.Tracer
+}
+
+// NewClientObserver creates an observer recording into metrics and the global tracer provider
+func NewClientObserver(metrics *Metrics) *ClientObserver {
+	return &ClientObserver{metrics: metrics, tracer: otel.Tracer("query-audit/aevumclient")}
+}
+
+// StartCall starts the client span of a call
+func (o *ClientObserver) StartCall(ctx context.Context, call aevumclient.Call) context.Context {
+	ctx, _ = o.tracer.Start(ctx, call.Service+" "+call.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", call.Method),
+			attribute.String("server.address", call.Host),
+			attribute.String("url.path", call.Path),
+			attribute.String("aevum.request_id", call.RequestID),
+		))
+	return ctx
+}
+
+// StartAttempt sends the trace context of the call with each attempt
+func (o *ClientObserver) StartAttempt(ctx context.Context, _ aevumclient.Call, attempt int, req *http.Request) {
+	if attempt > 1 {
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+}
+
+// EndCall ends the span of a call and counts it
+func (o *ClientObserver) EndCall(ctx context.Context, call aevumclient.Call, result aevumclient.CallResult) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int("aevum.attempts", result.Attempts))
+	if status := aevumclient.StatusCode(result.Err); status != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+	}
+	outcome := "success"
+	switch {
+	case result.Err != nil && result.Attempts == 0:
+		outcome = "rejected"
+	case result.Err != nil:
+		outcome = "error"
+	}
+	if result.Err != nil {
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+	}
+	span.End()
+
+	if o.metrics == nil {
+		return
+	}
+	o.metrics.ClientCallsTotal.WithLabelValues(call.Service, call.Operation, outcome).Inc()
+	o.metrics.ClientCallDuration.WithLabelValues(call.Service, call.Operation).Observe(result.Duration.Seconds())
+	if result.Attempts > 1 {
+		o.metrics.ClientRetriesTotal.WithLabelValues(call.Service, call.Operation).Add(float64(result.Attempts - 1))
+	}
+	if result.BudgetExhausted {
+		o.metrics.ClientRetriesDenied.WithLabelValues(call.Service, call.Operation).Inc()
+	}
+	if result.Rejected {
+		o.metrics.ClientBreakerRejections.WithLabelValues(call.Service, call.Operation).Inc()
+	}
+}
+
+// BreakerChanged sets the breaker state gauge of the host
+func (o *ClientObserver) BreakerChanged(service, host string, state aevumclient.BreakerState) {
+	if o.metrics != nil {
+		o.metrics.ClientBreakerState.WithLabelValues(service, host).Set(float64(state))
+	}
+}
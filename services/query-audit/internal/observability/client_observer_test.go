@@ -0,0 +1,100 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// metricValue returns the value of the counter or gauge name with labels, or -1 when it is not found
+func metricValue(t *testing.T, m *Metrics, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := m.Registry.Gather()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if want, ok := labels[label.GetName()]; ok && want != label.GetValue() {
+					continue metrics
+				}
+			}
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue()
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+	return -1
+}
+
+func TestClientObserverTracesAndCountsCalls(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+
+	var calls atomic.Int32
+	traceparents := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("Traceparent")
+		if calls.Add(1) == 1 || r.URL.Path == "/api/v1/events/missing" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"streams":[]}`))
+	}))
+	defer server.Close()
+
+	metrics := NewMetrics()
+	observer := &ClientObserver{metrics: metrics, tracer: tp.Tracer("test")}
+	client := aevumclient.NewEventTimelineClient(server.URL).
+		WithRetryPolicy(aevumclient.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}).
+		WithBreakerPolicy(aevumclient.BreakerPolicy{FailureThreshold: 2, OpenTimeout: time.Hour}).
+		WithObserver(observer)
+
+	if _, err := client.ListStreams(context.Background()); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if _, err := client.GetEvent(context.Background(), "missing"); aevumclient.StatusCode(err) != http.StatusServiceUnavailable {
+		t.Fatalf("expected a 503, got %v", err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 || ended[0].Name() != "event-timeline ListStreams" || ended[1].Status().Code != codes.Error {
+		t.Fatalf("unexpected spans %+v", ended)
+	}
+	// Both attempts of ListStreams send the trace ID of its span
+	traceID := ended[0].SpanContext().TraceID().String()
+	for i := 0; i < 2; i++ {
+		if traceparent := <-traceparents; len(traceparent) < 35 || traceparent[3:35] != traceID {
+			t.Fatalf("expected trace %s in the traceparent header, got %q", traceID, traceparent)
+		}
+	}
+
+	success := map[string]string{"service": "event-timeline", "operation": "ListStreams", "result": "success"}
+	if v := metricValue(t, metrics, "aevum_client_calls_total", success); v != 1 {
+		t.Fatalf("expected one successful call, got %v", v)
+	}
+	if v := metricValue(t, metrics, "aevum_client_retries_total", map[string]string{"operation": "ListStreams"}); v != 1 {
+		t.Fatalf("expected one retry, got %v", v)
+	}
+	if v := metricValue(t, metrics, "aevum_client_breaker_state", map[string]string{"service": "event-timeline"}); v != float64(aevumclient.BreakerOpen) {
+		t.Fatalf("expected the open breaker to be reported, got %v", v)
+	}
+}
@@ -0,0 +1,116 @@
+package observability
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds the Prometheus metrics of the service
+type Metrics struct {
+	Registry              *prometheus.Registry
+	IndexedDocumentsTotal *prometheus.CounterVec
+	FailedDocumentsTotal  *prometheus.CounterVec
+	BulkRetriesTotal      *prometheus.CounterVec
+	BulkRequestDuration   prometheus.Histogram
+	DeadLetters           prometheus.Gauge
+
+	ConsistencyMissing             *prometheus.GaugeVec
+	ConsistencyMismatched          *prometheus.GaugeVec
+	ConsistencyLag                 *prometheus.GaugeVec
+	ConsistencyInconsistentStreams prometheus.Gauge
+	ConsistencyLastCheck           prometheus.Gauge
+
+	ClientCallsTotal        *prometheus.CounterVec
+	ClientCallDuration      *prometheus.HistogramVec
+	ClientRetriesTotal      *prometheus.CounterVec
+	ClientRetriesDenied     *prometheus.CounterVec
+	ClientBreakerState      *prometheus.GaugeVec
+	ClientBreakerRejections *prometheus.CounterVec
+}
+
+// NewMetrics creates the metrics in their own registry
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		IndexedDocumentsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aevum_indexed_documents_total",
+			Help: "Documents indexed into Elasticsearch",
+		}, []string{"index"}),
+		FailedDocumentsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aevum_failed_documents_total",
+			Help: "Documents that could not be indexed, by reason: rejected by Elasticsearch, out of retries or unconvertible",
+		}, []string{"index", "reason"}),
+		BulkRetriesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aevum_bulk_retried_documents_total",
+			Help: "Documents sent again after a retriable bulk failure",
+		}, []string{"index"}),
+		BulkRequestDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name: "aevum_bulk_request_duration_seconds",
+			Help: "Bulk request duration seconds",
+		}),
+		DeadLetters: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "aevum_dead_letters",
+			Help: "Documents in the dead-letter index waiting to be retried",
+		}),
+		ConsistencyMissing: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "aevum_consistency_missing_events",
+			Help: "Events of a stream synced but not found in the events index at the last consistency check",
+		}, []string{"stream"}),
+		ConsistencyMismatched: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "aevum_consistency_mismatched_events",
+			Help: "Sampled events of a stream whose indexed content differs from Event Timeline at the last consistency check",
+		}, []string{"stream"}),
+		ConsistencyLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "aevum_consistency_lag_events",
+			Help: "Events of a stream not synced yet at the last consistency check",
+		}, []string{"stream"}),
+		ConsistencyInconsistentStreams: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "aevum_consistency_inconsistent_streams",
+			Help: "Streams with missing or mismatched events at the last consistency check",
+		}),
+		ConsistencyLastCheck: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "aevum_consistency_last_check_timestamp_seconds",
+			Help: "Unix time the last consistency check finished",
+		}),
+		ClientCallsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aevum_client_calls_total",
+			Help: "Calls to Event Timeline and Decision Engine, by result: success, error or rejected by an open breaker",
+		}, []string{"service", "operation", "result"}),
+		ClientCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "aevum_client_call_duration_seconds",
+			Help: "Duration of calls to other services, including their retries",
+		}, []string{"service", "operation"}),
+		ClientRetriesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aevum_client_retries_total",
+			Help: "Attempts of calls to other services after their first",
+		}, []string{"service", "operation"}),
+		ClientRetriesDenied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aevum_client_retries_denied_total",
+			Help: "Failed attempts not retried because the retry budget of the client was spent",
+		}, []string{"service", "operation"}),
+		ClientBreakerState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "aevum_client_breaker_state",
+			Help: "Circuit breaker of the host of a service: 0 closed, 1 half-open, 2 open",
+		}, []string{"service", "host"}),
+		ClientBreakerRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aevum_client_breaker_rejections_total",
+			Help: "Attempts not sent because the circuit breaker of the host was open",
+		}, []string{"service", "operation"}),
+	}
+	m.Registry.MustRegister(
+		m.IndexedDocumentsTotal,
+		m.FailedDocumentsTotal,
+		m.BulkRetriesTotal,
+		m.BulkRequestDuration,
+		m.DeadLetters,
+		m.ConsistencyMissing,
+		m.ConsistencyMismatched,
+		m.ConsistencyLag,
+		m.ConsistencyInconsistentStreams,
+		m.ConsistencyLastCheck,
+		m.ClientCallsTotal,
+		m.ClientCallDuration,
+		m.ClientRetriesTotal,
+		m.ClientRetriesDenied,
+		m.ClientBreakerState,
+		m.ClientBreakerRejections,
+	)
+	return m
+}
@@ -0,0 +1,192 @@
+package redaction
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Mode selects how a redacted value is replaced
+type Mode string
+
+const (
+	// ModeMask replaces the value with MaskedValue
+	ModeMask Mode = "mask"
+	// ModeHash replaces the value with a salted SHA-256 of its JSON encoding
+	ModeHash Mode = "hash"
+	// ModeDrop removes the key
+	ModeDrop Mode = "drop"
+
+	// MaskedValue is the replacement used by ModeMask
+	MaskedValue = "***"
+
+	anyEventType = "*"
+)
+
+// Rule selects payload values by dot-separated paths or key patterns
+type Rule struct {
+	Paths       []string `json:"paths"`
+	KeyPatterns []string `json:"key_patterns"`
+	Mode        Mode     `json:"mode"`
+
+	keys []*regexp.Regexp
+}
+
+// Policy holds redaction rules per event type, "*" applying to every type
+type Policy struct {
+	Rules    map[string][]Rule `json:"rules"`
+	HashSalt string            `json:"hash_salt"`
+}
+
+// LoadPolicy reads a redaction policy from a JSON file
+func LoadPolicy(path string) (*Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redaction policy: %w", err)
+	}
+	return ParsePolicy(raw)
+}
+
+// ParsePolicy decodes and validates a redaction policy
+func ParsePolicy(raw []byte) (*Policy, error) {
+	var policy Policy
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		return nil, fmt.Errorf("failed to decode redaction policy: %w", err)
+	}
+	for eventType, rules := range policy.Rules {
+		for i := range rules {
+			rule := &rules[i]
+			switch rule.Mode {
+			case ModeMask, ModeHash, ModeDrop:
+			default:
+				return nil, fmt.Errorf("redaction policy %q: unknown mode %q", eventType, rule.Mode)
+			}
+			if len(rule.Paths) == 0 && len(rule.KeyPatterns) == 0 {
+				return nil, fmt.Errorf("redaction policy %q: rule needs paths or key_patterns", eventType)
+			}
+			for _, path := range rule.Paths {
+				if path == "" || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+					return nil, fmt.Errorf("redaction policy %q: invalid path %q", eventType, path)
+				}
+			}
+			for _, pattern := range rule.KeyPatterns {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("redaction policy %q: key pattern %q: %w", eventType, pattern, err)
+				}
+				rule.keys = append(rule.keys, re)
+			}
+		}
+	}
+	return &policy, nil
+}
+
+// Empty reports whether the policy redacts nothing
+func (p *Policy) Empty() bool {
+	if p == nil {
+		return true
+	}
+	for _, rules := range p.Rules {
+		if len(rules) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Redact applies the rules for eventType to payload in place and returns it
+func (p *Policy) Redact(eventType string, payload map[string]interface{}) map[string]interface{} {
+	if p.Empty() {
+		return payload
+	}
+	rules := append([]Rule{}, p.Rules[anyEventType]...)
+	if eventType != anyEventType {
+		rules = append(rules, p.Rules[eventType]...)
+	}
+	// Paths run first so a value dropped by path is not also matched by a key pattern
+	for _, rule := range rules {
+		for _, path := range rule.Paths {
+			p.redactPath(payload, strings.Split(path, "."), rule.Mode)
+		}
+	}
+	for _, rule := range rules {
+		if len(rule.keys) > 0 {
+			p.redactKeys(payload, rule)
+		}
+	}
+	return payload
+}
+
+// Value returns the replacement for a single redacted value
+func (p *Policy) Value(mode Mode, value interface{}) interface{} {
+	if mode != ModeHash {
+		return MaskedValue
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded = []byte(fmt.Sprint(value))
+	}
+	sum := sha256.Sum256(append([]byte(p.HashSalt), encoded...))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// redactPath replaces the value at segments, crossing arrays element-wise
+func (p *Policy) redactPath(value interface{}, segments []string, mode Mode) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		child, ok := typed[segments[0]]
+		if !ok {
+			return
+		}
+		if len(segments) == 1 {
+			p.replace(typed, segments[0], child, mode)
+			return
+		}
+		p.redactPath(child, segments[1:], mode)
+	case []interface{}:
+		for _, item := range typed {
+			p.redactPath(item, segments, mode)
+		}
+	}
+}
+
+// redactKeys replaces values of keys matching the rule at any depth
+func (p *Policy) redactKeys(value interface{}, rule Rule) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if matchesAny(rule.keys, key) {
+				p.replace(typed, key, child, rule.Mode)
+				continue
+			}
+			p.redactKeys(child, rule)
+		}
+	case []interface{}:
+		for _, item := range typed {
+			p.redactKeys(item, rule)
+		}
+	}
+}
+
+// replace applies mode to parent[key]
+func (p *Policy) replace(parent map[string]interface{}, key string, value interface{}, mode Mode) {
+	if mode == ModeDrop {
+		delete(parent, key)
+		return
+	}
+	parent[key] = p.Value(mode, value)
+}
+
+// matchesAny reports whether any pattern matches key
+func matchesAny(patterns []*regexp.Regexp, key string) bool {
+	for _, re := range patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
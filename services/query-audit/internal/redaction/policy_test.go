@@ -0,0 +1,76 @@
+package redaction
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedactModes(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`{
+		"hash_salt": "pepper",
+		"rules": {
+			"*": [{"key_patterns": ["(?i)^ssn$"], "mode": "hash"}],
+			"signup": [
+				{"paths": ["customer.email"], "mode": "mask"},
+				{"paths": ["addresses.street"], "mode": "drop"}
+			]
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("expected valid policy, got %v", err)
+	}
+
+	payload := map[string]interface{}{
+		"customer": map[string]interface{}{"email": "a@b.test", "SSN": "123-45-6789", "name": "Ada"},
+		"addresses": []interface{}{
+			map[string]interface{}{"street": "1 Main St", "city": "Berlin"},
+		},
+	}
+	redacted := policy.Redact("signup", payload)
+
+	customer := redacted["customer"].(map[string]interface{})
+	if customer["email"] != MaskedValue {
+		t.Fatalf("expected masked email, got %v", customer["email"])
+	}
+	hashed, _ := customer["SSN"].(string)
+	if !strings.HasPrefix(hashed, "sha256:") || hashed != policy.Value(ModeHash, "123-45-6789") {
+		t.Fatalf("expected stable hash, got %v", customer["SSN"])
+	}
+	if customer["name"] != "Ada" {
+		t.Fatalf("expected untouched name, got %v", customer["name"])
+	}
+	address := redacted["addresses"].([]interface{})[0].(map[string]interface{})
+	if _, ok := address["street"]; ok || address["city"] != "Berlin" {
+		t.Fatalf("expected dropped street, got %v", address)
+	}
+
+	other := policy.Redact("order_placed", map[string]interface{}{"customer": map[string]interface{}{"email": "a@b.test"}})
+	if other["customer"].(map[string]interface{})["email"] != "a@b.test" {
+		t.Fatal("expected signup rules not to apply to other event types")
+	}
+	if (*Policy)(nil).Redact("signup", payload)["customer"] == nil {
+		t.Fatal("expected nil policy to return payload")
+	}
+}
+
+func TestLoadPolicyRejectsInvalidRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redaction.json")
+	for _, raw := range []string{
+		`{"rules":{"*":[{"paths":["a"],"mode":"shuffle"}]}}`,
+		`{"rules":{"*":[{"mode":"mask"}]}}`,
+		`{"rules":{"*":[{"paths":[".a"],"mode":"mask"}]}}`,
+		`{"rules":{"*":[{"key_patterns":["("],"mode":"hash"}]}}`,
+	} {
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPolicy(path); err == nil {
+			t.Fatalf("expected error for %s", raw)
+		}
+	}
+	if _, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
@@ -9,20 +9,21 @@ import (
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
-	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/clients"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/storage"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/pkg/aevumclient"
 )
 
 // AuditBuilder builds complete causal chains
 type AuditBuilder struct {
 	esClient       *elasticsearch.Client
-	eventClient    *clients.EventTimelineClient
-	decisionClient *clients.DecisionEngineClient
+	eventClient    *aevumclient.EventTimelineClient
+	decisionClient *aevumclient.DecisionEngineClient
 	logger         *slog.Logger
 }
 
 // NewAuditBuilder creates a new audit builder
-func NewAuditBuilder(esClient *elasticsearch.Client, eventClient *clients.EventTimelineClient, decisionClient *clients.DecisionEngineClient, logger *slog.Logger) *AuditBuilder {
+func NewAuditBuilder(esClient *elasticsearch.Client, eventClient *aevumclient.EventTimelineClient, decisionClient *aevumclient.DecisionEngineClient, logger *slog.Logger) *AuditBuilder {
 	return &AuditBuilder{
 		esClient:       esClient,
 		eventClient:    eventClient,
@@ -40,7 +41,7 @@ func (ab *AuditBuilder) Build(ctx context.Context, decisionID string) (*domain.A
 	}
 
 	// Fetch event from Event Timeline Service
-	eventRaw, err := ab.eventClient.GetEvent(ctx, decision.EventID)
+	source, err := ab.eventClient.GetEvent(ctx, decision.EventID)
 	if err != nil {
 		ab.logger.Warn("failed to fetch event", slog.String("event_id", decision.EventID), slog.Any("error", err))
 	}
@@ -51,29 +52,30 @@ func (ab *AuditBuilder) Build(ctx context.Context, decisionID string) (*domain.A
 		ab.logger.Warn("failed to fetch rule", slog.String("rule_id", decision.RuleID), slog.Any("error", err))
 	}
 
-	// Build chain - pass raw event and convert in function
-	chain := ab.buildChain(decision, eventRaw, rule)
+	chain := ab.buildChain(decision, source)
 
-	// Convert event map to IndexedEvent if present
 	var event *domain.IndexedEvent
-	if eventRaw != nil {
+	if source != nil {
 		event = &domain.IndexedEvent{
-			EventID:  fmt.Sprint((*eventRaw)["event_id"]),
-			StreamID: fmt.Sprint((*eventRaw)["stream_id"]),
+			EventID:  source.EventID,
+			StreamID: source.StreamID,
 		}
 	}
 
-	return &domain.AuditTrail{
-		Decision:       decision,
-		Event:          event,
-		RuleDefinition: rule,
-		Chain:          chain,
-	}, nil
+	trail := &domain.AuditTrail{
+		Decision: decision,
+		Event:    event,
+		Chain:    chain,
+	}
+	if rule != nil {
+		trail.RuleDefinition = rule
+	}
+	return trail, nil
 }
 
 // fetchDecision fetches a decision from ES
 func (ab *AuditBuilder) fetchDecision(ctx context.Context, decisionID string) (*domain.IndexedDecision, error) {
-	res, err := ab.esClient.Get("aevum-decisions", decisionID, ab.esClient.Get.WithContext(ctx))
+	res, err := ab.esClient.Get(storage.DecisionsAlias, decisionID, ab.esClient.Get.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -105,20 +107,20 @@ func (ab *AuditBuilder) fetchDecision(ctx context.Context, decisionID string) (*
 }
 
 // buildChain builds the causal chain
-func (ab *AuditBuilder) buildChain(decision *domain.IndexedDecision, eventRaw *map[string]interface{}, rule interface{}) []domain.AuditStep {
+func (ab *AuditBuilder) buildChain(decision *domain.IndexedDecision, event *aevumclient.Event) []domain.AuditStep {
 	chain := []domain.AuditStep{}
 
 	// Event step
-	if eventRaw != nil {
+	if event != nil {
 		chain = append(chain, domain.AuditStep{
 			Type:        "event_occurred",
 			Description: "Event occurred in stream",
 			Data: map[string]interface{}{
-				"event_id":   (*eventRaw)["event_id"],
-				"stream_id":  (*eventRaw)["stream_id"],
-				"event_type": (*eventRaw)["event_type"],
+				"event_id":   event.EventID,
+				"stream_id":  event.StreamID,
+				"event_type": event.EventType,
 			},
-			Timestamp: time.Now(),
+			Timestamp: event.OccurredAt,
 		})
 	}
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/storage"
 )
 
 // DiffEngine compares decision sets
@@ -76,7 +77,7 @@ func (de *DiffEngine) queryDecisions(ctx context.Context, before time.Time, rule
 	}
 
 	body, _ := json.Marshal(q)
-	res, err := de.client.Search(de.client.Search.WithContext(ctx), de.client.Search.WithIndex("aevum-decisions"), de.client.Search.WithBody(bytes.NewBufferString(string(body))))
+	res, err := de.client.Search(de.client.Search.WithContext(ctx), de.client.Search.WithIndex(storage.DecisionsAlias), de.client.Search.WithBody(bytes.NewBufferString(string(body))))
 	if err != nil {
 		return nil, err
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/storage"
 )
 
 // Engine performs full-text search
@@ -54,11 +55,11 @@ func (e *Engine) Search(ctx context.Context, query string, searchType string, st
 func searchIndexes(searchType string) []string {
 	switch searchType {
 	case "events":
-		return []string{"aevum-events"}
+		return []string{storage.EventsAlias}
 	case "decisions":
-		return []string{"aevum-decisions"}
+		return []string{storage.DecisionsAlias}
 	default:
-		return []string{"aevum-events", "aevum-decisions"}
+		return []string{storage.EventsAlias, storage.DecisionsAlias}
 	}
 }
 
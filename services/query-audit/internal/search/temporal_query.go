@@ -9,6 +9,7 @@ import (
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/domain"
+	"github.com/kushal-sharma-works/aevum-platform/services/query-audit/internal/storage"
 )
 
 // TemporalQuery executes time-range queries
@@ -77,11 +78,11 @@ func (tq *TemporalQuery) Execute(ctx context.Context, query *domain.TemporalQuer
 func temporalIndexes(queryType string) []string {
 	switch queryType {
 	case "events":
-		return []string{"aevum-events"}
+		return []string{storage.EventsAlias}
 	case "decisions":
-		return []string{"aevum-decisions"}
+		return []string{storage.DecisionsAlias}
 	default:
-		return []string{"aevum-events", "aevum-decisions"}
+		return []string{storage.EventsAlias, storage.DecisionsAlias}
 	}
 }
 
@@ -179,7 +180,7 @@ func (cq *CorrelationQuery) Execute(ctx context.Context, query *domain.Correlati
 	}
 
 	body, _ := json.Marshal(q)
-	res, err := cq.client.Search(cq.client.Search.WithContext(ctx), cq.client.Search.WithIndex("aevum-events", "aevum-decisions"), cq.client.Search.WithBody(bytes.NewBufferString(string(body))))
+	res, err := cq.client.Search(cq.client.Search.WithContext(ctx), cq.client.Search.WithIndex(storage.EventsAlias, storage.DecisionsAlias), cq.client.Search.WithBody(bytes.NewBufferString(string(body))))
 	if err != nil {
 		return nil, fmt.Errorf("correlation query failed: %w", err)
 	}
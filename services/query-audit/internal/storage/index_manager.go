@@ -21,13 +21,18 @@ func NewIndexManager(esClient *elasticsearch.Client) *IndexManager {
 
 // CreateIndexes creates all required indexes
 func (im *IndexManager) CreateIndexes(ctx context.Context) error {
-	if err := im.createIndex(ctx, "aevum-events", EventMapping); err != nil {
+	for _, vi := range VersionedIndexes {
+		if err := im.ensureVersionedIndex(ctx, vi); err != nil {
+			return err
+		}
+	}
+	if err := im.createIndex(ctx, "aevum-sync-state", SyncStateMapping); err != nil {
 		return err
 	}
-	if err := im.createIndex(ctx, "aevum-decisions", DecisionMapping); err != nil {
+	if err := im.createIndex(ctx, "aevum-access-log", AccessLogMapping); err != nil {
 		return err
 	}
-	if err := im.createIndex(ctx, "aevum-sync-state", SyncStateMapping); err != nil {
+	if err := im.createIndex(ctx, "aevum-dlq", DeadLetterMapping); err != nil {
 		return err
 	}
 	return nil
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,50 +10,143 @@ import (
 	"testing"
 )
 
-func TestIndexManagerLifecycle(t *testing.T) {
-	var mu sync.Mutex
-	exists := map[string]bool{}
+// fakeIndices serves the index and alias APIs of Elasticsearch the index manager uses
+type fakeIndices struct {
+	mu      sync.Mutex
+	indices map[string]int // index to mapping version
+	aliases map[string][]string
+	docs    map[string]int
+}
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("X-Elastic-Product", "Elasticsearch")
-		path := strings.TrimPrefix(r.URL.Path, "/")
+func newFakeIndices() *fakeIndices {
+	return &fakeIndices{indices: map[string]int{}, aliases: map[string][]string{}, docs: map[string]int{}}
+}
 
-		if strings.HasSuffix(path, "_stats") {
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"_all":{"primaries":{}}}`))
+func (f *fakeIndices) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch {
+	case strings.HasSuffix(path, "_stats"):
+		_, _ = w.Write([]byte(`{"_all":{"primaries":{}}}`))
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "_alias/"):
+		indices := f.aliases[strings.TrimPrefix(path, "_alias/")]
+		if len(indices) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"alias missing","status":404}`))
 			return
 		}
-
-		index := strings.Split(path, "/")[0]
-		mu.Lock()
-		defer mu.Unlock()
-
-		switch r.Method {
-		case http.MethodHead:
-			if exists[index] {
-				w.WriteHeader(http.StatusOK)
-			} else {
-				w.WriteHeader(http.StatusNotFound)
+		result := map[string]interface{}{}
+		for _, index := range indices {
+			result[index] = map[string]interface{}{"aliases": map[string]interface{}{}}
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/_mapping"):
+		result := map[string]interface{}{}
+		for _, index := range f.resolve(strings.TrimSuffix(path, "/_mapping")) {
+			result[index] = map[string]interface{}{"mappings": map[string]interface{}{"_meta": map[string]interface{}{"mapping_version": f.indices[index]}}}
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodGet:
+		result := map[string]interface{}{}
+		prefix := strings.TrimSuffix(path, "*")
+		for index := range f.indices {
+			if strings.HasPrefix(index, prefix) {
+				result[index] = map[string]interface{}{}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodPost && path == "_aliases":
+		var body struct {
+			Actions []map[string]map[string]string `json:"actions"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, action := range body.Actions {
+			for kind, target := range action {
+				switch kind {
+				case "add":
+					f.aliases[target["alias"]] = append(f.aliases[target["alias"]], target["index"])
+				case "remove":
+					f.aliases[target["alias"]] = without(f.aliases[target["alias"]], target["index"])
+				case "remove_index":
+					delete(f.indices, target["index"])
+				}
 			}
-		case http.MethodPut:
-			exists[index] = true
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"acknowledged":true}`))
-		case http.MethodDelete:
-			delete(exists, index)
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"acknowledged":true}`))
-		default:
+		}
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	case r.Method == http.MethodPost && path == "_reindex":
+		var body struct {
+			Source struct{ Index string } `json:"source"`
+			Dest   struct{ Index string } `json:"dest"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.docs[body.Dest.Index] += f.docs[body.Source.Index]
+		_, _ = w.Write([]byte(`{"created":1}`))
+	case r.Method == http.MethodHead:
+		if len(f.resolve(path)) == 0 {
 			w.WriteHeader(http.StatusNotFound)
 		}
-	}))
-	defer server.Close()
+	case r.Method == http.MethodPut:
+		var body struct {
+			Mappings struct {
+				Meta struct {
+					MappingVersion int `json:"mapping_version"`
+				} `json:"_meta"`
+			} `json:"mappings"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.indices[path] = body.Mappings.Meta.MappingVersion
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	case r.Method == http.MethodDelete:
+		if _, ok := f.indices[path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"type":"index_not_found_exception"}}`))
+			return
+		}
+		delete(f.indices, path)
+		for alias, indices := range f.aliases {
+			f.aliases[alias] = without(indices, path)
+		}
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// resolve returns the indices name refers to, as an index or an alias
+func (f *fakeIndices) resolve(name string) []string {
+	if _, ok := f.indices[name]; ok {
+		return []string{name}
+	}
+	return f.aliases[name]
+}
+
+func without(values []string, value string) []string {
+	kept := []string{}
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
 
+func newTestIndexManager(t *testing.T, fake *fakeIndices) *IndexManager {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
 	ec, err := NewElasticsearchClient([]string{server.URL})
 	if err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
-	im := NewIndexManager(ec.GetClient())
+	return NewIndexManager(ec.GetClient())
+}
+
+func TestIndexManagerLifecycle(t *testing.T) {
+	fake := newFakeIndices()
+	im := newTestIndexManager(t, fake)
 
 	if err := im.CreateIndexes(context.Background()); err != nil {
 		t.Fatalf("expected create indexes success, got %v", err)
@@ -62,14 +156,103 @@ func TestIndexManagerLifecycle(t *testing.T) {
 	if err != nil || !ok {
 		t.Fatalf("expected existing index, ok=%v err=%v", ok, err)
 	}
+	if ok, _ := im.IndexExists(context.Background(), "aevum-access-log"); !ok {
+		t.Fatal("expected access log index to be created")
+	}
+	if got := fake.aliases[EventsAlias]; len(got) != 1 || got[0] != "aevum-events-v1" {
+		t.Fatalf("expected events alias on the first version, got %v", got)
+	}
+	if got := fake.aliases[DecisionsWriteAlias]; len(got) != 1 || got[0] != "aevum-decisions-v1" {
+		t.Fatalf("expected decisions write alias on the first version, got %v", got)
+	}
+
+	// Creating the indexes again leaves them as they are
+	if err := im.CreateIndexes(context.Background()); err != nil {
+		t.Fatalf("expected second create indexes success, got %v", err)
+	}
+	if _, ok := fake.indices["aevum-events-v2"]; ok {
+		t.Fatal("expected no new version on a second start")
+	}
 
 	if _, err := im.GetIndexStats(context.Background(), "aevum-events"); err != nil {
 		t.Fatalf("expected stats success, got %v", err)
 	}
 
-	if err := im.DeleteIndex(context.Background(), "aevum-events"); err != nil {
+	if err := im.DeleteIndex(context.Background(), "aevum-access-log"); err != nil {
 		t.Fatalf("expected delete success, got %v", err)
 	}
+	if ok, _ := im.IndexExists(context.Background(), "aevum-access-log"); ok {
+		t.Fatal("expected access log index to be deleted")
+	}
+}
+
+func TestIndexManagerMigratesLegacyIndex(t *testing.T) {
+	fake := newFakeIndices()
+	fake.indices["aevum-events"] = 0
+	fake.docs["aevum-events"] = 7
+	im := newTestIndexManager(t, fake)
+
+	if err := im.CreateIndexes(context.Background()); err != nil {
+		t.Fatalf("expected create indexes success, got %v", err)
+	}
+	if _, ok := fake.indices["aevum-events"]; ok {
+		t.Fatal("expected legacy index to be replaced by the alias")
+	}
+	if fake.docs["aevum-events-v1"] != 7 {
+		t.Fatalf("expected legacy documents copied into the first version, got %d", fake.docs["aevum-events-v1"])
+	}
+	if got := fake.aliases[EventsAlias]; len(got) != 1 || got[0] != "aevum-events-v1" {
+		t.Fatalf("expected events alias on the first version, got %v", got)
+	}
+}
+
+func TestIndexManagerBuildsAndPromotesVersions(t *testing.T) {
+	fake := newFakeIndices()
+	im := newTestIndexManager(t, fake)
+	ctx := context.Background()
+	if err := im.CreateIndexes(ctx); err != nil {
+		t.Fatalf("expected create indexes success, got %v", err)
+	}
+
+	// A build that never finished is abandoned with the write alias on it
+	abandoned, err := im.CreateVersion(ctx, DecisionsAlias)
+	if err != nil || abandoned != "aevum-decisions-v2" {
+		t.Fatalf("expected second version, got %q, %v", abandoned, err)
+	}
+	if got := fake.aliases[DecisionsAlias]; len(got) != 1 || got[0] != "aevum-decisions-v1" {
+		t.Fatalf("expected reads to stay on the first version, got %v", got)
+	}
+	if got := fake.aliases[DecisionsWriteAlias]; len(got) != 1 || got[0] != abandoned {
+		t.Fatalf("expected writes on the new version, got %v", got)
+	}
+
+	version, err := im.CreateVersion(ctx, DecisionsAlias)
+	if err != nil || version != "aevum-decisions-v3" {
+		t.Fatalf("expected third version, got %q, %v", version, err)
+	}
+	if _, ok := fake.indices[abandoned]; ok {
+		t.Fatal("expected abandoned version to be deleted")
+	}
+	if err := im.PromoteVersion(ctx, DecisionsAlias, version); err != nil {
+		t.Fatalf("expected promote success, got %v", err)
+	}
+	if got := fake.aliases[DecisionsAlias]; len(got) != 1 || got[0] != version {
+		t.Fatalf("expected reads on the new version, got %v", got)
+	}
+	if _, ok := fake.indices["aevum-decisions-v1"]; ok {
+		t.Fatal("expected previous version to be deleted")
+	}
+
+	if outdated, err := im.MappingOutdated(ctx, DecisionsAlias); err != nil || outdated {
+		t.Fatalf("expected current mapping, got %v, %v", outdated, err)
+	}
+	fake.indices[version] = 0
+	if outdated, err := im.MappingOutdated(ctx, DecisionsAlias); err != nil || !outdated {
+		t.Fatalf("expected outdated mapping, got %v, %v", outdated, err)
+	}
+	if _, err := im.CreateVersion(ctx, "aevum-access-log"); err == nil {
+		t.Fatal("expected error for an index without versions")
+	}
 }
 
 func TestIndexManagerStatsError(t *testing.T) {
@@ -5,6 +5,7 @@ const EventMapping = `{
   "mappings": {
     "properties": {
       "event_id": {"type": "keyword"},
+      "tenant_id": {"type": "keyword"},
       "stream_id": {"type": "keyword"},
       "sequence_number": {"type": "long"},
       "event_type": {"type": "keyword"},
@@ -52,8 +53,49 @@ const SyncStateMapping = `{
     "properties": {
       "service_name": {"type": "keyword"},
       "last_synced_cursor": {"type": "keyword"},
+      "last_sequence": {"type": "long"},
       "last_sync_time": {"type": "date"},
-      "sync_status": {"type": "keyword"}
+      "sync_status": {"type": "keyword"},
+      "last_error": {"type": "text"},
+      "consecutive_failures": {"type": "integer"}
+    }
+  }
+}`
+
+// AccessLogMapping defines the ES mapping for API access log entries
+const AccessLogMapping = `{
+  "mappings": {
+    "dynamic": "strict",
+    "properties": {
+      "time": {"type": "date"},
+      "subject": {"type": "keyword"},
+      "method": {"type": "keyword"},
+      "route": {"type": "keyword"},
+      "stream_id": {"type": "keyword"},
+      "event_id": {"type": "keyword"},
+      "decision_id": {"type": "keyword"},
+      "status": {"type": "integer"},
+      "request_id": {"type": "keyword"}
+    }
+  }
+}`
+
+// DeadLetterMapping defines the ES mapping for documents that could not be indexed; the source
+// document is kept as stored, unindexed JSON, since its shape is arbitrary
+const DeadLetterMapping = `{
+  "mappings": {
+    "dynamic": "strict",
+    "properties": {
+      "id": {"type": "keyword"},
+      "index": {"type": "keyword"},
+      "document_id": {"type": "keyword"},
+      "document": {"type": "object", "enabled": false},
+      "stage": {"type": "keyword"},
+      "status": {"type": "integer"},
+      "reason": {"type": "text"},
+      "attempts": {"type": "integer"},
+      "first_failed_at": {"type": "date"},
+      "last_failed_at": {"type": "date"}
     }
   }
 }`
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EventsAlias is the alias events are read through
+	EventsAlias = "aevum-events"
+	// EventsWriteAlias is the alias events are indexed through
+	EventsWriteAlias = "aevum-events-write"
+	// DecisionsAlias is the alias decisions are read through
+	DecisionsAlias = "aevum-decisions"
+	// DecisionsWriteAlias is the alias decisions are indexed through
+	DecisionsWriteAlias = "aevum-decisions-write"
+)
+
+// VersionedIndex is served through a read and a write alias over versioned physical indices
+// (aevum-events-v1, aevum-events-v2, ...), so it can be rebuilt with a new mapping while it is read
+type VersionedIndex struct {
+	Alias      string
+	WriteAlias string
+	// MappingVersion is stored in the mapping's _meta; raise it whenever Mapping changes
+	MappingVersion int
+	Mapping        string
+}
+
+// VersionedIndexes are the indexes rebuilt from the source services
+var VersionedIndexes = []VersionedIndex{
+	{Alias: EventsAlias, WriteAlias: EventsWriteAlias, MappingVersion: 2, Mapping: EventMapping},
+	{Alias: DecisionsAlias, WriteAlias: DecisionsWriteAlias, MappingVersion: 1, Mapping: DecisionMapping},
+}
+
+// versionedIndex returns the versioned index read through alias
+func versionedIndex(alias string) (VersionedIndex, error) {
+	for _, vi := range VersionedIndexes {
+		if vi.Alias == alias {
+			return vi, nil
+		}
+	}
+	return VersionedIndex{}, fmt.Errorf("%s is not a versioned index", alias)
+}
+
+// CreateVersion creates the next physical index of alias with the current mapping and moves the write
+// alias to it; reads stay on the current index until PromoteVersion
+func (im *IndexManager) CreateVersion(ctx context.Context, alias string) (string, error) {
+	vi, err := versionedIndex(alias)
+	if err != nil {
+		return "", err
+	}
+	name, err := im.createVersion(ctx, vi)
+	if err != nil {
+		return "", err
+	}
+
+	writers, err := im.aliasIndices(ctx, vi.WriteAlias)
+	if err != nil {
+		return "", err
+	}
+	actions := []map[string]interface{}{aliasAction("add", name, vi.WriteAlias)}
+	for _, writer := range writers {
+		actions = append(actions, aliasAction("remove", writer, vi.WriteAlias))
+	}
+	if err := im.updateAliases(ctx, actions...); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// PromoteVersion atomically moves the read alias of alias to the physical index name and deletes the
+// indices it was on before
+func (im *IndexManager) PromoteVersion(ctx context.Context, alias, name string) error {
+	vi, err := versionedIndex(alias)
+	if err != nil {
+		return err
+	}
+	readers, err := im.aliasIndices(ctx, vi.Alias)
+	if err != nil {
+		return err
+	}
+
+	actions := []map[string]interface{}{aliasAction("add", name, vi.Alias)}
+	var previous []string
+	for _, reader := range readers {
+		if reader != name {
+			actions = append(actions, aliasAction("remove", reader, vi.Alias))
+			previous = append(previous, reader)
+		}
+	}
+	if err := im.updateAliases(ctx, actions...); err != nil {
+		return err
+	}
+	for _, index := range previous {
+		// An index that cannot be deleted now is deleted by the next CreateVersion
+		_ = im.DeleteIndex(ctx, index)
+	}
+	return nil
+}
+
+// MappingOutdated reports whether the index read through alias has an older mapping version than the
+// current mapping and needs a rebuild
+func (im *IndexManager) MappingOutdated(ctx context.Context, alias string) (bool, error) {
+	vi, err := versionedIndex(alias)
+	if err != nil {
+		return false, err
+	}
+	res, err := im.client.Indices.GetMapping(im.client.Indices.GetMapping.WithIndex(vi.Alias), im.client.Indices.GetMapping.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to get mapping of %s: %w", vi.Alias, err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return false, fmt.Errorf("failed to get mapping of %s: status %d", vi.Alias, res.StatusCode)
+	}
+
+	var mappings map[string]struct {
+		Mappings struct {
+			Meta struct {
+				MappingVersion int `json:"mapping_version"`
+			} `json:"_meta"`
+		} `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&mappings); err != nil {
+		return false, fmt.Errorf("failed to decode mapping of %s: %w", vi.Alias, err)
+	}
+	for _, index := range mappings {
+		if index.Mappings.Meta.MappingVersion < vi.MappingVersion {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ensureVersionedIndex gives vi a physical index behind both aliases. An index created under the
+// alias name before aliases were used is copied into the first version and replaced by the alias
+func (im *IndexManager) ensureVersionedIndex(ctx context.Context, vi VersionedIndex) error {
+	readers, err := im.aliasIndices(ctx, vi.Alias)
+	if err != nil {
+		return err
+	}
+	if len(readers) > 0 {
+		writers, err := im.aliasIndices(ctx, vi.WriteAlias)
+		if err != nil || len(writers) > 0 {
+			return err
+		}
+		return im.updateAliases(ctx, aliasAction("add", readers[0], vi.WriteAlias))
+	}
+
+	legacy, err := im.IndexExists(ctx, vi.Alias)
+	if err != nil {
+		return err
+	}
+	name, err := im.createVersion(ctx, vi)
+	if err != nil {
+		return err
+	}
+	actions := []map[string]interface{}{aliasAction("add", name, vi.Alias), aliasAction("add", name, vi.WriteAlias)}
+	if legacy {
+		if err := im.reindex(ctx, vi.Alias, name); err != nil {
+			return err
+		}
+		// The alias takes the name of the legacy index in the same step that deletes it
+		actions = append(actions, map[string]interface{}{"remove_index": map[string]interface{}{"index": vi.Alias}})
+	}
+	return im.updateAliases(ctx, actions...)
+}
+
+// createVersion creates the physical index after the newest version of vi, deleting versions left
+// behind by failed builds on the way
+func (im *IndexManager) createVersion(ctx context.Context, vi VersionedIndex) (string, error) {
+	readers, err := im.aliasIndices(ctx, vi.Alias)
+	if err != nil {
+		return "", err
+	}
+	versions, err := im.versions(ctx, vi)
+	if err != nil {
+		return "", err
+	}
+
+	next := 1
+	for index, version := range versions {
+		if version >= next {
+			next = version + 1
+		}
+		if !contains(readers, index) {
+			if err := im.DeleteIndex(ctx, index); err != nil {
+				return "", fmt.Errorf("failed to delete abandoned index %s: %w", index, err)
+			}
+		}
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(vi.Mapping), &body); err != nil {
+		return "", fmt.Errorf("invalid mapping for %s: %w", vi.Alias, err)
+	}
+	mappings, _ := body["mappings"].(map[string]interface{})
+	if mappings == nil {
+		mappings = map[string]interface{}{}
+		body["mappings"] = mappings
+	}
+	mappings["_meta"] = map[string]interface{}{"mapping_version": vi.MappingVersion}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	name := vi.Alias + "-v" + strconv.Itoa(next)
+	if err := im.createIndex(ctx, name, string(data)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// versions returns the physical indices of vi with their version numbers
+func (im *IndexManager) versions(ctx context.Context, vi VersionedIndex) (map[string]int, error) {
+	res, err := im.client.Indices.Get([]string{vi.Alias + "-v*"}, im.client.Indices.Get.WithAllowNoIndices(true), im.client.Indices.Get.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of %s: %w", vi.Alias, err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to list versions of %s: status %d", vi.Alias, res.StatusCode)
+	}
+
+	var indices map[string]json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return nil, fmt.Errorf("failed to decode versions of %s: %w", vi.Alias, err)
+	}
+	versions := map[string]int{}
+	for index := range indices {
+		version, err := strconv.Atoi(strings.TrimPrefix(index, vi.Alias+"-v"))
+		if err == nil {
+			versions[index] = version
+		}
+	}
+	return versions, nil
+}
+
+// aliasIndices returns the indices alias points to, none when the alias does not exist
+func (im *IndexManager) aliasIndices(ctx context.Context, alias string) ([]string, error) {
+	res, err := im.client.Indices.GetAlias(im.client.Indices.GetAlias.WithName(alias), im.client.Indices.GetAlias.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alias %s: %w", alias, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to get alias %s: status %d", alias, res.StatusCode)
+	}
+
+	var indices map[string]json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return nil, fmt.Errorf("failed to decode alias %s: %w", alias, err)
+	}
+	names := make([]string, 0, len(indices))
+	for index := range indices {
+		names = append(names, index)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// updateAliases applies alias actions in one atomic request
+func (im *IndexManager) updateAliases(ctx context.Context, actions ...map[string]interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return err
+	}
+	res, err := im.client.Indices.UpdateAliases(bytes.NewReader(body), im.client.Indices.UpdateAliases.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update aliases: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		data, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to update aliases: %s", string(data))
+	}
+	return nil
+}
+
+// reindex copies every document of source into dest
+func (im *IndexManager) reindex(ctx context.Context, source, dest string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"source": map[string]interface{}{"index": source},
+		"dest":   map[string]interface{}{"index": dest},
+	})
+	if err != nil {
+		return err
+	}
+	res, err := im.client.Reindex(bytes.NewReader(body), im.client.Reindex.WithWaitForCompletion(true), im.client.Reindex.WithRefresh(true), im.client.Reindex.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to copy %s into %s: %w", source, dest, err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		data, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to copy %s into %s: %s", source, dest, string(data))
+	}
+	return nil
+}
+
+func aliasAction(action, index, alias string) map[string]interface{} {
+	return map[string]interface{}{action: map[string]interface{}{"index": index, "alias": alias}}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,292 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	gosync "sync"
+	"time"
+)
+
+const (
+	// OperationSync catches a source up from its current position
+	OperationSync = "sync"
+	// OperationRebuild indexes a source from the start into a new version of its index and swaps it in
+	OperationRebuild = "rebuild"
+
+	maxKeptOperations = 50
+)
+
+var (
+	// ErrUnknownSource is returned for a source without a registered worker
+	ErrUnknownSource = errors.New("unknown sync source")
+	// ErrOperationRunning is returned when an operation is already running for the source
+	ErrOperationRunning = errors.New("operation already running for source")
+	// ErrInvalidPosition is returned for a position the source cannot be reset to
+	ErrInvalidPosition = errors.New("invalid position")
+)
+
+// Position is where a reset moves a source; event streams are positioned by sequence, decisions by time.
+// The zero position is the start of the source
+type Position struct {
+	TenantID  string    `json:"tenant_id,omitempty"`
+	StreamID  string    `json:"stream_id,omitempty"`
+	Sequence  int64     `json:"sequence,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// Indexer is the part of a source's indexer that operations use
+type Indexer interface {
+	// Remaining returns the number of source documents after cursor that are not indexed yet
+	Remaining(ctx context.Context, cursor string) (int64, error)
+	// Reset moves the indexer back to position and returns the worker cursor to continue from
+	Reset(ctx context.Context, position Position) (string, error)
+}
+
+// IndexVersions builds new versions of an index behind its aliases
+type IndexVersions interface {
+	// CreateVersion creates a new, empty version of index and indexes new documents into it
+	CreateVersion(ctx context.Context, index string) (string, error)
+	// PromoteVersion moves reads of index to version
+	PromoteVersion(ctx context.Context, index, version string) error
+}
+
+// Source is a sync source that operations act on
+type Source struct {
+	Worker  *Worker
+	Indexer Indexer
+	Index   string
+}
+
+// Operation reports the progress of an admin operation
+type Operation struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Source     string     `json:"source"`
+	Status     string     `json:"status"`
+	Passes     int        `json:"passes"`
+	Remaining  int64      `json:"remaining"`
+	Cursor     string     `json:"cursor"`
+	Version    string     `json:"version,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Operations runs sync and rebuild operations in the background and keeps their progress
+type Operations struct {
+	sources map[string]Source
+	indices IndexVersions
+	logger  *slog.Logger
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      gosync.WaitGroup
+
+	mu         gosync.Mutex
+	nextID     int
+	operations []*Operation
+	running    map[string]*Operation
+}
+
+// NewOperations creates an operation runner for sources
+func NewOperations(indices IndexVersions, logger *slog.Logger, sources ...Source) *Operations {
+	ctx, cancel := context.WithCancel(context.Background())
+	o := &Operations{
+		sources: map[string]Source{},
+		indices: indices,
+		logger:  logger,
+		ctx:     ctx,
+		cancel:  cancel,
+		running: map[string]*Operation{},
+	}
+	for _, source := range sources {
+		o.sources[source.Worker.Name()] = source
+	}
+	return o
+}
+
+// Sources returns the names of the registered sources in order
+func (o *Operations) Sources() []string {
+	names := make([]string, 0, len(o.sources))
+	for name := range o.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start begins an operation of kind on source and returns it; only one operation runs per source
+func (o *Operations) Start(kind, sourceName string) (Operation, error) {
+	source, ok := o.sources[sourceName]
+	if !ok {
+		return Operation{}, fmt.Errorf("%w: %s", ErrUnknownSource, sourceName)
+	}
+	if kind != OperationSync && kind != OperationRebuild {
+		return Operation{}, fmt.Errorf("unknown operation %q", kind)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if running, ok := o.running[sourceName]; ok {
+		return *running, ErrOperationRunning
+	}
+	o.nextID++
+	op := &Operation{
+		ID:        strconv.Itoa(o.nextID),
+		Kind:      kind,
+		Source:    sourceName,
+		Status:    "running",
+		Remaining: -1,
+		StartedAt: time.Now().UTC(),
+	}
+	o.operations = append(o.operations, op)
+	if len(o.operations) > maxKeptOperations {
+		o.operations = o.operations[len(o.operations)-maxKeptOperations:]
+	}
+	o.running[sourceName] = op
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		o.finish(op, o.run(op, source))
+	}()
+	return *op, nil
+}
+
+// Reset moves source to position; the next sync continues from there
+func (o *Operations) Reset(ctx context.Context, sourceName string, position Position) (SyncState, error) {
+	source, ok := o.sources[sourceName]
+	if !ok {
+		return SyncState{}, fmt.Errorf("%w: %s", ErrUnknownSource, sourceName)
+	}
+	o.mu.Lock()
+	running, busy := o.running[sourceName]
+	o.mu.Unlock()
+	if busy {
+		return SyncState{}, fmt.Errorf("%w: %s", ErrOperationRunning, running.ID)
+	}
+
+	return source.Worker.Do(ctx, func(state *SyncState) error {
+		cursor, err := source.Indexer.Reset(ctx, position)
+		if err != nil {
+			return err
+		}
+		state.Reset(cursor)
+		return nil
+	})
+}
+
+// Get returns an operation by ID
+func (o *Operations) Get(id string) (Operation, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, op := range o.operations {
+		if op.ID == id {
+			return *op, true
+		}
+	}
+	return Operation{}, false
+}
+
+// List returns the kept operations, newest first
+func (o *Operations) List() []Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ops := make([]Operation, 0, len(o.operations))
+	for i := len(o.operations) - 1; i >= 0; i-- {
+		ops = append(ops, *o.operations[i])
+	}
+	return ops
+}
+
+// Close cancels running operations and waits for them to stop
+func (o *Operations) Close() {
+	o.cancel()
+	o.wg.Wait()
+}
+
+// run executes an operation. A rebuild indexes the source from the start into a new version of its
+// index while reads stay on the current version, and swaps the new version in once it has caught up
+func (o *Operations) run(op *Operation, source Source) error {
+	if op.Kind == OperationSync {
+		return o.catchUp(op, source)
+	}
+
+	var version string
+	_, err := source.Worker.Do(o.ctx, func(state *SyncState) error {
+		var err error
+		if version, err = o.indices.CreateVersion(o.ctx, source.Index); err != nil {
+			return err
+		}
+		cursor, err := source.Indexer.Reset(o.ctx, Position{})
+		if err != nil {
+			return err
+		}
+		state.Reset(cursor)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	o.update(func() { op.Version = version })
+
+	if err := o.catchUp(op, source); err != nil {
+		return err
+	}
+	return o.indices.PromoteVersion(o.ctx, source.Index, version)
+}
+
+// catchUp syncs until nothing is left or a sync stops reducing what is left, as when the source
+// grows as fast as it is indexed; the background worker carries on from there
+func (o *Operations) catchUp(op *Operation, source Source) error {
+	previous := int64(-1)
+	for {
+		var remaining int64
+		state, err := source.Worker.Do(o.ctx, func(state *SyncState) error {
+			var err error
+			remaining, err = source.Indexer.Remaining(o.ctx, state.LastSyncedCursor)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		o.update(func() {
+			op.Remaining = remaining
+			op.Cursor = state.LastSyncedCursor
+		})
+		if remaining == 0 || (previous >= 0 && remaining >= previous) {
+			return nil
+		}
+		previous = remaining
+
+		if _, err := source.Worker.SyncNow(o.ctx); err != nil {
+			return err
+		}
+		o.update(func() { op.Passes++ })
+	}
+}
+
+// update changes an operation under the lock, so List and Get return consistent copies
+func (o *Operations) update(fn func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fn()
+}
+
+// finish records the outcome of op and frees its source
+func (o *Operations) finish(op *Operation, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now().UTC()
+	op.FinishedAt = &now
+	op.Status = "succeeded"
+	if err != nil {
+		op.Status = "failed"
+		op.Error = err.Error()
+		o.logger.Error("sync operation failed", slog.String("operation", op.Kind), slog.String("source", op.Source), slog.Any("error", err))
+	}
+	delete(o.running, op.Source)
+}
@@ -0,0 +1,202 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	gosync "sync"
+	"testing"
+	"time"
+)
+
+// countingIndexer has remaining documents that each sync reduces by perSync
+type countingIndexer struct {
+	mu        gosync.Mutex
+	remaining int64
+	perSync   int64
+	resets    []Position
+}
+
+func (i *countingIndexer) sync(_ context.Context, cursor string) (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remaining -= i.perSync
+	if i.remaining < 0 {
+		i.remaining = 0
+	}
+	return cursor + "+", nil
+}
+
+func (i *countingIndexer) Remaining(context.Context, string) (int64, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.remaining, nil
+}
+
+func (i *countingIndexer) Reset(_ context.Context, position Position) (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if position.StreamID != "" {
+		return "", ErrInvalidPosition
+	}
+	i.resets = append(i.resets, position)
+	if position.Timestamp.IsZero() {
+		i.remaining = 10
+		return "", nil
+	}
+	return position.Timestamp.Format(time.RFC3339), nil
+}
+
+// recordingIndices records index versions and checks that reads move to a version only after it is created
+type recordingIndices struct {
+	created  []string
+	promoted []string
+}
+
+func (r *recordingIndices) CreateVersion(_ context.Context, index string) (string, error) {
+	version := fmt.Sprintf("%s-v%d", index, len(r.created)+2)
+	r.created = append(r.created, version)
+	return version, nil
+}
+
+func (r *recordingIndices) PromoteVersion(_ context.Context, index, version string) error {
+	if len(r.created) == 0 || r.created[len(r.created)-1] != version {
+		return fmt.Errorf("promoted %s of %s before creating it", version, index)
+	}
+	r.promoted = append(r.promoted, version)
+	return nil
+}
+
+// startedWorker returns a worker whose state is loaded and whose background loop never syncs on its own
+func startedWorker(t *testing.T, name string, syncFunc func(context.Context, string) (string, error)) *Worker {
+	t.Helper()
+	worker := NewWorker(name, syncFunc, time.Hour, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	worker.Start(context.Background(), "c0")
+	t.Cleanup(worker.Stop)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := worker.Do(context.Background(), func(*SyncState) error { return nil }); !errors.Is(err, ErrNotReady) {
+			return worker
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("worker state was not loaded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func waitForOperation(t *testing.T, ops *Operations, id string) Operation {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		op, ok := ops.Get(id)
+		if ok && op.Status != "running" {
+			return op
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("operation %s did not finish: %+v", id, op)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestOperationsSyncUntilCaughtUp(t *testing.T) {
+	indexer := &countingIndexer{remaining: 5, perSync: 2}
+	worker := startedWorker(t, "decision-engine", indexer.sync)
+	ops := NewOperations(&recordingIndices{}, slog.New(slog.NewTextHandler(io.Discard, nil)), Source{Worker: worker, Indexer: indexer})
+	defer ops.Close()
+
+	started, err := ops.Start(OperationSync, "decision-engine")
+	if err != nil || started.Status != "running" {
+		t.Fatalf("expected running operation, got %+v, %v", started, err)
+	}
+	op := waitForOperation(t, ops, started.ID)
+	if op.Status != "succeeded" || op.Passes != 3 || op.Remaining != 0 || op.Cursor != "c0+++" || op.FinishedAt == nil {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+
+	if _, err := ops.Start(OperationSync, "unknown"); !errors.Is(err, ErrUnknownSource) {
+		t.Fatalf("expected unknown source error, got %v", err)
+	}
+
+	// A source that grows as fast as it is indexed stops after a sync that makes no progress
+	indexer.mu.Lock()
+	indexer.remaining, indexer.perSync = 4, 0
+	indexer.mu.Unlock()
+	started, _ = ops.Start(OperationSync, "decision-engine")
+	if op := waitForOperation(t, ops, started.ID); op.Status != "succeeded" || op.Passes != 1 || op.Remaining != 4 {
+		t.Fatalf("expected operation to stop without progress, got %+v", op)
+	}
+	if list := ops.List(); len(list) != 2 || list[0].ID != started.ID {
+		t.Fatalf("expected newest operation first, got %+v", list)
+	}
+}
+
+func TestOperationsRebuildBuildsNewVersionAndSwapsIt(t *testing.T) {
+	indexer := &countingIndexer{perSync: 5}
+	worker := startedWorker(t, "event-timeline", indexer.sync)
+	indices := &recordingIndices{}
+	ops := NewOperations(indices, slog.New(slog.NewTextHandler(io.Discard, nil)), Source{Worker: worker, Indexer: indexer, Index: "aevum-events"})
+	defer ops.Close()
+
+	started, err := ops.Start(OperationRebuild, "event-timeline")
+	if err != nil {
+		t.Fatalf("expected rebuild to start, got %v", err)
+	}
+	op := waitForOperation(t, ops, started.ID)
+	if op.Status != "succeeded" || op.Passes != 2 || op.Cursor != "++" || op.Version != "aevum-events-v2" {
+		t.Fatalf("unexpected rebuild %+v", op)
+	}
+	if len(indices.promoted) != 1 || indices.promoted[0] != "aevum-events-v2" || len(indexer.resets) != 1 {
+		t.Fatalf("expected new version promoted and indexer reset, got %v %v", indices.promoted, indexer.resets)
+	}
+}
+
+func TestOperationsReset(t *testing.T) {
+	indexer := &countingIndexer{}
+	worker := startedWorker(t, "decision-engine", indexer.sync)
+	ops := NewOperations(&recordingIndices{}, slog.New(slog.NewTextHandler(io.Discard, nil)), Source{Worker: worker, Indexer: indexer})
+	defer ops.Close()
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	state, err := ops.Reset(context.Background(), "decision-engine", Position{Timestamp: at})
+	if err != nil || state.LastSyncedCursor != "2026-03-01T12:00:00Z" || state.SyncStatus != "initialized" {
+		t.Fatalf("unexpected reset state %+v, %v", state, err)
+	}
+	if _, err := ops.Reset(context.Background(), "decision-engine", Position{StreamID: "orders"}); !errors.Is(err, ErrInvalidPosition) {
+		t.Fatalf("expected invalid position, got %v", err)
+	}
+	if state, _ := worker.Do(context.Background(), func(*SyncState) error { return nil }); state.LastSyncedCursor != "2026-03-01T12:00:00Z" {
+		t.Fatalf("expected failed reset to keep the cursor, got %+v", state)
+	}
+}
+
+func TestOperationsRejectSecondOperationOnSource(t *testing.T) {
+	release := make(chan struct{})
+	indexer := &countingIndexer{remaining: 1}
+	worker := startedWorker(t, "decision-engine", func(ctx context.Context, cursor string) (string, error) {
+		<-release
+		return indexer.sync(ctx, cursor)
+	})
+	indexer.perSync = 1
+	ops := NewOperations(&recordingIndices{}, slog.New(slog.NewTextHandler(io.Discard, nil)), Source{Worker: worker, Indexer: indexer})
+	defer ops.Close()
+
+	first, err := ops.Start(OperationSync, "decision-engine")
+	if err != nil {
+		t.Fatalf("expected sync to start, got %v", err)
+	}
+	running, err := ops.Start(OperationRebuild, "decision-engine")
+	if !errors.Is(err, ErrOperationRunning) || running.ID != first.ID {
+		t.Fatalf("expected running operation %s, got %+v, %v", first.ID, running, err)
+	}
+	if _, err := ops.Reset(context.Background(), "decision-engine", Position{}); !errors.Is(err, ErrOperationRunning) {
+		t.Fatalf("expected reset to be rejected while an operation runs, got %v", err)
+	}
+	close(release)
+	if op := waitForOperation(t, ops, first.ID); op.Status != "succeeded" {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+}
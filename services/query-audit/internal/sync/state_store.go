@@ -0,0 +1,122 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+const (
+	// StateIndex is the Elasticsearch index holding sync state documents
+	StateIndex = "aevum-sync-state"
+
+	maxListedStates = 1000
+)
+
+// StateStore loads and persists sync state
+type StateStore interface {
+	Load(ctx context.Context, serviceName string) (*SyncState, error)
+	Save(ctx context.Context, state SyncState) error
+	List(ctx context.Context) ([]SyncState, error)
+}
+
+// ElasticsearchStateStore keeps one sync state document per service, with the service name as document ID
+type ElasticsearchStateStore struct {
+	client *elasticsearch.Client
+}
+
+// NewElasticsearchStateStore creates a new sync state store
+func NewElasticsearchStateStore(client *elasticsearch.Client) *ElasticsearchStateStore {
+	return &ElasticsearchStateStore{client: client}
+}
+
+// Load returns the stored state of a service, or a new state when none has been saved yet
+func (s *ElasticsearchStateStore) Load(ctx context.Context, serviceName string) (*SyncState, error) {
+	res, err := esapi.GetRequest{Index: StateIndex, DocumentID: url.PathEscape(serviceName)}.Do(ctx, s.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sync state: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return NewSyncState(serviceName), nil
+	}
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("failed to load sync state: %s", string(body))
+	}
+
+	var doc struct {
+		Source SyncState `json:"_source"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode sync state: %w", err)
+	}
+	return &doc.Source, nil
+}
+
+// Save replaces the stored state of a service
+func (s *ElasticsearchStateStore) Save(ctx context.Context, state SyncState) error {
+	body, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync state: %w", err)
+	}
+
+	res, err := esapi.IndexRequest{Index: StateIndex, DocumentID: url.PathEscape(state.ServiceName), Body: bytes.NewReader(body)}.Do(ctx, s.client)
+	if err != nil {
+		return fmt.Errorf("failed to save sync state: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		respBody, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to save sync state: %s", string(respBody))
+	}
+	return nil
+}
+
+// List returns all stored states ordered by service name
+func (s *ElasticsearchStateStore) List(ctx context.Context) ([]SyncState, error) {
+	query := map[string]interface{}{
+		"size": maxListedStates,
+		"sort": []map[string]interface{}{{"service_name": map[string]interface{}{"order": "asc"}}},
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sync state query: %w", err)
+	}
+
+	res, err := s.client.Search(s.client.Search.WithContext(ctx), s.client.Search.WithIndex(StateIndex), s.client.Search.WithBody(bytes.NewReader(body)))
+	if err != nil {
+		return nil, fmt.Errorf("sync state search failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("sync state search failed: status %d", res.StatusCode)
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source SyncState `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode sync state search response: %w", err)
+	}
+
+	states := make([]SyncState, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		states = append(states, hit.Source)
+	}
+	return states, nil
+}
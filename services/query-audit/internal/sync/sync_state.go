@@ -4,10 +4,13 @@ import "time"
 
 // SyncState tracks sync progress
 type SyncState struct {
-	ServiceName      string    `json:"service_name"`
-	LastSyncedCursor string    `json:"last_synced_cursor"`
-	LastSyncTime     time.Time `json:"last_sync_time"`
-	SyncStatus       string    `json:"sync_status"`
+	ServiceName         string    `json:"service_name"`
+	LastSyncedCursor    string    `json:"last_synced_cursor"`
+	LastSequence        int64     `json:"last_sequence,omitempty"`
+	LastSyncTime        time.Time `json:"last_sync_time"`
+	SyncStatus          string    `json:"sync_status"`
+	LastError           string    `json:"last_error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
 }
 
 // NewSyncState creates a new sync state
@@ -25,9 +28,24 @@ func (ss *SyncState) UpdateCursor(cursor string) {
 	ss.LastSyncedCursor = cursor
 	ss.LastSyncTime = time.Now()
 	ss.SyncStatus = "synced"
+	ss.LastError = ""
+	ss.ConsecutiveFailures = 0
 }
 
-// MarkFailed marks the sync as failed
-func (ss *SyncState) MarkFailed() {
+// MarkFailed marks the sync as failed; the cursor and last sync time keep pointing at the last success
+func (ss *SyncState) MarkFailed(err error) {
 	ss.SyncStatus = "failed"
+	ss.ConsecutiveFailures++
+	if err != nil {
+		ss.LastError = err.Error()
+	}
+}
+
+// Reset moves the cursor to cursor, as if no sync had run since
+func (ss *SyncState) Reset(cursor string) {
+	ss.LastSyncedCursor = cursor
+	ss.LastSyncTime = time.Now()
+	ss.SyncStatus = "initialized"
+	ss.LastError = ""
+	ss.ConsecutiveFailures = 0
 }
@@ -2,11 +2,16 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"math"
+	gosync "sync"
 	"time"
 )
 
+// ErrNotReady is returned by operations on a worker whose state has not been loaded yet
+var ErrNotReady = errors.New("sync state not loaded yet")
+
 // Worker manages background sync
 type Worker struct {
 	serviceName string
@@ -14,7 +19,12 @@ type Worker struct {
 	interval    time.Duration
 	maxBackoff  time.Duration
 	logger      *slog.Logger
+	store       StateStore
 	stopChan    chan struct{}
+
+	// mu serializes syncs with admin operations on the state
+	mu    gosync.Mutex
+	state *SyncState
 }
 
 // NewWorker creates a new sync worker
@@ -29,7 +39,18 @@ func NewWorker(serviceName string, syncFunc func(context.Context, string) (strin
 	}
 }
 
-// Start begins the background sync
+// WithStateStore persists the worker's state after every sync, so it resumes from its last cursor after a restart
+func (w *Worker) WithStateStore(store StateStore) *Worker {
+	w.store = store
+	return w
+}
+
+// Name returns the name of the synced service
+func (w *Worker) Name() string {
+	return w.serviceName
+}
+
+// Start begins the background sync; a persisted cursor takes precedence over initialCursor
 func (w *Worker) Start(ctx context.Context, initialCursor string) {
 	go w.run(ctx, initialCursor)
 }
@@ -40,7 +61,15 @@ func (w *Worker) Stop() {
 }
 
 // run executes the sync loop with exponential backoff
-func (w *Worker) run(ctx context.Context, cursor string) {
+func (w *Worker) run(ctx context.Context, initialCursor string) {
+	state, ok := w.loadState(ctx, initialCursor)
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	w.state = state
+	w.mu.Unlock()
+
 	backoff := w.interval
 	for {
 		select {
@@ -50,15 +79,88 @@ func (w *Worker) run(ctx context.Context, cursor string) {
 		case <-ctx.Done():
 			return
 		case <-time.After(backoff):
-			newCursor, err := w.syncFunc(ctx, cursor)
-			if err != nil {
-				w.logger.Error("sync failed", slog.String("service", w.serviceName), slog.Any("error", err))
+			if _, err := w.SyncNow(ctx); err != nil {
 				backoff = time.Duration(math.Min(float64(backoff)*2, float64(w.maxBackoff)))
 				continue
 			}
-			w.logger.Info("sync successful", slog.String("service", w.serviceName))
-			cursor = newCursor
 			backoff = w.interval
 		}
 	}
 }
+
+// SyncNow runs a sync immediately, after a sync that is already running, and returns the resulting state
+func (w *Worker) SyncNow(ctx context.Context) (SyncState, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.state == nil {
+		return SyncState{}, ErrNotReady
+	}
+
+	newCursor, err := w.syncFunc(ctx, w.state.LastSyncedCursor)
+	if err != nil {
+		w.logger.Error("sync failed", slog.String("service", w.serviceName), slog.Any("error", err))
+		w.state.MarkFailed(err)
+		w.saveState(ctx, w.state)
+		return *w.state, err
+	}
+	w.logger.Info("sync successful", slog.String("service", w.serviceName))
+	w.state.UpdateCursor(newCursor)
+	w.saveState(ctx, w.state)
+	return *w.state, nil
+}
+
+// Do runs fn with the worker's state while no sync is running and saves the state when fn succeeds
+func (w *Worker) Do(ctx context.Context, fn func(state *SyncState) error) (SyncState, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.state == nil {
+		return SyncState{}, ErrNotReady
+	}
+
+	if err := fn(w.state); err != nil {
+		return *w.state, err
+	}
+	w.saveState(ctx, w.state)
+	return *w.state, nil
+}
+
+// loadState reads the persisted state, retrying until it succeeds; starting over after a failed load would re-index everything
+func (w *Worker) loadState(ctx context.Context, initialCursor string) (*SyncState, bool) {
+	if w.store == nil {
+		state := NewSyncState(w.serviceName)
+		state.LastSyncedCursor = initialCursor
+		return state, true
+	}
+
+	backoff := w.interval
+	for {
+		state, err := w.store.Load(ctx, w.serviceName)
+		if err == nil {
+			if state.LastSyncedCursor == "" {
+				state.LastSyncedCursor = initialCursor
+			}
+			w.logger.Info("sync state loaded", slog.String("service", w.serviceName), slog.String("cursor", state.LastSyncedCursor))
+			return state, true
+		}
+		w.logger.Warn("failed to load sync state", slog.String("service", w.serviceName), slog.Any("error", err))
+
+		select {
+		case <-w.stopChan:
+			return nil, false
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(backoff):
+			backoff = time.Duration(math.Min(float64(backoff)*2, float64(w.maxBackoff)))
+		}
+	}
+}
+
+// saveState persists the state; a failed save is retried with the next sync
+func (w *Worker) saveState(ctx context.Context, state *SyncState) {
+	if w.store == nil {
+		return
+	}
+	if err := w.store.Save(ctx, *state); err != nil {
+		w.logger.Warn("failed to save sync state", slog.String("service", w.serviceName), slog.Any("error", err))
+	}
+}
@@ -2,11 +2,18 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	gosync "sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
 )
 
 func TestSyncStateTransitions(t *testing.T) {
@@ -20,10 +27,18 @@ func TestSyncStateTransitions(t *testing.T) {
 		t.Fatal("expected synced state")
 	}
 
-	state.MarkFailed()
-	if state.SyncStatus != "failed" {
+	state.MarkFailed(errors.New("timeout"))
+	if state.SyncStatus != "failed" || state.LastError != "timeout" || state.ConsecutiveFailures != 1 {
 		t.Fatal("expected failed state")
 	}
+	if state.LastSyncedCursor != "cursor-1" {
+		t.Fatal("expected failure to keep the cursor")
+	}
+
+	state.UpdateCursor("cursor-2")
+	if state.LastError != "" || state.ConsecutiveFailures != 0 {
+		t.Fatal("expected success to clear the failure")
+	}
 }
 
 func TestWorkerRunAndStop(t *testing.T) {
@@ -52,3 +67,154 @@ func TestWorkerRunAndStop(t *testing.T) {
 		t.Fatal("expected worker to execute at least once")
 	}
 }
+
+type memoryStateStore struct {
+	mu      gosync.Mutex
+	states  map[string]SyncState
+	saves   int
+	loadErr error
+}
+
+func (s *memoryStateStore) Load(_ context.Context, serviceName string) (*SyncState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loadErr != nil {
+		err := s.loadErr
+		s.loadErr = nil
+		return nil, err
+	}
+	if state, ok := s.states[serviceName]; ok {
+		return &state, nil
+	}
+	return NewSyncState(serviceName), nil
+}
+
+func (s *memoryStateStore) Save(_ context.Context, state SyncState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.ServiceName] = state
+	s.saves++
+	return nil
+}
+
+func (s *memoryStateStore) List(context.Context) ([]SyncState, error) {
+	return nil, nil
+}
+
+func (s *memoryStateStore) get(serviceName string) (SyncState, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.states[serviceName], s.saves
+}
+
+func TestWorkerResumesFromPersistedCursor(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := &memoryStateStore{
+		states:  map[string]SyncState{"svc": {ServiceName: "svc", LastSyncedCursor: "c5"}},
+		loadErr: errors.New("elasticsearch unavailable"),
+	}
+	seen := make(chan string, 10)
+	worker := NewWorker("svc", func(_ context.Context, cursor string) (string, error) {
+		seen <- cursor
+		return cursor + "x", nil
+	}, time.Millisecond, 5*time.Millisecond, logger).WithStateStore(store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker.Start(ctx, "")
+	defer worker.Stop()
+
+	if first := <-seen; first != "c5" {
+		t.Fatalf("expected sync to resume from c5 after a failed load, got %q", first)
+	}
+	<-seen
+	deadline := time.Now().Add(time.Second)
+	for {
+		state, _ := store.get("svc")
+		if strings.HasPrefix(state.LastSyncedCursor, "c5xx") && state.SyncStatus == "synced" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected persisted cursor to advance, got %+v", state)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkerPersistsFailures(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := &memoryStateStore{states: map[string]SyncState{}}
+	worker := NewWorker("svc", func(_ context.Context, cursor string) (string, error) {
+		return cursor, errors.New("event-timeline unavailable")
+	}, time.Millisecond, 2*time.Millisecond, logger).WithStateStore(store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker.Start(ctx, "initial")
+	defer worker.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		state, _ := store.get("svc")
+		if state.SyncStatus == "failed" {
+			if state.LastError != "event-timeline unavailable" || state.LastSyncedCursor != "initial" {
+				t.Fatalf("unexpected failed state: %+v", state)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected failure to be persisted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestElasticsearchStateStore(t *testing.T) {
+	var savedPath, savedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodGet && r.URL.EscapedPath() == "/aevum-sync-state/_doc/event-timeline":
+			_, _ = w.Write([]byte(`{"found":true,"_source":{"service_name":"event-timeline","last_synced_cursor":"42","sync_status":"synced"}}`))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/aevum-sync-state/_doc/"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"found":false}`))
+		case r.Method == http.MethodPut:
+			savedPath, savedBody = r.URL.EscapedPath(), string(body)
+			_, _ = w.Write([]byte(`{"result":"updated"}`))
+		case r.URL.Path == "/aevum-sync-state/_search":
+			_, _ = w.Write([]byte(`{"hits":{"hits":[{"_source":{"service_name":"decision-engine"}},{"_source":{"service_name":"event-timeline"}}]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	store := NewElasticsearchStateStore(es)
+
+	state, err := store.Load(context.Background(), "event-timeline")
+	if err != nil || state.LastSyncedCursor != "42" {
+		t.Fatalf("expected stored cursor, got %+v, %v", state, err)
+	}
+	state, err = store.Load(context.Background(), "decision-engine")
+	if err != nil || state.SyncStatus != "initialized" || state.LastSyncedCursor != "" {
+		t.Fatalf("expected new state for unknown service, got %+v, %v", state, err)
+	}
+
+	if err := store.Save(context.Background(), SyncState{ServiceName: "event-timeline/orders", LastSyncedCursor: "7"}); err != nil {
+		t.Fatalf("expected save success, got %v", err)
+	}
+	if savedPath != "/aevum-sync-state/_doc/event-timeline%2Forders" || !strings.Contains(savedBody, `"last_synced_cursor":"7"`) {
+		t.Fatalf("unexpected save request %s %s", savedPath, savedBody)
+	}
+
+	states, err := store.List(context.Background())
+	if err != nil || len(states) != 2 || states[0].ServiceName != "decision-engine" {
+		t.Fatalf("unexpected states %+v, %v", states, err)
+	}
+}
@@ -0,0 +1,187 @@
+package aevumclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by calls a client does not send because the breaker of its host is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState is the state of the circuit breaker of a host
+type BreakerState int
+
+const (
+	// BreakerClosed sends every call
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen sends a limited number of probes to find out whether the host recovered
+	BreakerHalfOpen
+	// BreakerOpen rejects calls without sending them
+	BreakerOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// MarshalText writes the state by name
+func (s BreakerState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// BreakerPolicy opens the breaker of a host after consecutive failed attempts: requests that got no
+// response or a 5xx status. Other statuses show the host is up and reset the count
+type BreakerPolicy struct {
+	// FailureThreshold is the number of failed attempts in a row that opens the breaker; 0 disables it
+	FailureThreshold int
+	// OpenTimeout is how long an open breaker rejects calls before it lets probes through
+	OpenTimeout time.Duration
+	// HalfOpenProbes is how many probes are sent at once while half-open; that many successful
+	// probes close the breaker and a failed one opens it again
+	HalfOpenProbes int
+}
+
+// DefaultBreakerPolicy opens after five failed attempts in a row and probes with one call after 30s
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{FailureThreshold: 5, OpenTimeout: 30 * time.Second, HalfOpenProbes: 1}
+}
+
+// BreakerStatus reports the breaker of the host a client calls
+type BreakerStatus struct {
+	Service string       `json:"service"`
+	Host    string       `json:"host"`
+	State   BreakerState `json:"state"`
+}
+
+// outcome is what an attempt tells the breaker about its host
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored is an attempt that says nothing about the host, such as one canceled by its caller
+	outcomeIgnored
+)
+
+// breaker is the circuit breaker of one host. A nil breaker lets every call through
+type breaker struct {
+	policy   BreakerPolicy
+	now      func() time.Time
+	onChange func(BreakerState)
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+}
+
+func newBreaker(policy BreakerPolicy, onChange func(BreakerState)) *breaker {
+	if policy.FailureThreshold <= 0 {
+		return nil
+	}
+	if policy.HalfOpenProbes <= 0 {
+		policy.HalfOpenProbes = 1
+	}
+	return &breaker{policy: policy, now: time.Now, onChange: onChange}
+}
+
+// State returns the current state, reporting an open breaker whose timeout passed as half-open
+func (b *breaker) State() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.policy.OpenTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether an attempt may be sent and whether it is a probe of a half-open breaker. Every
+// allowed attempt must be reported to done
+func (b *breaker) allow() (probe bool, err error) {
+	if b == nil {
+		return false, nil
+	}
+	b.mu.Lock()
+	changed := false
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.policy.OpenTimeout {
+		b.state, b.probes, b.successes = BreakerHalfOpen, 0, 0
+		changed = true
+	}
+	switch {
+	case b.state == BreakerClosed:
+	case b.state == BreakerHalfOpen && b.probes < b.policy.HalfOpenProbes:
+		b.probes++
+		probe = true
+	default:
+		err = ErrCircuitOpen
+	}
+	state := b.state
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(state)
+	}
+	return probe, err
+}
+
+// done records the outcome of an attempt allowed by allow. Outcomes of attempts sent before the breaker
+// opened do not count once it has
+func (b *breaker) done(probe bool, result outcome) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	previous := b.state
+	switch {
+	case probe && b.state == BreakerHalfOpen:
+		b.probes--
+		switch result {
+		case outcomeFailure:
+			b.open()
+		case outcomeSuccess:
+			b.successes++
+			if b.successes >= b.policy.HalfOpenProbes {
+				b.state, b.failures = BreakerClosed, 0
+			}
+		}
+	case b.state == BreakerClosed:
+		switch result {
+		case outcomeFailure:
+			b.failures++
+			if b.failures >= b.policy.FailureThreshold {
+				b.open()
+			}
+		case outcomeSuccess:
+			b.failures = 0
+		}
+	}
+	state := b.state
+	b.mu.Unlock()
+
+	if state != previous {
+		b.notify(state)
+	}
+}
+
+func (b *breaker) open() {
+	b.state, b.openedAt, b.failures = BreakerOpen, b.now(), 0
+}
+
+func (b *breaker) notify(state BreakerState) {
+	if b.onChange != nil {
+		b.onChange(state)
+	}
+}
@@ -0,0 +1,192 @@
+package aevumclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAndProbesWhenHalfOpen(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var changes []BreakerState
+	b := newBreaker(BreakerPolicy{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenProbes: 1}, func(s BreakerState) { changes = append(changes, s) })
+	b.now = func() time.Time { return now }
+
+	// A success in between resets the count of failures in a row
+	for _, result := range []outcome{outcomeFailure, outcomeSuccess, outcomeFailure, outcomeIgnored} {
+		_, _ = b.allow()
+		b.done(false, result)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected the breaker to stay closed, got %s", b.State())
+	}
+	_, _ = b.allow()
+	b.done(false, outcomeFailure)
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) || b.State() != BreakerOpen {
+		t.Fatalf("expected two failures in a row to open the breaker, got %s %v", b.State(), err)
+	}
+
+	now = now.Add(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("expected the breaker to be half-open after its timeout, got %s", b.State())
+	}
+	probe, err := b.allow()
+	if err != nil || !probe {
+		t.Fatalf("expected one probe, got %v %v", probe, err)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected a second call to wait for the probe, got %v", err)
+	}
+	// A probe canceled by its caller frees its slot without deciding anything
+	b.done(true, outcomeIgnored)
+	probe, _ = b.allow()
+	b.done(probe, outcomeFailure)
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected a failed probe to open the breaker again, got %s", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	probe, _ = b.allow()
+	b.done(probe, outcomeSuccess)
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected a successful probe to close the breaker, got %s", b.State())
+	}
+	want := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if len(changes) != len(want) {
+		t.Fatalf("expected changes %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("expected changes %v, got %v", want, changes)
+		}
+	}
+}
+
+func TestRetryBudgetLimitsRetriesToAShareOfCalls(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	b := newRetryBudget(RetryBudget{Ratio: 0.5, Window: time.Second})
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		b.deposit()
+	}
+	if !b.withdraw() || !b.withdraw() || b.withdraw() {
+		t.Fatal("expected two retries for four calls")
+	}
+	now = now.Add(time.Second)
+	if b.withdraw() {
+		t.Fatal("expected a new window to start without retries")
+	}
+	if !newRetryBudget(RetryBudget{}).withdraw() {
+		t.Fatal("expected a zero budget not to limit retries")
+	}
+}
+
+// recordingObserver keeps what a client reports
+type recordingObserver struct {
+	mu       sync.Mutex
+	results  []CallResult
+	attempts int
+	states   []BreakerState
+}
+
+func (o *recordingObserver) StartCall(ctx context.Context, _ Call) context.Context { return ctx }
+
+func (o *recordingObserver) StartAttempt(_ context.Context, _ Call, _ int, req *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.attempts++
+	req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+}
+
+func (o *recordingObserver) EndCall(_ context.Context, _ Call, result CallResult) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.results = append(o.results, result)
+}
+
+func (o *recordingObserver) BreakerChanged(_, _ string, state BreakerState) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.states = append(o.states, state)
+}
+
+func TestClientStopsCallingAHostWithAnOpenBreaker(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("Traceparent") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client := NewEventTimelineClient(server.URL).
+		WithRetryPolicy(fastRetries).
+		WithBreakerPolicy(BreakerPolicy{FailureThreshold: 4, OpenTimeout: time.Hour}).
+		WithObserver(observer)
+
+	if _, err := client.ListStreams(context.Background()); StatusCode(err) != http.StatusServiceUnavailable {
+		t.Fatalf("expected the 503 of the last attempt, got %v", err)
+	}
+	// The fourth failed attempt opens the breaker, so the retry after it is not sent
+	if _, err := client.ListStreams(context.Background()); StatusCode(err) != http.StatusServiceUnavailable || calls.Load() != 4 {
+		t.Fatalf("expected the breaker to stop the retries after 4 attempts, got %d attempts and %v", calls.Load(), err)
+	}
+	_, err := client.ListStreams(context.Background())
+	if !errors.Is(err, ErrCircuitOpen) || calls.Load() != 4 {
+		t.Fatalf("expected the call to be rejected without a request, got %d attempts and %v", calls.Load(), err)
+	}
+	status := client.BreakerStatus()
+	if status.Service != "event-timeline" || status.Host != server.Listener.Addr().String() || status.State != BreakerOpen {
+		t.Fatalf("unexpected breaker status %+v", status)
+	}
+
+	if observer.attempts != 4 || len(observer.results) != 3 || len(observer.states) != 1 || observer.states[0] != BreakerOpen {
+		t.Fatalf("unexpected observations %+v", observer)
+	}
+	if last := observer.results[2]; last.Attempts != 0 || !last.Rejected || !errors.Is(last.Err, ErrCircuitOpen) {
+		t.Fatalf("expected the rejected call to be reported, got %+v", last)
+	}
+	if second := observer.results[1]; second.Attempts != 1 || !second.Rejected {
+		t.Fatalf("expected the stopped retry to be reported, got %+v", second)
+	}
+}
+
+func TestClientStopsRetryingWhenTheBudgetIsSpent(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client := NewDecisionEngineClient(server.URL).
+		WithRetryPolicy(fastRetries).
+		WithRetryBudget(RetryBudget{Ratio: 0.5, Window: time.Hour}).
+		WithBreakerPolicy(BreakerPolicy{}).
+		WithObserver(observer)
+
+	// Half a retry per call: the first call may not retry, the second may retry once
+	for i := 0; i < 2; i++ {
+		_, _ = client.ListRules(context.Background())
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected one retry for two calls, got %d attempts", calls.Load())
+	}
+	if !observer.results[0].BudgetExhausted || !observer.results[1].BudgetExhausted || observer.results[1].Attempts != 2 {
+		t.Fatalf("expected a spent budget to be reported, got %+v", observer.results)
+	}
+	if client.BreakerStatus().State != BreakerClosed {
+		t.Fatal("expected a disabled breaker to stay closed")
+	}
+}
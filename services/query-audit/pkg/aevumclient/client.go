@@ -0,0 +1,257 @@
+// Package aevumclient is a typed client for the Event Timeline and Decision Engine APIs described in
+// docs/api. It depends on the standard library only, so any Go service of the platform can use it.
+package aevumclient
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader carries the request ID to the called service and back
+const RequestIDHeader = "X-Request-ID"
+
+// defaultTimeout bounds a single attempt of a call
+const defaultTimeout = 10 * time.Second
+
+// maxErrorBody bounds how much of an error response is read for its envelope
+const maxErrorBody = 64 << 10
+
+// TokenSource returns the bearer token of a request; an empty token sends the request without one
+type TokenSource func(ctx context.Context) (string, error)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose calls send id as their request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID set on ctx with WithRequestID, or an empty string
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+type tenantKey struct{}
+
+// DefaultTenant is the tenant of Event Timeline tokens without a tenant claim
+const DefaultTenant = "default"
+
+// WithTenant returns a context whose calls are made on behalf of tenantID; the token source reads it
+// to issue a token of that tenant
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// Tenant returns the tenant set on ctx with WithTenant, or DefaultTenant
+func Tenant(ctx context.Context) string {
+	if id, _ := ctx.Value(tenantKey{}).(string); id != "" {
+		return id
+	}
+	return DefaultTenant
+}
+
+// newRequestID returns a random request ID for calls made without one
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// transport sends the requests of a service client, retrying them under its policy and budget and
+// through the circuit breaker of its host
+type transport struct {
+	service    string
+	baseURL    string
+	host       string
+	httpClient *http.Client
+	retry      RetryPolicy
+	budget     *retryBudget
+	breaker    *breaker
+	observer   Observer
+	token      TokenSource
+}
+
+func newTransport(service, baseURL string) *transport {
+	t := &transport{
+		service:    service,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retry:      DefaultRetryPolicy(),
+		budget:     newRetryBudget(DefaultRetryBudget()),
+	}
+	if u, err := url.Parse(t.baseURL); err == nil {
+		t.host = u.Host
+	}
+	t.breaker = newBreaker(DefaultBreakerPolicy(), t.breakerChanged)
+	return t
+}
+
+func (t *transport) breakerChanged(state BreakerState) {
+	if t.observer != nil {
+		t.observer.BreakerChanged(t.service, t.host, state)
+	}
+}
+
+func (t *transport) breakerStatus() BreakerStatus {
+	return BreakerStatus{Service: t.service, Host: t.host, State: t.breaker.State()}
+}
+
+// tlsTransport clones the default transport with cfg
+func tlsTransport(cfg *tls.Config) http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg
+	return transport
+}
+
+// get decodes the response to a GET of path into out; operation names the client method for the
+// observer. All attempts send the same request ID: the one on ctx, or a new one when ctx has none
+func (t *transport) get(ctx context.Context, operation, path string, query url.Values, out interface{}) error {
+	endpoint := t.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	call := Call{Service: t.service, Operation: operation, Method: http.MethodGet, Path: path, Host: t.host, RequestID: RequestID(ctx)}
+	if call.RequestID == "" {
+		call.RequestID = newRequestID()
+	}
+	if t.observer != nil {
+		ctx = t.observer.StartCall(ctx, call)
+	}
+
+	start := time.Now()
+	result := t.attempts(ctx, call, endpoint, out)
+	result.Duration = time.Since(start)
+	if t.observer != nil {
+		t.observer.EndCall(ctx, call, result)
+	}
+	return result.Err
+}
+
+// attempts sends the attempts of a call until one succeeds, the error is not retried, the retry budget
+// is spent or the breaker rejects the next attempt. A rejected retry returns the error of the attempt
+// before it
+func (t *transport) attempts(ctx context.Context, call Call, endpoint string, out interface{}) CallResult {
+	var result CallResult
+	t.budget.deposit()
+	for attempt := 1; ; attempt++ {
+		probe, err := t.breaker.allow()
+		if err != nil {
+			result.Rejected = true
+			if result.Err == nil {
+				result.Err = fmt.Errorf("%s %s %s: %w", t.service, call.Method, call.Path, err)
+			}
+			return result
+		}
+		result.Attempts = attempt
+		result.Err = t.do(ctx, call, attempt, endpoint, out)
+		t.breaker.done(probe, outcomeOf(ctx, result.Err))
+		if result.Err == nil {
+			return result
+		}
+
+		delay, ok := t.retry.backoff(attempt, result.Err)
+		if !ok || ctx.Err() != nil {
+			return result
+		}
+		if !t.budget.withdraw() {
+			result.BudgetExhausted = true
+			return result
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result
+		case <-timer.C:
+		}
+	}
+}
+
+// do sends one attempt of a call
+func (t *transport) do(ctx context.Context, call Call, attempt int, endpoint string, out interface{}) error {
+	method, path := call.Method, call.Path
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(RequestIDHeader, call.RequestID)
+	if t.token != nil {
+		token, err := t.token(ctx)
+		if err != nil {
+			return fmt.Errorf("%s %s %s: failed to get token: %w", t.service, method, path, err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	if t.observer != nil {
+		t.observer.StartAttempt(ctx, call, attempt, req)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("%s %s %s: %w", t.service, method, path, err)
+		// A certificate the client does not trust will not be trusted on the next attempt either
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			return err
+		}
+		return &transportError{err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return newAPIError(t.service, method, path, call.RequestID, resp, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s %s: invalid response: %w", t.service, method, path, err)
+	}
+	return nil
+}
+
+// outcomeOf classifies the error of an attempt for the breaker: only a request that got no response or
+// a 5xx status counts against the host, and nothing counts once the caller gave up
+func outcomeOf(ctx context.Context, err error) outcome {
+	if err == nil {
+		return outcomeSuccess
+	}
+	if ctx.Err() != nil {
+		return outcomeIgnored
+	}
+	var certErr *tls.CertificateVerificationError
+	if isTransportError(err) || errors.As(err, &certErr) || StatusCode(err) >= http.StatusInternalServerError {
+		return outcomeFailure
+	}
+	if StatusCode(err) != 0 {
+		return outcomeSuccess
+	}
+	return outcomeIgnored
+}
+
+// transportError is a request that got no response and may get one on another attempt
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+
+func (e *transportError) Unwrap() error { return e.err }
+
+// isTransportError reports whether err is a request that got no response
+func isTransportError(err error) bool {
+	var te *transportError
+	return errors.As(err, &te)
+}
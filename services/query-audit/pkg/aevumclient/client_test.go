@@ -0,0 +1,118 @@
+package aevumclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries keeps the waits of retry tests short
+var fastRetries = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestRetriesUnavailableAndKeepsTheRequestID(t *testing.T) {
+	var calls atomic.Int32
+	ids := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids <- r.Header.Get(RequestIDHeader)
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"streams":[{"stream_id":"orders","latest_sequence":4}]}`))
+	}))
+	defer server.Close()
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	streams, err := NewEventTimelineClient(server.URL).WithRetryPolicy(fastRetries).ListStreams(ctx)
+	if err != nil || len(streams) != 1 || streams[0].LatestSequence != 4 {
+		t.Fatalf("expected the third attempt to succeed, got %v %v", streams, err)
+	}
+	close(ids)
+	for id := range ids {
+		if id != "req-1" {
+			t.Fatalf("expected every attempt to send the request ID, got %q", id)
+		}
+	}
+
+	// Without a request ID on the context, one is generated and kept across attempts
+	calls.Store(0)
+	ids = make(chan string, 3)
+	if _, err := NewEventTimelineClient(server.URL).WithRetryPolicy(fastRetries).ListStreams(context.Background()); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	close(ids)
+	first := ""
+	for id := range ids {
+		if id == "" || (first != "" && id != first) {
+			t.Fatalf("expected one generated request ID, got %q after %q", id, first)
+		}
+		first = id
+	}
+}
+
+func TestDoesNotRetryClientErrorsOrExhaustedAttempts(t *testing.T) {
+	var calls atomic.Int32
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	client := NewDecisionEngineClient(server.URL).WithRetryPolicy(fastRetries)
+
+	if _, err := client.ListRules(context.Background()); StatusCode(err) != http.StatusBadRequest || calls.Load() != 1 {
+		t.Fatalf("expected one attempt for a 400, got %d attempts and %v", calls.Load(), err)
+	}
+
+	calls.Store(0)
+	status = http.StatusBadGateway
+	if _, err := client.ListRules(context.Background()); StatusCode(err) != http.StatusBadGateway || calls.Load() != 3 {
+		t.Fatalf("expected three attempts for a 502, got %d attempts and %v", calls.Load(), err)
+	}
+}
+
+func TestRetryStopsWhenTheContextEnds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := NewEventTimelineClient(server.URL).WithRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Minute}).ListStreams(ctx)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 30*time.Second {
+		t.Fatalf("expected the 429 with its Retry-After, got %v", err)
+	}
+	if time.Since(started) > 5*time.Second {
+		t.Fatal("expected the wait for Retry-After to end with the context")
+	}
+}
+
+func TestBackoffIsJitteredAndCapped(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	unavailable := &APIError{StatusCode: http.StatusServiceUnavailable}
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 50; i++ {
+		delay, ok := policy.backoff(5, unavailable)
+		if !ok || delay < 0 || delay > policy.MaxDelay {
+			t.Fatalf("expected a wait up to the cap, got %v %v", delay, ok)
+		}
+		seen[delay] = true
+	}
+	if len(seen) < 2 {
+		t.Fatal("expected waits to vary")
+	}
+	if _, ok := policy.backoff(10, unavailable); ok {
+		t.Fatal("expected no retry after the last attempt")
+	}
+	if delay, _ := policy.backoff(1, &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second}); delay != policy.MaxDelay {
+		t.Fatalf("expected Retry-After to be capped, got %v", delay)
+	}
+}
@@ -0,0 +1,267 @@
+package aevumclient
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DecisionTimeLayout formats decision times with the 100ns precision Decision Engine stores
+const DecisionTimeLayout = "2006-01-02T15:04:05.9999999Z07:00"
+
+// MaxDecisionPageSize is the largest page Decision Engine serves
+const MaxDecisionPageSize = 200
+
+// Decision is a decision evaluated by Decision Engine
+type Decision struct {
+	ID                string                 `json:"id"`
+	EventID           string                 `json:"eventId,omitempty"`
+	StreamID          string                 `json:"streamId,omitempty"`
+	RequestID         string                 `json:"requestId,omitempty"`
+	RuleID            string                 `json:"ruleId"`
+	RuleVersion       int                    `json:"ruleVersion"`
+	Status            DecisionStatus         `json:"status"`
+	InputContext      map[string]interface{} `json:"inputContext,omitempty"`
+	Output            map[string]interface{} `json:"output,omitempty"`
+	OutputData        map[string]interface{} `json:"outputData,omitempty"`
+	Trace             []TraceStep            `json:"trace,omitempty"`
+	EvaluatedAt       time.Time              `json:"evaluatedAt"`
+	DeterministicHash string                 `json:"deterministicHash"`
+	ErrorMessage      string                 `json:"errorMessage,omitempty"`
+}
+
+// TraceStep is the evaluation of one rule condition
+type TraceStep struct {
+	Step     int         `json:"step"`
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"`
+	Expected interface{} `json:"expected,omitempty"`
+	Actual   interface{} `json:"actual,omitempty"`
+	Matched  bool        `json:"matched"`
+	Message  string      `json:"message,omitempty"`
+}
+
+// DecisionPage is one page of the decision listing
+type DecisionPage struct {
+	Items    []Decision `json:"items"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"pageSize"`
+	Total    int        `json:"total"`
+}
+
+// Rule is a version of a Decision Engine rule
+type Rule struct {
+	ID          string            `json:"id"`
+	Version     int               `json:"version"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Conditions  []RuleCondition   `json:"conditions"`
+	Actions     []RuleAction      `json:"actions"`
+	Status      RuleStatus        `json:"status,omitempty"`
+	IsActive    bool              `json:"isActive"`
+	Priority    int               `json:"priority,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// RuleCondition compares an event field with a value, or combines nested conditions
+type RuleCondition struct {
+	Field            string             `json:"field"`
+	Operator         ComparisonOperator `json:"operator"`
+	Value            interface{}        `json:"value"`
+	LogicalOperator  LogicalOperator    `json:"logicalOperator,omitempty"`
+	NestedConditions []RuleCondition    `json:"nestedConditions,omitempty"`
+}
+
+// RuleAction is an action a rule runs when its conditions match
+type RuleAction struct {
+	Type        ActionType             `json:"type"`
+	Order       int                    `json:"order"`
+	Parameters  map[string]interface{} `json:"parameters"`
+	Description string                 `json:"description,omitempty"`
+}
+
+// Decision Engine serializes its enums as numbers; these types read both numbers and names,
+// and always write names
+type (
+	// DecisionStatus is the outcome of a decision
+	DecisionStatus string
+	// RuleStatus is the lifecycle state of a rule
+	RuleStatus string
+	// ComparisonOperator compares a condition's field with its value
+	ComparisonOperator string
+	// LogicalOperator combines nested conditions
+	LogicalOperator string
+	// ActionType is the kind of a rule action
+	ActionType string
+)
+
+// The names of each enum, in the order of their numbers
+var (
+	decisionStatuses    = []string{"Approved", "Rejected", "Pending", "Error"}
+	ruleStatuses        = []string{"Draft", "Active", "Inactive", "Archived"}
+	comparisonOperators = []string{"Equals", "NotEquals", "GreaterThan", "GreaterThanOrEqual", "LessThan", "LessThanOrEqual", "Contains", "NotContains", "StartsWith", "EndsWith", "In", "NotIn", "Regex"}
+	logicalOperators    = []string{"And", "Or"}
+	actionTypes         = []string{"SetValue", "CallWebhook", "SendNotification", "LogEvent", "StoreDecision"}
+)
+
+// UnmarshalJSON reads a status given by name or number
+func (s *DecisionStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, decisionStatuses, (*string)(s))
+}
+
+// UnmarshalJSON reads a status given by name or number
+func (s *RuleStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, ruleStatuses, (*string)(s))
+}
+
+// UnmarshalJSON reads an operator given by name or number
+func (o *ComparisonOperator) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, comparisonOperators, (*string)(o))
+}
+
+// UnmarshalJSON reads an operator given by name or number
+func (o *LogicalOperator) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, logicalOperators, (*string)(o))
+}
+
+// UnmarshalJSON reads an action type given by name or number
+func (t *ActionType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, actionTypes, (*string)(t))
+}
+
+// unmarshalEnum reads an enum given by name or by its number in names
+func unmarshalEnum(data []byte, names []string, out *string) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err == nil {
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("enum must be a name or a number: %s", data)
+	}
+	if n < 0 || n >= len(names) {
+		return fmt.Errorf("unknown enum value %d", n)
+	}
+	*out = names[n]
+	return nil
+}
+
+// DecisionEngineClient reads decisions and rules from Decision Engine
+type DecisionEngineClient struct {
+	t *transport
+}
+
+// NewDecisionEngineClient creates a new client of the Decision Engine at baseURL
+func NewDecisionEngineClient(baseURL string) *DecisionEngineClient {
+	return &DecisionEngineClient{t: newTransport("decision-engine", baseURL)}
+}
+
+// WithTLSConfig sends requests over TLS using cfg, presenting its client
+// certificate to servers that ask for one
+func (c *DecisionEngineClient) WithTLSConfig(cfg *tls.Config) *DecisionEngineClient {
+	c.t.httpClient.Transport = tlsTransport(cfg)
+	return c
+}
+
+// WithTokenSource authenticates requests with the bearer tokens of source
+func (c *DecisionEngineClient) WithTokenSource(source TokenSource) *DecisionEngineClient {
+	c.t.token = source
+	return c
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy
+func (c *DecisionEngineClient) WithRetryPolicy(policy RetryPolicy) *DecisionEngineClient {
+	c.t.retry = policy
+	return c
+}
+
+// WithRetryBudget replaces DefaultRetryBudget
+func (c *DecisionEngineClient) WithRetryBudget(budget RetryBudget) *DecisionEngineClient {
+	c.t.budget = newRetryBudget(budget)
+	return c
+}
+
+// WithBreakerPolicy replaces DefaultBreakerPolicy, closing the breaker
+func (c *DecisionEngineClient) WithBreakerPolicy(policy BreakerPolicy) *DecisionEngineClient {
+	c.t.breaker = newBreaker(policy, c.t.breakerChanged)
+	return c
+}
+
+// WithObserver reports the calls of the client to observer
+func (c *DecisionEngineClient) WithObserver(observer Observer) *DecisionEngineClient {
+	c.t.observer = observer
+	return c
+}
+
+// BreakerStatus reports the circuit breaker of the host of the client
+func (c *DecisionEngineClient) BreakerStatus() BreakerStatus {
+	return c.t.breakerStatus()
+}
+
+// ListDecisions fetches one page of the decisions evaluated between from and to, oldest first; a zero time leaves that bound open
+func (c *DecisionEngineClient) ListDecisions(ctx context.Context, from, to time.Time, page, pageSize int) (*DecisionPage, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("pageSize", strconv.Itoa(pageSize))
+	if !from.IsZero() {
+		query.Set("from", from.UTC().Format(DecisionTimeLayout))
+	}
+	if !to.IsZero() {
+		query.Set("to", to.UTC().Format(DecisionTimeLayout))
+	}
+
+	var result DecisionPage
+	if err := c.t.get(ctx, "ListDecisions", "/api/v1/decisions", query, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetDecisions fetches all decisions in a time range, paging through the decision listing
+func (c *DecisionEngineClient) GetDecisions(ctx context.Context, from, to time.Time) ([]Decision, error) {
+	decisions := []Decision{}
+	for page := 1; ; page++ {
+		result, err := c.ListDecisions(ctx, from, to, page, MaxDecisionPageSize)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, result.Items...)
+		if len(result.Items) < MaxDecisionPageSize || page*MaxDecisionPageSize >= result.Total {
+			return decisions, nil
+		}
+	}
+}
+
+// GetDecision fetches a single decision by ID
+func (c *DecisionEngineClient) GetDecision(ctx context.Context, decisionID string) (*Decision, error) {
+	var decision Decision
+	if err := c.t.get(ctx, "GetDecision", "/api/v1/decisions/"+url.PathEscape(decisionID), nil, &decision); err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+// ListRules fetches the active rules
+func (c *DecisionEngineClient) ListRules(ctx context.Context) ([]Rule, error) {
+	rules := []Rule{}
+	if err := c.t.get(ctx, "ListRules", "/api/v1/rules", nil, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetRule fetches the active version of a rule by ID
+func (c *DecisionEngineClient) GetRule(ctx context.Context, ruleID string) (*Rule, error) {
+	var rule Rule
+	if err := c.t.get(ctx, "GetRule", "/api/v1/rules/"+url.PathEscape(ruleID), nil, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
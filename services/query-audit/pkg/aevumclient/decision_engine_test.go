@@ -0,0 +1,85 @@
+package aevumclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecisionEngineClientEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/decisions":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			items := []string{}
+			for i := 0; i < MaxDecisionPageSize && (page-1)*MaxDecisionPageSize+i < 250; i++ {
+				items = append(items, fmt.Sprintf(`{"id":"d%d","ruleId":"r1","ruleVersion":2,"status":0,"evaluatedAt":"2026-01-01T00:00:00.1234567Z"}`, (page-1)*MaxDecisionPageSize+i))
+			}
+			_, _ = fmt.Fprintf(w, `{"items":[%s],"page":%d,"pageSize":%d,"total":250}`, strings.Join(items, ","), page, MaxDecisionPageSize)
+		case r.URL.Path == "/api/v1/decisions/d1":
+			_, _ = w.Write([]byte(`{"id":"d1","ruleId":"r1","ruleVersion":2,"status":"Rejected","inputContext":{"amount":5},"evaluatedAt":"2026-01-01T00:00:00Z","deterministicHash":"h1"}`))
+		case r.URL.Path == "/api/v1/rules":
+			_, _ = w.Write([]byte(`[{"id":"r1","version":2,"name":"limit","conditions":[{"field":"amount","operator":2,"value":100}],"actions":[{"type":0,"order":1,"parameters":{"approved":false}}],"status":1}]`))
+		case r.URL.Path == "/api/v1/rules/r1":
+			_, _ = w.Write([]byte(`{"id":"r1","version":2,"name":"limit","conditions":[],"actions":[],"status":"Active"}`))
+		default:
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type":"https://httpstatuses.com/404","title":"Rule not found","status":404,"detail":"Rule 'r2' was not found"}`))
+		}
+	}))
+	defer server.Close()
+	client := NewDecisionEngineClient(server.URL)
+
+	decisions, err := client.GetDecisions(context.Background(), time.Now().Add(-time.Hour), time.Now())
+	if err != nil || len(decisions) != 250 {
+		t.Fatalf("unexpected decisions response: err=%v len=%d", err, len(decisions))
+	}
+	if decisions[249].ID != "d249" || decisions[0].Status != "Approved" || decisions[0].EvaluatedAt.Nanosecond() != 123456700 {
+		t.Fatalf("unexpected decision %+v", decisions[0])
+	}
+
+	decision, err := client.GetDecision(context.Background(), "d1")
+	if err != nil || decision.Status != "Rejected" || decision.RuleVersion != 2 || decision.InputContext["amount"] != float64(5) {
+		t.Fatalf("unexpected decision response: err=%v value=%+v", err, decision)
+	}
+
+	rules, err := client.ListRules(context.Background())
+	if err != nil || len(rules) != 1 {
+		t.Fatalf("unexpected rules response: err=%v", err)
+	}
+	if rules[0].Status != "Active" || rules[0].Conditions[0].Operator != "GreaterThan" || rules[0].Actions[0].Type != "SetValue" {
+		t.Fatalf("expected numeric enums to be named, got %+v", rules[0])
+	}
+	encoded, _ := json.Marshal(rules[0].Conditions[0])
+	if string(encoded) != `{"field":"amount","operator":"GreaterThan","value":100}` {
+		t.Fatalf("expected enums to be written by name, got %s", encoded)
+	}
+
+	rule, err := client.GetRule(context.Background(), "r1")
+	if err != nil || rule.ID != "r1" || rule.Status != "Active" {
+		t.Fatalf("unexpected rule response: err=%v value=%+v", err, rule)
+	}
+
+	_, err = client.GetRule(context.Background(), "r2")
+	apiErr, ok := err.(*APIError)
+	if !ok || !IsNotFound(err) || apiErr.Code != "Rule not found" || apiErr.Message != "Rule 'r2' was not found" {
+		t.Fatalf("expected the problem details in the error, got %v", err)
+	}
+}
+
+func TestDecisionEnumsRejectUnknownValues(t *testing.T) {
+	var status DecisionStatus
+	if err := json.Unmarshal([]byte(`7`), &status); err == nil {
+		t.Fatal("expected an unknown number to fail")
+	}
+	if err := json.Unmarshal([]byte(`true`), &status); err == nil {
+		t.Fatal("expected a boolean to fail")
+	}
+}
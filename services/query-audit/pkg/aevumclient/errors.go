@@ -0,0 +1,116 @@
+package aevumclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is a response with a status outside 2xx, carrying the error envelope of the service
+type APIError struct {
+	// Service names the called service, "event-timeline" or "decision-engine"
+	Service    string
+	Method     string
+	Path       string
+	StatusCode int
+	// Code is the machine-readable error code of Event Timeline, or the problem title of Decision Engine
+	Code    string
+	Message string
+	// Details holds the envelope's details, or the validation errors of a problem
+	Details   map[string]interface{}
+	RequestID string
+	// RetryAfter is the wait the service asked for with a Retry-After header
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s %s: status %d", e.Service, e.Method, e.Path, e.StatusCode)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// IsNotFound reports whether err is an APIError with status 404
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// StatusCode returns the status of an APIError in err's chain, or 0 when there is none
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// errorEnvelope covers the error bodies of both services: Event Timeline's {"error": {...}},
+// Decision Engine's problem details and its plain {"message": ...}
+type errorEnvelope struct {
+	Error json.RawMessage `json:"error"`
+
+	Title   string                 `json:"title"`
+	Detail  string                 `json:"detail"`
+	Errors  map[string]interface{} `json:"errors"`
+	Message string                 `json:"message"`
+}
+
+type timelineError struct {
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	RequestID string                 `json:"request_id"`
+	Details   map[string]interface{} `json:"details"`
+}
+
+// newAPIError reads the error envelope of resp from body; bodies that are not JSON are kept as the message
+func newAPIError(service, method, path, requestID string, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		Service:    service,
+		Method:     method,
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		RequestID:  requestID,
+		RetryAfter: retryAfter(resp.Header.Get("Retry-After")),
+	}
+	if id := resp.Header.Get(RequestIDHeader); id != "" {
+		apiErr.RequestID = id
+	}
+
+	var envelope errorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+	var te timelineError
+	switch {
+	case len(envelope.Error) > 0 && json.Unmarshal(envelope.Error, &te) == nil:
+		apiErr.Code, apiErr.Message, apiErr.Details = te.Code, te.Message, te.Details
+		if te.RequestID != "" {
+			apiErr.RequestID = te.RequestID
+		}
+	case len(envelope.Error) > 0:
+		_ = json.Unmarshal(envelope.Error, &apiErr.Message)
+	case envelope.Title != "":
+		apiErr.Code, apiErr.Message, apiErr.Details = envelope.Title, envelope.Detail, envelope.Errors
+	default:
+		apiErr.Message = envelope.Message
+	}
+	return apiErr
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,163 @@
+package aevumclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Event is an event of Event Timeline
+type Event struct {
+	EventID        string                 `json:"event_id"`
+	TenantID       string                 `json:"tenant_id,omitempty"`
+	StreamID       string                 `json:"stream_id"`
+	SequenceNumber int64                  `json:"sequence_number"`
+	EventType      string                 `json:"event_type"`
+	Payload        map[string]interface{} `json:"payload"`
+	Metadata       map[string]string      `json:"metadata,omitempty"`
+	IdempotencyKey string                 `json:"idempotency_key"`
+	OccurredAt     time.Time              `json:"occurred_at"`
+	IngestedAt     time.Time              `json:"ingested_at"`
+	SchemaVersion  int                    `json:"schema_version,omitempty"`
+}
+
+// StreamInfo describes a stream listed by Event Timeline
+type StreamInfo struct {
+	StreamID       string `json:"stream_id"`
+	LatestSequence int64  `json:"latest_sequence"`
+}
+
+// EventPage is one page of the events of a stream
+type EventPage struct {
+	Events []Event `json:"events"`
+	// NextCursor reads the page that follows; it is empty on the last page
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// EventTimelineClient reads streams and events from Event Timeline
+type EventTimelineClient struct {
+	t *transport
+}
+
+// NewEventTimelineClient creates a new client of the Event Timeline at baseURL
+func NewEventTimelineClient(baseURL string) *EventTimelineClient {
+	return &EventTimelineClient{t: newTransport("event-timeline", baseURL)}
+}
+
+// WithTLSConfig sends requests over TLS using cfg, presenting its client
+// certificate to servers that ask for one
+func (c *EventTimelineClient) WithTLSConfig(cfg *tls.Config) *EventTimelineClient {
+	c.t.httpClient.Transport = tlsTransport(cfg)
+	return c
+}
+
+// WithTokenSource authenticates requests with the bearer tokens of source
+func (c *EventTimelineClient) WithTokenSource(source TokenSource) *EventTimelineClient {
+	c.t.token = source
+	return c
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy
+func (c *EventTimelineClient) WithRetryPolicy(policy RetryPolicy) *EventTimelineClient {
+	c.t.retry = policy
+	return c
+}
+
+// WithRetryBudget replaces DefaultRetryBudget
+func (c *EventTimelineClient) WithRetryBudget(budget RetryBudget) *EventTimelineClient {
+	c.t.budget = newRetryBudget(budget)
+	return c
+}
+
+// WithBreakerPolicy replaces DefaultBreakerPolicy, closing the breaker
+func (c *EventTimelineClient) WithBreakerPolicy(policy BreakerPolicy) *EventTimelineClient {
+	c.t.breaker = newBreaker(policy, c.t.breakerChanged)
+	return c
+}
+
+// WithObserver reports the calls of the client to observer
+func (c *EventTimelineClient) WithObserver(observer Observer) *EventTimelineClient {
+	c.t.observer = observer
+	return c
+}
+
+// BreakerStatus reports the circuit breaker of the host of the client
+func (c *EventTimelineClient) BreakerStatus() BreakerStatus {
+	return c.t.breakerStatus()
+}
+
+// streamPageSize is the largest page of streams the service returns
+const streamPageSize = 1000
+
+// ListStreams lists the streams the client's token can read, following the cursor through every page
+func (c *EventTimelineClient) ListStreams(ctx context.Context) ([]StreamInfo, error) {
+	var streams []StreamInfo
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(streamPageSize))
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		var page struct {
+			Streams    []StreamInfo `json:"streams"`
+			NextCursor string       `json:"next_cursor"`
+		}
+		if err := c.t.get(ctx, "ListStreams", "/api/v1/streams", query, &page); err != nil {
+			return nil, err
+		}
+		streams = append(streams, page.Streams...)
+		if page.NextCursor == "" {
+			return streams, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// GetStreamEvents fetches a page of up to limit events of a stream, in sequence order from the start
+// of the stream or from cursor
+func (c *EventTimelineClient) GetStreamEvents(ctx context.Context, streamID, cursor string, limit int) (*EventPage, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var page EventPage
+	if err := c.t.get(ctx, "GetStreamEvents", "/api/v1/streams/"+url.PathEscape(streamID)+"/events", query, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetStreamEventsFrom fetches the first page of up to limit events of a stream starting at fromSequence;
+// the next pages are read with GetStreamEvents and the cursor of the page
+func (c *EventTimelineClient) GetStreamEventsFrom(ctx context.Context, streamID string, fromSequence int64, limit int) (*EventPage, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("from_sequence", strconv.FormatInt(fromSequence, 10))
+
+	var page EventPage
+	if err := c.t.get(ctx, "GetStreamEvents", "/api/v1/streams/"+url.PathEscape(streamID)+"/events", query, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetEvent fetches a single event by ID
+func (c *EventTimelineClient) GetEvent(ctx context.Context, eventID string) (*Event, error) {
+	var result struct {
+		Event *Event `json:"event"`
+	}
+	if err := c.t.get(ctx, "GetEvent", "/api/v1/events/"+url.PathEscape(eventID), nil, &result); err != nil {
+		return nil, err
+	}
+	if result.Event == nil {
+		return nil, fmt.Errorf("%s GET /api/v1/events/%s: invalid response: no event", c.t.service, eventID)
+	}
+	return result.Event, nil
+}
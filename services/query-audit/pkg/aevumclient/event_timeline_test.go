@@ -0,0 +1,106 @@
+package aevumclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEventTimelineClientGetStreamEventsAndGetEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/streams/all/events":
+			if r.URL.Query().Get("limit") != "50" || r.URL.Query().Get("cursor") != "c1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"events":[{"event_id":"e1","stream_id":"all","sequence_number":3,"event_type":"created","payload":{"amount":10},"metadata":{"source":"test"},"occurred_at":"2026-01-01T00:00:00Z","ingested_at":"2026-01-01T00:00:01Z","schema_version":2}],"next_cursor":"c2","has_more":true}`))
+		case "/api/v1/events/e1":
+			_, _ = w.Write([]byte(`{"event":{"event_id":"e1","stream_id":"s1"}}`))
+		default:
+			w.Header().Set(RequestIDHeader, "req-9")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":"event_not_found","message":"event not found","request_id":"req-9"}}`))
+		}
+	}))
+	defer server.Close()
+	client := NewEventTimelineClient(server.URL)
+
+	page, err := client.GetStreamEvents(context.Background(), "all", "c1", 50)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(page.Events) != 1 || page.NextCursor != "c2" || !page.HasMore {
+		t.Fatalf("unexpected events response: %+v", page)
+	}
+	event := page.Events[0]
+	if event.SequenceNumber != 3 || event.SchemaVersion != 2 || event.Metadata["source"] != "test" || event.Payload["amount"] != float64(10) {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if !event.OccurredAt.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !event.IngestedAt.Equal(time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC)) {
+		t.Fatalf("unexpected event times %+v", event)
+	}
+
+	single, err := client.GetEvent(context.Background(), "e1")
+	if err != nil || single.EventID != "e1" || single.StreamID != "s1" {
+		t.Fatalf("unexpected event response: %+v %v", single, err)
+	}
+
+	_, err = client.GetEvent(context.Background(), "missing")
+	if !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	apiErr := err.(*APIError)
+	if apiErr.Code != "event_not_found" || apiErr.Message != "event not found" || apiErr.RequestID != "req-9" || apiErr.Path != "/api/v1/events/missing" {
+		t.Fatalf("unexpected error envelope %+v", apiErr)
+	}
+	if apiErr.Error() != "event-timeline GET /api/v1/events/missing: status 404 event_not_found: event not found" {
+		t.Fatalf("unexpected error message %q", apiErr.Error())
+	}
+}
+
+func TestEventTimelineClientListStreamsFollowsTheCursor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "1000" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			_, _ = w.Write([]byte(`{"streams":[{"stream_id":"orders","latest_sequence":4}],"next_cursor":"p2","has_more":true}`))
+		case "p2":
+			_, _ = w.Write([]byte(`{"streams":[],"next_cursor":"p3","has_more":true}`))
+		case "p3":
+			_, _ = w.Write([]byte(`{"streams":[{"stream_id":"payments","latest_sequence":1}],"next_cursor":"","has_more":false}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	streams, err := NewEventTimelineClient(server.URL).ListStreams(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(streams) != 2 || streams[0].StreamID != "orders" || streams[1].StreamID != "payments" {
+		t.Fatalf("expected the streams of every page, got %+v", streams)
+	}
+}
+
+func TestEventTimelineClientGetStreamEventsFrom(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/streams/orders/events" || r.URL.Query().Get("from_sequence") != "1200" || r.URL.Query().Get("limit") != "5" || r.URL.Query().Has("cursor") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"events":[{"event_id":"e1200","stream_id":"orders","sequence_number":1200}],"next_cursor":"c2","has_more":true}`))
+	}))
+	defer server.Close()
+
+	page, err := NewEventTimelineClient(server.URL).GetStreamEventsFrom(context.Background(), "orders", 1200, 5)
+	if err != nil || len(page.Events) != 1 || page.Events[0].SequenceNumber != 1200 || page.NextCursor != "c2" {
+		t.Fatalf("unexpected page %+v %v", page, err)
+	}
+}
@@ -0,0 +1,42 @@
+package aevumclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Call describes a call of a client method to a service
+type Call struct {
+	Service string
+	// Operation is the client method making the call, such as GetEvent
+	Operation string
+	Method    string
+	Path      string
+	Host      string
+	RequestID string
+}
+
+// CallResult is the outcome of a call after its last attempt
+type CallResult struct {
+	// Attempts counts the requests sent; it is 0 for a call rejected by an open breaker
+	Attempts int
+	Duration time.Duration
+	Err      error
+	// BudgetExhausted reports a failed attempt that was not retried because the retry budget was spent
+	BudgetExhausted bool
+	// Rejected reports an attempt that was not sent because the breaker was open
+	Rejected bool
+}
+
+// Observer is told about the calls of a client, to record metrics and traces of them
+type Observer interface {
+	// StartCall is called before the first attempt; the attempts and EndCall get the context it returns
+	StartCall(ctx context.Context, call Call) context.Context
+	// StartAttempt is called with each request before it is sent, so it may add headers to it
+	StartAttempt(ctx context.Context, call Call, attempt int, req *http.Request)
+	// EndCall is called once the call returns
+	EndCall(ctx context.Context, call Call, result CallResult)
+	// BreakerChanged is called when the breaker of the host of a service changes state
+	BreakerChanged(service, host string, state BreakerState)
+}
@@ -0,0 +1,134 @@
+package aevumclient
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RetryPolicy retries calls that got no response or a 429, 502, 503 or 504 status. Every call of the
+// clients is a GET, so a retry cannot apply a change twice
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; 1 disables retries
+	MaxAttempts int
+	// BaseDelay is the upper bound of the first wait, doubled for each further attempt
+	BaseDelay time.Duration
+	// MaxDelay caps every wait, including one asked for with Retry-After
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy makes up to three attempts, waiting up to 100ms and then up to 200ms
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}
+}
+
+// backoff returns how long to wait before the attempt after attempt, and false when err is not retried
+// or no attempt is left. The wait is drawn uniformly up to the exponential bound, so clients that failed
+// together do not retry together
+func (p RetryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || !retryable(err) {
+		return 0, false
+	}
+	bound := p.BaseDelay << (attempt - 1)
+	if bound > p.MaxDelay || bound <= 0 {
+		bound = p.MaxDelay
+	}
+	var delay time.Duration
+	if bound > 0 {
+		delay = time.Duration(rand.Int64N(int64(bound) + 1))
+	}
+	if after := retryAfterOf(err); after > delay {
+		delay = min(after, p.MaxDelay)
+	}
+	return delay, true
+}
+
+// retryable reports whether err may succeed on another attempt
+func retryable(err error) bool {
+	if isTransportError(err) {
+		return true
+	}
+	switch StatusCode(err) {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func retryAfterOf(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// RetryBudget bounds the retries of a client to a share of its calls, so that retries do not multiply
+// the load on a service that is failing. Calls and retries are counted over fixed windows
+type RetryBudget struct {
+	// Ratio is the number of retries allowed per call
+	Ratio float64
+	// MinPerSecond retries are allowed whatever the number of calls, so a client that calls rarely can retry
+	MinPerSecond int
+	// Window is the period calls and retries are counted over; 0 does not limit retries
+	Window time.Duration
+}
+
+// DefaultRetryBudget allows one retry per five calls, and at least ten retries a second, over 10s windows
+func DefaultRetryBudget() RetryBudget {
+	return RetryBudget{Ratio: 0.2, MinPerSecond: 10, Window: 10 * time.Second}
+}
+
+// retryBudget counts the calls and retries of a client under a RetryBudget. A nil budget allows every retry
+type retryBudget struct {
+	budget RetryBudget
+	now    func() time.Time
+
+	mu      sync.Mutex
+	start   time.Time
+	calls   int
+	retries int
+}
+
+func newRetryBudget(budget RetryBudget) *retryBudget {
+	if budget.Window <= 0 {
+		return nil
+	}
+	return &retryBudget{budget: budget, now: time.Now}
+}
+
+// deposit counts a call
+func (b *retryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll()
+	b.calls++
+}
+
+// withdraw counts a retry and reports whether the budget allows it
+func (b *retryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll()
+	allowed := float64(b.budget.MinPerSecond)*b.budget.Window.Seconds() + b.budget.Ratio*float64(b.calls)
+	if float64(b.retries+1) > allowed {
+		return false
+	}
+	b.retries++
+	return true
+}
+
+// roll starts a new window once the current one is over
+func (b *retryBudget) roll() {
+	if now := b.now(); now.Sub(b.start) >= b.budget.Window {
+		b.start, b.calls, b.retries = now, 0, 0
+	}
+}
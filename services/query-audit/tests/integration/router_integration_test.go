@@ -9,7 +9,7 @@ import (
 )
 
 func TestRouterHealthEndpoint(t *testing.T) {
-	router := api.SetupRouter(nil, nil, nil, nil, nil)
+	router := api.SetupRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
@@ -24,7 +24,7 @@ func TestRouterHealthEndpoint(t *testing.T) {
 }
 
 func TestRouterMetricsEndpoint(t *testing.T) {
-	router := api.SetupRouter(nil, nil, nil, nil, nil)
+	router := api.SetupRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/admin/metrics", nil)